@@ -19,13 +19,17 @@ func PlayFile(m *model.Model) {
 
 	filename := m.Files[m.CurrentRow]
 
-	// Don't play directories
-	if strings.HasSuffix(filename, "/") || filename == ".." {
+	// Don't play directories or DX7 banks
+	if strings.HasSuffix(filename, "/") || filename == ".." || m.FileSelectView == types.SoundMakerView {
 		return
 	}
 
-	// Get full path for the file
-	fullPath := filepath.Join(m.CurrentDir, filename)
+	PlayFilePath(m, filepath.Join(m.CurrentDir, filename))
+}
+
+// PlayFilePath starts previewing a file, or stops it if it is already playing
+func PlayFilePath(m *model.Model, fullPath string) {
+	filename := filepath.Base(fullPath)
 
 	// Check if this specific file is currently playing
 	if m.CurrentlyPlayingFile == fullPath {
@@ -49,9 +53,19 @@ func PlayFile(m *model.Model) {
 	}
 }
 
+// SelectFile opens the folder under the cursor in the file browser, or assigns the file to
+// the phrase row the browser was opened for
 func SelectFile(m *model.Model) {
+	if fullPath := EnterFile(m); fullPath != "" {
+		SelectFilePath(m, fullPath)
+	}
+}
+
+// EnterFile opens the folder under the cursor in the file browser and returns "", or returns
+// the path of the file under the cursor
+func EnterFile(m *model.Model) string {
 	if len(m.Files) == 0 || m.CurrentRow >= len(m.Files) {
-		return
+		return ""
 	}
 
 	selected := m.Files[m.CurrentRow]
@@ -62,7 +76,7 @@ func SelectFile(m *model.Model) {
 		storage.LoadFiles(m)
 		m.CurrentRow = 0
 		m.ScrollOffset = 0
-		return
+		return ""
 	}
 
 	if strings.HasSuffix(selected, "/") {
@@ -70,11 +84,17 @@ func SelectFile(m *model.Model) {
 		storage.LoadFiles(m)
 		m.CurrentRow = 0
 		m.ScrollOffset = 0
-		return
+		return ""
 	}
 
-	// Select audio file - store the full path
-	fullPath := filepath.Join(m.CurrentDir, selected)
+	// Select audio file - return the full path
+	return filepath.Join(m.CurrentDir, selected)
+}
+
+// SelectFilePath assigns a file to the phrase row the file browser was opened for
+func SelectFilePath(m *model.Model, fullPath string) {
+	selected := filepath.Base(fullPath)
+
 	fileIndex := m.AppendPhrasesFile(fullPath)
 	phrasesData := m.GetCurrentPhrasesData()
 	(*phrasesData)[m.CurrentPhrase][m.FileSelectRow][int(types.ColFilename)] = fileIndex
@@ -87,11 +107,14 @@ func SelectFile(m *model.Model) {
 	beats, bpm, err = getbpm.GetBPM(fullPath)
 	if err == nil {
 		slices := int(2 * math.Round(beats))
+		// The frame count bounds the loop points in the metadata editor
+		_, _, frames, _ := getbpm.Length(fullPath)
 		m.FileMetadata[fullPath] = types.FileMetadata{
 			BPM:         float32(bpm),
 			Slices:      slices,
 			Playthrough: 0, // Default: Sliced
 			SyncToBPM:   1, // Default: Yes
+			Frames:      int(max(frames, 0)),
 		}
 	} else {
 		log.Printf("Could not get BPM for %s: %v", fullPath, err)
@@ -197,3 +197,9 @@ func Length(filename string) (seconds float64, sampleRate int64, totalFrames int
 	sampleRate = int64(d.SampleRate)
 	return
 }
+
+// FromName reads the beats and BPM from a file name such as "amen_beats8_bpm172.wav" without
+// guessing from the length. It returns an error when the name carries no BPM.
+func FromName(name string) (beats float64, bpm float64, err error) {
+	return parseName(name)
+}
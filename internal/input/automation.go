@@ -0,0 +1,299 @@
+package input
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/ticks"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// AutomationTickMsg fires when the automated values are due to be sent again
+type AutomationTickMsg time.Time
+
+// AutomationTick schedules the next automation update next to the row ticks of playback.
+// It returns nil when nothing is automated or a tick is already on its way.
+func AutomationTick(m *model.Model) tea.Cmd {
+	if !m.AutomationPlaying() || !m.AutomationTickAt.IsZero() {
+		return nil
+	}
+	next := time.Now().Add(types.AutomationInterval)
+	m.AutomationTickAt = next
+	return tea.Tick(types.AutomationInterval, func(time.Time) tea.Msg {
+		return AutomationTickMsg(next)
+	})
+}
+
+// HandleAutomationTick sends the automated values of the playing tracks and schedules the next update
+func HandleAutomationTick(m *model.Model, msg AutomationTickMsg) tea.Cmd {
+	if time.Time(msg).Equal(m.AutomationTickAt) {
+		m.AutomationTickAt = time.Time{}
+	}
+	if !m.IsPlaying {
+		return nil
+	}
+	m.AdvanceAutomation(time.Now())
+	return AutomationTick(m)
+}
+
+// automationOwner returns the phrase or chain of a track that lanes belong to
+func automationOwner(m *model.Model, scope types.AutomationScope, track, id int) types.AutomationOwner {
+	return types.AutomationOwner{Scope: scope, Sampler: !isInstrumentTrack(m, track), ID: id}
+}
+
+// AutomationOwnerTicks returns the length in ticks of the phrase or chain of a lane
+func AutomationOwnerTicks(m *model.Model, owner types.AutomationOwner) int {
+	phrasesData, chainsData := &m.InstrumentPhrasesData, &m.InstrumentChainsData
+	if owner.Sampler {
+		phrasesData, chainsData = &m.SamplerPhrasesData, &m.SamplerChainsData
+	}
+	if owner.Scope == types.AutomationScopeChain {
+		return ticks.CalculateChainTicks(chainsData, phrasesData, owner.ID)
+	}
+	return ticks.CalculatePhraseTicks(phrasesData, owner.ID)
+}
+
+// tickSeconds returns the length of a tick at the tempo of the project
+func tickSeconds(m *model.Model) float64 {
+	if m.BPM <= 0 || m.PPQ <= 0 {
+		return 0.25 // 120 BPM, PPQ=2
+	}
+	return 60 / float64(m.BPM) / float64(m.PPQ)
+}
+
+// startAutomationRow places the row a track starts playing within its phrase and chain, so
+// the automation of the track follows it. Chain is -1 when a phrase plays on its own.
+func startAutomationRow(m *model.Model, track, phrase, row, chain, chainRow int) {
+	if !m.IsPlaying || phrase < 0 || phrase >= 255 || row < 0 || row >= 255 {
+		return
+	}
+	phrasesData := GetPhrasesDataForTrack(m, track)
+	automationRow := model.AutomationRow{
+		Phrase:       automationOwner(m, types.AutomationScopePhrase, track, phrase),
+		Chain:        automationOwner(m, types.AutomationScopeChain, track, chain),
+		Ticks:        max(1, (*phrasesData)[phrase][row][types.ColDeltaTime]),
+		TickDuration: time.Duration(tickSeconds(m) * float64(time.Second)),
+		Start:        time.Now(),
+	}
+	for r := 0; r < row; r++ {
+		automationRow.PhraseTick += max(0, (*phrasesData)[phrase][r][types.ColDeltaTime])
+	}
+	automationRow.ChainTick = automationRow.PhraseTick
+	if chain >= 0 {
+		chainsData := GetChainsDataForTrack(m, track)
+		for r := 0; r < chainRow && r < 16; r++ {
+			automationRow.ChainTick += ticks.CalculatePhraseTicks(phrasesData, (*chainsData)[chain][r])
+		}
+	}
+	m.StartAutomationRow(track, automationRow)
+}
+
+// startSongAutomationRow follows the row a track starts playing in song playback
+func startSongAutomationRow(m *model.Model, track int) {
+	startAutomationRow(m, track, m.SongPlaybackPhrase[track], m.SongPlaybackRowInPhrase[track],
+		m.SongPlaybackChain[track], m.SongPlaybackChainRow[track])
+}
+
+// startPlaybackAutomationRow follows the row the current track starts playing in chain and
+// phrase playback
+func startPlaybackAutomationRow(m *model.Model) {
+	chain, chainRow := -1, -1
+	if m.PlaybackMode == types.ChainView {
+		chain, chainRow = m.PlaybackChain, m.PlaybackChainRow
+	}
+	startAutomationRow(m, m.CurrentTrack, m.PlaybackPhrase, m.PlaybackRow, chain, chainRow)
+}
+
+// automatedEffectiveValue returns the effective value of a column of a row, or the value a
+// lane of the playing phrase or chain gave the column when the row started
+func automatedEffectiveValue(m *model.Model, phrase, row int, col types.PhraseColumn, trackId int) int {
+	if value, ok := m.AutomatedColumn(trackId, col); ok {
+		return value
+	}
+	return GetEffectiveValueForTrack(m, phrase, row, int(col), trackId)
+}
+
+// OpenAutomation opens the automation lanes of the phrase or chain being edited
+func OpenAutomation(m *model.Model) {
+	switch m.ViewMode {
+	case types.PhraseView:
+		m.AutomationEditing = automationOwner(m, types.AutomationScopePhrase, m.CurrentTrack, m.CurrentPhrase)
+		m.LastPhraseRow = m.CurrentRow
+		m.LastPhraseCol = m.CurrentCol
+	case types.ChainView:
+		m.AutomationEditing = automationOwner(m, types.AutomationScopeChain, m.CurrentTrack, m.CurrentChain)
+		m.LastChainRow = m.CurrentRow
+	default:
+		return
+	}
+	m.AutomationLane = 0
+	row := types.AutomationRowTarget
+	if AutomationEditingLane(m) != nil {
+		row = types.AutomationRowFirstPoint
+	}
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.AutomationView,
+		Row:          row,
+		Col:          int(types.AutomationColValue),
+		ScrollOffset: 0,
+	})
+}
+
+// CloseAutomation returns to the phrase or chain whose lanes were edited
+func CloseAutomation(m *model.Model) {
+	if m.AutomationEditing.Scope == types.AutomationScopeChain {
+		switchToViewWithVisibilityCheck(m, chainViewConfig(m.LastChainRow))
+	} else {
+		switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
+	}
+}
+
+// AutomationEditingLane returns the lane being edited, nil when it has no target yet
+func AutomationEditingLane(m *model.Model) *types.AutomationLane {
+	indices := m.AutomationLaneIndices(m.AutomationEditing)
+	if m.AutomationLane < 0 || m.AutomationLane >= len(indices) {
+		return nil
+	}
+	return &m.AutomationLanes[indices[m.AutomationLane]]
+}
+
+// AutomationMaxRow returns the last row of the automation view. The row after the last
+// breakpoint adds a new one.
+func AutomationMaxRow(m *model.Model) int {
+	lane := AutomationEditingLane(m)
+	if lane == nil {
+		return types.AutomationRowTarget
+	}
+	return types.AutomationRowFirstPoint + len(lane.Points)
+}
+
+// ModifyAutomationValue changes the selected lane, target or breakpoint value. Coarse steps
+// are ±1.0 and fine steps ±0.05, as in the other settings views.
+func ModifyAutomationValue(m *model.Model, delta float32) {
+	direction := 1
+	if delta < 0 {
+		direction = -1
+	}
+	lane := AutomationEditingLane(m)
+
+	switch {
+	case m.CurrentRow == types.AutomationRowLane:
+		// The lane after the last one starts a new lane
+		last := min(len(m.AutomationLaneIndices(m.AutomationEditing)), types.AutomationLanesPerOwner-1)
+		m.AutomationLane = clampInt(m.AutomationLane+direction, 0, last)
+		return
+	case m.CurrentRow == types.AutomationRowTarget:
+		choices := []string{""}
+		for _, target := range types.AutomationTargets() {
+			choices = append(choices, target.ID())
+		}
+		current := ""
+		if lane != nil {
+			current = lane.Target
+		}
+		target := choices[stepChoice(choices, current, delta)]
+		if target == "" {
+			removeAutomationLane(m)
+		} else if lane == nil {
+			addAutomationLane(m, target)
+		} else {
+			lane.Target = target
+		}
+	case lane == nil:
+		return
+	case m.CurrentRow >= types.AutomationRowFirstPoint+len(lane.Points):
+		addAutomationPoint(m, lane)
+		m.CurrentRow = types.AutomationRowFirstPoint + len(lane.Points) - 1
+	default:
+		index := m.CurrentRow - types.AutomationRowFirstPoint
+		point := &lane.Points[index]
+		switch types.AutomationUIColumn(m.CurrentCol) {
+		case types.AutomationColTick:
+			// Breakpoints keep their order
+			low, high := 0, types.AutomationMaxTick
+			if index > 0 {
+				low = lane.Points[index-1].Tick
+			}
+			if index < len(lane.Points)-1 {
+				high = lane.Points[index+1].Tick
+			}
+			modifier := createIntModifier(
+				func() int { return point.Tick },
+				func(v int) { point.Tick = v },
+				low, high, "automation breakpoint tick",
+			)
+			modifyValueWithBounds(modifier, deltaHandler(delta, 16))
+		case types.AutomationColValue:
+			modifier := createIntModifier(
+				func() int { return point.Value },
+				func(v int) { point.Value = v },
+				0, 254, "automation breakpoint value",
+			)
+			modifyValueWithBounds(modifier, deltaHandler(delta, 16))
+		case types.AutomationColCurve:
+			count := int(types.AutomationCurveCount)
+			point.Curve = types.AutomationCurve((int(point.Curve) + direction + count) % count)
+		}
+	}
+	storage.AutoSave(m)
+}
+
+// addAutomationLane starts a lane of the phrase or chain being edited, holding the value
+// the target has when nothing moves it
+func addAutomationLane(m *model.Model, targetID string) {
+	target, _ := types.FindAutomationTarget(targetID)
+	m.AutomationLanes = append(m.AutomationLanes, types.AutomationLane{
+		Owner:  m.AutomationEditing,
+		Target: targetID,
+		Points: []types.AutomationPoint{{Tick: 0, Value: target.HexFromValue(target.Center)}},
+	})
+	m.AutomationLane = len(m.AutomationLaneIndices(m.AutomationEditing)) - 1
+	log.Printf("Added automation lane %s to %+v", targetID, m.AutomationEditing)
+}
+
+// removeAutomationLane removes the lane being edited
+func removeAutomationLane(m *model.Model) {
+	indices := m.AutomationLaneIndices(m.AutomationEditing)
+	if m.AutomationLane < 0 || m.AutomationLane >= len(indices) {
+		return
+	}
+	m.AutomationLanes = slices.Delete(m.AutomationLanes, indices[m.AutomationLane], indices[m.AutomationLane]+1)
+	log.Printf("Removed automation lane %d of %+v", m.AutomationLane, m.AutomationEditing)
+	m.CurrentRow = min(m.CurrentRow, AutomationMaxRow(m))
+}
+
+// addAutomationPoint adds a breakpoint after the last one: at the end of the phrase or
+// chain, or a tick later once the last breakpoint is there
+func addAutomationPoint(m *model.Model, lane *types.AutomationLane) {
+	point := types.AutomationPoint{Tick: AutomationOwnerTicks(m, lane.Owner)}
+	if len(lane.Points) > 0 {
+		last := lane.Points[len(lane.Points)-1]
+		point.Value = last.Value
+		point.Tick = max(point.Tick, last.Tick+1)
+	}
+	point.Tick = min(point.Tick, types.AutomationMaxTick)
+	lane.Points = append(lane.Points, point)
+}
+
+// DeleteAutomationRow removes the selected breakpoint, or the lane from its target row
+func DeleteAutomationRow(m *model.Model) {
+	lane := AutomationEditingLane(m)
+	if lane == nil {
+		return
+	}
+	index := m.CurrentRow - types.AutomationRowFirstPoint
+	if m.CurrentRow == types.AutomationRowTarget {
+		removeAutomationLane(m)
+	} else if index >= 0 && index < len(lane.Points) {
+		lane.Points = slices.Delete(lane.Points, index, index+1)
+		m.CurrentRow = min(m.CurrentRow, AutomationMaxRow(m))
+	} else {
+		return
+	}
+	storage.AutoSave(m)
+}
@@ -0,0 +1,136 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestAutomationView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 1 // Sampler
+	m.CurrentPhrase = 5
+	m.CurrentRow = 7
+	for row := 0; row < 4; row++ {
+		m.SamplerPhrasesData[5][row][types.ColDeltaTime] = 8
+	}
+
+	// "a" opens the lanes of the phrase, starting on the target of its first lane
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.Equal(t, types.AutomationView, m.ViewMode)
+	assert.Equal(t, types.AutomationOwner{Scope: types.AutomationScopePhrase, Sampler: true, ID: 5}, m.AutomationEditing)
+	assert.Equal(t, types.AutomationRowTarget, m.CurrentRow)
+	assert.Equal(t, 32, AutomationOwnerTicks(m, m.AutomationEditing))
+
+	// Choosing a target starts a lane holding the value of the target when nothing moves
+	// it, and the breakpoints stay when the target changes
+	ModifyAutomationValue(m, 1)
+	lane := AutomationEditingLane(m)
+	require.NotNil(t, lane)
+	assert.Equal(t, "pitch", lane.Target)
+	assert.Equal(t, []types.AutomationPoint{{Tick: 0, Value: 127}}, lane.Points)
+	ModifyAutomationValue(m, 1)
+	assert.Equal(t, "columnGate", lane.Target)
+	for range types.AutomationColumnTargets {
+		ModifyAutomationValue(m, 1)
+	}
+	ModifyAutomationValue(m, 1)
+	assert.Equal(t, "lowPassFilter", lane.Target)
+	assert.Len(t, lane.Points, 1)
+
+	// The row after the breakpoints adds one at the end of the phrase
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, AutomationMaxRow(m), m.CurrentRow)
+	ModifyAutomationValue(m, 1)
+	assert.Equal(t, types.AutomationPoint{Tick: 32, Value: 127}, lane.Points[1])
+	assert.Equal(t, types.AutomationRowFirstPoint+1, m.CurrentRow)
+
+	m.CurrentCol = int(types.AutomationColValue)
+	ModifyAutomationValue(m, -1)
+	ModifyAutomationValue(m, -0.05)
+	assert.Equal(t, 127-17, lane.Points[1].Value)
+	m.CurrentCol = int(types.AutomationColTick)
+	ModifyAutomationValue(m, -1)
+	ModifyAutomationValue(m, -1)
+	ModifyAutomationValue(m, -1)
+	assert.Equal(t, 0, lane.Points[1].Tick, "breakpoints keep their order")
+
+	// The first breakpoint curves into the second
+	m.CurrentRow = types.AutomationRowFirstPoint
+	m.CurrentCol = int(types.AutomationColCurve)
+	ModifyAutomationValue(m, -1)
+	assert.Equal(t, types.AutomationCurveLogarithmic, lane.Points[0].Curve)
+
+	// A second lane, then removing breakpoints and the first lane
+	m.CurrentRow = types.AutomationRowLane
+	ModifyAutomationValue(m, 1)
+	ModifyAutomationValue(m, 1)
+	assert.Equal(t, 1, m.AutomationLane)
+	assert.Nil(t, AutomationEditingLane(m))
+	m.CurrentRow = types.AutomationRowTarget
+	ModifyAutomationValue(m, 1)
+	assert.Equal(t, "pitch", AutomationEditingLane(m).Target)
+	assert.Len(t, m.AutomationLaneIndices(m.AutomationEditing), 2)
+
+	m.AutomationLane = 0
+	m.CurrentRow = types.AutomationRowFirstPoint + 1
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Len(t, AutomationEditingLane(m).Points, 1)
+	m.CurrentRow = types.AutomationRowTarget
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "pitch", AutomationEditingLane(m).Target)
+	assert.Len(t, m.AutomationLanes, 1)
+
+	// Shift+Left goes back to the phrase row it was opened from
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+	assert.Equal(t, 7, m.CurrentRow)
+}
+
+func TestStartAutomationRow(t *testing.T) {
+	m := createTestModel()
+	m.TrackTypes[0] = false // Instrument
+	m.BPM = 120
+	m.PPQ = 2
+	for row := 0; row < 4; row++ {
+		m.InstrumentPhrasesData[2][row][types.ColDeltaTime] = 2
+		m.InstrumentPhrasesData[3][row][types.ColDeltaTime] = 4
+	}
+	m.InstrumentChainsData[6][0] = 2
+	m.InstrumentChainsData[6][1] = 3
+	chain := types.AutomationOwner{Scope: types.AutomationScopeChain, ID: 6}
+	m.AutomationLanes = []types.AutomationLane{
+		{Owner: chain, Target: "effectComb", Points: []types.AutomationPoint{{Tick: 0, Value: 0}, {Tick: 24, Value: 240}}},
+		{Owner: chain, Target: "columnGate", Points: []types.AutomationPoint{{Tick: 0, Value: 0x10}, {Tick: 24, Value: 0x40}}},
+	}
+	m.InstrumentPhrasesData[3][2][types.ColGate] = 0xA0
+	m.IsPlaying = true
+
+	// Row 2 of the second phrase sits 8+8 ticks into the chain: 16/24 of the way
+	m.SongPlaybackPhrase[0] = 3
+	m.SongPlaybackRowInPhrase[0] = 2
+	m.SongPlaybackChain[0] = 6
+	m.SongPlaybackChainRow[0] = 1
+	startSongAutomationRow(m, 0)
+	value, ok := m.AutomatedValue(0, "effectComb")
+	assert.True(t, ok)
+	assert.InDelta(t, 160.0/254, value, 1e-3)
+	assert.True(t, m.AutomationPlaying())
+	assert.NotNil(t, AutomationTick(m))
+	assert.Nil(t, AutomationTick(m), "one automation tick at a time")
+
+	// The row plays the gate of the lane instead of its own
+	assert.Equal(t, 0x30, automatedEffectiveValue(m, 3, 2, types.ColGate, 0))
+
+	stopPlayback(m)
+	assert.False(t, m.AutomationPlaying())
+	_, ok = m.AutomatedValue(0, "effectComb")
+	assert.False(t, ok)
+	assert.Equal(t, 0xA0, automatedEffectiveValue(m, 3, 2, types.ColGate, 0))
+}
@@ -0,0 +1,284 @@
+package input
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/schollz/collidertracker/internal/getbpm"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// bounceRows returns the tick each row of one pass over the given phrases starts on and how
+// many ticks the pass takes. It mirrors AdvancePlayback: rows with DT > 0 are played for DT
+// ticks and a phrase without playable rows still holds its first row for one tick.
+func bounceRows(m *model.Model, phrases []int, track int) (rowTicks []int, ticks int) {
+	phrasesData := GetPhrasesDataForTrack(m, track)
+	for _, phrase := range phrases {
+		if phrase < 0 || phrase >= 255 {
+			continue
+		}
+		phraseRows := 0
+		for row := 0; row < 255; row++ {
+			dtValue := (*phrasesData)[phrase][row][types.ColDeltaTime]
+			if IsRowPlayable(dtValue) {
+				phraseRows++
+				rowTicks = append(rowTicks, ticks)
+				ticks += dtValue
+			}
+		}
+		if phraseRows == 0 {
+			rowTicks = append(rowTicks, ticks)
+			ticks++
+		}
+	}
+	return rowTicks, ticks
+}
+
+// bouncePhrases returns the phrases played by one pass of a chain, in chain order
+func bouncePhrases(m *model.Model, chain, track int) []int {
+	var phrases []int
+	chainsData := GetChainsDataForTrack(m, track)
+	for row := 0; row < 16; row++ {
+		if phraseID := (*chainsData)[chain][row]; phraseID >= 0 && phraseID < 255 {
+			phrases = append(phrases, phraseID)
+		}
+	}
+	return phrases
+}
+
+// BounceTailBeats is how long a bounce keeps recording after its pass, so releases and
+// effects ring out into the file
+const BounceTailBeats = 4
+
+// BounceWriteTimeout is how long a bounce waits for SuperCollider to report the file written
+const BounceWriteTimeout = 10 * time.Second
+
+// BounceTailMsg ends the tail of a bounce
+type BounceTailMsg struct {
+	File string
+}
+
+// BounceWrittenMsg reports that SuperCollider has finished writing a recording
+type BounceWrittenMsg struct {
+	File string // Absolute path of the recording
+}
+
+// BounceWriteTimeoutMsg gives up on a bounce SuperCollider did not report written
+type BounceWriteTimeoutMsg struct {
+	File string
+}
+
+// StartBounce plays the current phrase (Phrase view) or chain (Chain view) of the current track
+// once from the top and records it into a new file in the save folder
+func StartBounce(m *model.Model) tea.Cmd {
+	if m.BounceActive {
+		// Pressing bounce again cancels it
+		log.Printf("Bounce cancelled: %s", m.BounceFile)
+		stopPlayback(m)
+		return nil
+	}
+	if m.BounceWriting {
+		log.Printf("Cannot bounce while %s is being written", m.BounceFile)
+		return nil
+	}
+	if m.IsPlaying {
+		log.Printf("Cannot bounce while playing")
+		return nil
+	}
+
+	var config PlaybackConfig
+	var phrases []int
+	var filename string
+	switch m.ViewMode {
+	case types.PhraseView:
+		config = PlaybackConfig{Mode: types.PhraseView, Chain: -1, Phrase: m.CurrentPhrase, Row: -1}
+		phrases = []int{m.CurrentPhrase}
+		filename = m.GenerateBounceFilename("phrase", m.CurrentPhrase)
+	case types.ChainView:
+		config = PlaybackConfig{Mode: types.ChainView, Chain: m.CurrentChain, Row: -1}
+		phrases = bouncePhrases(m, m.CurrentChain, m.CurrentTrack)
+		filename = m.GenerateBounceFilename("chain", m.CurrentChain)
+	default:
+		return nil
+	}
+
+	rowTicks, ticks := bounceRows(m, phrases, m.CurrentTrack)
+	if len(phrases) == 0 || len(rowTicks) == 0 {
+		log.Printf("Nothing to bounce")
+		return nil
+	}
+
+	// Stop a queued or running recording so the bounce owns /record
+	if m.RecordingActive {
+		stopRecording(m)
+	}
+
+	// Start recording before the first row is emitted so its attack is captured.
+	// Only the master mix is written; during Phrase/Chain playback it holds just this track.
+	m.BounceActive = true
+	m.BounceFile = filename
+	m.BounceRowsLeft = len(rowTicks)
+	m.BounceRowTicks = rowTicks
+	m.BounceTicks = ticks
+	m.RecordingActive = true
+	m.CurrentRecordingFile = filename
+	m.SendOSCRecordMessage(filename, true, 0)
+	log.Printf("Bounce started: %s (%d rows, %d ticks)", filename, len(rowTicks), ticks)
+
+	return startPlaybackWithConfig(m, config)
+}
+
+// advanceBounce counts down the rows of a running bounce and ends its pass after the last row.
+// It returns true when playback was stopped.
+func advanceBounce(m *model.Model) bool {
+	if !m.BounceActive {
+		return false
+	}
+	m.BounceRowsLeft--
+	if m.BounceRowsLeft > 0 {
+		return false
+	}
+	endBouncePass(m)
+	return true
+}
+
+// endBouncePass stops playback after the last row of a bounce. The recording keeps running
+// through the tail; BounceTail schedules its end.
+func endBouncePass(m *model.Model) {
+	filename, rowTicks, ticks := m.BounceFile, m.BounceRowTicks, m.BounceTicks
+	m.RecordingActive = false // Playback stops, the recording does not
+	stopPlayback(m)
+	m.BounceWriting = true
+	m.BounceFile, m.BounceRowTicks, m.BounceTicks = filename, rowTicks, ticks
+	log.Printf("Bounce pass finished, recording %d beats of tail: %s", BounceTailBeats, filename)
+}
+
+// bounceTailSeconds returns how long the tail of a bounce lasts at the tempo of the project
+func bounceTailSeconds(m *model.Model) float64 {
+	return tickSeconds(m) * float64(max(m.PPQ, 1)) * BounceTailBeats
+}
+
+// BounceTail schedules the end of the tail of a bounce whose pass just finished. It returns
+// nil when no bounce is ringing out.
+func BounceTail(m *model.Model) tea.Cmd {
+	if !m.BounceWriting {
+		return nil
+	}
+	filename := m.BounceFile
+	tail := time.Duration(bounceTailSeconds(m) * float64(time.Second))
+	return tea.Tick(tail, func(time.Time) tea.Msg {
+		return BounceTailMsg{File: filename}
+	})
+}
+
+// HandleBounceTail stops the recording of a bounce at the end of its tail. The file is added
+// once SuperCollider reports it written.
+func HandleBounceTail(m *model.Model, msg BounceTailMsg) tea.Cmd {
+	if !m.BounceWriting || msg.File != m.BounceFile {
+		return nil
+	}
+	m.SendOSCRecordMessage(m.BounceFile, false, 0)
+	m.CurrentRecordingFile = ""
+	log.Printf("Bounce tail finished, waiting for %s to be written", m.BounceFile)
+	return tea.Tick(BounceWriteTimeout, func(time.Time) tea.Msg {
+		return BounceWriteTimeoutMsg(msg)
+	})
+}
+
+// HandleBounceWritten adds a bounce to the sampler files once its recording is written.
+// Other recordings are ignored.
+func HandleBounceWritten(m *model.Model, msg BounceWrittenMsg) {
+	if !m.BounceWriting {
+		return
+	}
+	if standardizePath(msg.File) != standardizePath(m.BounceFile) {
+		return
+	}
+	finishBounce(m)
+}
+
+// standardizePath returns the absolute, cleaned path of a file with home directories expanded
+// and symlinks resolved, so paths written by the tracker and reported by SuperCollider
+// (String.standardizePath) compare equal
+func standardizePath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if absolutePath, err := filepath.Abs(path); err == nil {
+		path = absolutePath
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
+
+// HandleBounceWriteTimeout gives up on a bounce SuperCollider did not report written. The
+// file is not added to the sampler files.
+func HandleBounceWriteTimeout(m *model.Model, msg BounceWriteTimeoutMsg) {
+	if !m.BounceWriting || msg.File != m.BounceFile {
+		return
+	}
+	log.Printf("Bounce not reported written after %s, not adding it: %s", BounceWriteTimeout, m.BounceFile)
+	clearBounce(m)
+}
+
+// finishBounce adds the bounced file to the sampler files, with one slice per row of the pass
+// at the project tempo. The tail rings out in the last slice.
+func finishBounce(m *model.Model) {
+	filename := m.BounceFile
+	rowTicks := m.BounceRowTicks
+	seconds := tickSeconds(m)
+	length := float64(m.BounceTicks)*seconds + bounceTailSeconds(m)
+	if fileSeconds, _, _, err := getbpm.Length(filename); err == nil && fileSeconds > 0 {
+		length = fileSeconds
+	} else {
+		log.Printf("Could not read the length of %s, placing the slices by tempo: %v", filename, err)
+	}
+	var markers []float32
+	for _, tick := range rowTicks[1:] {
+		markers = append(markers, float32(min(float64(tick)*seconds/length, 1)))
+	}
+	clearBounce(m)
+
+	m.FileMetadata[filename] = types.FileMetadata{
+		BPM:          m.BPM,
+		Slices:       len(rowTicks),
+		Playthrough:  0, // Sliced
+		SyncToBPM:    1,
+		SliceMarkers: markers,
+	}
+	if err := storage.SaveMetadataForFile(filename, m.FileMetadata); err != nil {
+		log.Printf("Error saving metadata for %s: %v", filename, err)
+	}
+	fileIndex := m.AppendSamplerFile(filename)
+	log.Printf("Bounce finished: %s (file %02X, %d slices at %.2f BPM)", filename, fileIndex, len(rowTicks), m.BPM)
+	storage.AutoSave(m)
+}
+
+// cancelBounce cancels a bounce that is playing its pass, e.g. when playback stops. The file
+// is not added to the sampler files. A bounce ringing out finishes on its own.
+func cancelBounce(m *model.Model) {
+	if m.BounceWriting {
+		return
+	}
+	clearBounce(m)
+}
+
+// clearBounce clears the bounce state
+func clearBounce(m *model.Model) {
+	m.BounceActive = false
+	m.BounceWriting = false
+	m.BounceFile = ""
+	m.BounceRowsLeft = 0
+	m.BounceRowTicks = nil
+	m.BounceTicks = 0
+}
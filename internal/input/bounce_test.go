@@ -0,0 +1,166 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestBounceRows(t *testing.T) {
+	m := createTestModel()
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = true
+
+	m.SamplerPhrasesData[1][0][types.ColDeltaTime] = 1
+	m.SamplerPhrasesData[1][2][types.ColDeltaTime] = 2
+	m.SamplerPhrasesData[1][3][types.ColDeltaTime] = 0 // skipped
+	m.SamplerPhrasesData[2][5][types.ColDeltaTime] = 4
+
+	rowTicks, ticks := bounceRows(m, []int{1}, 0)
+	assert.Equal(t, []int{0, 1}, rowTicks)
+	assert.Equal(t, 3, ticks)
+
+	rowTicks, ticks = bounceRows(m, []int{1, 2}, 0)
+	assert.Equal(t, []int{0, 1, 3}, rowTicks)
+	assert.Equal(t, 7, ticks)
+
+	// A phrase without playable rows still holds its first row for one tick
+	rowTicks, ticks = bounceRows(m, []int{3}, 0)
+	assert.Equal(t, []int{0}, rowTicks)
+	assert.Equal(t, 1, ticks)
+
+	m.SamplerChainsData[4][0] = 1
+	m.SamplerChainsData[4][3] = 2
+	assert.Equal(t, []int{1, 2}, bouncePhrases(m, 4, 0))
+}
+
+func TestBouncePhrase(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = true
+	m.BPM = 140
+	m.PPQ = 2
+	m.ViewMode = types.PhraseView
+	m.CurrentPhrase = 1
+	for row := 0; row < 4; row++ {
+		m.SamplerPhrasesData[1][row][types.ColDeltaTime] = 1
+	}
+	m.SamplerPhrasesData[1][3][types.ColDeltaTime] = 4
+	filesBefore := len(m.SamplerPhrasesFiles)
+
+	StartBounce(m)
+	require.True(t, m.BounceActive)
+	assert.True(t, m.IsPlaying)
+	assert.True(t, m.RecordingActive)
+	assert.Equal(t, m.SaveFolder, filepath.Dir(m.BounceFile))
+	filename := m.BounceFile
+
+	// The bounce plays each row once and stops instead of looping
+	for i := 0; i < 3; i++ {
+		AdvancePlayback(m)
+		assert.True(t, m.IsPlaying)
+	}
+	AdvancePlayback(m)
+	assert.False(t, m.IsPlaying)
+	assert.False(t, m.BounceActive)
+
+	// The recording rings out for the tail and the file waits until it is written
+	assert.True(t, m.BounceWriting)
+	assert.NotNil(t, BounceTail(m))
+	assert.Nil(t, StartBounce(m))
+	assert.Equal(t, filename, m.BounceFile, "no new bounce while one is being written")
+	stopPlayback(m)
+	assert.True(t, m.BounceWriting, "stopping playback leaves the tail alone")
+	assert.NotNil(t, HandleBounceTail(m, BounceTailMsg{File: filename}))
+	assert.Len(t, m.SamplerPhrasesFiles, filesBefore)
+
+	absolutePath, err := filepath.Abs(filename)
+	require.NoError(t, err)
+	HandleBounceWritten(m, BounceWrittenMsg{File: absolutePath + ".other"})
+	assert.True(t, m.BounceWriting)
+	HandleBounceWritten(m, BounceWrittenMsg{File: absolutePath})
+	assert.False(t, m.BounceWriting)
+
+	// One slice per row, placed by tempo when the file cannot be read, with the tail of
+	// 4 beats (8 ticks) in the last slice
+	require.Len(t, m.SamplerPhrasesFiles, filesBefore+1)
+	assert.Equal(t, filename, m.SamplerPhrasesFiles[filesBefore])
+	metadata := m.FileMetadata[filename]
+	assert.Equal(t, float32(140), metadata.BPM)
+	assert.Equal(t, 4, metadata.Slices)
+	assert.Equal(t, 1, metadata.SyncToBPM)
+	require.Len(t, metadata.SliceMarkers, 3)
+	assert.InDeltaSlice(t, []float32{1.0 / 15, 2.0 / 15, 3.0 / 15}, metadata.SliceMarkers, 1e-6)
+}
+
+func TestBounceWriteTimeout(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = true
+	m.ViewMode = types.PhraseView
+	m.CurrentPhrase = 1
+	m.SamplerPhrasesData[1][0][types.ColDeltaTime] = 1
+	filesBefore := len(m.SamplerPhrasesFiles)
+
+	StartBounce(m)
+	filename := m.BounceFile
+	AdvancePlayback(m)
+	require.True(t, m.BounceWriting)
+
+	// A bounce SuperCollider never reports written is dropped
+	HandleBounceWriteTimeout(m, BounceWriteTimeoutMsg{File: filename})
+	assert.False(t, m.BounceWriting)
+	assert.Len(t, m.SamplerPhrasesFiles, filesBefore)
+	assert.NotNil(t, StartBounce(m))
+}
+
+func TestBounceCancel(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = true
+	m.ViewMode = types.ChainView
+	m.CurrentChain = 2
+	m.SamplerChainsData[2][0] = 1
+	m.SamplerPhrasesData[1][0][types.ColDeltaTime] = 1
+	filesBefore := len(m.SamplerPhrasesFiles)
+
+	StartBounce(m)
+	require.True(t, m.BounceActive)
+
+	// Pressing bounce again stops playback without keeping the file
+	StartBounce(m)
+	assert.False(t, m.IsPlaying)
+	assert.False(t, m.BounceActive)
+	assert.Len(t, m.SamplerPhrasesFiles, filesBefore)
+
+	// Nothing to bounce in an empty chain
+	m.CurrentChain = 3
+	StartBounce(m)
+	assert.False(t, m.BounceActive)
+	assert.False(t, m.IsPlaying)
+}
+
+func TestStandardizePath(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "bounce.wav")
+	require.NoError(t, os.WriteFile(filename, nil, 0644))
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(dir, link))
+
+	// Paths SuperCollider reports through a symlink or with extra elements match the bounce
+	assert.Equal(t, standardizePath(filename), standardizePath(filepath.Join(link, "bounce.wav")))
+	assert.Equal(t, standardizePath(filename), standardizePath(dir+"/./sub/../bounce.wav"))
+	assert.NotEqual(t, standardizePath(filename), standardizePath(filename+".other"))
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Equal(t, standardizePath(filepath.Join(home, "x.wav")), standardizePath("~/x.wav"))
+}
@@ -80,20 +80,10 @@ func CopyCellToClipboard(m *model.Model) {
 		}
 	} else if m.ViewMode == types.ArpeggioView {
 		// Copy from arpeggio view
-		settings := m.ArpeggioSettings[m.ArpeggioEditingIndex]
-		currentRow := &settings.Rows[m.CurrentRow]
-
-		var value int
-		switch m.CurrentCol {
-		case int(types.ArpeggioColDI): // Direction column
-			value = currentRow.Direction
-		case int(types.ArpeggioColCO): // Count column
-			value = currentRow.Count
-		case int(types.ArpeggioColDIV): // Divisor column
-			value = currentRow.Divisor
-		default:
-			return // Invalid column
+		if m.CurrentRow < 0 || m.CurrentRow >= 16 || m.CurrentCol < 0 || m.CurrentCol >= int(types.ArpeggioColCount) {
+			return // Latch row or invalid column
 		}
+		value := m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow].Value(types.ArpeggioUIColumn(m.CurrentCol))
 
 		clipboard := types.ClipboardData{
 			Value:           value,
@@ -137,6 +127,21 @@ func CopyCellToClipboard(m *model.Model) {
 		}
 		m.Clipboard = clipboard
 		log.Printf("Copied timestrech index: %02X", value)
+	} else if m.ViewMode == types.GranularView {
+		// Copy granular index from granular view
+		value := m.GranularEditingIndex
+		clipboard := types.ClipboardData{
+			Value:           value,
+			CellType:        types.HexCell,
+			Mode:            types.CellMode,
+			HasData:         true,
+			HighlightRow:    m.CurrentRow,
+			HighlightCol:    m.CurrentCol,
+			HighlightPhrase: -1, // Not applicable for granular view
+			HighlightView:   types.GranularView,
+		}
+		m.Clipboard = clipboard
+		log.Printf("Copied granular index: %02X", value)
 	}
 }
 
@@ -180,16 +185,24 @@ func CutRowToClipboard(m *model.Model) {
 			filename = (*phrasesFiles)[fileIndex]
 		}
 
+		// Parameter locks go with the row
+		var locks map[string]float32
+		if m.GetPhraseViewType() == types.InstrumentPhraseView {
+			locks = m.ParameterLocks.CopyRow(m.CurrentPhrase, m.CurrentRow)
+			m.ParameterLocks.ClearRow(m.CurrentPhrase, m.CurrentRow)
+		}
+
 		clipboard := types.ClipboardData{
-			RowData:         rowData,
-			RowFilename:     filename,
-			SourceView:      types.PhraseView,
-			Mode:            types.RowMode,
-			HasData:         true,
-			HighlightRow:    m.CurrentRow,
-			HighlightCol:    -1, // Highlight entire row
-			HighlightPhrase: m.CurrentPhrase,
-			HighlightView:   types.PhraseView,
+			RowData:           rowData,
+			RowFilename:       filename,
+			RowParameterLocks: locks,
+			SourceView:        types.PhraseView,
+			Mode:              types.RowMode,
+			HasData:           true,
+			HighlightRow:      m.CurrentRow,
+			HighlightCol:      -1, // Highlight entire row
+			HighlightPhrase:   m.CurrentPhrase,
+			HighlightView:     types.PhraseView,
 		}
 		m.Clipboard = clipboard
 		// Clear the row - reset all columns to their default values
@@ -200,7 +213,12 @@ func CutRowToClipboard(m *model.Model) {
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColGate)] = -1                               // Clear gate (displays "--", behaves as 80)
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColRetrigger)] = -1                          // Clear retrigger
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColTimestretch)] = -1                        // Clear timestretch
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColGranular)] = -1                           // Clear granular
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColSampleStart)] = -1                        // Clear start offset
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColSampleLength)] = -1                       // Clear length
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColEffectDucking)] = -1                      // Clear ducking
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColInsertFX)] = -1                           // Clear insert FX
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColInsertFXValue)] = -1                      // Clear insert FX value
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColModulate)] = -1                           // Clear modulation
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColEffectReverse)] = -1                      // Clear effect reverse
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColPan)] = -1                                // Clear pan
@@ -220,6 +238,7 @@ func CutRowToClipboard(m *model.Model) {
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColMidi)] = -1                                      // Clear MIDI
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColSoundMaker)] = -1                                // Clear SoundMaker
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColChordTransposition)] = int(types.ChordTransNone) // Clear chord transposition
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColChordVoicing)] = int(types.ChordVoicingClose)    // Clear chord voicing
 		log.Printf("Cut phrase row %d", m.CurrentRow)
 	} else if m.ViewMode == types.ArpeggioView {
 		// Cut row from arpeggio view
@@ -230,12 +249,12 @@ func CutRowToClipboard(m *model.Model) {
 			return
 		}
 
-		// Copy current arpeggio row data (3 columns: Direction, Count, Divisor)
+		// Copy current arpeggio row data (one value per column)
 		currentRow := m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow]
-		arpeggioRowData := make([]int, 3)
-		arpeggioRowData[0] = currentRow.Direction
-		arpeggioRowData[1] = currentRow.Count
-		arpeggioRowData[2] = currentRow.Divisor
+		arpeggioRowData := make([]int, types.ArpeggioColCount)
+		for col := range arpeggioRowData {
+			arpeggioRowData[col] = currentRow.Value(types.ArpeggioUIColumn(col))
+		}
 
 		clipboard := types.ClipboardData{
 			RowData:         arpeggioRowData,
@@ -250,10 +269,7 @@ func CutRowToClipboard(m *model.Model) {
 		m.Clipboard = clipboard
 
 		// Clear the row - reset to defaults
-		currentRowRef := &m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow]
-		currentRowRef.Direction = 0 // Clear to "--"
-		currentRowRef.Count = -1    // Clear to "--"
-		currentRowRef.Divisor = -1  // Clear to "--"
+		m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow] = types.NewArpeggioRow()
 
 		log.Printf("Cut arpeggio %02X row %02X", m.ArpeggioEditingIndex, m.CurrentRow)
 	}
@@ -363,6 +379,28 @@ func PasteCellFromClipboard(m *model.Model) {
 						(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = m.Clipboard.Value
 						log.Printf("Pasted timestrech reference %02X to phrase cell", m.Clipboard.Value)
 					}
+				} else if colIndex == int(types.ColGranular) && m.Clipboard.Value >= 0 && m.Clipboard.Value < 255 {
+					// Special handling for granular column - implement deep copying
+					// Check if this is marked for deep copy on paste (Ctrl+D)
+					if m.Clipboard.IsFreshDeepCopy {
+						// Create the deep copy now (on paste)
+						newGranularIndex := FindNextUnusedGranular(m, m.Clipboard.Value)
+						if newGranularIndex != -1 {
+							// Deep copy the granular settings
+							m.GranularSettings[newGranularIndex] = m.GranularSettings[m.Clipboard.Value]
+							// Update the phrase data with the new granular index
+							(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = newGranularIndex
+							log.Printf("Deep copied granular settings %02X to %02X and pasted to phrase cell", m.Clipboard.Value, newGranularIndex)
+						} else {
+							// No unused granular slots available, just copy the reference
+							(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = m.Clipboard.Value
+							log.Printf("Warning: No unused granular slots available, pasted reference to granular %02X", m.Clipboard.Value)
+						}
+					} else {
+						// Regular copy (Ctrl+C) - just paste the reference
+						(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = m.Clipboard.Value
+						log.Printf("Pasted granular reference %02X to phrase cell", m.Clipboard.Value)
+					}
 				} else if colIndex == int(types.ColArpeggio) && m.Clipboard.Value >= 0 && m.Clipboard.Value < 255 {
 					// Special handling for arpeggio column - implement deep copying
 					// Check if this is marked for deep copy on paste (Ctrl+D)
@@ -399,22 +437,12 @@ func PasteCellFromClipboard(m *model.Model) {
 	} else if m.ViewMode == types.ArpeggioView {
 		// Paste to arpeggio view - only paste within same column
 		if m.Clipboard.CellType == types.HexCell && m.Clipboard.HighlightView == types.ArpeggioView && m.Clipboard.HighlightCol == m.CurrentCol {
-			settings := &m.ArpeggioSettings[m.ArpeggioEditingIndex]
-			currentRow := &settings.Rows[m.CurrentRow]
-
-			switch m.CurrentCol {
-			case int(types.ArpeggioColDI): // Direction column
-				currentRow.Direction = m.Clipboard.Value
-				log.Printf("Pasted to arpeggio %02X row %02X Direction: %d", m.ArpeggioEditingIndex, m.CurrentRow, m.Clipboard.Value)
-			case int(types.ArpeggioColCO): // Count column
-				currentRow.Count = m.Clipboard.Value
-				log.Printf("Pasted to arpeggio %02X row %02X Count: %d", m.ArpeggioEditingIndex, m.CurrentRow, m.Clipboard.Value)
-			case int(types.ArpeggioColDIV): // Divisor column
-				currentRow.Divisor = m.Clipboard.Value
-				log.Printf("Pasted to arpeggio %02X row %02X Divisor: %d", m.ArpeggioEditingIndex, m.CurrentRow, m.Clipboard.Value)
-			default:
-				log.Printf("Cannot paste: invalid arpeggio column %d", m.CurrentCol)
+			if m.CurrentRow < 0 || m.CurrentRow >= 16 || m.CurrentCol < 0 || m.CurrentCol >= int(types.ArpeggioColCount) {
+				log.Printf("Cannot paste: invalid arpeggio row %d col %d", m.CurrentRow, m.CurrentCol)
+				return
 			}
+			m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow].SetValue(types.ArpeggioUIColumn(m.CurrentCol), m.Clipboard.Value)
+			log.Printf("Pasted to arpeggio %02X row %02X col %d: %d", m.ArpeggioEditingIndex, m.CurrentRow, m.CurrentCol, m.Clipboard.Value)
 		} else {
 			log.Printf("Cannot paste: incompatible cell type or different column (source col: %d, target col: %d)", m.Clipboard.HighlightCol, m.CurrentCol)
 		}
@@ -452,6 +480,23 @@ func PasteCellFromClipboard(m *model.Model) {
 		} else {
 			log.Printf("Cannot paste: incompatible cell type for timestrech view")
 		}
+	} else if m.ViewMode == types.GranularView {
+		// Paste to granular view - find next empty slot in granular pool
+		if m.Clipboard.CellType == types.HexCell && m.Clipboard.Value >= 0 && m.Clipboard.Value < 255 {
+			// Find next unused granular slot
+			nextSlot := FindNextUnusedGranular(m, m.Clipboard.Value)
+			if nextSlot != -1 {
+				// Deep copy the granular settings to the next empty slot
+				sourceSettings := m.GranularSettings[m.Clipboard.Value]
+				m.GranularSettings[nextSlot] = sourceSettings
+				log.Printf("Deep copied granular settings %02X to next empty slot %02X (Size: %.0fms, Density: %.0f/s, Position: %.2f)",
+					m.Clipboard.Value, nextSlot, sourceSettings.Size, sourceSettings.Density, sourceSettings.Position)
+			} else {
+				log.Printf("Cannot paste: no empty granular slots available")
+			}
+		} else {
+			log.Printf("Cannot paste: incompatible cell type for granular view")
+		}
 	}
 }
 
@@ -482,16 +527,21 @@ func PasteRowFromClipboard(m *model.Model) {
 			(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColFilename)] = fileIndex
 		}
 
+		// The row takes the parameter locks of the copied row, replacing its own
+		if m.GetPhraseViewType() == types.InstrumentPhraseView {
+			m.ParameterLocks.SetRow(m.CurrentPhrase, m.CurrentRow, m.Clipboard.RowParameterLocks)
+		}
+
 		log.Printf("Pasted phrase row to row %d", m.CurrentRow)
 		// Track this row as the last edited row
 		m.LastEditRow = m.CurrentRow
 	} else if m.ViewMode == types.ArpeggioView && m.Clipboard.SourceView == types.ArpeggioView {
 		// Paste arpeggio row to arpeggio row
 		settings := &m.ArpeggioSettings[m.ArpeggioEditingIndex]
-		if len(m.Clipboard.RowData) == 3 {
-			settings.Rows[m.CurrentRow].Direction = m.Clipboard.RowData[0]
-			settings.Rows[m.CurrentRow].Count = m.Clipboard.RowData[1]
-			settings.Rows[m.CurrentRow].Divisor = m.Clipboard.RowData[2]
+		if len(m.Clipboard.RowData) == int(types.ArpeggioColCount) && m.CurrentRow >= 0 && m.CurrentRow < 16 {
+			for col, value := range m.Clipboard.RowData {
+				settings.Rows[m.CurrentRow].SetValue(types.ArpeggioUIColumn(col), value)
+			}
 			log.Printf("Pasted arpeggio row to row %d", m.CurrentRow)
 		} else {
 			log.Printf("Cannot paste: invalid arpeggio clipboard data")
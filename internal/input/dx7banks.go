@@ -0,0 +1,62 @@
+package input
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/supercollider"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// BrowseDX7Bank opens the file browser to pick a SysEx bank for the DX7 being edited
+func BrowseDX7Bank(m *model.Model) {
+	if m.SoundMakerEditingIndex < 0 || m.SoundMakerEditingIndex >= 255 {
+		return
+	}
+	if m.SoundMakerSettings[m.SoundMakerEditingIndex].Name != "DX7" {
+		return
+	}
+	m.FileSelectRow = m.CurrentRow
+	m.FileSelectCol = 0
+	m.FileSelectView = types.SoundMakerView
+	m.DX7BankMessage = ""
+
+	m.ViewMode = types.FileView
+	m.CurrentRow = 0
+	m.CurrentCol = 0
+	m.ScrollOffset = 0
+	storage.LoadFiles(m)
+	log.Printf("Browsing DX7 banks for SoundMaker %02X", m.SoundMakerEditingIndex)
+}
+
+// ImportDX7BankFile imports the bank picked in the file browser, dials in its first voice
+// and goes back to the SoundMaker view
+func ImportDX7BankFile(m *model.Model, fullPath string) {
+	settings := &m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	if preset, err := supercollider.ImportDX7Bank(m.DX7BankDir, fullPath); err != nil {
+		log.Printf("Importing DX7 bank %s failed: %v", fullPath, err)
+		m.DX7BankMessage = fmt.Sprintf("Import failed: %v", err)
+	} else if err := supercollider.SetDX7PatchByIndex(settings, preset); err != nil {
+		log.Printf("Imported DX7 bank %s but could not select preset %d: %v", fullPath, preset, err)
+		m.DX7BankMessage = fmt.Sprintf("Imported %s but could not select preset %d: %v", filepath.Base(fullPath), preset, err)
+	} else {
+		log.Printf("Imported DX7 bank %s for SoundMaker %02X from preset %d", fullPath, m.SoundMakerEditingIndex, preset)
+		m.DX7BankMessage = fmt.Sprintf("Imported %s from preset %d", filepath.Base(fullPath), preset)
+	}
+	CloseDX7BankBrowser(m)
+}
+
+// CloseDX7BankBrowser returns from the file browser to the SoundMaker view
+func CloseDX7BankBrowser(m *model.Model) {
+	m.FileSelectView = types.PhraseView
+	storage.LoadFiles(m)
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.SoundMakerView,
+		Row:          m.FileSelectRow,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
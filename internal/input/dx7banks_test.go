@@ -0,0 +1,69 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/supercollider"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestDX7BankImport(t *testing.T) {
+	noBanks := t.TempDir()
+	t.Cleanup(func() { supercollider.LoadDX7Banks(noBanks) })
+
+	// An initialized bank: 32 silent voices without names, checksum 0
+	source := t.TempDir()
+	bank := append([]byte{0xF0, 0x43, 0x00, 0x09, 0x20, 0x00}, make([]byte, 32*128)...)
+	bank = append(bank, 0x00, 0xF7)
+	require.NoError(t, os.WriteFile(filepath.Join(source, "init.syx"), bank, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "loop.wav"), []byte{}, 0644))
+
+	m := createTestModel()
+	m.DX7BankDir = filepath.Join(t.TempDir(), "dx7")
+	m.ViewMode = types.SoundMakerView
+	m.SoundMakerEditingIndex = 2
+	m.CurrentDir = source
+
+	// Only DX7 SoundMakers import banks
+	m.SoundMakerSettings[2].Name = "PolyPerc"
+	handleShiftRight(m)
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+
+	m.SoundMakerSettings[2].Name = "DX7"
+	m.CurrentRow = 1
+	handleShiftRight(m)
+	assert.Equal(t, types.FileView, m.ViewMode)
+	assert.Equal(t, types.SoundMakerView, m.FileSelectView)
+	assert.Equal(t, []string{"..", "init.syx"}, m.Files, "only banks are listed")
+
+	m.CurrentRow = 1
+	handleSpace(m)
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+	assert.Equal(t, types.PhraseView, m.FileSelectView)
+	assert.Equal(t, 1, m.CurrentRow)
+	preset := supercollider.GetDX7PatchCount() - 32
+	assert.Equal(t, float32(preset), m.SoundMakerSettings[2].GetParameterValue("preset"))
+	assert.Equal(t, "Voice 1", m.SoundMakerSettings[2].PatchName)
+	assert.Contains(t, m.DX7BankMessage, "Imported init.syx")
+	banks, _ := filepath.Glob(filepath.Join(m.DX7BankDir, "*.syx"))
+	assert.Len(t, banks, 1)
+
+	// Backing out of the browser leaves the SoundMaker alone
+	handleShiftRight(m)
+	handleShiftLeft(m)
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+	assert.Equal(t, float32(preset), m.SoundMakerSettings[2].GetParameterValue("preset"))
+
+	// A voice that cannot be dialed in is reported
+	m.SoundMakerSettings[2].Name = "PolyPerc"
+	m.ViewMode = types.FileView
+	m.FileSelectView = types.SoundMakerView
+	ImportDX7BankFile(m, filepath.Join(source, "init.syx"))
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+	assert.Contains(t, m.DX7BankMessage, "could not select preset")
+}
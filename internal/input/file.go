@@ -2,7 +2,10 @@ package input
 
 import (
 	"fmt"
+	"log"
 
+	"github.com/schollz/collidertracker/internal/audio"
+	"github.com/schollz/collidertracker/internal/getbpm"
 	"github.com/schollz/collidertracker/internal/model"
 	"github.com/schollz/collidertracker/internal/storage"
 	"github.com/schollz/collidertracker/internal/types"
@@ -14,10 +17,7 @@ func ModifyFileMetadataValue(m *model.Model, delta float32) {
 	}
 
 	// Get current metadata or create default
-	metadata, exists := m.FileMetadata[m.MetadataEditingFile]
-	if !exists {
-		metadata = types.FileMetadata{BPM: 120.0, Slices: 16, Playthrough: 0, SyncToBPM: 1} // Default values
-	}
+	metadata := getFileMetadataOrDefault(m, m.MetadataEditingFile)
 
 	switch types.FileMetadataRow(m.CurrentRow) {
 	case types.FileMetadataRowBPM: // BPM
@@ -35,6 +35,10 @@ func ModifyFileMetadataValue(m *model.Model, delta float32) {
 		modifier := createIntModifier(
 			func() int { return metadata.Slices },
 			func(v int) {
+				if v != metadata.Slices {
+					// Custom slice markers only apply to the slice count they were made for
+					metadata.SliceMarkers = nil
+				}
 				metadata.Slices = v
 				m.FileMetadata[m.MetadataEditingFile] = metadata
 			},
@@ -63,7 +67,136 @@ func ModifyFileMetadataValue(m *model.Model, delta float32) {
 			0, 1, fmt.Sprintf("file metadata SyncToBPM for %s", m.MetadataEditingFile),
 		)
 		modifyValueWithBounds(modifier, delta)
+
+	case types.FileMetadataRowLoopMode: // Loop mode (0=Off, 1=Forward, 2=Ping-pong)
+		modifier := createIntModifier(
+			func() int { return metadata.LoopMode },
+			func(v int) {
+				metadata.LoopMode = v
+				m.FileMetadata[m.MetadataEditingFile] = metadata
+			},
+			0, int(types.LoopModeCount)-1, fmt.Sprintf("file metadata LoopMode for %s", m.MetadataEditingFile),
+		)
+		modifyValueWithBounds(modifier, delta)
+
+	case types.FileMetadataRowLoopStart: // Loop start in frames
+		frames := fileFrameCount(&metadata, m.MetadataEditingFile)
+		loopEnd := metadata.LoopEnd
+		if loopEnd <= 0 {
+			loopEnd = frames
+		}
+		modifier := createIntModifier(
+			func() int { return metadata.LoopStart },
+			func(v int) {
+				metadata.LoopStart = v
+				m.FileMetadata[m.MetadataEditingFile] = metadata
+			},
+			0, loopEnd-1, fmt.Sprintf("file metadata LoopStart for %s", m.MetadataEditingFile),
+		)
+		modifyValueWithBounds(modifier, float32(frameStep(frames, delta)))
+
+	case types.FileMetadataRowLoopEnd: // Loop end in frames (0 = end of file)
+		frames := fileFrameCount(&metadata, m.MetadataEditingFile)
+		modifier := createIntModifier(
+			func() int {
+				if metadata.LoopEnd <= 0 {
+					return frames
+				}
+				return metadata.LoopEnd
+			},
+			func(v int) {
+				if v >= frames {
+					v = 0 // Store the end of the file as the default
+				}
+				metadata.LoopEnd = v
+				m.FileMetadata[m.MetadataEditingFile] = metadata
+			},
+			metadata.LoopStart+1, frames, fmt.Sprintf("file metadata LoopEnd for %s", m.MetadataEditingFile),
+		)
+		modifyValueWithBounds(modifier, float32(frameStep(frames, delta)))
+
+	case types.FileMetadataRowLoopXfade: // Loop crossfade in milliseconds
+		modifier := createIntModifier(
+			func() int { return metadata.LoopXfadeMs },
+			func(v int) {
+				metadata.LoopXfadeMs = v
+				m.FileMetadata[m.MetadataEditingFile] = metadata
+			},
+			0, 1000, fmt.Sprintf("file metadata LoopXfadeMs for %s", m.MetadataEditingFile),
+		)
+		modifyValueWithBounds(modifier, float32(deltaHandler(delta, 10)))
 	}
 
 	storage.AutoSave(m)
 }
+
+// unknownFrameCount bounds loop points for files whose length cannot be read
+const unknownFrameCount = 1 << 30
+
+// fileFrameCount returns the number of frames in a WAV file, or a large bound if unknown.
+// The file is only read when its metadata does not have the count yet.
+func fileFrameCount(metadata *types.FileMetadata, filename string) int {
+	if metadata.Frames > 0 {
+		return metadata.Frames
+	}
+	_, _, frames, err := getbpm.Length(filename)
+	if err != nil || frames <= 0 {
+		log.Printf("Could not read frame count for %s: %v", filename, err)
+		return unknownFrameCount
+	}
+	metadata.Frames = int(frames)
+	return metadata.Frames
+}
+
+// cacheFileFrameCount stores the length of a file in its metadata so that editing the loop
+// points does not read the file on every key press
+func cacheFileFrameCount(m *model.Model, filename string) {
+	if metadata, exists := m.FileMetadata[filename]; exists && metadata.Frames == 0 {
+		fileFrameCount(&metadata, filename)
+		m.FileMetadata[filename] = metadata
+	}
+}
+
+// frameStep converts a coarse (+/-1.0) or fine (+/-0.05) delta into a frame step:
+// coarse moves 1% of the file and fine moves 0.01%
+func frameStep(frames int, delta float32) int {
+	if frames >= unknownFrameCount {
+		frames = 44100 * 10 // Assume ten seconds when the length is unknown
+	}
+	var step int
+	if delta == 1.0 || delta == -1.0 {
+		step = frames / 100
+	} else {
+		step = frames / 10000
+	}
+	if step < 1 {
+		step = 1
+	}
+	if delta < 0 {
+		return -step
+	}
+	return step
+}
+
+// SelectFile opens the folder under the cursor in the file browser, or picks the file for
+// the view the browser was opened for
+func SelectFile(m *model.Model) {
+	if fullPath := audio.EnterFile(m); fullPath != "" {
+		SelectFilePath(m, fullPath)
+	}
+}
+
+// SelectFilePath picks a file for the view the file browser was opened for: a Multisample
+// zone, a missing project file, a DX7 bank, or a phrase row
+func SelectFilePath(m *model.Model, fullPath string) {
+	switch m.FileSelectView {
+	case types.MultisampleView:
+		SelectMultisampleZoneFile(m, fullPath)
+	case types.ProjectFilesView:
+		RelinkSelectedFile(m, fullPath)
+	case types.SoundMakerView:
+		ImportDX7BankFile(m, fullPath)
+	default:
+		audio.SelectFilePath(m, fullPath)
+	}
+}
@@ -0,0 +1,65 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestModifyFileMetadataLoop(t *testing.T) {
+	m := createTestModel()
+	// 123069 frames at 44.1kHz
+	m.MetadataEditingFile = "../getbpm/amen_beats8_bpm172.wav"
+	m.ViewMode = types.FileMetadataView
+
+	// Loop mode cycles Off -> Forward -> Ping-pong and stops at the ends
+	m.CurrentRow = int(types.FileMetadataRowLoopMode)
+	ModifyFileMetadataValue(m, 0.05)
+	assert.Equal(t, int(types.LoopModeForward), m.FileMetadata[m.MetadataEditingFile].LoopMode)
+	ModifyFileMetadataValue(m, 0.05)
+	ModifyFileMetadataValue(m, 0.05)
+	assert.Equal(t, int(types.LoopModePingPong), m.FileMetadata[m.MetadataEditingFile].LoopMode)
+
+	// Loop start moves 1% of the file on coarse steps
+	m.CurrentRow = int(types.FileMetadataRowLoopStart)
+	ModifyFileMetadataValue(m, 1.0)
+	assert.Equal(t, 1230, m.FileMetadata[m.MetadataEditingFile].LoopStart)
+	assert.Equal(t, 123069, m.FileMetadata[m.MetadataEditingFile].Frames, "the length is read once and kept")
+	ModifyFileMetadataValue(m, -1.0)
+	ModifyFileMetadataValue(m, -1.0)
+	assert.Equal(t, 0, m.FileMetadata[m.MetadataEditingFile].LoopStart)
+
+	// Loop end starts from the end of the file and stores 0 when returning to it
+	m.CurrentRow = int(types.FileMetadataRowLoopEnd)
+	ModifyFileMetadataValue(m, -1.0)
+	assert.Equal(t, 123069-1230, m.FileMetadata[m.MetadataEditingFile].LoopEnd)
+	ModifyFileMetadataValue(m, 1.0)
+	assert.Equal(t, 0, m.FileMetadata[m.MetadataEditingFile].LoopEnd)
+
+	// The kept length is used instead of the file
+	metadata := m.FileMetadata[m.MetadataEditingFile]
+	metadata.Frames = 10000
+	m.FileMetadata[m.MetadataEditingFile] = metadata
+	ModifyFileMetadataValue(m, -1.0)
+	assert.Equal(t, 10000-100, m.FileMetadata[m.MetadataEditingFile].LoopEnd)
+	ModifyFileMetadataValue(m, 1.0)
+
+	// Crossfade: coarse 10ms, fine 1ms, clamped at 0
+	m.CurrentRow = int(types.FileMetadataRowLoopXfade)
+	ModifyFileMetadataValue(m, 1.0)
+	ModifyFileMetadataValue(m, 0.05)
+	assert.Equal(t, 11, m.FileMetadata[m.MetadataEditingFile].LoopXfadeMs)
+	ModifyFileMetadataValue(m, -1.0)
+	ModifyFileMetadataValue(m, -1.0)
+	assert.Equal(t, 0, m.FileMetadata[m.MetadataEditingFile].LoopXfadeMs)
+}
+
+func TestFrameStep(t *testing.T) {
+	assert.Equal(t, 1000, frameStep(100000, 1.0))
+	assert.Equal(t, -10, frameStep(100000, -0.05))
+	assert.Equal(t, 1, frameStep(50, 0.05))
+	// Unknown length assumes ten seconds at 44.1kHz
+	assert.Equal(t, 4410, frameStep(unknownFrameCount, 1.0))
+}
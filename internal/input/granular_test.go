@@ -0,0 +1,63 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestGranular(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	m.ViewMode = types.PhraseView
+	m.CurrentPhrase = 0
+	m.CurrentRow = 3
+	m.CurrentCol = int(types.SamplerColGR)
+
+	// Shift+Right does nothing until the row has a granular setting
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftRight})
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+
+	m.SamplerPhrasesData[0][3][types.ColGranular] = 0x0A
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftRight})
+	assert.Equal(t, types.GranularView, m.ViewMode)
+	assert.Equal(t, 0x0A, m.GranularEditingIndex)
+
+	// Coarse and fine steps, clamped to the range of each setting
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlLeft})
+	assert.Equal(t, float32(9), m.GranularSettings[0x0A].Size)
+	for i := 0; i < int(types.GranularSettingsRowSpray)+3; i++ {
+		HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	}
+	assert.Equal(t, int(types.GranularSettingsRowSpray), m.CurrentRow)
+	for i := 0; i < 12; i++ {
+		HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlUp})
+	}
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlLeft})
+	assert.InDelta(t, 0.99, m.GranularSettings[0x0A].Spray, 1e-6)
+	m.CurrentRow = int(types.GranularSettingsRowDensity)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	assert.Equal(t, float32(1), m.GranularSettings[0x0A].Density)
+	assert.True(t, m.GranularSettings[0x0A].Active())
+
+	// Shift+Left returns to the GR column
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+	assert.Equal(t, int(types.SamplerColGR), m.CurrentCol)
+
+	// Deep copy pastes the settings into the next unused slot
+	m.CurrentRow = 3
+	DeepCopyToClipboard(m)
+	m.CurrentRow = 4
+	PasteCellFromClipboard(m)
+	pasted := m.SamplerPhrasesData[0][4][types.ColGranular]
+	assert.Equal(t, 0x0B, pasted)
+	assert.Equal(t, m.GranularSettings[0x0A], m.GranularSettings[pasted])
+	assert.Equal(t, 0x0C, FindNextUnusedGranular(m, 0x0A))
+}
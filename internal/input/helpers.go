@@ -45,6 +45,35 @@ func GetModulateSettingsForTrack(m *model.Model, track int) *[255]types.Modulate
 	return &m.SamplerModulateSettings
 }
 
+// modulateSettingsFor converts modulate settings for the modulation package. Settings whose
+// scale follows the project take the key and scale of the project.
+func modulateSettingsFor(m *model.Model, settings types.ModulateSettings) modulation.ModulateSettings {
+	if settings.Scale == modulation.ProjectScale {
+		settings.ScaleRoot = m.ProjectKey
+		settings.Scale = m.ProjectScaleName()
+	}
+	return modulation.ModulateSettings{
+		Seed:        settings.Seed,
+		IRandom:     settings.IRandom,
+		Sub:         settings.Sub,
+		Add:         settings.Add,
+		Increment:   settings.Increment,
+		Wrap:        settings.Wrap,
+		ScaleRoot:   settings.ScaleRoot,
+		Scale:       settings.Scale,
+		Probability: settings.Probability,
+	}
+}
+
+// stepInstrumentNote moves a note of an instrument phrase by semitones, or by notes of the
+// project scale when the project has one
+func stepInstrumentNote(m *model.Model, note, steps int) int {
+	if scale := m.ProjectScaleName(); scale != "all" {
+		return modulation.StepInScale(note, steps, scale, m.ProjectKey, m.TuningForTrack(m.CurrentTrack))
+	}
+	return note + steps
+}
+
 // ValueModifier represents a function that modifies a value with bounds checking
 type ValueModifier struct {
 	GetValue         func() interface{}
@@ -205,8 +234,9 @@ func ModifyValue(m *model.Model, delta int) {
 			// Instrument view note column: MIDI notes (0-127) with special increment behavior
 			var newValue int
 			if currentValue == -1 {
-				// First edit on an empty cell: initialize to middle C (60)
-				newValue = 60
+				// First edit on an empty cell: initialize to middle C (60), or the note of
+				// the project scale from there
+				newValue = stepInstrumentNote(m, 59, 1)
 			} else {
 				// Apply special increment logic for instrument notes
 				// Coarse (Ctrl+Up/Down) should increment by 12 (octaves)
@@ -216,8 +246,8 @@ func ModifyValue(m *model.Model, delta int) {
 					octaveDelta := (delta / 16) * 12
 					newValue = currentValue + octaveDelta
 				} else {
-					// This is fine increment (+/-1)
-					newValue = currentValue + delta
+					// This is fine increment (+/-1), through the project scale if it has one
+					newValue = stepInstrumentNote(m, currentValue, delta)
 				}
 			}
 
@@ -304,6 +334,16 @@ func ModifyValue(m *model.Model, delta int) {
 				}
 			}
 			(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = newValue
+		} else if phraseViewType == types.InstrumentPhraseView && colIndex == int(types.ColChordVoicing) {
+			// Instrument view chord voicing column: "-" -> "2" -> "S" -> "O" -> "L", stop at ends
+			if currentValue == -1 {
+				currentValue = int(types.ChordVoicingClose)
+			}
+			newValue := currentValue - 1
+			if delta > 0 {
+				newValue = currentValue + 1
+			}
+			(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = max(0, min(int(types.ChordVoicingCount)-1, newValue))
 		} else if phraseViewType == types.InstrumentPhraseView && colIndex == int(types.ColMidi) {
 			// Instrument view MIDI column: hex values 00-FE
 			var newValue int
@@ -342,8 +382,8 @@ func ModifyValue(m *model.Model, delta int) {
 				newValue = 127
 			}
 			(*phrasesData)[m.CurrentPhrase][m.CurrentRow][colIndex] = newValue
-		} else if colIndex >= int(types.ColMidiCC0) && colIndex <= int(types.ColMidiCC8) {
-			// MIDI CC columns: special handling to limit to 0x7F (127)
+		} else if (colIndex >= int(types.ColMidiCC0) && colIndex <= int(types.ColMidiCC8)) || colIndex == int(types.ColAftertouch) {
+			// MIDI CC and aftertouch columns: special handling to limit to 0x7F (127)
 			var newValue int
 			if currentValue == -1 {
 				// First edit on an empty cell: initialize to 00 and DO NOT apply delta
@@ -415,7 +455,9 @@ func DebugLogRowEmission(m *model.Model) {
 		log.Printf("ROW_EMIT: Invalid playback position - Phrase: %d, Row: %d", m.PlaybackPhrase, m.PlaybackRow)
 		return
 	}
-	// Use current track context for chain and phrase playback modes
+	// Use current track context for chain and phrase playback modes. Automation moves
+	// first so the voices of the row start at the automated values.
+	startPlaybackAutomationRow(m)
 	EmitRowDataFor(m, m.PlaybackPhrase, m.PlaybackRow, m.CurrentTrack)
 }
 
@@ -507,10 +549,11 @@ func CopyLastRowWithIncrement(m *model.Model) {
 	// Increment the note and set it
 	var newNote int
 	if phraseViewType == types.InstrumentPhraseView {
-		// For Instrument view: increment MIDI notes (0-127), with chromatic progression
-		newNote = sourceNote + 1
-		if newNote > 127 { // Wrap around MIDI range
-			newNote = 0
+		// For Instrument view: increment MIDI notes (0-127), chromatically or through the
+		// project scale
+		newNote = stepInstrumentNote(m, sourceNote, 1)
+		if newNote > 127 || newNote == sourceNote { // Wrap around MIDI range
+			newNote = stepInstrumentNote(m, -1, 1)
 		}
 	} else {
 		// For Sampler view: increment sample/note numbers (0-254)
@@ -653,6 +696,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	rawGate := rowData[types.ColGate]
 	rawRetrigger := rowData[types.ColRetrigger]
 	rawTimestretch := rowData[types.ColTimestretch]
+	rawGranular := rowData[types.ColGranular]
 	rawModulate := rowData[types.ColModulate]
 	rawFilenameIndex := rowData[types.ColFilename]
 
@@ -678,6 +722,8 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	effectiveHighPassFilter := GetEffectiveValueForTrack(m, phrase, row, int(types.ColHighPassFilter), trackId)
 	effectiveComb := GetEffectiveValueForTrack(m, phrase, row, int(types.ColEffectComb), trackId)
 	effectiveReverb := GetEffectiveValueForTrack(m, phrase, row, int(types.ColEffectReverb), trackId)
+	effectiveSampleStart := automatedEffectiveValue(m, phrase, row, types.ColSampleStart, trackId)
+	effectiveSampleLength := automatedEffectiveValue(m, phrase, row, types.ColSampleLength, trackId)
 
 	// Effective/inherited values
 	effectiveNote := GetEffectiveValueForTrack(m, phrase, row, int(types.ColNote), trackId)
@@ -703,31 +749,11 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 				trackRng = rand.New(rand.NewSource(time.Now().UnixNano()))
 			}
 
-			modulatedNote := modulation.ApplyModulation(originalNote, modulation.ModulateSettings{
-				Seed:        modulateSettings.Seed,
-				IRandom:     modulateSettings.IRandom,
-				Sub:         modulateSettings.Sub,
-				Add:         modulateSettings.Add,
-				Increment:   modulateSettings.Increment,
-				Wrap:        modulateSettings.Wrap,
-				ScaleRoot:   modulateSettings.ScaleRoot,
-				Scale:       modulateSettings.Scale,
-				Probability: modulateSettings.Probability,
-			}, trackRng)
+			modulatedNote := modulation.ApplyModulation(originalNote, modulateSettingsFor(m, modulateSettings), trackRng)
 
 			// Apply the same logic for raw note
 			rawNoteWithIncrement := modulation.ApplyIncrement(rawNote, incrementCounter, modulateSettings.Increment, modulateSettings.Wrap)
-			rawNoteModulated = modulation.ApplyModulation(rawNoteWithIncrement, modulation.ModulateSettings{
-				Seed:        modulateSettings.Seed,
-				IRandom:     modulateSettings.IRandom,
-				Sub:         modulateSettings.Sub,
-				Add:         modulateSettings.Add,
-				Increment:   modulateSettings.Increment,
-				Wrap:        modulateSettings.Wrap,
-				ScaleRoot:   modulateSettings.ScaleRoot,
-				Scale:       modulateSettings.Scale,
-				Probability: modulateSettings.Probability,
-			}, trackRng)
+			rawNoteModulated = modulation.ApplyModulation(rawNoteWithIncrement, modulateSettingsFor(m, modulateSettings), trackRng)
 			log.Printf("Applied modulation %02X: NN %02X -> %02X (Seed=%d, IRandom=%d, Sub=%d, Add=%d, Increment=%d, Scale=%s)",
 				rawModulate, effectiveNote, modulatedNote, modulateSettings.Seed, modulateSettings.IRandom, modulateSettings.Sub, modulateSettings.Add, modulateSettings.Increment, modulateSettings.Scale)
 			effectiveNote = modulatedNote
@@ -806,12 +832,13 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 		)
 	} else {
 		// Sampler track - show traditional sampler information
-		log.Printf("Raw:  NN=%s DT=%s GT=%s RT=%s TS=%s FI=%d Я=%s PA=%s LP=%s HP=%s CO=%s VE=%s",
+		log.Printf("Raw:  NN=%s DT=%s GT=%s RT=%s TS=%s GR=%s FI=%d Я=%s PA=%s LP=%s HP=%s CO=%s VE=%s",
 			formatHex(rawNote),
 			formatHex(rawDeltaTime),
 			formatHex(rawGate),
 			formatHex(rawRetrigger),
 			formatHex(rawTimestretch),
+			formatHex(rawGranular),
 			rawFilenameIndex,
 			func() string {
 				if rawEffectReverse == -1 {
@@ -838,6 +865,11 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 		)
 	}
 
+	// Rows that play set the insert FX of the track, with or without a note
+	if IsRowPlayable(rawDeltaTime) {
+		m.SendOSCInsertFXColumn(trackId, rowData[types.ColInsertFX], rowData[types.ColInsertFXValue])
+	}
+
 	// Only emit if we have playback enabled and a concrete note
 	// For samplers, also check that we have a filename
 	needsFile := trackId >= 0 && trackId < 8 && m.TrackTypes[trackId] // Sampler tracks need files
@@ -851,6 +883,10 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 				break
 			}
 		}
+		// Pitch bend and aftertouch move sounding notes like CCs do
+		if rowData[types.ColPitchBend] != -1 || rowData[types.ColAftertouch] != -1 {
+			hasCCValues = true
+		}
 	}
 
 	// Unified DT-based playback condition: DT > 0 means play for both instruments and samplers
@@ -895,7 +931,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	sliceNumber := rawNoteModulated % sliceCount
 
 	// Get effective gate value (handles sticky behavior and virtual defaults)
-	effectiveGate := GetEffectiveValueForTrack(m, phrase, row, int(types.ColGate), trackId)
+	effectiveGate := automatedEffectiveValue(m, phrase, row, types.ColGate, trackId)
 	if effectiveGate == -1 {
 		effectiveGate = 0x80 // Default Gate value (128)
 	}
@@ -913,7 +949,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	deltaTimeSeconds := calculateDeltaTimeSeconds(m, phrase, row, trackId)
 
 	// Calculate velocity from velocity column (sticky behavior)
-	rawVelocity := GetEffectiveValueForTrack(m, phrase, row, int(types.ColVelocity), trackId)
+	rawVelocity := automatedEffectiveValue(m, phrase, row, types.ColVelocity, trackId)
 	velocity := 64 // Default velocity (0x40)
 	if rawVelocity != -1 {
 		velocity = rawVelocity // Keep as integer (0x00-0x7F = 0-127)
@@ -1005,7 +1041,8 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	// Pitch conversion from hex to float: 128 (0x80) = 0.0, range 0-254 maps to -24 to +24
 	if rawPitch != -1 {
 		// Map 0-254 to -24 to +24, with 128 as center (0.0)
-		oscParams.Pitch = ((float32(rawPitch) - 128.0) / 128.0) * 24.0
+		// In a tuning the pitch counts keys of the tuning rather than semitones
+		oscParams.Pitch = m.TuningForTrack(trackId).Transpose(((float32(rawPitch) - 128.0) / 128.0) * 24.0)
 	} else {
 		// Default pitch is 0.0 when cleared (-1)
 		oscParams.Pitch = 0.0
@@ -1060,6 +1097,19 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 		}
 	}
 
+	// Granular - play the row as a grain cloud when the slot is switched on
+	if rawGranular >= 0 && rawGranular < 255 {
+		gs := m.GranularSettings[rawGranular]
+		if gs.Active() {
+			oscParams.GranularSize = gs.Size
+			oscParams.GranularDensity = gs.Density
+			oscParams.GranularPosition = gs.Position
+			oscParams.GranularPosJitter = gs.PositionJitter
+			oscParams.GranularPitchJitter = gs.PitchJitter
+			oscParams.GranularSpray = gs.Spray
+		}
+	}
+
 	oscParams.DuckingIndex = effectiveDucking
 
 	// NEW effect params
@@ -1124,6 +1174,23 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	// Set file metadata parameters
 	oscParams.Playthrough = playthrough
 	oscParams.SyncToBPM = syncToBPM
+	if effectiveSampleStart != -1 || effectiveSampleLength != -1 {
+		// ST/LN need an explicit region even with equal slicing
+		regionMetadata := getFileMetadataOrDefault(m, effectiveFilename)
+		oscParams.SliceStart, oscParams.SliceEnd = samplerRowRegion(regionMetadata, sliceNumber, playthrough, oscParams.EffectReverse,
+			effectiveSampleStart, effectiveSampleLength)
+		if effectiveSampleLength != -1 {
+			oscParams.ClipToRegion = 1
+		}
+	} else if exists && fileMetadata.HasCustomSlicing() {
+		oscParams.SliceStart, oscParams.SliceEnd = samplerRegion(fileMetadata, sliceNumber, playthrough, oscParams.EffectReverse)
+	}
+	if exists && fileMetadata.LoopMode > 0 {
+		oscParams.LoopMode = fileMetadata.LoopMode
+		oscParams.LoopStart = fileMetadata.LoopStart
+		oscParams.LoopEnd = fileMetadata.LoopEnd
+		oscParams.LoopXfadeMs = fileMetadata.LoopXfadeMs
+	}
 
 	// Set update flag if this is an update call
 	if shouldUpdate {
@@ -1135,7 +1202,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 	// Determine track type and emit appropriate message
 	if isInstrumentTrack(m, trackId) {
 		// For instrument tracks, extract all instrument-specific parameters
-		rawVelocity := GetEffectiveValueForTrack(m, phrase, row, int(types.ColVelocity), trackId)
+		rawVelocity := automatedEffectiveValue(m, phrase, row, types.ColVelocity, trackId)
 		velocity := float32(64) // Default velocity as float for instrument OSC (0x40)
 		if rawVelocity != -1 {
 			velocity = float32(rawVelocity) // Keep as integer value, just convert to float32 for OSC
@@ -1169,7 +1236,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 		rawSoundMaker := GetEffectiveValueForTrack(m, phrase, row, int(types.ColSoundMaker), trackId)
 
 		// Extract Gate parameter with effective value (sticky)
-		effectiveGate := GetEffectiveValueForTrack(m, phrase, row, int(types.ColGate), trackId)
+		effectiveGate := automatedEffectiveValue(m, phrase, row, types.ColGate, trackId)
 		if effectiveGate == -1 {
 			effectiveGate = 0x80 // Default Gate value (128)
 		}
@@ -1267,8 +1334,16 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 			rawEffectDucking,
 			midiCC,
 		)
+		instrumentParams.ParameterLocks = m.ParameterLocks.Row(phrase, row)
+		instrumentParams.PitchBend = rowData[types.ColPitchBend]
+		instrumentParams.Aftertouch = rowData[types.ColAftertouch]
 		// Generate chord notes and apply modulation according to user specification
 		midiNotes := types.GetChordNotes(rowData[types.ColNote], types.ChordType(rawChord), types.ChordAddition(rawChordAdd), types.ChordTransposition(rawChordTrans))
+		if len(midiNotes) > 1 && trackId >= 0 && trackId < 8 {
+			// Voice the chord; voice leading moves from the last chord played on the track
+			midiNotes = types.VoiceChord(midiNotes, types.ChordVoicing(rowData[types.ColChordVoicing]), m.LastChordNotes[trackId])
+			m.LastChordNotes[trackId] = midiNotes
+		}
 		instrumentParams.Notes = make([]float32, len(midiNotes))
 
 		// Apply modulation to notes according to the new logic for instrument view:
@@ -1297,17 +1372,7 @@ func EmitRowDataFor(m *model.Model, phrase, row, trackId int, isUpdate ...bool)
 				// Apply increment before other modulation operations if counter > -1
 				noteWithIncrement := modulation.ApplyIncrement(note, incrementCounter, modulateSettings.Increment, modulateSettings.Wrap)
 
-				modulatedNote := modulation.ApplyModulation(noteWithIncrement, modulation.ModulateSettings{
-					Seed:        modulateSettings.Seed,
-					IRandom:     modulateSettings.IRandom,
-					Sub:         modulateSettings.Sub,
-					Add:         modulateSettings.Add,
-					Increment:   modulateSettings.Increment,
-					Wrap:        modulateSettings.Wrap,
-					ScaleRoot:   modulateSettings.ScaleRoot,
-					Scale:       modulateSettings.Scale,
-					Probability: modulateSettings.Probability,
-				}, trackRng)
+				modulatedNote := modulation.ApplyModulationInTuning(noteWithIncrement, modulateSettingsFor(m, modulateSettings), trackRng, m.TuningForTrack(trackId))
 				instrumentParams.Notes[i] = float32(modulatedNote)
 				log.Printf("Applied modulation to instrument note %d: %d -> %d (increment=%d, hasArpeggio=%v, hasChord=%v)", i, note, modulatedNote, modulateSettings.Increment, hasArpeggio, hasChord)
 			}
@@ -1466,6 +1531,7 @@ func shouldEmitRowForTrackAtPosition(m *model.Model, phrase, row, trackId int) b
 			types.ColMidiCC0, types.ColMidiCC1, types.ColMidiCC2,
 			types.ColMidiCC3, types.ColMidiCC4, types.ColMidiCC5,
 			types.ColMidiCC6, types.ColMidiCC7, types.ColMidiCC8,
+			types.ColPitchBend, types.ColAftertouch,
 		}
 		for _, ccCol := range ccCols {
 			if (*phrasesData)[phrase][row][ccCol] != -1 {
@@ -1549,6 +1615,9 @@ func stopPlayback(m *model.Model) {
 	if m.RecordingActive {
 		stopRecording(m)
 	}
+	cancelBounce(m)
+	m.CancelAllArpeggios()
+	m.StopAutomation()
 
 	// Clear file browser playback state when stopping tracker playback
 	if m.CurrentlyPlayingFile != "" {
@@ -1558,6 +1627,8 @@ func stopPlayback(m *model.Model) {
 	}
 
 	m.SendStopOSC()
+	m.StopMidi()
+	m.SendOSCInsertFXMessages() // Back to the saved values the FX columns changed
 	log.Printf("Playback stopped")
 }
 
@@ -1575,6 +1646,7 @@ func startPlaybackWithConfig(m *model.Model, config PlaybackConfig) tea.Cmd {
 		}
 	}
 	log.Printf("DEBUG_INCREMENT: Initialized all increment counters to -1 for playback start")
+	m.LastChordNotes = [8][]int{} // Voice leading starts over
 
 	if config.Mode == types.SongView {
 		// Song playback mode - reset single-track playback variables and initialize all tracks with data
@@ -1631,6 +1703,7 @@ func startPlaybackWithConfig(m *model.Model, config PlaybackConfig) tea.Cmd {
 				m.LoadTicksLeftForTrack(track)
 
 				// Emit initial row for this track
+				startSongAutomationRow(m, track)
 				EmitRowDataFor(m, firstPhraseID, m.SongPlaybackRowInPhrase[track], track)
 				log.Printf("Song track %d started at row %02X, chain %02X (chain row %d), phrase %02X with %d ticks", track, startRow, chainID, firstChainRow, firstPhraseID, m.SongPlaybackTicksLeft[track])
 			} else {
@@ -1660,6 +1733,7 @@ func startPlaybackWithConfig(m *model.Model, config PlaybackConfig) tea.Cmd {
 			m.SongPlaybackRowInPhrase[0] = FindFirstNonEmptyRowInPhraseForTrack(m, 0, 0)
 			// Initialize ticks for fallback track 0
 			m.LoadTicksLeftForTrack(0)
+			startSongAutomationRow(m, 0)
 			EmitRowDataFor(m, 0, m.SongPlaybackRowInPhrase[0], 0)
 			log.Printf("Song track 0 fallback started at phrase 0, row %d with %d ticks", m.SongPlaybackRowInPhrase[0], m.SongPlaybackTicksLeft[0])
 		}
@@ -1750,7 +1824,7 @@ func startPlaybackWithConfig(m *model.Model, config PlaybackConfig) tea.Cmd {
 		startRecordingWithContext(m, fromSongView, fromCtrlSpace)
 	}
 
-	return Tick(m)
+	return tea.Batch(Tick(m), AutomationTick(m))
 }
 
 // startPlaybackWithConfigFromCtrlSpace is specialized for Ctrl+Space recording context
@@ -1764,6 +1838,7 @@ func startPlaybackWithConfigFromCtrlSpace(m *model.Model, config PlaybackConfig)
 		}
 	}
 	log.Printf("DEBUG_INCREMENT: Initialized all increment counters to -1 for Ctrl+Space playback start")
+	m.LastChordNotes = [8][]int{} // Voice leading starts over
 	m.IsPlaying = true
 	m.PlaybackMode = config.Mode
 
@@ -1813,6 +1888,7 @@ func startPlaybackWithConfigFromCtrlSpace(m *model.Model, config PlaybackConfig)
 				m.LoadTicksLeftForTrack(track)
 
 				// Emit the initial row immediately
+				startSongAutomationRow(m, track)
 				EmitRowDataFor(m, firstPhraseID, m.SongPlaybackRowInPhrase[track], track)
 				log.Printf("Song track %d initialized: phrase %d, row %d, ticks %d", track, firstPhraseID, m.SongPlaybackRowInPhrase[track], m.SongPlaybackTicksLeft[track])
 			} else {
@@ -1874,7 +1950,7 @@ func startPlaybackWithConfigFromCtrlSpace(m *model.Model, config PlaybackConfig)
 		startRecordingWithContext(m, fromSongView, fromCtrlSpace)
 	}
 
-	return Tick(m)
+	return tea.Batch(Tick(m), AutomationTick(m))
 }
 
 // togglePlaybackWithConfig provides common toggle logic
@@ -1908,6 +1984,8 @@ func DeepCopyToClipboard(m *model.Model) {
 			DeepCopyRetriggerToClipboard(m)
 		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColTimestretch) {
 			DeepCopyTimestrechToClipboard(m)
+		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColGranular) {
+			DeepCopyGranularToClipboard(m)
 		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColArpeggio) {
 			DeepCopyArpeggioToClipboard(m)
 		} else {
@@ -1917,6 +1995,8 @@ func DeepCopyToClipboard(m *model.Model) {
 		DeepCopyRetriggerToClipboard(m)
 	} else if m.ViewMode == types.TimestrechView {
 		DeepCopyTimestrechToClipboard(m)
+	} else if m.ViewMode == types.GranularView {
+		DeepCopyGranularToClipboard(m)
 	} else {
 		log.Printf("Deep copy not supported in this view")
 	}
@@ -2019,6 +2099,11 @@ func DeepCopyPhraseToClipboard(m *model.Model) {
 		}
 	}
 
+	// Instrument phrases take the parameter locks of their rows along
+	if m.GetPhraseViewType() == types.InstrumentPhraseView {
+		m.ParameterLocks.CopyPhrase(sourcePhraseID, destPhraseID)
+	}
+
 	// Copy and remap arpeggio settings referenced in the phrase
 	arpeggioMapping := make(map[int]int) // Map from source arpeggio index to destination arpeggio index
 	for row := 0; row < 255; row++ {
@@ -2104,6 +2189,11 @@ func DeepCopyCurrentPhraseToClipboard(m *model.Model) {
 		}
 	}
 
+	// Instrument phrases take the parameter locks of their rows along
+	if m.GetPhraseViewType() == types.InstrumentPhraseView {
+		m.ParameterLocks.CopyPhrase(sourcePhraseID, destPhraseID)
+	}
+
 	// Copy and remap arpeggio settings referenced in the phrase
 	arpeggioMapping := make(map[int]int) // Map from source arpeggio index to destination arpeggio index
 	for row := 0; row < 255; row++ {
@@ -2225,6 +2315,45 @@ func DeepCopyTimestrechToClipboard(m *model.Model) {
 	log.Printf("Marked timestrech %02X for deep copy on paste", sourceTimestrechIndex)
 }
 
+func DeepCopyGranularToClipboard(m *model.Model) {
+	var sourceGranularIndex int
+
+	if m.ViewMode == types.GranularView {
+		// In GranularView, use the currently editing granular index
+		sourceGranularIndex = m.GranularEditingIndex
+	} else {
+		// In PhraseView, get the granular index from the current cell
+		phrasesData := m.GetCurrentPhrasesData()
+		sourceGranularIndex = (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColGranular]
+
+		if sourceGranularIndex == -1 {
+			log.Printf("Cannot deep copy granular: no granular set in current cell")
+			return
+		}
+	}
+
+	if sourceGranularIndex < 0 || sourceGranularIndex >= 255 {
+		log.Printf("Cannot deep copy granular: invalid granular index %d", sourceGranularIndex)
+		return
+	}
+
+	// Put the ORIGINAL granular index in clipboard, but mark it for deep copy on paste
+	clipboard := types.ClipboardData{
+		Value:           sourceGranularIndex, // Keep original reference
+		CellType:        types.HexCell,
+		Mode:            types.CellMode,
+		HasData:         true,
+		HighlightRow:    m.CurrentRow,
+		HighlightCol:    m.CurrentCol,
+		HighlightPhrase: m.CurrentPhrase,
+		HighlightView:   m.ViewMode,
+		IsFreshDeepCopy: true, // Mark for deep copy on paste
+	}
+	m.Clipboard = clipboard
+
+	log.Printf("Marked granular %02X for deep copy on paste", sourceGranularIndex)
+}
+
 func DeepCopyArpeggioToClipboard(m *model.Model) {
 	// Get the arpeggio index from the current cell
 	phrasesData := m.GetCurrentPhrasesData()
@@ -2378,8 +2507,11 @@ func IsArpeggioUnused(m *model.Model, arpeggioID int) bool {
 
 	// Check if arpeggio has any non-default settings
 	settings := m.ArpeggioSettings[arpeggioID]
+	if settings.Latch {
+		return false
+	}
 	for _, row := range settings.Rows {
-		if row.Direction != 0 || row.Count != -1 || row.Divisor != -1 {
+		if !row.IsEmpty() {
 			return false
 		}
 	}
@@ -2678,8 +2810,8 @@ func FillSequentialPhrase(m *model.Model) {
 			}
 			log.Printf("Cleared %d MO cells with value %02X", clearCount, currentValue)
 		}
-	} else if colIndex == int(types.ColRetrigger) || colIndex == int(types.ColTimestretch) {
-		// RT and TS columns should keep the reference value constant
+	} else if colIndex == int(types.ColRetrigger) || colIndex == int(types.ColTimestretch) || colIndex == int(types.ColGranular) {
+		// RT, TS and GR columns should keep the reference value constant
 		// Find the last non-null value and fill all cells with that same value
 		referenceValue := 0
 		fillStartRow := 0
@@ -2886,6 +3018,33 @@ func IsTimestrechUnused(m *model.Model, timestrechID int) bool {
 	return settings.Start == 0.0 && settings.End == 0.0 && settings.Beats == 0
 }
 
+// FindNextUnusedGranular finds the next unused granular slot starting from a given index
+func FindNextUnusedGranular(m *model.Model, startingFrom int) int {
+	// Bounds check input
+	if startingFrom < 0 || startingFrom >= 255 {
+		return -1
+	}
+
+	// Search from startingFrom+1 to 254, then wrap to 0 to startingFrom-1
+	for offset := 1; offset < 255; offset++ {
+		granularID := (startingFrom + offset) % 255
+		if IsGranularUnused(m, granularID) {
+			return granularID
+		}
+	}
+	return -1 // No unused granular found
+}
+
+// IsGranularUnused checks if a granular slot is unused (Size == 0 keeps it off)
+func IsGranularUnused(m *model.Model, granularID int) bool {
+	// Bounds check first
+	if granularID < 0 || granularID >= 255 {
+		return false
+	}
+
+	return m.GranularSettings[granularID].Size == 0
+}
+
 // ResolveDuckingIndex returns the sticky/effective DU value for a row (or -1)
 func ResolveDuckingIndex(m *model.Model, phrase, row, trackId int) int {
 	return GetEffectiveValueForTrack(m, phrase, row, int(types.ColEffectDucking), trackId)
@@ -171,6 +171,18 @@ func GetModifierKey() string {
 
 func HandleKeyInput(m *model.Model, msg tea.KeyMsg) tea.Cmd {
 	log.Printf("key: %s, %+v", msg.String(), msg)
+	if m.ViewMode == types.LibraryView {
+		// The library search box takes typed text before the regular key bindings
+		if cmd, handled := HandleLibraryKey(m, msg); handled {
+			return cmd
+		}
+	}
+	if m.ViewMode == types.PresetView && m.PresetNaming {
+		// So does the name of a preset being saved
+		if cmd, handled := HandlePresetNameKey(m, msg); handled {
+			return cmd
+		}
+	}
 	switch msg.String() {
 	case "ctrl+q", "alt+q":
 		return tea.Quit
@@ -180,6 +192,16 @@ func HandleKeyInput(m *model.Model, msg tea.KeyMsg) tea.Cmd {
 		// Clear current cell in Arpeggio Settings
 		if m.ViewMode == types.ArpeggioView {
 			ClearArpeggioCell(m)
+		} else if m.ViewMode == types.PresetView {
+			ClosePresets(m)
+		} else if m.ViewMode == types.LFOView {
+			ClearLFO(m)
+		} else if m.ViewMode == types.AutomationView {
+			DeleteAutomationRow(m)
+		} else if m.ViewMode == types.ParameterLockView {
+			ClearParameterLock(m)
+		} else if m.ViewMode == types.InsertFXView {
+			RemoveInsertFXSlot(m)
 		}
 
 	case "shift+right":
@@ -266,6 +288,34 @@ func HandleKeyInput(m *model.Model, msg tea.KeyMsg) tea.Cmd {
 	case "m":
 		return handleM(m)
 
+	case "b":
+		return StartBounce(m)
+
+	case "/":
+		if m.ViewMode == types.FileView {
+			return OpenLibrary(m)
+		} else if m.ViewMode == types.SoundMakerView {
+			OpenPresets(m)
+		}
+
+	case "e":
+		if m.ViewMode == types.FileView {
+			OpenSampleTools(m)
+		}
+
+	case "n":
+		if m.ViewMode == types.PresetView {
+			StartPresetNaming(m)
+		}
+
+	case "a":
+		if m.ViewMode == types.PhraseView || m.ViewMode == types.ChainView {
+			OpenAutomation(m)
+		}
+
+	case "o":
+		OpenParameterLocks(m)
+
 	case "pgdown":
 		return handlePgDown(m)
 
@@ -275,6 +325,22 @@ func HandleKeyInput(m *model.Model, msg tea.KeyMsg) tea.Cmd {
 	case "ctrl+o", "alt+o":
 		return handleCtrlO(m)
 
+	case "ctrl+u", "alt+u":
+		return handleCtrlU(m)
+
+	case "enter":
+		return handleEnter(m)
+
+	case "ctrl+t", "alt+t":
+		if m.ViewMode == types.LibraryView {
+			StartLibraryTagEdit(m)
+		}
+
+	case "ctrl+a", "alt+a":
+		if m.ViewMode == types.LibraryView {
+			return ToggleLibraryRoot(m)
+		}
+
 	// Vim movement keys (only when vim mode is enabled)
 	case "h":
 		if m.VimMode {
@@ -458,6 +524,23 @@ func handleShiftRight(m *model.Model) tea.Cmd {
 			m.ScrollOffset = 0
 			storage.AutoSave(m)
 			return nil
+		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColGranular) {
+			// Navigate to granular view only if a granular is selected (not -1)
+			phrasesData := m.GetCurrentPhrasesData()
+			granularIndex := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColGranular]
+			if granularIndex == -1 {
+				return nil // Don't navigate if no granular is selected
+			}
+			// Save current phrase view position
+			m.LastPhraseRow = m.CurrentRow
+			m.LastPhraseCol = m.CurrentCol
+			m.GranularEditingIndex = granularIndex
+			m.ViewMode = types.GranularView
+			m.CurrentRow = 0 // Start at first setting
+			m.CurrentCol = 0
+			m.ScrollOffset = 0
+			storage.AutoSave(m)
+			return nil
 		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColModulate) {
 			// Navigate to modulate view - if no modulate is selected, use index 00
 			phrasesData := m.GetCurrentPhrasesData()
@@ -532,6 +615,10 @@ func handleShiftRight(m *model.Model) tea.Cmd {
 			m.ScrollOffset = 0
 			storage.AutoSave(m)
 			return nil
+		} else if columnMapping != nil && (columnMapping.DataColumnIndex == int(types.ColInsertFX) || columnMapping.DataColumnIndex == int(types.ColInsertFXValue)) {
+			// Navigate to the insert chain of the track
+			OpenInsertFX(m)
+			return nil
 		} else if columnMapping != nil && columnMapping.DataColumnIndex == int(types.ColEffectDucking) {
 			// Navigate to ducking view - if no ducking is selected, use 00
 			phrasesData := m.GetCurrentPhrasesData()
@@ -596,6 +683,7 @@ func handleShiftRight(m *model.Model) tea.Cmd {
 		// Navigate to file view from any other column in phrase view
 		m.FileSelectRow = m.CurrentRow // Remember which row we're selecting for
 		m.FileSelectCol = m.CurrentCol // Remember which column we were on
+		m.FileSelectView = types.PhraseView
 
 		// Try to navigate to the folder containing the current row's file
 		selectedFilename := ""
@@ -641,6 +729,30 @@ func handleShiftRight(m *model.Model) tea.Cmd {
 		}
 
 		storage.AutoSave(m)
+	} else if m.ViewMode == types.FileMetadataView {
+		// Open the slice editor for the file being edited
+		OpenSliceEditor(m)
+	} else if m.ViewMode == types.SoundMakerView {
+		// Open the zone editor for Multisample SoundMakers, or import a bank for DX7s
+		OpenMultisampleEditor(m)
+		BrowseDX7Bank(m)
+	} else if m.ViewMode == types.MultisampleView {
+		// Pick the file for the selected zone
+		BrowseMultisampleZoneFile(m)
+	} else if m.ViewMode == types.SettingsView {
+		// Check the project's sample files
+		OpenProjectFiles(m)
+	} else if m.ViewMode == types.MixerView {
+		if m.CurrentMixerRow >= types.MixerRowSend {
+			// Edit the return the send goes to
+			OpenReturns(m)
+		} else {
+			// Edit the LFOs that move the mixer and SoundMaker parameters
+			OpenLFOs(m)
+		}
+	} else if m.ViewMode == types.ProjectFilesView {
+		// Pick a new location for the selected missing file
+		BrowseRelinkFile(m)
 	}
 	return nil
 }
@@ -684,10 +796,26 @@ func handleShiftUp(m *model.Model) tea.Cmd {
 			if !strings.HasSuffix(selectedFile, "/") && selectedFile != ".." {
 				fullPath := filepath.Join(m.CurrentDir, selectedFile)
 				m.MetadataEditingFile = fullPath
+				cacheFileFrameCount(m, fullPath)
 				switchToView(m, fileMetadataViewConfig())
 				log.Printf("Opening metadata editor for file: %s", fullPath)
 			}
 		}
+	} else if m.ViewMode == types.LFOView {
+		// Navigate back to mixer view
+		CloseLFOs(m)
+	} else if m.ViewMode == types.ReturnsView {
+		// Navigate back to mixer view
+		CloseReturns(m)
+	} else if m.ViewMode == types.AutomationView {
+		// Navigate back to the phrase or chain
+		CloseAutomation(m)
+	} else if m.ViewMode == types.ParameterLockView {
+		// Navigate back to phrase view
+		CloseParameterLocks(m)
+	} else if m.ViewMode == types.InsertFXView {
+		// Move the selected module up the chain
+		MoveInsertFXSlot(m, -1)
 	}
 	return nil
 }
@@ -746,6 +874,9 @@ func handleShiftDown(m *model.Model) tea.Cmd {
 			ScrollOffset: 0,
 		})
 		m.MetadataEditingFile = "" // Clear the editing file
+	} else if m.ViewMode == types.InsertFXView {
+		// Move the selected module down the chain
+		MoveInsertFXSlot(m, 1)
 	}
 	return nil
 }
@@ -777,6 +908,21 @@ func handleShiftLeft(m *model.Model) tea.Cmd {
 		m.ScrollOffset = 0
 		storage.AutoSave(m)
 	} else if m.ViewMode == types.FileView {
+		if m.FileSelectView == types.MultisampleView {
+			// Navigate back to the zone we were picking a file for
+			CloseMultisampleFileBrowser(m)
+			return nil
+		}
+		if m.FileSelectView == types.ProjectFilesView {
+			// Navigate back to the missing file we were relinking
+			CloseRelinkFileBrowser(m)
+			return nil
+		}
+		if m.FileSelectView == types.SoundMakerView {
+			// Navigate back to the DX7 we were importing a bank for
+			CloseDX7BankBrowser(m)
+			return nil
+		}
 		// Navigate back to phrase view - return to the column we came from
 		switchToView(m, phraseViewConfig(m.FileSelectRow, m.FileSelectCol)) // Go back to original column
 	} else if m.ViewMode == types.RetriggerView {
@@ -785,6 +931,9 @@ func handleShiftLeft(m *model.Model) tea.Cmd {
 	} else if m.ViewMode == types.TimestrechView {
 		// Navigate back to phrase view - return to the original column
 		switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
+	} else if m.ViewMode == types.GranularView {
+		// Navigate back to phrase view - return to the original column
+		switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
 	} else if m.ViewMode == types.ModulateView {
 		// Navigate back to phrase view - return to the original column
 		switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
@@ -807,6 +956,39 @@ func handleShiftLeft(m *model.Model) tea.Cmd {
 	} else if m.ViewMode == types.DuckingView {
 		// Navigate back to phrase view - use saved column position
 		switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
+	} else if m.ViewMode == types.SliceEditorView {
+		// Navigate back to file metadata view
+		CloseSliceEditor(m)
+	} else if m.ViewMode == types.MultisampleView {
+		// Navigate back to SoundMaker view
+		CloseMultisampleEditor(m)
+	} else if m.ViewMode == types.LibraryView {
+		// Navigate back to file view
+		CloseLibrary(m)
+	} else if m.ViewMode == types.ProjectFilesView {
+		// Navigate back to Preferences view
+		CloseProjectFiles(m)
+	} else if m.ViewMode == types.SampleToolsView {
+		// Navigate back to file view
+		CloseSampleTools(m)
+	} else if m.ViewMode == types.PresetView {
+		// Navigate back to SoundMaker view
+		ClosePresets(m)
+	} else if m.ViewMode == types.LFOView {
+		// Navigate back to mixer view
+		CloseLFOs(m)
+	} else if m.ViewMode == types.AutomationView {
+		// Navigate back to the phrase or chain
+		CloseAutomation(m)
+	} else if m.ViewMode == types.ParameterLockView {
+		// Navigate back to phrase view
+		CloseParameterLocks(m)
+	} else if m.ViewMode == types.InsertFXView {
+		// Navigate back to phrase view
+		CloseInsertFX(m)
+	} else if m.ViewMode == types.ReturnsView {
+		// Navigate back to mixer view
+		CloseReturns(m)
 	}
 	return nil
 }
@@ -831,6 +1013,9 @@ func handleUp(m *model.Model) tea.Cmd {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
 		}
+	} else if m.ViewMode == types.SliceEditorView {
+		// Up zooms in on the selected marker
+		ZoomSliceEditor(m, 1)
 	} else if m.ViewMode == types.RetriggerView {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
@@ -839,6 +1024,10 @@ func handleUp(m *model.Model) tea.Cmd {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
 		}
+	} else if m.ViewMode == types.GranularView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+		}
 	} else if m.ViewMode == types.ModulateView {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
@@ -850,11 +1039,8 @@ func handleUp(m *model.Model) tea.Cmd {
 	} else if m.ViewMode == types.MidiView {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
-			if m.CurrentRow < m.ScrollOffset {
-				m.ScrollOffset = m.CurrentRow
-			}
 		}
-	} else if m.ViewMode == types.SoundMakerView {
+	} else if m.ViewMode == types.SoundMakerView || m.ViewMode == types.MultisampleView {
 		if m.CurrentRow > 0 {
 			m.CurrentRow = m.CurrentRow - 1
 			if m.CurrentRow < m.ScrollOffset {
@@ -881,6 +1067,42 @@ func handleUp(m *model.Model) tea.Cmd {
 				m.ScrollOffset = m.CurrentRow
 			}
 		}
+	} else if m.ViewMode == types.LibraryView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+			keepLibraryRowVisible(m)
+		}
+	} else if m.ViewMode == types.ProjectFilesView {
+		moveProjectFilesRow(m, -1)
+	} else if m.ViewMode == types.SampleToolsView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+		}
+	} else if m.ViewMode == types.PresetView {
+		m.CurrentRow = m.CurrentRow - 1
+		clampPresetCursor(m)
+	} else if m.ViewMode == types.LFOView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+		}
+	} else if m.ViewMode == types.AutomationView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+		}
+	} else if m.ViewMode == types.ParameterLockView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+		}
+	} else if m.ViewMode == types.InsertFXView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+			m.CurrentCol = min(m.CurrentCol, insertFXColCount(m, m.CurrentRow)-1)
+		}
+	} else if m.ViewMode == types.ReturnsView {
+		if m.CurrentRow > 0 {
+			m.CurrentRow = m.CurrentRow - 1
+			m.CurrentCol = min(m.CurrentCol, len(types.ReturnModules[m.CurrentRow].Parameters)-1)
+		}
 	} else if m.ViewMode == types.PhraseView {
 		// In Instrument view, allow going to row -1 (header) for SO/MI column and CC columns (when in MI mode)
 		if m.GetPhraseViewType() == types.InstrumentPhraseView {
@@ -953,20 +1175,16 @@ func handleDown(m *model.Model) tea.Cmd {
 			m.CurrentRow = m.CurrentRow + 1
 		}
 	} else if m.ViewMode == types.SettingsView {
-		// Column 0 (Global): BPM to Shimmer, Column 1 (Input): InputLevelDB to ReverbSendPercent
-		var maxRow int
-		if m.CurrentCol == 0 {
-			maxRow = int(types.GlobalSettingsRowShimmerPercent) // Global column: BPM(0) to Shimmer(8)
-		} else {
-			maxRow = int(types.InputSettingsRowReverbSendPercent) // Input column: InputLevelDB(0) to ReverbSendPercent(1)
-		}
-		if m.CurrentRow < maxRow {
+		if m.CurrentRow < settingsColumnMaxRow(m.CurrentCol) {
 			m.CurrentRow = m.CurrentRow + 1
 		}
 	} else if m.ViewMode == types.FileMetadataView {
-		if m.CurrentRow < int(types.FileMetadataRowSyncToBPM) { // BPM(0) to SyncToBPM(3)
+		if m.CurrentRow < int(types.FileMetadataRowLoopXfade) { // BPM(0) to LoopXfade(7)
 			m.CurrentRow = m.CurrentRow + 1
 		}
+	} else if m.ViewMode == types.SliceEditorView {
+		// Down zooms out
+		ZoomSliceEditor(m, -1)
 	} else if m.ViewMode == types.RetriggerView {
 		if m.CurrentRow < int(types.RetriggerSettingsRowProbability) { // Times(0) to Probability(9)
 			m.CurrentRow = m.CurrentRow + 1
@@ -975,23 +1193,23 @@ func handleDown(m *model.Model) tea.Cmd {
 		if m.CurrentRow < int(types.TimestrechSettingsRowProbability) { // Start(0) to Probability(4)
 			m.CurrentRow = m.CurrentRow + 1
 		}
+	} else if m.ViewMode == types.GranularView {
+		if m.CurrentRow < int(types.GranularSettingsRowSpray) { // Size(0) to Spray(5)
+			m.CurrentRow = m.CurrentRow + 1
+		}
 	} else if m.ViewMode == types.ModulateView {
 		if m.CurrentRow < int(types.ModulateSettingsRowProbability) { // Seed(0) to Probability(6)
 			m.CurrentRow = m.CurrentRow + 1
 		}
 	} else if m.ViewMode == types.ArpeggioView {
-		if m.CurrentRow < 15 { // 0-15 (16 rows total)
+		if m.CurrentRow < types.ArpeggioLatchRow { // 16 rows (0-15), then the latch setting
 			m.CurrentRow = m.CurrentRow + 1
 		}
 	} else if m.ViewMode == types.MidiView {
-		// Calculate maximum row: 2 settings rows + available MIDI devices
-		maxRow := int(types.MidiSettingsRowChannel) + len(m.AvailableMidiDevices) // Device(0), Channel(1), then devices starting at row 2
+		// Calculate maximum row: settings rows + available MIDI devices (the view scrolls the devices)
+		maxRow := int(types.MidiSettingsRowFirstDevice) + len(m.AvailableMidiDevices) - 1 // Settings, then devices
 		if m.CurrentRow < maxRow {
 			m.CurrentRow = m.CurrentRow + 1
-			visibleRows := m.GetVisibleRows()
-			if m.CurrentRow >= m.ScrollOffset+visibleRows {
-				m.ScrollOffset = m.CurrentRow - visibleRows + 1
-			}
 		}
 	} else if m.ViewMode == types.SoundMakerView {
 		// Calculate maximum row based on current instrument parameters
@@ -1010,6 +1228,15 @@ func handleDown(m *model.Model) tea.Cmd {
 				m.ScrollOffset = m.CurrentRow - visibleRows + 1
 			}
 		}
+	} else if m.ViewMode == types.MultisampleView {
+		// Zones plus the row that adds a new zone
+		if m.CurrentRow < MultisampleMaxRow(m) {
+			m.CurrentRow = m.CurrentRow + 1
+			visibleRows := m.GetVisibleRows()
+			if m.CurrentRow >= m.ScrollOffset+visibleRows {
+				m.ScrollOffset = m.CurrentRow - visibleRows + 1
+			}
+		}
 	} else if m.ViewMode == types.DuckingView {
 		// Get current ducking settings to check type
 		settings := m.DuckingSettings[m.DuckingEditingIndex]
@@ -1023,8 +1250,10 @@ func handleDown(m *model.Model) tea.Cmd {
 			m.CurrentRow = m.CurrentRow + 1
 		}
 	} else if m.ViewMode == types.MixerView {
-		// Only row 0 (set level) exists now, no navigation needed
-		// Keep CurrentMixerRow at 0
+		// The set level, then the sends of the track (the Input track has none)
+		if m.CurrentMixerTrack < 8 && m.CurrentMixerRow < types.MixerRowSend+types.ReturnCount-1 {
+			m.CurrentMixerRow = m.CurrentMixerRow + 1
+		}
 	} else if m.ViewMode == types.FileView {
 		// Ensure we don't go beyond the last file
 		if len(m.Files) > 0 && m.CurrentRow < len(m.Files)-1 {
@@ -1034,6 +1263,42 @@ func handleDown(m *model.Model) tea.Cmd {
 				m.ScrollOffset = m.CurrentRow - visibleRows + 1
 			}
 		}
+	} else if m.ViewMode == types.LibraryView {
+		if m.CurrentRow < len(m.LibraryResults)-1 {
+			m.CurrentRow = m.CurrentRow + 1
+			keepLibraryRowVisible(m)
+		}
+	} else if m.ViewMode == types.ProjectFilesView {
+		moveProjectFilesRow(m, 1)
+	} else if m.ViewMode == types.SampleToolsView {
+		if m.CurrentRow < int(types.SampleToolCount)-1 {
+			m.CurrentRow = m.CurrentRow + 1
+		}
+	} else if m.ViewMode == types.PresetView {
+		m.CurrentRow = m.CurrentRow + 1
+		clampPresetCursor(m)
+	} else if m.ViewMode == types.LFOView {
+		if m.CurrentRow < types.LFOCount-1 {
+			m.CurrentRow = m.CurrentRow + 1
+		}
+	} else if m.ViewMode == types.AutomationView {
+		if m.CurrentRow < AutomationMaxRow(m) {
+			m.CurrentRow = m.CurrentRow + 1
+		}
+	} else if m.ViewMode == types.ParameterLockView {
+		if def, ok := ParameterLockDefinition(m); ok && m.CurrentRow < len(def.Parameters)-1 {
+			m.CurrentRow = m.CurrentRow + 1
+		}
+	} else if m.ViewMode == types.InsertFXView {
+		if m.CurrentRow < InsertFXRowCount(m)-1 {
+			m.CurrentRow = m.CurrentRow + 1
+			m.CurrentCol = min(m.CurrentCol, insertFXColCount(m, m.CurrentRow)-1)
+		}
+	} else if m.ViewMode == types.ReturnsView {
+		if m.CurrentRow < types.ReturnCount-1 {
+			m.CurrentRow = m.CurrentRow + 1
+			m.CurrentCol = min(m.CurrentCol, len(types.ReturnModules[m.CurrentRow].Parameters)-1)
+		}
 	} else if m.ViewMode == types.PhraseView {
 		// In Instrument view, allow special behavior for SO/MI column header
 		if m.GetPhraseViewType() == types.InstrumentPhraseView && m.CurrentCol == int(types.InstrumentColSOMI) {
@@ -1102,7 +1367,15 @@ func handleLeft(m *model.Model) tea.Cmd {
 			storage.AutoSave(m)
 		}
 	} else if m.ViewMode == types.ArpeggioView {
-		if m.CurrentCol > int(types.ArpeggioColDI) { // 3 columns: DI, CO, Divisor
+		if m.CurrentCol > int(types.ArpeggioColDI) { // DI is the first column
+			m.CurrentCol = m.CurrentCol - 1
+			storage.AutoSave(m)
+		}
+	} else if m.ViewMode == types.SliceEditorView {
+		// Select previous marker (trim start is the first)
+		SelectSliceMarker(m, -1)
+	} else if m.ViewMode == types.MultisampleView {
+		if m.CurrentCol > int(types.MultisampleColFile) {
 			m.CurrentCol = m.CurrentCol - 1
 			storage.AutoSave(m)
 		}
@@ -1124,17 +1397,34 @@ func handleLeft(m *model.Model) tea.Cmd {
 			m.ScrollOffset = 0
 			storage.AutoSave(m)
 		}
+	} else if m.ViewMode == types.PresetView {
+		// Browse the presets of the previous SoundMaker type
+		CyclePresetSoundMaker(m, -1)
+	} else if m.ViewMode == types.LFOView {
+		if m.CurrentCol > 0 {
+			m.CurrentCol = m.CurrentCol - 1
+		}
+	} else if m.ViewMode == types.AutomationView {
+		if m.CurrentCol > 0 {
+			m.CurrentCol = m.CurrentCol - 1
+		}
+	} else if m.ViewMode == types.InsertFXView {
+		if m.CurrentCol > 0 {
+			m.CurrentCol = m.CurrentCol - 1
+		}
+	} else if m.ViewMode == types.ReturnsView {
+		if m.CurrentCol > 0 {
+			m.CurrentCol = m.CurrentCol - 1
+		}
 	} else if m.ViewMode == types.MidiView {
 		// No horizontal navigation in MIDI view - use up/down for settings
 	} else if m.ViewMode == types.SoundMakerView {
 		// No horizontal navigation in SoundMaker view - use up/down for settings
 	} else if m.ViewMode == types.SettingsView {
-		if m.CurrentCol > 0 { // Switch between Global (0) and Input (1) columns
+		if m.CurrentCol > 0 { // Switch between Global (0), Input (1) and Tuning (2) columns
 			m.CurrentCol = m.CurrentCol - 1
 			// Adjust row if it's beyond the bounds of the new column
-			if m.CurrentCol == 0 && m.CurrentRow > int(types.GlobalSettingsRowShimmerPercent) {
-				m.CurrentRow = int(types.GlobalSettingsRowShimmerPercent) // Global column max is 8
-			}
+			m.CurrentRow = min(m.CurrentRow, settingsColumnMaxRow(m.CurrentCol))
 			storage.AutoSave(m)
 		}
 	} else if m.ViewMode == types.MixerView {
@@ -1165,7 +1455,10 @@ func handleRight(m *model.Model) tea.Cmd {
 		phraseViewType := m.GetPhraseViewType()
 		var maxValidCol int
 		if phraseViewType == types.InstrumentPhraseView {
-			maxValidCol = int(types.InstrumentColDU) // Instrument: last valid column is DU (Ducking)
+			maxValidCol = int(types.InstrumentColFV) // Instrument: last valid column is FV (Insert FX value)
+			if m.SOColumnMode == types.SOModeMIDI {
+				maxValidCol = int(types.InstrumentColAT) // MI mode adds pitch bend and aftertouch
+			}
 		} else {
 			maxValidCol = int(types.SamplerColFI) // Sampler: last valid column is FI (Filename)
 		}
@@ -1175,7 +1468,15 @@ func handleRight(m *model.Model) tea.Cmd {
 			storage.AutoSave(m)
 		}
 	} else if m.ViewMode == types.ArpeggioView {
-		if m.CurrentCol < int(types.ArpeggioColDIV) { // 3 columns: DI, CO, Divisor
+		if m.CurrentCol < int(types.ArpeggioColCount)-1 { // RT is the last column
+			m.CurrentCol = m.CurrentCol + 1
+			storage.AutoSave(m)
+		}
+	} else if m.ViewMode == types.SliceEditorView {
+		// Select next marker (trim end is the last)
+		SelectSliceMarker(m, 1)
+	} else if m.ViewMode == types.MultisampleView {
+		if m.CurrentCol < int(types.MultisampleColCount)-1 {
 			m.CurrentCol = m.CurrentCol + 1
 			storage.AutoSave(m)
 		}
@@ -1200,22 +1501,42 @@ func handleRight(m *model.Model) tea.Cmd {
 				storage.AutoSave(m)
 			}
 		}
+	} else if m.ViewMode == types.PresetView {
+		// Browse the presets of the next SoundMaker type
+		CyclePresetSoundMaker(m, 1)
+	} else if m.ViewMode == types.LFOView {
+		if m.CurrentCol < int(types.LFOColCount)-1 {
+			m.CurrentCol = m.CurrentCol + 1
+		}
+	} else if m.ViewMode == types.AutomationView {
+		if m.CurrentCol < int(types.AutomationColCount)-1 {
+			m.CurrentCol = m.CurrentCol + 1
+		}
+	} else if m.ViewMode == types.InsertFXView {
+		if m.CurrentCol < insertFXColCount(m, m.CurrentRow)-1 {
+			m.CurrentCol = m.CurrentCol + 1
+		}
+	} else if m.ViewMode == types.ReturnsView {
+		if m.CurrentCol < len(types.ReturnModules[m.CurrentRow].Parameters)-1 {
+			m.CurrentCol = m.CurrentCol + 1
+		}
 	} else if m.ViewMode == types.MidiView {
 		// No horizontal navigation in MIDI view - use up/down for settings
 	} else if m.ViewMode == types.SoundMakerView {
 		// No horizontal navigation in SoundMaker view - use up/down for settings
 	} else if m.ViewMode == types.SettingsView {
-		if m.CurrentCol < 1 { // Switch between Global (0) and Input (1) columns
+		if m.CurrentCol < 2 { // Switch between Global (0), Input (1) and Tuning (2) columns
 			m.CurrentCol = m.CurrentCol + 1
 			// Adjust row if it's beyond the bounds of the new column
-			if m.CurrentCol == 1 && m.CurrentRow > 1 {
-				m.CurrentRow = 1 // Input column max is 1
-			}
+			m.CurrentRow = min(m.CurrentRow, settingsColumnMaxRow(m.CurrentCol))
 			storage.AutoSave(m)
 		}
 	} else if m.ViewMode == types.MixerView {
 		if m.CurrentMixerTrack < 8 { // Select next track (0-8, including Input track)
 			m.CurrentMixerTrack = m.CurrentMixerTrack + 1
+			if m.CurrentMixerTrack == 8 {
+				m.CurrentMixerRow = types.MixerRowSetLevel // The Input track has no sends
+			}
 			storage.AutoSave(m)
 		}
 	} else { // FileView
@@ -1247,10 +1568,9 @@ func handleCtrlUp(m *model.Model) tea.Cmd {
 				storage.AutoSave(m)
 			} else if m.SOColumnMode == types.SOModeMIDI {
 				// Modify CC number for CC columns
-				ccIndex := getCCColumnIndex(m.CurrentCol)
+				ccIndex := GetCCColumnIndex(m.CurrentCol)
 				if ccIndex != -1 {
-					m.MidiCCNumbers[ccIndex] = clampInt(m.MidiCCNumbers[ccIndex]+16, 0, 127)
-					storage.AutoSave(m)
+					modifyCCNumber(m, ccIndex, 16)
 				}
 			}
 		} else {
@@ -1261,10 +1581,14 @@ func handleCtrlUp(m *model.Model) tea.Cmd {
 		ModifySettingsValue(m, 1.0)
 	} else if m.ViewMode == types.FileMetadataView {
 		ModifyFileMetadataValue(m, 1.0)
+	} else if m.ViewMode == types.SliceEditorView {
+		MoveSliceMarker(m, 1.0)
 	} else if m.ViewMode == types.RetriggerView {
 		ModifyRetriggerValue(m, 1.0)
 	} else if m.ViewMode == types.TimestrechView {
 		ModifyTimestrechValue(m, 1.0)
+	} else if m.ViewMode == types.GranularView {
+		ModifyGranularValue(m, 1.0)
 	} else if m.ViewMode == types.ModulateView {
 		ModifyModulateValue(m, 1.0)
 	} else if m.ViewMode == types.ArpeggioView {
@@ -1273,13 +1597,29 @@ func handleCtrlUp(m *model.Model) tea.Cmd {
 		ModifyMidiValue(m, 1.0)
 	} else if m.ViewMode == types.SoundMakerView {
 		ModifySoundMakerValue(m, 1.0)
+	} else if m.ViewMode == types.MultisampleView {
+		ModifyMultisampleZoneValue(m, 1.0)
+	} else if m.ViewMode == types.SampleToolsView {
+		ModifySampleToolValue(m, 1.0)
+	} else if m.ViewMode == types.LFOView {
+		ModifyLFOValue(m, 1.0)
+	} else if m.ViewMode == types.AutomationView {
+		ModifyAutomationValue(m, 1.0)
+	} else if m.ViewMode == types.ParameterLockView {
+		ModifyParameterLockValue(m, 1.0)
+	} else if m.ViewMode == types.InsertFXView {
+		ModifyInsertFXValue(m, 1.0)
+	} else if m.ViewMode == types.ReturnsView {
+		ModifyReturnValue(m, 1.0)
 	} else if m.ViewMode == types.DuckingView {
 		ModifyDuckingValue(m, 1.0)
 	} else if m.ViewMode == types.MixerView {
-		if m.CurrentMixerRow == 0 {
+		if m.CurrentMixerRow == types.MixerRowSetLevel {
 			ModifyMixerSetLevel(m, 1.0) // Coarse increment for set level
+		} else {
+			ModifyMixerSend(m, 1.0)
 		}
-	} else if m.ViewMode != types.FileView {
+	} else if m.ViewMode == types.ChainView {
 		ModifyValue(m, 16)
 	}
 	return nil
@@ -1303,10 +1643,9 @@ func handleCtrlDown(m *model.Model) tea.Cmd {
 				storage.AutoSave(m)
 			} else if m.SOColumnMode == types.SOModeMIDI {
 				// Modify CC number for CC columns
-				ccIndex := getCCColumnIndex(m.CurrentCol)
+				ccIndex := GetCCColumnIndex(m.CurrentCol)
 				if ccIndex != -1 {
-					m.MidiCCNumbers[ccIndex] = clampInt(m.MidiCCNumbers[ccIndex]-16, 0, 127)
-					storage.AutoSave(m)
+					modifyCCNumber(m, ccIndex, -16)
 				}
 			}
 		} else {
@@ -1317,10 +1656,14 @@ func handleCtrlDown(m *model.Model) tea.Cmd {
 		ModifySettingsValue(m, -1.0)
 	} else if m.ViewMode == types.FileMetadataView {
 		ModifyFileMetadataValue(m, -1.0)
+	} else if m.ViewMode == types.SliceEditorView {
+		MoveSliceMarker(m, -1.0)
 	} else if m.ViewMode == types.RetriggerView {
 		ModifyRetriggerValue(m, -1.0)
 	} else if m.ViewMode == types.TimestrechView {
 		ModifyTimestrechValue(m, -1.0)
+	} else if m.ViewMode == types.GranularView {
+		ModifyGranularValue(m, -1.0)
 	} else if m.ViewMode == types.ModulateView {
 		ModifyModulateValue(m, -1.0)
 	} else if m.ViewMode == types.ArpeggioView {
@@ -1329,13 +1672,29 @@ func handleCtrlDown(m *model.Model) tea.Cmd {
 		ModifyMidiValue(m, -1.0)
 	} else if m.ViewMode == types.SoundMakerView {
 		ModifySoundMakerValue(m, -1.0)
+	} else if m.ViewMode == types.MultisampleView {
+		ModifyMultisampleZoneValue(m, -1.0)
+	} else if m.ViewMode == types.SampleToolsView {
+		ModifySampleToolValue(m, -1.0)
+	} else if m.ViewMode == types.LFOView {
+		ModifyLFOValue(m, -1.0)
+	} else if m.ViewMode == types.AutomationView {
+		ModifyAutomationValue(m, -1.0)
+	} else if m.ViewMode == types.ParameterLockView {
+		ModifyParameterLockValue(m, -1.0)
+	} else if m.ViewMode == types.InsertFXView {
+		ModifyInsertFXValue(m, -1.0)
+	} else if m.ViewMode == types.ReturnsView {
+		ModifyReturnValue(m, -1.0)
 	} else if m.ViewMode == types.DuckingView {
 		ModifyDuckingValue(m, -1.0)
 	} else if m.ViewMode == types.MixerView {
-		if m.CurrentMixerRow == 0 {
+		if m.CurrentMixerRow == types.MixerRowSetLevel {
 			ModifyMixerSetLevel(m, -1.0) // Coarse decrement for set level
+		} else {
+			ModifyMixerSend(m, -1.0)
 		}
-	} else if m.ViewMode != types.FileView {
+	} else if m.ViewMode == types.ChainView {
 		ModifyValue(m, -16)
 	}
 	return nil
@@ -1358,10 +1717,9 @@ func handleCtrlLeft(m *model.Model) tea.Cmd {
 				storage.AutoSave(m)
 			} else if m.SOColumnMode == types.SOModeMIDI {
 				// Modify CC number for CC columns
-				ccIndex := getCCColumnIndex(m.CurrentCol)
+				ccIndex := GetCCColumnIndex(m.CurrentCol)
 				if ccIndex != -1 {
-					m.MidiCCNumbers[ccIndex] = clampInt(m.MidiCCNumbers[ccIndex]-1, 0, 127)
-					storage.AutoSave(m)
+					modifyCCNumber(m, ccIndex, -1)
 				}
 			}
 		} else {
@@ -1372,10 +1730,14 @@ func handleCtrlLeft(m *model.Model) tea.Cmd {
 		ModifySettingsValue(m, -0.05)
 	} else if m.ViewMode == types.FileMetadataView {
 		ModifyFileMetadataValue(m, -0.05)
+	} else if m.ViewMode == types.SliceEditorView {
+		MoveSliceMarker(m, -0.05)
 	} else if m.ViewMode == types.RetriggerView {
 		ModifyRetriggerValue(m, -0.05)
 	} else if m.ViewMode == types.TimestrechView {
 		ModifyTimestrechValue(m, -0.05)
+	} else if m.ViewMode == types.GranularView {
+		ModifyGranularValue(m, -0.05)
 	} else if m.ViewMode == types.ModulateView {
 		ModifyModulateValue(m, -0.05)
 	} else if m.ViewMode == types.ArpeggioView {
@@ -1384,13 +1746,29 @@ func handleCtrlLeft(m *model.Model) tea.Cmd {
 		ModifyMidiValue(m, -0.05)
 	} else if m.ViewMode == types.SoundMakerView {
 		ModifySoundMakerValue(m, -0.05)
+	} else if m.ViewMode == types.MultisampleView {
+		ModifyMultisampleZoneValue(m, -0.05)
+	} else if m.ViewMode == types.SampleToolsView {
+		ModifySampleToolValue(m, -0.05)
+	} else if m.ViewMode == types.LFOView {
+		ModifyLFOValue(m, -0.05)
+	} else if m.ViewMode == types.AutomationView {
+		ModifyAutomationValue(m, -0.05)
+	} else if m.ViewMode == types.ParameterLockView {
+		ModifyParameterLockValue(m, -0.05)
+	} else if m.ViewMode == types.InsertFXView {
+		ModifyInsertFXValue(m, -0.05)
+	} else if m.ViewMode == types.ReturnsView {
+		ModifyReturnValue(m, -0.05)
 	} else if m.ViewMode == types.DuckingView {
 		ModifyDuckingValue(m, -0.05)
 	} else if m.ViewMode == types.MixerView {
-		if m.CurrentMixerRow == 0 {
+		if m.CurrentMixerRow == types.MixerRowSetLevel {
 			ModifyMixerSetLevel(m, -0.05) // Fine decrement for set level
+		} else {
+			ModifyMixerSend(m, -0.05)
 		}
-	} else if m.ViewMode != types.FileView {
+	} else if m.ViewMode == types.ChainView {
 		ModifyValue(m, -1)
 	}
 	return nil
@@ -1413,10 +1791,9 @@ func handleCtrlRight(m *model.Model) tea.Cmd {
 				storage.AutoSave(m)
 			} else if m.SOColumnMode == types.SOModeMIDI {
 				// Modify CC number for CC columns
-				ccIndex := getCCColumnIndex(m.CurrentCol)
+				ccIndex := GetCCColumnIndex(m.CurrentCol)
 				if ccIndex != -1 {
-					m.MidiCCNumbers[ccIndex] = clampInt(m.MidiCCNumbers[ccIndex]+1, 0, 127)
-					storage.AutoSave(m)
+					modifyCCNumber(m, ccIndex, 1)
 				}
 			}
 		} else {
@@ -1425,14 +1802,20 @@ func handleCtrlRight(m *model.Model) tea.Cmd {
 		}
 	} else if m.ViewMode == types.FileView {
 		audio.PlayFile(m)
+	} else if m.ViewMode == types.LibraryView {
+		PlayLibraryEntry(m)
 	} else if m.ViewMode == types.SettingsView {
 		ModifySettingsValue(m, 0.05)
 	} else if m.ViewMode == types.FileMetadataView {
 		ModifyFileMetadataValue(m, 0.05)
+	} else if m.ViewMode == types.SliceEditorView {
+		MoveSliceMarker(m, 0.05)
 	} else if m.ViewMode == types.RetriggerView {
 		ModifyRetriggerValue(m, 0.05)
 	} else if m.ViewMode == types.TimestrechView {
 		ModifyTimestrechValue(m, 0.05)
+	} else if m.ViewMode == types.GranularView {
+		ModifyGranularValue(m, 0.05)
 	} else if m.ViewMode == types.ModulateView {
 		ModifyModulateValue(m, 0.05)
 	} else if m.ViewMode == types.ArpeggioView {
@@ -1441,20 +1824,37 @@ func handleCtrlRight(m *model.Model) tea.Cmd {
 		ModifyMidiValue(m, 0.05)
 	} else if m.ViewMode == types.SoundMakerView {
 		ModifySoundMakerValue(m, 0.05)
+	} else if m.ViewMode == types.MultisampleView {
+		ModifyMultisampleZoneValue(m, 0.05)
+	} else if m.ViewMode == types.SampleToolsView {
+		ModifySampleToolValue(m, 0.05)
+	} else if m.ViewMode == types.LFOView {
+		ModifyLFOValue(m, 0.05)
+	} else if m.ViewMode == types.AutomationView {
+		ModifyAutomationValue(m, 0.05)
+	} else if m.ViewMode == types.ParameterLockView {
+		ModifyParameterLockValue(m, 0.05)
+	} else if m.ViewMode == types.InsertFXView {
+		ModifyInsertFXValue(m, 0.05)
+	} else if m.ViewMode == types.ReturnsView {
+		ModifyReturnValue(m, 0.05)
 	} else if m.ViewMode == types.DuckingView {
 		ModifyDuckingValue(m, 0.05)
 	} else if m.ViewMode == types.MixerView {
-		if m.CurrentMixerRow == 0 {
+		if m.CurrentMixerRow == types.MixerRowSetLevel {
 			ModifyMixerSetLevel(m, 0.05) // Fine increment for set level
+		} else {
+			ModifyMixerSend(m, 0.05)
 		}
-	} else {
+	} else if m.ViewMode == types.ChainView {
 		ModifyValue(m, 1)
 	}
 	return nil
 }
 
 func handleS(m *model.Model) tea.Cmd {
-	if m.ViewMode != types.FileView && m.ViewMode != types.SettingsView && m.ViewMode != types.FileMetadataView {
+	// Only chain and phrase rows are pasted
+	if m.ViewMode == types.ChainView || m.ViewMode == types.PhraseView {
 		PasteLastEditedRow(m)
 		storage.AutoSave(m)
 	}
@@ -1472,6 +1872,8 @@ func handleC(m *model.Model) tea.Cmd {
 		if m.RecordingActive {
 			stopRecording(m)
 		}
+		cancelBounce(m)
+		m.CancelAllArpeggios()
 
 		// Clear file browser playback state when stopping tracker playback
 		if m.CurrentlyPlayingFile != "" {
@@ -1482,6 +1884,8 @@ func handleC(m *model.Model) tea.Cmd {
 
 		// Send OSC "/stop" with no params (tiny helper on the model)
 		m.SendStopOSC()
+		m.StopMidi()
+		m.SendOSCInsertFXMessages() // Back to the saved values the FX columns changed
 
 		log.Printf("Playback stopped via 'C'")
 		return nil
@@ -1570,17 +1974,28 @@ func handleC(m *model.Model) tea.Cmd {
 		EmitLastSelectedPhraseRowData(m)
 	} else if m.ViewMode == types.TimestrechView {
 		EmitLastSelectedPhraseRowData(m)
+	} else if m.ViewMode == types.GranularView {
+		EmitLastSelectedPhraseRowData(m)
 	} else if m.ViewMode == types.ModulateView {
 		EmitLastSelectedPhraseRowData(m)
 	} else if m.ViewMode == types.ArpeggioView {
 		// Play the last edited phrase row from Instrument view
 		EmitLastSelectedPhraseRowData(m)
-	} else if m.ViewMode == types.SoundMakerView {
+	} else if m.ViewMode == types.SoundMakerView || m.ViewMode == types.MultisampleView {
 		// Play the last edited phrase row from Phrase view
 		EmitLastSelectedPhraseRowData(m)
 	} else if m.ViewMode == types.FileView || m.ViewMode == types.FileMetadataView {
 		// Play the last edited phrase row (same as sampler in phrase view)
 		EmitLastSelectedPhraseRowData(m)
+	} else if m.ViewMode == types.SliceEditorView {
+		// Audition the slice starting at the selected marker
+		AuditionSlice(m)
+	} else if m.ViewMode == types.SampleToolsView {
+		// Play the file being edited
+		audio.PlayFilePath(m, m.SampleToolsFile)
+	} else if m.ViewMode == types.PresetView {
+		// Audition the selected preset
+		AuditionSelectedPreset(m)
 	}
 	return nil
 }
@@ -1611,12 +2026,22 @@ func handleCtrlD(m *model.Model) tea.Cmd {
 
 func handleSpace(m *model.Model) tea.Cmd {
 	if m.ViewMode == types.FileView {
-		audio.SelectFile(m)
+		SelectFile(m)
+		return nil
+	} else if m.ViewMode == types.SampleToolsView {
+		// Run the selected tool
+		ApplySampleTool(m)
+		return nil
+	} else if m.ViewMode == types.PresetView {
+		// Load the selected preset into the SoundMaker
+		LoadSelectedPreset(m)
+		storage.AutoSave(m)
 		return nil
 	} else if m.ViewMode == types.MidiView {
 		// Handle device selection in MIDI view
-		if m.CurrentRow >= 2 && m.CurrentRow-2+m.ScrollOffset < len(m.AvailableMidiDevices) {
-			deviceIndex := m.CurrentRow - 2 + m.ScrollOffset
+		firstDevice := int(types.MidiSettingsRowFirstDevice)
+		if m.CurrentRow >= firstDevice && m.CurrentRow-firstDevice < len(m.AvailableMidiDevices) {
+			deviceIndex := m.CurrentRow - firstDevice
 			selectedDevice := m.AvailableMidiDevices[deviceIndex]
 			m.MidiSettings[m.MidiEditingIndex].Device = selectedDevice
 			log.Printf("Selected MIDI device: %s for MIDI %02X", selectedDevice, m.MidiEditingIndex)
@@ -1634,7 +2059,7 @@ func handleSpace(m *model.Model) tea.Cmd {
 			storage.AutoSave(m)
 		}
 		return nil
-	} else if m.ViewMode != types.SettingsView && m.ViewMode != types.FileMetadataView {
+	} else if m.ViewMode != types.SettingsView && m.ViewMode != types.FileMetadataView && m.ViewMode != types.SliceEditorView {
 		return TogglePlayback(m)
 	}
 	return nil
@@ -1682,21 +2107,28 @@ func handleBackspace(m *model.Model) tea.Cmd {
 		}
 	} else if m.ViewMode == types.ArpeggioView {
 		// Clear the current cell in arpeggio view
-		settings := &m.ArpeggioSettings[m.ArpeggioEditingIndex]
-		currentRow := &settings.Rows[m.CurrentRow]
-
-		switch m.CurrentCol {
-		case 0: // Direction column
-			currentRow.Direction = int(types.ArpeggioDirectionNone) // Clear to "--"
-			log.Printf("Cleared arpeggio %02X row %02X Direction", m.ArpeggioEditingIndex, m.CurrentRow)
-		case 1: // Count column
-			currentRow.Count = -1 // Clear to "--"
-			log.Printf("Cleared arpeggio %02X row %02X Count", m.ArpeggioEditingIndex, m.CurrentRow)
-		case 2: // Divisor column
-			currentRow.Divisor = -1 // Clear to "--"
-			log.Printf("Cleared arpeggio %02X row %02X Divisor", m.ArpeggioEditingIndex, m.CurrentRow)
-		}
-		storage.AutoSave(m)
+		ClearArpeggioCell(m)
+	} else if m.ViewMode == types.SliceEditorView {
+		// Reset trim points and slice markers to equal slicing
+		ResetSliceMarkers(m)
+	} else if m.ViewMode == types.MultisampleView {
+		// Remove the selected zone
+		DeleteMultisampleZone(m)
+	} else if m.ViewMode == types.ProjectFilesView {
+		// Delete, merge or drop the selected file
+		ResolveProjectFileIssue(m)
+	} else if m.ViewMode == types.LFOView {
+		// Turn the selected LFO off
+		ClearLFO(m)
+	} else if m.ViewMode == types.AutomationView {
+		// Remove the selected breakpoint or lane
+		DeleteAutomationRow(m)
+	} else if m.ViewMode == types.ParameterLockView {
+		// Remove the lock of the selected parameter
+		ClearParameterLock(m)
+	} else if m.ViewMode == types.InsertFXView {
+		// Remove the selected module
+		RemoveInsertFXSlot(m)
 	}
 	return nil
 }
@@ -1720,7 +2152,12 @@ func handleCtrlH(m *model.Model) tea.Cmd {
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColGate)] = -1          // Clear gate (displays "--", behaves as 80)
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColRetrigger)] = -1     // Clear retrigger
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColEffectDucking)] = -1 // Clear ducking
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColInsertFX)] = -1      // Clear insert FX
+		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColInsertFXValue)] = -1 // Clear insert FX value
 		(*phrasesData)[m.CurrentPhrase][m.CurrentRow][int(types.ColFilename)] = -1      // Clear filename
+		if phraseViewType == types.InstrumentPhraseView {
+			m.ParameterLocks.ClearRow(m.CurrentPhrase, m.CurrentRow) // Clear parameter locks
+		}
 		log.Printf("Deleted phrase %d row %d (cleared all columns)", m.CurrentPhrase, m.CurrentRow)
 		storage.AutoSave(m)
 	}
@@ -1786,6 +2223,11 @@ func stopRecording(m *model.Model) {
 }
 
 func handleCtrlF(m *model.Model) tea.Cmd {
+	if m.ViewMode == types.ProjectFilesView {
+		// Resolve every issue that can be resolved
+		ResolveAllProjectFileIssues(m)
+		return nil
+	}
 	FillSequential(m)
 	storage.AutoSave(m)
 	return nil
@@ -1807,6 +2249,9 @@ func handleM(m *model.Model) tea.Cmd {
 	if m.ViewMode == types.MixerView {
 		// If we're in mixer view, act like Shift+Up (go back to previous view)
 		return handleShiftUp(m)
+	} else if m.ViewMode == types.LFOView || m.ViewMode == types.ReturnsView {
+		// Back to the mixer the LFOs or returns were opened from
+		return handleShiftUp(m)
 	} else if m.ViewMode == types.SongView || m.ViewMode == types.ChainView || m.ViewMode == types.PhraseView {
 		// If we're in Song, Chain, or Phrase view, act like Shift+Down (go to mixer)
 		return handleShiftDown(m)
@@ -1854,9 +2299,14 @@ func handlePgDown(m *model.Model) tea.Cmd {
 			// Update position tracking for view navigation
 			m.LastPhraseRow = m.CurrentRow
 		}
-	} else if m.ViewMode == types.SettingsView {
-		// Settings view doesn't benefit from 16-row jumping, do regular down
+	} else if m.ViewMode == types.SettingsView || m.ViewMode == types.SampleToolsView || m.ViewMode == types.LFOView || m.ViewMode == types.AutomationView || m.ViewMode == types.ParameterLockView || m.ViewMode == types.InsertFXView || m.ViewMode == types.ReturnsView {
+		// Settings views don't benefit from 16-row jumping, do regular down
 		return handleDown(m)
+	} else if m.ViewMode == types.ProjectFilesView {
+		moveProjectFilesRow(m, 16)
+	} else if m.ViewMode == types.PresetView {
+		m.CurrentRow = ((m.CurrentRow + 16) / 16) * 16
+		clampPresetCursor(m)
 	} else if m.ViewMode == types.FileView {
 		// Calculate next 16-aligned row for File view
 		if len(m.Files) > 0 {
@@ -1882,9 +2332,9 @@ func handlePgDown(m *model.Model) tea.Cmd {
 		var maxRow int
 		switch m.ViewMode {
 		case types.ArpeggioView:
-			maxRow = 15 // 0-15 (16 rows total)
+			maxRow = types.ArpeggioLatchRow // 16 rows (0-15), then the latch setting
 		case types.MidiView:
-			maxRow = int(types.MidiSettingsRowChannel) + len(m.AvailableMidiDevices) // Settings + devices
+			maxRow = int(types.MidiSettingsRowFirstDevice) + len(m.AvailableMidiDevices) - 1 // Settings + devices
 		case types.SoundMakerView:
 			// Calculate maximum row based on current instrument parameters
 			settings := m.SoundMakerSettings[m.SoundMakerEditingIndex]
@@ -1897,10 +2347,18 @@ func handlePgDown(m *model.Model) tea.Cmd {
 			maxRow = int(types.RetriggerSettingsRowProbability) // Times(0) to Probability(9)
 		case types.TimestrechView:
 			maxRow = int(types.TimestrechSettingsRowProbability) // Start(0) to Probability(4)
+		case types.GranularView:
+			maxRow = int(types.GranularSettingsRowSpray) // Size(0) to Spray(5)
 		case types.ModulateView:
 			maxRow = int(types.ModulateSettingsRowProbability) // Seed(0) to Probability(6)
 		case types.FileMetadataView:
-			maxRow = int(types.FileMetadataRowSyncToBPM) // BPM(0) to SyncToBPM(3)
+			maxRow = int(types.FileMetadataRowLoopXfade) // BPM(0) to LoopXfade(7)
+		case types.SliceEditorView:
+			maxRow = 0 // Single waveform row
+		case types.MultisampleView:
+			maxRow = MultisampleMaxRow(m) // Zones plus the add row
+		case types.LibraryView:
+			maxRow = max(0, len(m.LibraryResults)-1) // Search results
 		default:
 			maxRow = 254 // Default maximum
 		}
@@ -1911,11 +2369,13 @@ func handlePgDown(m *model.Model) tea.Cmd {
 		if newRow != m.CurrentRow {
 			m.CurrentRow = newRow
 			// Update scroll offset if needed for scrollable views
-			if m.ViewMode == types.MidiView || m.ViewMode == types.SoundMakerView {
+			if m.ViewMode == types.SoundMakerView || m.ViewMode == types.MultisampleView {
 				visibleRows := m.GetVisibleRows()
 				if m.CurrentRow >= m.ScrollOffset+visibleRows {
 					m.ScrollOffset = m.CurrentRow - visibleRows + 1
 				}
+			} else if m.ViewMode == types.LibraryView {
+				keepLibraryRowVisible(m)
 			}
 		}
 	}
@@ -1966,9 +2426,14 @@ func handlePgUp(m *model.Model) tea.Cmd {
 			// Update position tracking for view navigation
 			m.LastPhraseRow = m.CurrentRow
 		}
-	} else if m.ViewMode == types.SettingsView {
-		// Settings view doesn't benefit from 16-row jumping, do regular up
+	} else if m.ViewMode == types.SettingsView || m.ViewMode == types.SampleToolsView || m.ViewMode == types.LFOView || m.ViewMode == types.AutomationView || m.ViewMode == types.ParameterLockView || m.ViewMode == types.InsertFXView || m.ViewMode == types.ReturnsView {
+		// Settings views don't benefit from 16-row jumping, do regular up
 		return handleUp(m)
+	} else if m.ViewMode == types.ProjectFilesView {
+		moveProjectFilesRow(m, -16)
+	} else if m.ViewMode == types.PresetView {
+		m.CurrentRow = ((m.CurrentRow - 1) / 16) * 16
+		clampPresetCursor(m)
 	} else if m.ViewMode == types.FileView {
 		// Calculate previous 16-aligned row for File view
 		newRow := ((m.CurrentRow - 1) / 16) * 16
@@ -1991,10 +2456,12 @@ func handlePgUp(m *model.Model) tea.Cmd {
 		if newRow != m.CurrentRow {
 			m.CurrentRow = newRow
 			// Update scroll offset if needed for scrollable views
-			if m.ViewMode == types.MidiView || m.ViewMode == types.SoundMakerView {
+			if m.ViewMode == types.SoundMakerView || m.ViewMode == types.MultisampleView {
 				if m.CurrentRow < m.ScrollOffset {
 					m.ScrollOffset = m.CurrentRow
 				}
+			} else if m.ViewMode == types.LibraryView {
+				keepLibraryRowVisible(m)
 			}
 		}
 	}
@@ -2023,8 +2490,31 @@ func handleCtrlO(m *model.Model) tea.Cmd {
 	return tea.Quit
 }
 
-// getCCColumnIndex returns the index (0-8) of the CC column, or -1 if not a CC column
-func getCCColumnIndex(col int) int {
+func handleCtrlU(m *model.Model) tea.Cmd {
+	if m.ViewMode == types.LibraryView {
+		// Rescan the library folders
+		return ScanLibrary(m)
+	} else if m.ViewMode == types.ProjectFilesView {
+		// Check the files again
+		refreshProjectFiles(m, m.CurrentRow)
+	}
+	return nil
+}
+
+func handleEnter(m *model.Model) tea.Cmd {
+	if m.ViewMode == types.SampleToolsView {
+		// Run the selected tool
+		ApplySampleTool(m)
+	} else if m.ViewMode == types.PresetView {
+		// Load the selected preset into the SoundMaker
+		LoadSelectedPreset(m)
+		storage.AutoSave(m)
+	}
+	return nil
+}
+
+// GetCCColumnIndex returns the index (0-8) of the CC column, or -1 if not a CC column
+func GetCCColumnIndex(col int) int {
 	switch col {
 	case int(types.InstrumentColATK):
 		return 0
@@ -2049,6 +2539,34 @@ func getCCColumnIndex(col int) int {
 	}
 }
 
+// CCMidiSettingsIndex returns the MIDI settings whose CCs the CC columns of the phrase view
+// send: the ones the row under the cursor plays (sticky), or on the header row the first
+// ones the phrase plays. -1 when the phrase plays none.
+func CCMidiSettingsIndex(m *model.Model) int {
+	phrasesData := m.GetCurrentPhrasesData()
+	if m.CurrentRow >= 0 {
+		return GetEffectiveValueForTrack(m, m.CurrentPhrase, m.CurrentRow, int(types.ColMidi), m.CurrentTrack)
+	}
+	for row := 0; row < 255; row++ {
+		if index := (*phrasesData)[m.CurrentPhrase][row][types.ColMidi]; index != -1 {
+			return index
+		}
+	}
+	return GetEffectiveValueForTrack(m, m.CurrentPhrase, 0, int(types.ColMidi), m.CurrentTrack)
+}
+
+// modifyCCNumber moves a CC column of the MIDI settings of the phrase to another controller
+func modifyCCNumber(m *model.Model, column, delta int) {
+	index := CCMidiSettingsIndex(m)
+	if index < 0 || index >= 255 {
+		return
+	}
+	settings := &m.MidiSettings[index]
+	settings.SetCCNumber(column, settings.CCs[column].Number+delta)
+	log.Printf("MIDI %02X column %d sends %s", index, column, settings.CCs[column])
+	storage.AutoSave(m)
+}
+
 // clampInt clamps an integer value between min and max
 func clampInt(value, min, max int) int {
 	if value < min {
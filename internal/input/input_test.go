@@ -2,7 +2,10 @@ package input
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
@@ -11,8 +14,23 @@ import (
 	"github.com/schollz/collidertracker/internal/types"
 )
 
+// testSaveFolder is where the autosaves started by the tests go, outside the source tree
+var testSaveFolder string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "collidertracker-input")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testSaveFolder = filepath.Join(dir, "save")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func createTestModel() *model.Model {
-	return model.NewModel(0, "test.json", false) // Port 0 to disable OSC for testing
+	return model.NewModel(0, testSaveFolder, false) // Port 0 to disable OSC for testing
 }
 
 func TestHandlePgDown(t *testing.T) {
@@ -826,6 +844,82 @@ func TestArpeggioCellCopyPaste(t *testing.T) {
 	assert.Equal(t, 1, m.ArpeggioSettings[0].Rows[7].Direction)
 }
 
+func TestArpeggioStepColumnsAndLatch(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.ArpeggioView
+	m.ArpeggioEditingIndex = 3
+	m.CurrentRow = 2
+
+	// Directions step through the new modes and stop at the last one
+	m.CurrentCol = int(types.ArpeggioColDI)
+	for i := 0; i < 10; i++ {
+		ModifyArpeggioValue(m, 1.0)
+	}
+	assert.Equal(t, int(types.ArpeggioDirectionConverge), m.ArpeggioSettings[3].Rows[2].Direction)
+
+	// Octaves and ratchet are capped, velocity stays within 7F
+	m.CurrentCol = int(types.ArpeggioColOC)
+	for i := 0; i < 6; i++ {
+		ModifyArpeggioValue(m, 0.05)
+	}
+	assert.Equal(t, types.ArpeggioMaxOctaves, m.ArpeggioSettings[3].Rows[2].Octaves)
+	m.CurrentCol = int(types.ArpeggioColRT)
+	ModifyArpeggioValue(m, 1.0)
+	ModifyArpeggioValue(m, 1.0)
+	assert.Equal(t, 2, m.ArpeggioSettings[3].Rows[2].Ratchet)
+	m.CurrentCol = int(types.ArpeggioColVE)
+	for i := 0; i < 10; i++ {
+		ModifyArpeggioValue(m, 1.0)
+	}
+	assert.Equal(t, 127, m.ArpeggioSettings[3].Rows[2].Velocity)
+
+	// Clearing goes back to "--"
+	ClearArpeggioCell(m)
+	assert.Equal(t, 0, m.ArpeggioSettings[3].Rows[2].Velocity)
+
+	// Cutting and pasting a row carries every column
+	CutRowToClipboard(m)
+	assert.True(t, m.ArpeggioSettings[3].Rows[2].IsEmpty())
+	m.CurrentRow = 9
+	PasteRowFromClipboard(m)
+	row := m.ArpeggioSettings[3].Rows[9]
+	assert.Equal(t, int(types.ArpeggioDirectionConverge), row.Direction)
+	assert.Equal(t, types.ArpeggioMaxOctaves, row.Octaves)
+	assert.Equal(t, 2, row.Ratchet)
+
+	// The latch setting sits below the rows
+	m.CurrentRow = 15
+	handleDown(m)
+	assert.Equal(t, types.ArpeggioLatchRow, m.CurrentRow)
+	handleDown(m)
+	assert.Equal(t, types.ArpeggioLatchRow, m.CurrentRow)
+	ModifyArpeggioValue(m, 1.0)
+	assert.True(t, m.ArpeggioSettings[3].Latch)
+	assert.False(t, IsArpeggioUnused(m, 3), "a latched arpeggio is in use")
+	CopyCellToClipboard(m) // Nothing to copy on the latch row
+	ClearArpeggioCell(m)
+	assert.False(t, m.ArpeggioSettings[3].Latch)
+}
+
+func TestArpeggioTick(t *testing.T) {
+	m := createTestModel()
+	assert.Nil(t, ArpeggioTick(m), "nothing to schedule without an arpeggio")
+
+	m.ArpeggioSettings[4].Rows[0] = types.ArpeggioRow{Direction: 1, Count: 2, Divisor: 1}
+	params := model.InstrumentOSCParams{TrackId: 1, ArpeggioIndex: 4, Notes: []float32{60, 64, 67}, DeltaTime: 0.25}
+	m.PlayArpeggio(params, m.ArpeggioSteps(params), time.Now())
+
+	assert.NotNil(t, ArpeggioTick(m))
+	first := m.ArpeggioTickAt
+	assert.Nil(t, ArpeggioTick(m), "the step is already scheduled")
+
+	// When the tick fires the step plays and the next one is scheduled
+	assert.Nil(t, HandleArpeggioTick(m, ArpeggioTickMsg(first.Add(-time.Hour))), "a stale tick leaves the scheduled one alone")
+	time.Sleep(time.Until(first))
+	assert.NotNil(t, HandleArpeggioTick(m, ArpeggioTickMsg(first)))
+	assert.Equal(t, first.Add(250*time.Millisecond), m.ArpeggioTickAt)
+}
+
 func TestRetriggerDeepCopy(t *testing.T) {
 	m := createTestModel()
 
@@ -1293,3 +1387,135 @@ func TestDuckingStickyBehavior(t *testing.T) {
 	result := GetEffectiveValueForTrack(m, 1, 2, int(types.ColEffectDucking), trackId)
 	assert.Equal(t, -1, result, "Should return -1 when no non-null values found")
 }
+
+func TestModifyMidiPatchRows(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MidiView
+	m.MidiEditingIndex = 2
+	m.AvailableMidiDevices = []string{"Synth A", "Synth B"}
+
+	// Program leaves "--" at its minimum and goes back to "--" below it
+	m.CurrentRow = int(types.MidiSettingsRowProgram)
+	ModifyMidiValue(m, 0.05)
+	assert.Equal(t, 0, m.MidiSettings[2].Program)
+	ModifyMidiValue(m, 1.0)
+	assert.Equal(t, 16, m.MidiSettings[2].Program)
+	ModifyMidiValue(m, -1.0)
+	ModifyMidiValue(m, -0.05)
+	assert.Equal(t, -1, m.MidiSettings[2].Program)
+
+	// Banks stop at 127
+	m.CurrentRow = int(types.MidiSettingsRowBankLSB)
+	ModifyMidiValue(m, 0.05)
+	for range 10 {
+		ModifyMidiValue(m, 1.0)
+	}
+	assert.Equal(t, 127, m.MidiSettings[2].BankLSB)
+
+	// Bend range is 1-24 semitones
+	m.CurrentRow = int(types.MidiSettingsRowBendRange)
+	ModifyMidiValue(m, 0.05)
+	assert.Equal(t, 1, m.MidiSettings[2].BendRange)
+	ModifyMidiValue(m, 1.0)
+	ModifyMidiValue(m, 1.0)
+	assert.Equal(t, 24, m.MidiSettings[2].BendRange)
+
+	// Devices are listed after the settings rows
+	for range 20 {
+		handleDown(m)
+	}
+	assert.Equal(t, int(types.MidiSettingsRowFirstDevice)+1, m.CurrentRow)
+	handleSpace(m)
+	assert.Equal(t, "Synth B", m.MidiSettings[2].Device)
+}
+
+func TestModifyMidiLatency(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MidiView
+	m.CurrentRow = int(types.MidiSettingsRowLatency)
+
+	// Without a device there is no latency to set
+	m.MidiEditingIndex = 1
+	ModifyMidiValue(m, 0.05)
+	assert.Empty(t, m.MidiLatencies)
+
+	// Latency belongs to the device, not the MIDI settings
+	m.MidiSettings[1].Device = "Synth A"
+	m.MidiSettings[2].Device = "Synth A"
+	ModifyMidiValue(m, 1.0)
+	ModifyMidiValue(m, 0.05)
+	assert.Equal(t, 11, m.MidiLatencies["Synth A"])
+	m.MidiEditingIndex = 2
+	ModifyMidiValue(m, -0.05)
+	assert.Equal(t, 10, m.MidiLatencies["Synth A"])
+
+	// 0-500 ms
+	for range 60 {
+		ModifyMidiValue(m, 1.0)
+	}
+	assert.Equal(t, 500, m.MidiLatencies["Synth A"])
+	for range 60 {
+		ModifyMidiValue(m, -1.0)
+	}
+	assert.NotContains(t, m.MidiLatencies, "Synth A")
+}
+
+func TestInstrumentPitchBendColumns(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = false // Instrument
+	m.CurrentCol = int(types.InstrumentColFV)
+
+	// PB and AT are only reachable in MI mode
+	handleRight(m)
+	assert.Equal(t, int(types.InstrumentColFV), m.CurrentCol)
+	m.SOColumnMode = types.SOModeMIDI
+	handleRight(m)
+	handleRight(m)
+	handleRight(m)
+	assert.Equal(t, int(types.InstrumentColAT), m.CurrentCol)
+
+	mapping := m.GetColumnMapping(int(types.InstrumentColPB))
+	assert.NotNil(t, mapping)
+	assert.Equal(t, int(types.ColPitchBend), mapping.DataColumnIndex)
+	m.SOColumnMode = types.SOModeSound
+	assert.Nil(t, m.GetColumnMapping(int(types.InstrumentColPB)))
+}
+
+func TestHeaderCCNumbersBelongToMidiSettings(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = false // Instrument
+	m.SOColumnMode = types.SOModeMIDI
+	m.CurrentPhrase = 1
+	m.CurrentCol = int(types.InstrumentColLP)
+	m.InstrumentPhrasesData[1][3][types.ColMidi] = 5
+
+	// The header edits the CCs of the first MIDI settings the phrase plays
+	m.CurrentRow = -1
+	assert.Equal(t, 5, CCMidiSettingsIndex(m))
+	modifyCCNumber(m, GetCCColumnIndex(m.CurrentCol), 67)
+	assert.Equal(t, types.MidiCC{Number: 74, Name: "Cutoff"}, m.MidiSettings[5].CCs[7])
+	assert.Equal(t, 7, m.MidiSettings[6].CCs[7].Number, "other MIDI settings keep their CCs")
+
+	// Rows use the MIDI settings they play (sticky)
+	m.CurrentRow = 2
+	assert.Equal(t, -1, CCMidiSettingsIndex(m))
+	modifyCCNumber(m, 7, 1)
+	m.CurrentRow = 9
+	assert.Equal(t, 5, CCMidiSettingsIndex(m))
+
+	// The profile row applies a profile
+	m.ViewMode = types.MidiView
+	m.MidiEditingIndex = 5
+	m.CurrentRow = int(types.MidiSettingsRowProfile)
+	ModifyMidiValue(m, 0.05)
+	assert.Equal(t, "General MIDI", m.MidiSettings[5].Profile)
+	assert.Equal(t, "Cutoff", m.MidiSettings[5].CCs[7].Name)
+	m.CurrentRow = int(types.MidiSettingsRowCC0) + 7
+	ModifyMidiValue(m, -0.05)
+	assert.Equal(t, types.MidiCC{Number: 73, Name: "Attack"}, m.MidiSettings[5].CCs[7])
+	assert.Empty(t, m.MidiSettings[5].Profile)
+}
@@ -0,0 +1,140 @@
+package input
+
+import (
+	"log"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// OpenInsertFX opens the insert chain of the current track from the FX or FV column, on the
+// slot and parameter the row addresses
+func OpenInsertFX(m *model.Model) {
+	if m.CurrentTrack < 0 || m.CurrentTrack >= len(m.InsertFX) {
+		return
+	}
+	row, col := 0, 0
+	phrasesData := m.GetCurrentPhrasesData()
+	if fx := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColInsertFX]; fx != -1 {
+		slot, param := types.DecodeInsertFXColumn(fx)
+		if slot < len(m.InsertFX[m.CurrentTrack].Slots) {
+			row = slot
+			if module, ok := types.GetInsertFXModule(m.InsertFX[m.CurrentTrack].Slots[slot].Module); ok && param < len(module.Parameters) {
+				col = param + 1
+			}
+		}
+	}
+	m.InsertFXEditingTrack = m.CurrentTrack
+	m.LastPhraseRow = m.CurrentRow
+	m.LastPhraseCol = m.CurrentCol
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.InsertFXView,
+		Row:          row,
+		Col:          col,
+		ScrollOffset: 0,
+	})
+}
+
+// CloseInsertFX returns to the phrase row the insert chain was opened from
+func CloseInsertFX(m *model.Model) {
+	switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
+}
+
+// InsertFXRowCount returns the rows of the insert FX view: the slots, then a row to add a
+// module to unless the chain is full
+func InsertFXRowCount(m *model.Model) int {
+	return min(len(m.InsertFX[m.InsertFXEditingTrack].Slots)+1, types.InsertFXSlotsPerTrack)
+}
+
+// insertFXColCount returns the columns of a row of the insert FX view: the module, then its parameters
+func insertFXColCount(m *model.Model, row int) int {
+	chain := m.InsertFX[m.InsertFXEditingTrack]
+	if row < 0 || row >= len(chain.Slots) {
+		return 1
+	}
+	module, ok := types.GetInsertFXModule(chain.Slots[row].Module)
+	if !ok {
+		return 1
+	}
+	return len(module.Parameters) + 1
+}
+
+// ModifyInsertFXValue changes the module of the selected slot, or adds one on the last row,
+// in the first column, and moves the selected parameter like the SoundMaker view does in
+// the others
+func ModifyInsertFXValue(m *model.Model, delta float32) {
+	track := m.InsertFXEditingTrack
+	chain := &m.InsertFX[track]
+	if m.CurrentCol == 0 {
+		names := make([]string, len(types.InsertFXModules))
+		for i, module := range types.InsertFXModules {
+			names[i] = module.Name
+		}
+		if m.CurrentRow >= len(chain.Slots) {
+			// The first step on the last row adds a module from either end of the list
+			name := names[0]
+			if delta < 0 {
+				name = names[len(names)-1]
+			}
+			if !chain.Add(name) {
+				return
+			}
+		} else {
+			current := chain.Slots[m.CurrentRow].Module
+			name := names[stepChoice(names, current, delta)]
+			if name == current {
+				return
+			}
+			chain.Slots[m.CurrentRow] = types.NewInsertFXSlot(name)
+		}
+		log.Printf("Insert FX of track %d: slot %d is %s", track+1, m.CurrentRow, chain.Slots[m.CurrentRow].Module)
+		m.SendOSCInsertFXChain(track)
+		storage.AutoSave(m)
+		return
+	}
+
+	if m.CurrentRow < 0 || m.CurrentRow >= len(chain.Slots) {
+		return
+	}
+	slot := &chain.Slots[m.CurrentRow]
+	module, ok := types.GetInsertFXModule(slot.Module)
+	if !ok || m.CurrentCol-1 >= len(module.Parameters) {
+		return
+	}
+	param := module.Parameters[m.CurrentCol-1]
+	newValue, _ := stepSoundMakerParameter(param, slot.Value(param), delta)
+	if slot.Parameters == nil {
+		slot.Parameters = make(map[string]float32)
+	}
+	slot.Parameters[param.Key] = newValue
+	log.Printf("Insert FX of track %d: slot %d %s %s = %f", track+1, m.CurrentRow, slot.Module, param.Key, newValue)
+	m.SendOSCInsertFXSlot(track, m.CurrentRow)
+	storage.AutoSave(m)
+}
+
+// RemoveInsertFXSlot takes the selected slot out of the chain
+func RemoveInsertFXSlot(m *model.Model) {
+	track := m.InsertFXEditingTrack
+	if m.CurrentRow < 0 || m.CurrentRow >= len(m.InsertFX[track].Slots) {
+		return
+	}
+	m.InsertFX[track].Remove(m.CurrentRow)
+	m.CurrentCol = 0
+	log.Printf("Insert FX of track %d: removed slot %d", track+1, m.CurrentRow)
+	m.SendOSCInsertFXChain(track)
+	storage.AutoSave(m)
+}
+
+// MoveInsertFXSlot moves the selected slot earlier (-1) or later (1) in the chain
+func MoveInsertFXSlot(m *model.Model, direction int) {
+	track := m.InsertFXEditingTrack
+	row := m.InsertFX[track].Move(m.CurrentRow, direction)
+	if row == m.CurrentRow {
+		return
+	}
+	log.Printf("Insert FX of track %d: moved slot %d to %d", track+1, m.CurrentRow, row)
+	m.CurrentRow = row
+	m.SendOSCInsertFXChain(track)
+	storage.AutoSave(m)
+}
@@ -0,0 +1,65 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestInsertFXView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 3
+	m.TrackTypes[3] = true // Sampler
+	m.InsertFX[3].Add("EQ3")
+	m.InsertFX[3].Add("Delay")
+	phrasesData := m.GetCurrentPhrasesData()
+	(*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColInsertFX] = 0x11
+	m.CurrentCol = int(types.SamplerColFV)
+
+	// Shift+Right on the FX columns opens the chain on the slot and parameter of the row
+	handleShiftRight(m)
+	assert.Equal(t, types.InsertFXView, m.ViewMode)
+	assert.Equal(t, 3, m.InsertFXEditingTrack)
+	assert.Equal(t, 1, m.CurrentRow)
+	assert.Equal(t, 2, m.CurrentCol)
+
+	ModifyInsertFXValue(m, 1.0)
+	ModifyInsertFXValue(m, -0.05)
+	assert.InDelta(t, 0.49, m.InsertFX[3].Slots[1].Parameters["feedback"], 1e-5)
+
+	// Changing the module starts it from its defaults
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyLeft})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyLeft})
+	ModifyInsertFXValue(m, 1.0)
+	assert.Equal(t, "Chorus", m.InsertFX[3].Slots[1].Module)
+
+	// The row after the slots adds a module
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 2, m.CurrentRow)
+	ModifyInsertFXValue(m, -1.0)
+	assert.Equal(t, "Distortion", m.InsertFX[3].Slots[2].Module)
+
+	// Shift+Up moves a slot earlier and Backspace removes it
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftUp})
+	assert.Equal(t, 1, m.CurrentRow)
+	assert.Equal(t, []string{"EQ3", "Distortion", "Chorus"}, insertFXModuleNames(m, 3))
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, []string{"EQ3", "Chorus"}, insertFXModuleNames(m, 3))
+
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+	assert.Equal(t, int(types.SamplerColFV), m.CurrentCol)
+}
+
+func insertFXModuleNames(m *model.Model, track int) []string {
+	var names []string
+	for _, slot := range m.InsertFX[track].Slots {
+		names = append(names, slot.Module)
+	}
+	return names
+}
@@ -3,6 +3,7 @@ package input
 import (
 	"log"
 
+	"github.com/schollz/collidertracker/internal/midiprofiles"
 	"github.com/schollz/collidertracker/internal/model"
 	"github.com/schollz/collidertracker/internal/storage"
 	"github.com/schollz/collidertracker/internal/supercollider"
@@ -13,34 +14,30 @@ func ModifyArpeggioValue(m *model.Model, baseDelta float32) {
 	if m.ArpeggioEditingIndex < 0 || m.ArpeggioEditingIndex >= 255 {
 		return
 	}
-	if m.CurrentRow < 0 || m.CurrentRow >= 16 {
+	if m.CurrentRow < 0 || m.CurrentRow > types.ArpeggioLatchRow {
 		return
 	}
 
 	// Get current settings
 	settings := m.ArpeggioSettings[m.ArpeggioEditingIndex]
-	currentRow := &settings.Rows[m.CurrentRow] // Get reference to specific row
 
-	if m.CurrentCol == int(types.ArpeggioColDI) { // DI (Direction) column
-		// Direction cycles through: ArpeggioDirectionNone, ArpeggioDirectionUp, ArpeggioDirectionDown
-		var delta int
-		if baseDelta > 0 {
-			delta = 1
-		} else {
-			delta = -1
-		}
+	if m.CurrentRow == types.ArpeggioLatchRow {
+		// Latch is on above zero, off below
+		settings.Latch = baseDelta > 0
+		log.Printf("Modified arpeggio %02X Latch: %v", m.ArpeggioEditingIndex, settings.Latch)
+		m.ArpeggioSettings[m.ArpeggioEditingIndex] = settings
+		storage.AutoSave(m)
+		return
+	}
 
-		newDirection := currentRow.Direction + delta
-		if newDirection < int(types.ArpeggioDirectionNone) {
-			newDirection = int(types.ArpeggioDirectionNone) // Stay at "--"
-		} else if newDirection > int(types.ArpeggioDirectionDown) {
-			newDirection = int(types.ArpeggioDirectionDown) // Stay at "d-"
-		}
-		currentRow.Direction = newDirection
-		log.Printf("Modified arpeggio %02X row %02X Direction: %d -> %d", m.ArpeggioEditingIndex, m.CurrentRow, currentRow.Direction-delta, currentRow.Direction)
-	} else if m.CurrentCol == int(types.ArpeggioColCO) { // CO (Count) column
-		// Count: -1="--", 0-254 for hex values 00-FE
-		var delta int
+	currentRow := &settings.Rows[m.CurrentRow] // Get reference to specific row
+	col := types.ArpeggioUIColumn(m.CurrentCol)
+	oldValue := currentRow.Value(col)
+
+	var delta int
+	switch col {
+	case types.ArpeggioColCO, types.ArpeggioColDIV, types.ArpeggioColVE, types.ArpeggioColGT:
+		// Hex columns: -1="--" or 00-FE
 		if baseDelta == 1.0 || baseDelta == -1.0 {
 			delta = int(baseDelta) * 16 // Coarse control (Ctrl+Up/Down): +/-16
 		} else if baseDelta == 0.05 || baseDelta == -0.05 {
@@ -48,40 +45,22 @@ func ModifyArpeggioValue(m *model.Model, baseDelta float32) {
 		} else {
 			delta = int(baseDelta) // Fallback
 		}
-
-		newCount := currentRow.Count + delta
-		if newCount < -1 {
-			newCount = -1 // Can't go below "--"
-		} else if newCount > 254 {
-			newCount = 254 // Cap at FE
-		}
-		currentRow.Count = newCount
-		log.Printf("Modified arpeggio %02X row %02X Count: %d -> %d (delta: %d)", m.ArpeggioEditingIndex, m.CurrentRow, currentRow.Count-delta, currentRow.Count, delta)
-	} else if m.CurrentCol == int(types.ArpeggioColDIV) { // Divisor (/) column
-		// Divisor: -1="--", 1-254 for hex values 01-FE (never allow 00)
-		var delta int
-		if baseDelta == 1.0 || baseDelta == -1.0 {
-			delta = int(baseDelta) * 16 // Coarse control (Ctrl+Up/Down): +/-16
-		} else if baseDelta == 0.05 || baseDelta == -0.05 {
-			delta = int(baseDelta / 0.05) // Fine control (Ctrl+Left/Right): +/-1
+	default:
+		// Direction, octaves, rest and ratchet step one at a time
+		if baseDelta > 0 {
+			delta = 1
 		} else {
-			delta = int(baseDelta) // Fallback
+			delta = -1
 		}
+	}
 
-		newDivisor := currentRow.Divisor + delta
-		if currentRow.Divisor == -1 && delta > 0 {
-			// When going up from "--", start at 1 (not 0)
-			newDivisor = 1
-		} else if newDivisor <= 0 {
-			// Going below 1, wrap to "--"
-			newDivisor = -1
-		} else if newDivisor > 254 {
-			// Cap at FE (254)
-			newDivisor = 254
-		}
-		currentRow.Divisor = newDivisor
-		log.Printf("Modified arpeggio %02X row %02X Divisor: %d -> %d (delta: %d)", m.ArpeggioEditingIndex, m.CurrentRow, currentRow.Divisor-delta, currentRow.Divisor, delta)
+	newValue := oldValue + delta
+	if col == types.ArpeggioColDIV && oldValue == -1 && delta > 0 {
+		// When going up from "--", start at 1 (not 0)
+		newValue = 1
 	}
+	currentRow.SetValue(col, newValue)
+	log.Printf("Modified arpeggio %02X row %02X col %d: %d -> %d (delta: %d)", m.ArpeggioEditingIndex, m.CurrentRow, m.CurrentCol, oldValue, currentRow.Value(col), delta)
 
 	// Store back the modified settings
 	m.ArpeggioSettings[m.ArpeggioEditingIndex] = settings
@@ -96,7 +75,7 @@ func ModifyMidiValue(m *model.Model, baseDelta float32) {
 	// Get current settings
 	settings := &m.MidiSettings[m.MidiEditingIndex]
 
-	if m.CurrentRow == 0 { // Device row
+	if m.CurrentRow == int(types.MidiSettingsRowDevice) {
 		// Device cycles through available MIDI devices from AvailableMidiDevices
 		var delta int
 		if baseDelta > 0 {
@@ -134,7 +113,7 @@ func ModifyMidiValue(m *model.Model, baseDelta float32) {
 		oldDevice := settings.Device
 		settings.Device = devices[newIndex]
 		log.Printf("Modified MIDI %02X Device: %s -> %s", m.MidiEditingIndex, oldDevice, settings.Device)
-	} else if m.CurrentRow == 1 { // Channel row
+	} else if m.CurrentRow == int(types.MidiSettingsRowChannel) {
 		// Channel cycles through: "1"-"16" and "all"
 		var delta int
 		if baseDelta > 0 {
@@ -170,6 +149,78 @@ func ModifyMidiValue(m *model.Model, baseDelta float32) {
 		oldChannel := settings.Channel
 		settings.Channel = channels[newIndex]
 		log.Printf("Modified MIDI %02X Channel: %s -> %s", m.MidiEditingIndex, oldChannel, settings.Channel)
+	} else if m.CurrentRow <= int(types.MidiSettingsRowBendRange) {
+		// Patch rows: -1 ("--") leaves the setting to the synth
+		var value *int
+		minValue, maxValue := 0, 127
+		switch types.MidiSettingsRow(m.CurrentRow) {
+		case types.MidiSettingsRowProgram:
+			value = &settings.Program
+		case types.MidiSettingsRowBankMSB:
+			value = &settings.BankMSB
+		case types.MidiSettingsRowBankLSB:
+			value = &settings.BankLSB
+		case types.MidiSettingsRowBendRange:
+			value = &settings.BendRange
+			minValue, maxValue = 1, 24
+		}
+
+		var delta int
+		if baseDelta == 1.0 || baseDelta == -1.0 {
+			delta = int(baseDelta) * 16 // Coarse control (Ctrl+Up/Down): +/-16
+		} else if baseDelta > 0 {
+			delta = 1
+		} else {
+			delta = -1
+		}
+		oldValue := *value
+		switch {
+		case oldValue == -1:
+			if delta > 0 {
+				*value = minValue // Leaving "--" starts at the minimum
+			}
+		case oldValue+delta < minValue:
+			*value = -1 // Going below the minimum goes back to "--"
+		default:
+			*value = min(oldValue+delta, maxValue)
+		}
+		log.Printf("Modified MIDI %02X row %d: %d -> %d", m.MidiEditingIndex, m.CurrentRow, oldValue, *value)
+
+		// Let the synth follow the patch while it is being picked
+		m.SendMidiPatch(m.MidiEditingIndex, false)
+	} else if m.CurrentRow == int(types.MidiSettingsRowLatency) {
+		// Latency belongs to the device, so it is shared by the MIDI settings that play it
+		delta := 1
+		if baseDelta == 1.0 || baseDelta == -1.0 {
+			delta = 10 // Coarse control (Ctrl+Up/Down): +/-10 ms
+		}
+		if baseDelta < 0 {
+			delta = -delta
+		}
+		m.SetMidiLatency(settings.Device, m.MidiLatencies[settings.Device]+delta)
+		log.Printf("Modified MIDI device %s latency: %d ms", settings.Device, m.MidiLatencies[settings.Device])
+	} else if m.CurrentRow == int(types.MidiSettingsRowProfile) {
+		// Profile steps through "--" and the device profiles; picking one sets the CCs
+		names := append([]string{""}, midiprofiles.Names()...)
+		name := names[stepChoice(names, settings.Profile, baseDelta)]
+		if profile, ok := midiprofiles.Get(name); ok {
+			midiprofiles.Apply(settings, profile)
+		} else {
+			settings.Profile = ""
+		}
+		log.Printf("Modified MIDI %02X Profile: %q", m.MidiEditingIndex, settings.Profile)
+	} else if m.CurrentRow < int(types.MidiSettingsRowFirstDevice) {
+		// CC rows move their column to another controller
+		column := m.CurrentRow - int(types.MidiSettingsRowCC0)
+		delta := 1
+		if baseDelta == 1.0 || baseDelta == -1.0 {
+			delta = 16 // Coarse control (Ctrl+Up/Down): +/-16
+		}
+		if baseDelta < 0 {
+			delta = -delta
+		}
+		settings.SetCCNumber(column, settings.CCs[column].Number+delta)
+		log.Printf("Modified MIDI %02X column %d: %s", m.MidiEditingIndex, column, settings.CCs[column])
 	}
 
 	storage.AutoSave(m)
@@ -233,67 +284,7 @@ func ModifySoundMakerValue(m *model.Model, baseDelta float32) {
 				param := def.Parameters[paramIndex]
 				oldValue := settings.GetParameterValue(param.Key)
 
-				// Calculate delta based on parameter type and input
-				var delta float32
-
-				// Check if custom step sizes are defined
-				var coarseStep, fineStep float32
-				if param.CoarseStep != 0 {
-					coarseStep = param.CoarseStep
-				} else {
-					// Use default coarse steps based on parameter type
-					if param.Type == types.ParameterTypeInt {
-						coarseStep = 50.0 // Default for DX7 preset and similar
-					} else {
-						coarseStep = 0.16 // Default for float and hex
-					}
-				}
-
-				if param.FineStep != 0 {
-					fineStep = param.FineStep
-				} else {
-					// Use default fine steps
-					if param.Type == types.ParameterTypeInt {
-						fineStep = 1.0 // Default fine step for int
-					} else {
-						fineStep = 0.01 // Default fine step for float and hex
-					}
-				}
-
-				// Apply the step sizes based on control type
-				if baseDelta >= 1.0 || baseDelta <= -1.0 {
-					// Coarse control
-					if baseDelta > 0 {
-						delta = coarseStep
-					} else {
-						delta = -coarseStep
-					}
-				} else {
-					// Fine control
-					if baseDelta > 0 {
-						delta = fineStep
-					} else {
-						delta = -fineStep
-					}
-				}
-
-				var newValue float32
-				if oldValue == -1 {
-					// If currently "--", start from min or max
-					if delta > 0 {
-						newValue = param.MinValue
-					} else {
-						newValue = param.MaxValue
-					}
-				} else {
-					newValue = oldValue + delta
-					// Handle clamping (no wrapping)
-					if newValue > param.MaxValue {
-						newValue = param.MaxValue // Clamp to max
-					} else if newValue < param.MinValue {
-						newValue = param.MinValue // Clamp to min
-					}
-				}
+				newValue, delta := stepSoundMakerParameter(param, oldValue, baseDelta)
 
 				// Set the new value
 				settings.SetParameterValue(param.Key, newValue)
@@ -304,8 +295,10 @@ func ModifySoundMakerValue(m *model.Model, baseDelta float32) {
 						if patchName, err := supercollider.GetDX7PatchName(int(newValue)); err == nil {
 							settings.PatchName = patchName
 						}
+						settings.DX7Voice = supercollider.DX7VoiceRef(int(newValue))
 					} else {
 						settings.PatchName = ""
+						settings.DX7Voice = ""
 					}
 				}
 
@@ -330,6 +323,75 @@ func ModifySoundMakerValue(m *model.Model, baseDelta float32) {
 	storage.AutoSave(m)
 }
 
+// stepSoundMakerParameter moves a SoundMaker parameter by a coarse (±1.0) or fine (±0.05)
+// adjustment, using the step sizes of its definition. Unset values (-1) start from the
+// minimum or maximum.
+func stepSoundMakerParameter(param types.InstrumentParameterDef, oldValue, baseDelta float32) (float32, float32) {
+	// Calculate delta based on parameter type and input
+	var delta float32
+
+	// Check if custom step sizes are defined
+	var coarseStep, fineStep float32
+	if param.CoarseStep != 0 {
+		coarseStep = param.CoarseStep
+	} else {
+		// Use default coarse steps based on parameter type
+		if param.Type == types.ParameterTypeInt {
+			coarseStep = 50.0 // Default for DX7 preset and similar
+		} else {
+			coarseStep = 0.16 // Default for float and hex
+		}
+	}
+
+	if param.FineStep != 0 {
+		fineStep = param.FineStep
+	} else {
+		// Use default fine steps
+		if param.Type == types.ParameterTypeInt {
+			fineStep = 1.0 // Default fine step for int
+		} else {
+			fineStep = 0.01 // Default fine step for float and hex
+		}
+	}
+
+	// Apply the step sizes based on control type
+	if baseDelta >= 1.0 || baseDelta <= -1.0 {
+		// Coarse control
+		if baseDelta > 0 {
+			delta = coarseStep
+		} else {
+			delta = -coarseStep
+		}
+	} else {
+		// Fine control
+		if baseDelta > 0 {
+			delta = fineStep
+		} else {
+			delta = -fineStep
+		}
+	}
+
+	var newValue float32
+	if oldValue == -1 {
+		// If currently "--", start from min or max
+		if delta > 0 {
+			newValue = param.MinValue
+		} else {
+			newValue = param.MaxValue
+		}
+	} else {
+		newValue = oldValue + delta
+		// Handle clamping (no wrapping)
+		if newValue > param.MaxValue {
+			newValue = param.MaxValue // Clamp to max
+		} else if newValue < param.MinValue {
+			newValue = param.MinValue // Clamp to min
+		}
+	}
+
+	return newValue, delta
+}
+
 // ClearArpeggioCell clears the current cell in Arpeggio Settings view
 func ClearArpeggioCell(m *model.Model) {
 	if m.ViewMode != types.ArpeggioView {
@@ -339,23 +401,17 @@ func ClearArpeggioCell(m *model.Model) {
 	if m.ArpeggioEditingIndex < 0 || m.ArpeggioEditingIndex >= 255 {
 		return
 	}
-	if m.CurrentRow < 0 || m.CurrentRow >= 16 {
+	if m.CurrentRow < 0 || m.CurrentRow > types.ArpeggioLatchRow {
 		return
 	}
 
-	// Get current row
-	currentRow := &m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow]
-
-	switch m.CurrentCol {
-	case 0: // DI (Direction) column
-		currentRow.Direction = int(types.ArpeggioDirectionNone) // Clear to "--"
-		log.Printf("Cleared arpeggio %02X row %02X Direction", m.ArpeggioEditingIndex, m.CurrentRow)
-	case 1: // CO (Count) column
-		currentRow.Count = -1 // Clear to "--"
-		log.Printf("Cleared arpeggio %02X row %02X Count", m.ArpeggioEditingIndex, m.CurrentRow)
-	case 2: // Divisor (/) column
-		currentRow.Divisor = -1 // Clear to "--"
-		log.Printf("Cleared arpeggio %02X row %02X Divisor", m.ArpeggioEditingIndex, m.CurrentRow)
+	if m.CurrentRow == types.ArpeggioLatchRow {
+		m.ArpeggioSettings[m.ArpeggioEditingIndex].Latch = false
+		log.Printf("Cleared arpeggio %02X Latch", m.ArpeggioEditingIndex)
+	} else {
+		// Clear the cell of the current row to "--"
+		m.ArpeggioSettings[m.ArpeggioEditingIndex].Rows[m.CurrentRow].ClearValue(types.ArpeggioUIColumn(m.CurrentCol))
+		log.Printf("Cleared arpeggio %02X row %02X col %d", m.ArpeggioEditingIndex, m.CurrentRow, m.CurrentCol)
 	}
 	storage.AutoSave(m)
 }
@@ -0,0 +1,110 @@
+package input
+
+import (
+	"log"
+	"math"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// OpenLFOs opens the LFO pool from the mixer, on the first LFO of the selected track
+func OpenLFOs(m *model.Model) {
+	row := 0
+	for i, settings := range m.LFOSettings {
+		if settings.Target != "" && settings.Track == m.CurrentMixerTrack {
+			row = i
+			break
+		}
+	}
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.LFOView,
+		Row:          row,
+		Col:          int(types.LFOColWaveform),
+		ScrollOffset: 0,
+	})
+}
+
+// CloseLFOs returns to the mixer
+func CloseLFOs(m *model.Model) {
+	switchToView(m, mixerViewConfig())
+}
+
+// ClearLFO turns the selected LFO off
+func ClearLFO(m *model.Model) {
+	if m.CurrentRow < 0 || m.CurrentRow >= types.LFOCount {
+		return
+	}
+	m.LFOSettings[m.CurrentRow].Target = ""
+	m.SendOSCLFOMessage(m.CurrentRow)
+	storage.AutoSave(m)
+}
+
+// ModifyLFOValue changes the selected value of the selected LFO. Coarse steps are ±1.0
+// and fine steps ±0.05, as in the other settings views.
+func ModifyLFOValue(m *model.Model, delta float32) {
+	if m.CurrentRow < 0 || m.CurrentRow >= types.LFOCount {
+		return
+	}
+	settings := &m.LFOSettings[m.CurrentRow]
+	coarse := delta == 1.0 || delta == -1.0
+	direction := 1
+	if delta < 0 {
+		direction = -1
+	}
+
+	switch types.LFOUIColumn(m.CurrentCol) {
+	case types.LFOColWaveform:
+		settings.Waveform = (settings.Waveform + direction + int(types.LFOWaveformCount)) % int(types.LFOWaveformCount)
+	case types.LFOColRate:
+		settings.Sync = 0 // Setting a rate in Hz leaves tempo sync
+		settings.Rate = stepLFORate(settings.Rate, direction, coarse)
+	case types.LFOColSync:
+		settings.Sync = clampInt(settings.Sync+direction, 0, len(types.LFOSyncDivisions))
+	case types.LFOColDepth:
+		step := 1
+		if coarse {
+			step = 10
+		}
+		settings.Depth = clampInt(settings.Depth+direction*step, 0, 100)
+	case types.LFOColPhase:
+		step := 15
+		if coarse {
+			step = 90
+		}
+		settings.Phase = ((settings.Phase+direction*step)%360 + 360) % 360
+	case types.LFOColRetrigger:
+		settings.Retrigger = delta > 0
+	case types.LFOColTrack:
+		settings.Track = clampInt(settings.Track+direction, 0, 7)
+	case types.LFOColTarget:
+		choices := []string{""}
+		for _, target := range types.LFOTargets() {
+			choices = append(choices, target.ID())
+		}
+		settings.Target = choices[stepChoice(choices, settings.Target, delta)]
+	}
+
+	log.Printf("Modified LFO %X: %+v", m.CurrentRow, *settings)
+	m.SendOSCLFOMessage(m.CurrentRow)
+	storage.AutoSave(m)
+}
+
+// stepLFORate doubles or halves a rate for coarse steps, and changes its last shown digit
+// for fine steps
+func stepLFORate(rate float32, direction int, coarse bool) float32 {
+	if coarse {
+		rate *= float32(math.Pow(2, float64(direction)))
+	} else {
+		step := float32(0.01)
+		if rate > 10 || rate == 10 && direction > 0 {
+			step = 1
+		} else if rate > 1 || rate == 1 && direction > 0 {
+			step = 0.1
+		}
+		rate += float32(direction) * step
+	}
+	rate = float32(math.Round(float64(rate)*100) / 100)
+	return max(types.LFOMinRate, min(rate, types.LFOMaxRate))
+}
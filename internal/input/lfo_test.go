@@ -0,0 +1,108 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestLFOView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MixerView
+	m.CurrentMixerTrack = 2
+	m.LFOSettings[4].Target = "pan"
+	m.LFOSettings[4].Track = 2
+
+	// Shift+Right in the mixer opens the first LFO of the selected track
+	handleShiftRight(m)
+	assert.Equal(t, types.LFOView, m.ViewMode)
+	assert.Equal(t, 4, m.CurrentRow)
+
+	press := func(key string) {
+		HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 3, m.CurrentRow)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+
+	// Vim keys move as in the other views
+	m.VimMode = true
+	press("k")
+	assert.Equal(t, 3, m.CurrentRow)
+	press("j")
+	assert.Equal(t, 4, m.CurrentRow)
+	m.VimMode = false
+
+	settings := &m.LFOSettings[4]
+	for col, check := range map[types.LFOUIColumn]func(){
+		types.LFOColWaveform: func() {
+			ModifyLFOValue(m, -1)
+			assert.Equal(t, int(types.LFOWaveformSmoothRandom), settings.Waveform)
+		},
+		types.LFOColSync: func() {
+			ModifyLFOValue(m, 1)
+			assert.Equal(t, "1/16", settings.RateName())
+		},
+		types.LFOColDepth: func() {
+			ModifyLFOValue(m, 1)
+			ModifyLFOValue(m, -0.05)
+			assert.Equal(t, 59, settings.Depth)
+		},
+		types.LFOColPhase: func() {
+			ModifyLFOValue(m, -1)
+			assert.Equal(t, 270, settings.Phase)
+		},
+		types.LFOColRetrigger: func() {
+			ModifyLFOValue(m, 0.05)
+			assert.True(t, settings.Retrigger)
+		},
+		types.LFOColTrack: func() {
+			ModifyLFOValue(m, 1)
+			assert.Equal(t, 3, settings.Track)
+		},
+		types.LFOColTarget: func() {
+			ModifyLFOValue(m, 1)
+			assert.Equal(t, "lowPassFilter", settings.Target)
+		},
+	} {
+		m.CurrentCol = int(col)
+		check()
+	}
+
+	// A rate in Hz leaves tempo sync
+	m.CurrentCol = int(types.LFOColRate)
+	ModifyLFOValue(m, 1)
+	assert.Equal(t, 0, settings.Sync)
+	assert.Equal(t, float32(2), settings.Rate)
+
+	// Esc turns the LFO off, Shift+Left goes back to the mixer
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, "", settings.Target)
+	press("m")
+	assert.Equal(t, types.MixerView, m.ViewMode)
+}
+
+func TestStepLFORate(t *testing.T) {
+	tests := []struct {
+		rate      float32
+		direction int
+		coarse    bool
+		expected  float32
+	}{
+		{1, 1, true, 2},
+		{0.02, -1, true, 0.01},
+		{0.01, -1, false, 0.01},
+		{0.5, 1, false, 0.51},
+		{1, 1, false, 1.1},
+		{1, -1, false, 0.99},
+		{10, 1, false, 11},
+		{10, -1, false, 9.9},
+		{40, 1, true, 50},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, stepLFORate(tt.rate, tt.direction, tt.coarse), "%v %+d coarse %v", tt.rate, tt.direction, tt.coarse)
+	}
+}
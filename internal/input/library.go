@@ -0,0 +1,240 @@
+package input
+
+import (
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/schollz/collidertracker/internal/audio"
+	"github.com/schollz/collidertracker/internal/library"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// LibraryScanMsg carries the result of a background library scan
+type LibraryScanMsg struct {
+	Entries []library.Entry
+}
+
+func libraryViewConfig() ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.LibraryView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	}
+}
+
+// LibraryVisibleRows returns how many results fit below the search line and column header
+func LibraryVisibleRows(m *model.Model) int {
+	return max(1, m.GetVisibleRows()-2)
+}
+
+// OpenLibrary switches from the file browser to the sample library and rescans it in the background
+func OpenLibrary(m *model.Model) tea.Cmd {
+	if m.Library == nil {
+		idx, err := library.Load(m.LibraryPath)
+		if err != nil {
+			log.Printf("Error loading sample library: %v", err)
+			idx = &library.Index{}
+		}
+		m.Library = idx
+	}
+	m.LibraryTagEditing = false
+	switchToView(m, libraryViewConfig())
+	RefreshLibraryResults(m)
+	log.Printf("Opening sample library (%d files, %d folders)", len(m.Library.Entries), len(m.Library.Roots))
+	return ScanLibrary(m)
+}
+
+// CloseLibrary returns to the file browser
+func CloseLibrary(m *model.Model) {
+	m.LibraryTagEditing = false
+	switchToView(m, fileViewConfig())
+	storage.LoadFiles(m)
+}
+
+// ScanLibrary rescans the library folders in the background. The result arrives as a LibraryScanMsg.
+func ScanLibrary(m *model.Model) tea.Cmd {
+	if m.Library == nil || m.LibraryScanning || len(m.Library.Roots) == 0 {
+		return nil
+	}
+	m.LibraryScanning = true
+	roots := append([]string(nil), m.Library.Roots...)
+	previous := append([]library.Entry(nil), m.Library.Entries...)
+	return func() tea.Msg {
+		return LibraryScanMsg{Entries: library.Scan(roots, previous)}
+	}
+}
+
+// ApplyLibraryScan installs the result of a scan and saves the index. Tags edited while the
+// scan was running are kept.
+func ApplyLibraryScan(m *model.Model, msg LibraryScanMsg) {
+	m.LibraryScanning = false
+	if m.Library == nil {
+		return
+	}
+	for i := range msg.Entries {
+		if current := m.Library.Find(msg.Entries[i].Path); current != nil {
+			msg.Entries[i].Tags = current.Tags
+		}
+	}
+	selected := selectedLibraryPath(m)
+	m.Library.Entries = msg.Entries
+	RefreshLibraryResults(m)
+
+	// Keep the cursor on the same file when it is still listed
+	for i, index := range m.LibraryResults {
+		if m.Library.Entries[index].Path == selected {
+			m.CurrentRow = i
+			break
+		}
+	}
+	keepLibraryRowVisible(m)
+	saveLibrary(m)
+}
+
+// RefreshLibraryResults runs the current query against the index
+func RefreshLibraryResults(m *model.Model) {
+	if m.Library == nil {
+		m.LibraryResults = nil
+		return
+	}
+	m.LibraryResults = m.Library.Search(m.LibraryQuery)
+	if m.CurrentRow >= len(m.LibraryResults) {
+		m.CurrentRow = max(0, len(m.LibraryResults)-1)
+	}
+	keepLibraryRowVisible(m)
+}
+
+func keepLibraryRowVisible(m *model.Model) {
+	visibleRows := LibraryVisibleRows(m)
+	if m.CurrentRow < m.ScrollOffset {
+		m.ScrollOffset = m.CurrentRow
+	} else if m.CurrentRow >= m.ScrollOffset+visibleRows {
+		m.ScrollOffset = m.CurrentRow - visibleRows + 1
+	}
+}
+
+// SelectedLibraryEntry returns the entry under the cursor, or nil
+func SelectedLibraryEntry(m *model.Model) *library.Entry {
+	if m.Library == nil || m.CurrentRow < 0 || m.CurrentRow >= len(m.LibraryResults) {
+		return nil
+	}
+	return &m.Library.Entries[m.LibraryResults[m.CurrentRow]]
+}
+
+func selectedLibraryPath(m *model.Model) string {
+	if entry := SelectedLibraryEntry(m); entry != nil {
+		return entry.Path
+	}
+	return ""
+}
+
+func saveLibrary(m *model.Model) {
+	if err := m.Library.Save(m.LibraryPath); err != nil {
+		log.Printf("Error saving sample library: %v", err)
+	}
+}
+
+// SelectLibraryEntry assigns the selected file exactly like picking it in the file browser
+func SelectLibraryEntry(m *model.Model) {
+	if entry := SelectedLibraryEntry(m); entry != nil {
+		SelectFilePath(m, entry.Path)
+	}
+}
+
+// PlayLibraryEntry previews the selected file
+func PlayLibraryEntry(m *model.Model) {
+	if entry := SelectedLibraryEntry(m); entry != nil {
+		audio.PlayFilePath(m, entry.Path)
+	}
+}
+
+// ToggleLibraryRoot adds the file browser folder to the library folders (or removes it) and rescans
+func ToggleLibraryRoot(m *model.Model) tea.Cmd {
+	if m.Library.ToggleRoot(m.CurrentDir) {
+		log.Printf("Added library folder %s", m.CurrentDir)
+	} else {
+		log.Printf("Removed library folder %s", m.CurrentDir)
+		m.Library.Prune()
+		saveLibrary(m)
+		RefreshLibraryResults(m)
+		return nil
+	}
+	saveLibrary(m)
+	return ScanLibrary(m)
+}
+
+// StartLibraryTagEdit starts editing the tags of the selected entry
+func StartLibraryTagEdit(m *model.Model) {
+	entry := SelectedLibraryEntry(m)
+	if entry == nil {
+		return
+	}
+	m.LibraryTagEditing = true
+	m.LibraryTagInput = strings.Join(entry.Tags, " ")
+}
+
+// FinishLibraryTagEdit stores the edited tags
+func FinishLibraryTagEdit(m *model.Model) {
+	m.LibraryTagEditing = false
+	entry := SelectedLibraryEntry(m)
+	if entry == nil {
+		return
+	}
+	m.Library.SetTags(entry.Path, strings.Fields(m.LibraryTagInput))
+	log.Printf("Tags of %s: %v", entry.Path, entry.Tags)
+	saveLibrary(m)
+	RefreshLibraryResults(m)
+}
+
+// HandleLibraryKey handles typing in the library view. It returns false for keys that use the
+// normal handling (navigation, tags, folders, quit).
+func HandleLibraryKey(m *model.Model, msg tea.KeyMsg) (tea.Cmd, bool) {
+	text := &m.LibraryQuery
+	if m.LibraryTagEditing {
+		text = &m.LibraryTagInput
+	}
+
+	switch msg.String() {
+	case "enter":
+		if m.LibraryTagEditing {
+			FinishLibraryTagEdit(m)
+		} else {
+			SelectLibraryEntry(m)
+		}
+	case "esc":
+		if m.LibraryTagEditing {
+			m.LibraryTagEditing = false
+		} else if m.LibraryQuery != "" {
+			m.LibraryQuery = ""
+			RefreshLibraryResults(m)
+		} else {
+			CloseLibrary(m)
+		}
+	case "backspace":
+		if r := []rune(*text); len(r) > 0 {
+			*text = string(r[:len(r)-1])
+		}
+		if !m.LibraryTagEditing {
+			RefreshLibraryResults(m)
+		}
+	default:
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace || msg.Alt {
+			return nil, false
+		}
+		if msg.Type == tea.KeySpace {
+			*text += " "
+		} else {
+			*text += string(msg.Runes)
+		}
+		if !m.LibraryTagEditing {
+			m.CurrentRow = 0
+			m.ScrollOffset = 0
+			RefreshLibraryResults(m)
+		}
+	}
+	return nil, true
+}
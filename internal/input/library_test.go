@@ -0,0 +1,98 @@
+package input
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/library"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestSampleLibrary(t *testing.T) {
+	m := createTestModel()
+	m.LibraryPath = filepath.Join(t.TempDir(), library.IndexFile)
+	root, err := filepath.Abs("../getbpm")
+	require.NoError(t, err)
+
+	// Pick a file for sampler phrase 0 row 2 from the file browser
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = true
+	m.CurrentPhrase = 0
+	m.FileSelectRow = 2
+	m.FileSelectView = types.PhraseView
+	m.CurrentDir = root
+	m.ViewMode = types.FileView
+
+	// "/" opens the library; without folders there is nothing to scan
+	cmd := HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	assert.Nil(t, cmd)
+	assert.Equal(t, types.LibraryView, m.ViewMode)
+	require.NotNil(t, m.Library)
+	assert.Empty(t, m.LibraryResults)
+
+	// Ctrl+A adds the file browser folder and scans it in the background
+	cmd = HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlA})
+	require.NotNil(t, cmd)
+	assert.True(t, m.LibraryScanning)
+	msg, ok := cmd().(LibraryScanMsg)
+	require.True(t, ok)
+	ApplyLibraryScan(m, msg)
+	assert.False(t, m.LibraryScanning)
+	assert.Equal(t, []string{root}, m.Library.Roots)
+	assert.Len(t, m.LibraryResults, len(m.Library.Entries))
+
+	// Typing filters as you go; letters do not trigger their usual bindings
+	for _, r := range "break104" {
+		HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	assert.Equal(t, "break104", m.LibraryQuery)
+	require.NotEmpty(t, m.LibraryResults)
+	assert.Equal(t, "Break104.wav", SelectedLibraryEntry(m).Name())
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "break10", m.LibraryQuery)
+
+	// Enter assigns the file like the file browser does
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	path := filepath.Join(root, "Break104.wav")
+	fileIndex := m.SamplerPhrasesData[0][2][types.ColFilename]
+	require.GreaterOrEqual(t, fileIndex, 0)
+	assert.Equal(t, path, m.SamplerPhrasesFiles[fileIndex])
+	assert.Contains(t, m.FileMetadata, path)
+
+	// Ctrl+T edits the tags of the selected file
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlT})
+	assert.True(t, m.LibraryTagEditing)
+	for _, r := range "amen #drums" {
+		if r == ' ' {
+			HandleKeyInput(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+		} else {
+			HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.LibraryTagEditing)
+	assert.Equal(t, []string{"amen", "drums"}, m.Library.Find(path).Tags)
+
+	// The index is saved with roots and tags
+	saved, err := library.Load(m.LibraryPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amen", "drums"}, saved.Find(path).Tags)
+
+	// Esc clears the search, Shift+Left goes back to the file browser
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, "", m.LibraryQuery)
+
+	// Paging stays on the results and Ctrl+arrows leave the phrases alone
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	assert.Less(t, m.CurrentRow, len(m.LibraryResults))
+	row := append([]int(nil), m.SamplerPhrasesData[0][m.CurrentRow]...)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	assert.Equal(t, row, m.SamplerPhrasesData[0][m.CurrentRow])
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.FileView, m.ViewMode)
+}
@@ -0,0 +1,66 @@
+package input
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/schollz/collidertracker/internal/midiconnector"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// MidiDevicesInterval is how often the MIDI devices are listed again for hot-plugging
+const MidiDevicesInterval = 2 * time.Second
+
+// MidiDevicesTickMsg asks for the MIDI devices to be listed again
+type MidiDevicesTickMsg struct{}
+
+// MidiDevicesMsg carries the result of listing the MIDI devices in the background
+type MidiDevicesMsg struct {
+	Devices  []string
+	Reopened []string // Devices that were plugged back in and opened again
+}
+
+// WatchMidiDevices schedules the next listing of the MIDI devices
+func WatchMidiDevices() tea.Cmd {
+	return tea.Tick(MidiDevicesInterval, func(time.Time) tea.Msg {
+		return MidiDevicesTickMsg{}
+	})
+}
+
+// RefreshMidiDevices lists the MIDI devices in the background. The result arrives as a MidiDevicesMsg.
+func RefreshMidiDevices() tea.Cmd {
+	return func() tea.Msg {
+		devices, reopened := midiconnector.Refresh()
+		return MidiDevicesMsg{Devices: devices, Reopened: reopened}
+	}
+}
+
+// ApplyMidiDevices installs a listing of the MIDI devices. Synths that were plugged back in
+// get their patches again, since they may have lost them.
+func ApplyMidiDevices(m *model.Model, msg MidiDevicesMsg) {
+	for _, device := range msg.Devices {
+		if !slices.Contains(m.AvailableMidiDevices, device) {
+			log.Printf("MIDI device plugged in: %s", device)
+		}
+	}
+	for _, device := range m.AvailableMidiDevices {
+		if !slices.Contains(msg.Devices, device) {
+			log.Printf("MIDI device unplugged: %s", device)
+		}
+	}
+	m.AvailableMidiDevices = msg.Devices
+
+	if m.ViewMode == types.MidiView {
+		maxRow := int(types.MidiSettingsRowFirstDevice) + len(m.AvailableMidiDevices) - 1
+		m.CurrentRow = clampInt(m.CurrentRow, 0, maxRow)
+	}
+
+	for i := range m.MidiSettings {
+		if slices.Contains(msg.Reopened, m.MidiSettings[i].Device) {
+			m.SendMidiPatch(i, true)
+		}
+	}
+}
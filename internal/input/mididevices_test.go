@@ -0,0 +1,33 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestApplyMidiDevices(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MidiView
+	m.AvailableMidiDevices = []string{"Synth A", "Synth B", "Synth C"}
+	m.CurrentRow = int(types.MidiSettingsRowFirstDevice) + 2
+
+	// Unplugging the device under the cursor moves the cursor onto the last device
+	ApplyMidiDevices(m, MidiDevicesMsg{Devices: []string{"Synth A", "Synth B"}})
+	assert.Equal(t, []string{"Synth A", "Synth B"}, m.AvailableMidiDevices)
+	assert.Equal(t, int(types.MidiSettingsRowFirstDevice)+1, m.CurrentRow)
+
+	// Without devices the cursor stays on the settings rows
+	ApplyMidiDevices(m, MidiDevicesMsg{})
+	assert.Empty(t, m.AvailableMidiDevices)
+	assert.Equal(t, int(types.MidiSettingsRowFirstDevice)-1, m.CurrentRow)
+
+	// Plugging in leaves the cursor alone, in other views too
+	m.ViewMode = types.PhraseView
+	m.CurrentRow = 40
+	ApplyMidiDevices(m, MidiDevicesMsg{Devices: []string{"Synth B"}})
+	assert.Equal(t, []string{"Synth B"}, m.AvailableMidiDevices)
+	assert.Equal(t, 40, m.CurrentRow)
+}
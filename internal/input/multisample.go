@@ -0,0 +1,180 @@
+package input
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func multisampleViewConfig(row, col int) ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.MultisampleView,
+		Row:          row,
+		Col:          col,
+		ScrollOffset: 0,
+	}
+}
+
+// OpenMultisampleEditor switches to the zone editor when the SoundMaker being edited is a Multisample
+func OpenMultisampleEditor(m *model.Model) {
+	if m.SoundMakerEditingIndex < 0 || m.SoundMakerEditingIndex >= 255 {
+		return
+	}
+	if m.SoundMakerSettings[m.SoundMakerEditingIndex].Name != types.MultisampleSoundMakerName {
+		return
+	}
+	switchToViewWithVisibilityCheck(m, multisampleViewConfig(0, int(types.MultisampleColFile)))
+	log.Printf("Opening zone editor for SoundMaker %02X", m.SoundMakerEditingIndex)
+}
+
+// CloseMultisampleEditor returns to the SoundMaker view
+func CloseMultisampleEditor(m *model.Model) {
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.SoundMakerView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// MultisampleMaxRow returns the last row of the zone editor. The row after the last zone adds a new zone.
+func MultisampleMaxRow(m *model.Model) int {
+	return len(m.SoundMakerSettings[m.SoundMakerEditingIndex].Zones)
+}
+
+// BrowseMultisampleZoneFile opens the file browser to pick the file of the selected zone
+func BrowseMultisampleZoneFile(m *model.Model) {
+	settings := m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	m.FileSelectRow = m.CurrentRow
+	m.FileSelectCol = m.CurrentCol
+	m.FileSelectView = types.MultisampleView
+
+	// Start in the folder of the zone's current file
+	selectedFilename := ""
+	if m.CurrentRow >= 0 && m.CurrentRow < len(settings.Zones) && settings.Zones[m.CurrentRow].File != "" {
+		fileDir := filepath.Dir(settings.Zones[m.CurrentRow].File)
+		selectedFilename = filepath.Base(settings.Zones[m.CurrentRow].File)
+		if fileDir != "." && fileDir != "" {
+			m.CurrentDir = fileDir
+		}
+	}
+
+	m.ViewMode = types.FileView
+	m.CurrentRow = 0
+	m.CurrentCol = 0
+	m.ScrollOffset = 0
+	storage.LoadFiles(m)
+
+	if selectedFilename != "" {
+		for i, filename := range m.Files {
+			if filename == selectedFilename {
+				m.CurrentRow = i
+				visibleRows := m.GetVisibleRows()
+				if m.CurrentRow >= m.ScrollOffset+visibleRows {
+					m.ScrollOffset = m.CurrentRow - visibleRows + 1
+				}
+				break
+			}
+		}
+	}
+	storage.AutoSave(m)
+}
+
+// SelectMultisampleZoneFile assigns a file picked in the file browser to the zone it was opened
+// for. Picking on the add row keeps adding zones.
+func SelectMultisampleZoneFile(m *model.Model, fullPath string) {
+	settings := &m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	adding := m.FileSelectRow >= len(settings.Zones)
+	zoneIndex := settings.SetZoneFile(m.FileSelectRow, fullPath)
+	if adding {
+		m.FileSelectRow = len(settings.Zones)
+	}
+	log.Printf("Selected file %s for SoundMaker %02X zone %02X", fullPath, m.SoundMakerEditingIndex, zoneIndex)
+	storage.AutoSave(m)
+}
+
+// CloseMultisampleFileBrowser returns from the file browser to the zone that was being edited
+func CloseMultisampleFileBrowser(m *model.Model) {
+	row := clampInt(m.FileSelectRow, 0, MultisampleMaxRow(m))
+	m.FileSelectView = types.PhraseView
+	switchToViewWithVisibilityCheck(m, multisampleViewConfig(row, m.FileSelectCol))
+}
+
+// ModifyMultisampleZoneValue changes the selected zone field. Note columns move by an octave on
+// coarse steps, velocities by 16 and fine tune by 10 cents.
+func ModifyMultisampleZoneValue(m *model.Model, baseDelta float32) {
+	settings := &m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	if m.CurrentRow < 0 || m.CurrentRow >= len(settings.Zones) {
+		return
+	}
+	zone := &settings.Zones[m.CurrentRow]
+
+	switch types.MultisampleZoneColumn(m.CurrentCol) {
+	case types.MultisampleColLowNote:
+		modifier := createIntModifier(
+			func() int { return zone.LowNote },
+			func(v int) { zone.LowNote = v },
+			0, zone.HighNote, "zone low note",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 12))
+	case types.MultisampleColHighNote:
+		modifier := createIntModifier(
+			func() int { return zone.HighNote },
+			func(v int) { zone.HighNote = v },
+			zone.LowNote, 127, "zone high note",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 12))
+	case types.MultisampleColRootNote:
+		modifier := createIntModifier(
+			func() int { return zone.RootNote },
+			func(v int) { zone.RootNote = v },
+			0, 127, "zone root note",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 12))
+	case types.MultisampleColLowVelocity:
+		modifier := createIntModifier(
+			func() int { return zone.LowVelocity },
+			func(v int) { zone.LowVelocity = v },
+			0, zone.HighVelocity, "zone low velocity",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 16))
+	case types.MultisampleColHighVelocity:
+		modifier := createIntModifier(
+			func() int { return zone.HighVelocity },
+			func(v int) { zone.HighVelocity = v },
+			zone.LowVelocity, 127, "zone high velocity",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 16))
+	case types.MultisampleColFineTune:
+		modifier := createIntModifier(
+			func() int { return zone.FineTune },
+			func(v int) { zone.FineTune = v },
+			-100, 100, "zone fine tune",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 10))
+	case types.MultisampleColRoundRobin:
+		modifier := createIntModifier(
+			func() int { return zone.RoundRobin },
+			func(v int) { zone.RoundRobin = v },
+			0, types.MultisampleMaxRoundRobin, "zone round-robin group",
+		)
+		modifyValueWithBounds(modifier, deltaHandler(baseDelta, 4))
+	default:
+		return // The file column is set from the file browser
+	}
+	storage.AutoSave(m)
+}
+
+// DeleteMultisampleZone removes the selected zone
+func DeleteMultisampleZone(m *model.Model) {
+	settings := &m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	if m.CurrentRow < 0 || m.CurrentRow >= len(settings.Zones) {
+		return
+	}
+	settings.RemoveZone(m.CurrentRow)
+	log.Printf("Removed zone %02X from SoundMaker %02X", m.CurrentRow, m.SoundMakerEditingIndex)
+	storage.AutoSave(m)
+}
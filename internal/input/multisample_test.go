@@ -0,0 +1,86 @@
+package input
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestMultisampleEditor(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.SoundMakerView
+	m.SoundMakerEditingIndex = 3
+
+	// Only Multisample SoundMakers have a zone editor
+	m.SoundMakerSettings[3].Name = "PolyPerc"
+	handleShiftRight(m)
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+
+	m.SoundMakerSettings[3].Name = types.MultisampleSoundMakerName
+	handleShiftRight(m)
+	assert.Equal(t, types.MultisampleView, m.ViewMode)
+	assert.Equal(t, 0, m.CurrentRow)
+
+	// With no zones only the add row exists
+	handleDown(m)
+	assert.Equal(t, 0, m.CurrentRow)
+
+	// Shift+Right on the add row browses for files; each pick adds a zone
+	m.CurrentDir = "../getbpm"
+	handleShiftRight(m)
+	assert.Equal(t, types.FileView, m.ViewMode)
+	assert.Equal(t, types.MultisampleView, m.FileSelectView)
+	for i, file := range m.Files {
+		if file == "Break078.wav" || file == "Break104.wav" {
+			m.CurrentRow = i
+			handleSpace(m)
+		}
+	}
+	zones := m.SoundMakerSettings[3].Zones
+	assert.Len(t, zones, 2)
+	assert.Equal(t, filepath.Join("../getbpm", "Break078.wav"), zones[0].File)
+	assert.Equal(t, 60, zones[0].RootNote)
+
+	// Shift+Left returns to the add row of the zone editor
+	handleShiftLeft(m)
+	assert.Equal(t, types.MultisampleView, m.ViewMode)
+	assert.Equal(t, 2, m.CurrentRow)
+
+	// Edit the first zone
+	m.CurrentRow = 0
+	m.CurrentCol = int(types.MultisampleColHighNote)
+	ModifyMultisampleZoneValue(m, 1.0)
+	ModifyMultisampleZoneValue(m, 0.05)
+	assert.Equal(t, 73, m.SoundMakerSettings[3].Zones[0].HighNote)
+
+	// Low note cannot pass the high note
+	m.CurrentCol = int(types.MultisampleColLowNote)
+	for i := 0; i < 3; i++ {
+		ModifyMultisampleZoneValue(m, 1.0)
+	}
+	assert.Equal(t, 73, m.SoundMakerSettings[3].Zones[0].LowNote)
+
+	m.CurrentCol = int(types.MultisampleColFineTune)
+	ModifyMultisampleZoneValue(m, -1.0)
+	assert.Equal(t, -10, m.SoundMakerSettings[3].Zones[0].FineTune)
+
+	m.CurrentCol = int(types.MultisampleColRoundRobin)
+	ModifyMultisampleZoneValue(m, 0.05)
+	assert.Equal(t, 1, m.SoundMakerSettings[3].Zones[0].RoundRobin)
+
+	// Columns stop at the round-robin group
+	handleRight(m)
+	assert.Equal(t, int(types.MultisampleColRoundRobin), m.CurrentCol)
+
+	// Backspace removes the zone
+	handleBackspace(m)
+	assert.Len(t, m.SoundMakerSettings[3].Zones, 1)
+	assert.Equal(t, filepath.Join("../getbpm", "Break104.wav"), m.SoundMakerSettings[3].Zones[0].File)
+
+	// Shift+Left goes back to the SoundMaker view
+	handleShiftLeft(m)
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+}
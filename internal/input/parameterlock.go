@@ -0,0 +1,94 @@
+package input
+
+import (
+	"log"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// OpenParameterLocks opens the parameter locks of the instrument row under the cursor
+func OpenParameterLocks(m *model.Model) {
+	if m.ViewMode != types.PhraseView || !isInstrumentTrack(m, m.CurrentTrack) {
+		return
+	}
+	if m.CurrentRow < 0 || m.CurrentRow >= 255 {
+		return
+	}
+	m.ParameterLockRow = m.CurrentRow
+	m.LastPhraseRow = m.CurrentRow
+	m.LastPhraseCol = m.CurrentCol
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.ParameterLockView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// CloseParameterLocks returns to the phrase row whose locks were edited
+func CloseParameterLocks(m *model.Model) {
+	switchToViewWithVisibilityCheck(m, phraseViewConfig(m.LastPhraseRow, m.LastPhraseCol))
+}
+
+// ParameterLockSoundMaker returns the SoundMaker the row being edited plays (sticky), -1 for none
+func ParameterLockSoundMaker(m *model.Model) int {
+	index := GetEffectiveValueForTrack(m, m.CurrentPhrase, m.ParameterLockRow, int(types.ColSoundMaker), m.CurrentTrack)
+	if index < 0 || index >= 255 {
+		return -1
+	}
+	return index
+}
+
+// ParameterLockDefinition returns the definition of the SoundMaker of the row being edited
+func ParameterLockDefinition(m *model.Model) (types.InstrumentDefinition, bool) {
+	index := ParameterLockSoundMaker(m)
+	if index == -1 {
+		return types.InstrumentDefinition{}, false
+	}
+	return types.GetInstrumentDefinition(m.SoundMakerSettings[index].Name)
+}
+
+// parameterLockParam returns the parameter on the selected row of the editor
+func parameterLockParam(m *model.Model) (types.InstrumentParameterDef, bool) {
+	def, ok := ParameterLockDefinition(m)
+	if !ok || m.CurrentRow < 0 || m.CurrentRow >= len(def.Parameters) {
+		return types.InstrumentParameterDef{}, false
+	}
+	return def.Parameters[m.CurrentRow], true
+}
+
+// ModifyParameterLockValue locks the selected parameter on the row, starting from the value
+// of the SoundMaker slot, and moves it like the SoundMaker view does
+func ModifyParameterLockValue(m *model.Model, baseDelta float32) {
+	param, ok := parameterLockParam(m)
+	if !ok {
+		return
+	}
+	oldValue, locked := m.ParameterLocks.Get(m.CurrentPhrase, m.ParameterLockRow, param.Key)
+	if !locked {
+		oldValue = m.SoundMakerSettings[ParameterLockSoundMaker(m)].GetParameterValue(param.Key)
+	}
+	newValue, _ := stepSoundMakerParameter(param, oldValue, baseDelta)
+	if m.ParameterLocks == nil {
+		m.ParameterLocks = make(types.ParameterLocks)
+	}
+	m.ParameterLocks.Set(m.CurrentPhrase, m.ParameterLockRow, param.Key, newValue)
+	log.Printf("Locked %s of phrase %02X row %02X to %f", param.Key, m.CurrentPhrase, m.ParameterLockRow, newValue)
+	storage.AutoSave(m)
+}
+
+// ClearParameterLock hands the selected parameter of the row back to the SoundMaker slot
+func ClearParameterLock(m *model.Model) {
+	param, ok := parameterLockParam(m)
+	if !ok {
+		return
+	}
+	if _, locked := m.ParameterLocks.Get(m.CurrentPhrase, m.ParameterLockRow, param.Key); !locked {
+		return
+	}
+	m.ParameterLocks.Clear(m.CurrentPhrase, m.ParameterLockRow, param.Key)
+	log.Printf("Unlocked %s of phrase %02X row %02X", param.Key, m.CurrentPhrase, m.ParameterLockRow)
+	storage.AutoSave(m)
+}
@@ -0,0 +1,109 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestParameterLockView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = false // Instrument
+	m.CurrentPhrase = 3
+	m.CurrentRow = 6
+	m.CurrentCol = int(types.InstrumentColSOMI)
+	m.SoundMakerSettings[2].Name = "PolyPerc"
+	m.SoundMakerSettings[2].InitializeParameters()
+	m.SoundMakerSettings[2].SetParameterValue("A", 0x40)
+	m.InstrumentPhrasesData[3][4][types.ColSoundMaker] = 2 // Sticky down to row 6
+
+	// "o" opens the locks of the row, which plays the SoundMaker of row 4
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	assert.Equal(t, types.ParameterLockView, m.ViewMode)
+	assert.Equal(t, 6, m.ParameterLockRow)
+	assert.Equal(t, 2, ParameterLockSoundMaker(m))
+
+	// Locking starts from the value of the slot, which is left alone
+	ModifyParameterLockValue(m, 1.0)
+	value, locked := m.ParameterLocks.Get(3, 6, "A")
+	assert.True(t, locked)
+	assert.InDelta(t, 0x40+0.16, value, 0.001)
+	assert.Equal(t, float32(0x40), m.SoundMakerSettings[2].GetParameterValue("A"))
+	assert.Nil(t, m.ParameterLocks.Row(3, 7), "locks belong to one row")
+
+	// Unset parameters start from their minimum
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	ModifyParameterLockValue(m, 0.05)
+	value, _ = m.ParameterLocks.Get(3, 6, "B")
+	assert.Equal(t, float32(0), value)
+	assert.Len(t, m.ParameterLocks.Row(3, 6), 2)
+
+	// Backspace unlocks, and the row drops out once nothing is locked
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Empty(t, m.ParameterLocks)
+
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+	assert.Equal(t, 6, m.CurrentRow)
+	assert.Equal(t, int(types.InstrumentColSOMI), m.CurrentCol)
+}
+
+func TestParameterLocksOnlyForInstrumentRows(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 1
+	m.TrackTypes[1] = true // Sampler
+
+	OpenParameterLocks(m)
+	assert.Equal(t, types.PhraseView, m.ViewMode)
+
+	// A row without a SoundMaker has nothing to lock
+	m.TrackTypes[1] = false
+	OpenParameterLocks(m)
+	assert.Equal(t, types.ParameterLockView, m.ViewMode)
+	assert.Equal(t, -1, ParameterLockSoundMaker(m))
+	ModifyParameterLockValue(m, 1.0)
+	assert.Empty(t, m.ParameterLocks)
+}
+
+func TestParameterLocksMoveWithRows(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = false // Instrument
+	m.CurrentPhrase = 3
+	m.CurrentRow = 6
+	m.InstrumentPhrasesData[3][6][types.ColNote] = 60
+	m.ParameterLocks.Set(3, 6, "A", 0x40)
+	m.ParameterLocks.Set(3, 8, "B", 0x10)
+
+	// Cutting a row takes its locks along, pasting replaces the locks of the target row
+	CutRowToClipboard(m)
+	assert.Nil(t, m.ParameterLocks.Row(3, 6))
+	m.CurrentRow = 8
+	PasteRowFromClipboard(m)
+	assert.Equal(t, map[string]float32{"A": 0x40}, m.ParameterLocks.Row(3, 8))
+	m.CurrentRow = 9
+	PasteRowFromClipboard(m)
+	m.ParameterLocks.Set(3, 9, "A", 0x50)
+	assert.Equal(t, map[string]float32{"A": 0x40}, m.ParameterLocks.Row(3, 8), "pasted rows do not share their locks")
+
+	// A cloned phrase gets its own copy of the locks
+	DeepCopyCurrentPhraseToClipboard(m)
+	clone := m.Clipboard.Value
+	assert.Equal(t, map[string]float32{"A": 0x40}, m.ParameterLocks.Row(clone, 8))
+	assert.Equal(t, map[string]float32{"A": 0x50}, m.ParameterLocks.Row(clone, 9))
+
+	// Deleting a row drops its locks
+	m.CurrentRow = 8
+	handleCtrlH(m)
+	assert.Nil(t, m.ParameterLocks.Row(3, 8))
+	assert.NotNil(t, m.ParameterLocks.Row(clone, 8))
+}
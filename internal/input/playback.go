@@ -139,9 +139,42 @@ func Tick(m *model.Model) tea.Cmd {
 	})
 }
 
+// ArpeggioTickMsg fires when the arpeggio step scheduled for its time is due
+type ArpeggioTickMsg time.Time
+
+// ArpeggioTick schedules the next arpeggio step next to the row ticks of playback. It
+// returns nil when no arpeggio is playing or a tick for the step is already on its way.
+func ArpeggioTick(m *model.Model) tea.Cmd {
+	next, ok := m.NextArpeggioStep()
+	if !ok {
+		return nil
+	}
+	if !m.ArpeggioTickAt.IsZero() && !m.ArpeggioTickAt.After(next) {
+		return nil
+	}
+	m.ArpeggioTickAt = next
+	return tea.Tick(time.Until(next), func(time.Time) tea.Msg {
+		return ArpeggioTickMsg(next)
+	})
+}
+
+// HandleArpeggioTick plays the arpeggio steps that are due and schedules the next one
+func HandleArpeggioTick(m *model.Model, msg ArpeggioTickMsg) tea.Cmd {
+	if time.Time(msg).Equal(m.ArpeggioTickAt) {
+		m.ArpeggioTickAt = time.Time{}
+	}
+	m.AdvanceArpeggios(time.Now())
+	return ArpeggioTick(m)
+}
+
 func AdvancePlayback(m *model.Model) {
 	oldRow := m.PlaybackRow
 
+	// A bounce stops after one pass instead of looping
+	if advanceBounce(m) {
+		return
+	}
+
 	if m.PlaybackMode == types.SongView {
 		// Song playback mode with per-track tick counting
 		log.Printf("Song playback advancing - checking %d tracks", 8)
@@ -169,6 +202,7 @@ func AdvancePlayback(m *model.Model) {
 			if !advanceToNextPlayableRowForTrack(m, track) {
 				// Track finished, deactivate
 				m.SongPlaybackActive[track] = false
+				m.StopAutomationForTrack(track)
 				log.Printf("Song track %d deactivated (end of sequence)", track)
 				continue
 			}
@@ -180,6 +214,7 @@ func AdvancePlayback(m *model.Model) {
 			phraseNum := m.SongPlaybackPhrase[track]
 			currentRow := m.SongPlaybackRowInPhrase[track]
 			if phraseNum >= 0 && phraseNum < 255 && currentRow >= 0 && currentRow < 255 {
+				startSongAutomationRow(m, track)
 				EmitRowDataFor(m, phraseNum, currentRow, track)
 				log.Printf("Song track %d emitted phrase %02X row %d with %d ticks", track, phraseNum, currentRow, m.SongPlaybackTicksLeft[track])
 			}
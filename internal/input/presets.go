@@ -0,0 +1,173 @@
+package input
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/soundmakers"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func presetViewConfig() ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.PresetView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	}
+}
+
+// PresetVisibleRows returns how many presets fit below the header lines
+func PresetVisibleRows(m *model.Model) int {
+	return max(1, m.GetVisibleRows()-2)
+}
+
+// RefreshPresets reloads the presets of the SoundMaker being browsed
+func RefreshPresets(m *model.Model) {
+	m.Presets = soundmakers.LoadPresets(m.PresetDir, m.PresetSoundMaker)
+	clampPresetCursor(m)
+}
+
+// clampPresetCursor keeps the cursor on a preset and in view
+func clampPresetCursor(m *model.Model) {
+	m.CurrentRow = clampInt(m.CurrentRow, 0, max(0, len(m.Presets)-1))
+	visibleRows := PresetVisibleRows(m)
+	if m.CurrentRow < m.ScrollOffset {
+		m.ScrollOffset = m.CurrentRow
+	} else if m.CurrentRow >= m.ScrollOffset+visibleRows {
+		m.ScrollOffset = m.CurrentRow - visibleRows + 1
+	}
+}
+
+// OpenPresets opens the preset library on the presets of the SoundMaker being edited
+func OpenPresets(m *model.Model) {
+	if m.SoundMakerEditingIndex < 0 || m.SoundMakerEditingIndex >= 255 {
+		return
+	}
+	m.PresetSoundMaker = m.SoundMakerSettings[m.SoundMakerEditingIndex].Name
+	if _, exists := types.GetInstrumentDefinition(m.PresetSoundMaker); !exists {
+		m.PresetSoundMaker = types.GetAvailableSoundMakers()[0]
+	}
+	m.PresetNaming = false
+	m.PresetNameInput = ""
+	m.PresetMessage = ""
+	switchToView(m, presetViewConfig())
+	RefreshPresets(m)
+	log.Printf("Opening presets of %s for SoundMaker %02X", m.PresetSoundMaker, m.SoundMakerEditingIndex)
+}
+
+// ClosePresets returns to the SoundMaker view
+func ClosePresets(m *model.Model) {
+	m.PresetNaming = false
+	m.Presets = nil
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.SoundMakerView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// SelectedPreset returns the preset under the cursor, or nil if there is none
+func SelectedPreset(m *model.Model) *soundmakers.Preset {
+	if m.CurrentRow < 0 || m.CurrentRow >= len(m.Presets) {
+		return nil
+	}
+	return &m.Presets[m.CurrentRow]
+}
+
+// CyclePresetSoundMaker browses the presets of the previous or next SoundMaker
+func CyclePresetSoundMaker(m *model.Model, delta int) {
+	names := types.GetAvailableSoundMakers()
+	index := slices.Index(names, m.PresetSoundMaker)
+	index = ((index+delta)%len(names) + len(names)) % len(names)
+	m.PresetSoundMaker = names[index]
+	m.CurrentRow = 0
+	m.ScrollOffset = 0
+	RefreshPresets(m)
+}
+
+// LoadSelectedPreset loads the selected preset into the SoundMaker being edited and
+// returns to the SoundMaker view
+func LoadSelectedPreset(m *model.Model) {
+	preset := SelectedPreset(m)
+	if preset == nil {
+		return
+	}
+	preset.Apply(&m.SoundMakerSettings[m.SoundMakerEditingIndex])
+	log.Printf("Loaded preset %s (%s) into SoundMaker %02X", preset.Name, preset.SoundMaker, m.SoundMakerEditingIndex)
+	ClosePresets(m)
+}
+
+// AuditionSelectedPreset plays the last edited phrase row with the selected preset loaded,
+// leaving the SoundMaker as it was
+func AuditionSelectedPreset(m *model.Model) {
+	preset := SelectedPreset(m)
+	if preset == nil {
+		return
+	}
+	settings := &m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	saved := *settings
+	preset.Apply(settings)
+	EmitLastSelectedPhraseRowData(m)
+	*settings = saved
+}
+
+// SavePresetFromSlot saves the SoundMaker being edited under the typed name
+func SavePresetFromSlot(m *model.Model) {
+	settings := m.SoundMakerSettings[m.SoundMakerEditingIndex]
+	preset, err := soundmakers.SavePreset(m.PresetDir, m.PresetNameInput, settings)
+	if err != nil {
+		log.Printf("Saving preset failed: %v", err)
+		m.PresetMessage = fmt.Sprintf("Save failed: %v", err)
+		return
+	}
+	m.PresetNaming = false
+	m.PresetNameInput = ""
+	m.PresetMessage = fmt.Sprintf("Saved %s", preset.Path)
+	m.PresetSoundMaker = preset.SoundMaker
+	RefreshPresets(m)
+	for i := range m.Presets {
+		if m.Presets[i].Path == preset.Path {
+			m.CurrentRow = i
+		}
+	}
+	clampPresetCursor(m)
+	log.Printf("Saved preset %s to %s", preset.Name, preset.Path)
+}
+
+// StartPresetNaming starts typing the name the SoundMaker is saved under
+func StartPresetNaming(m *model.Model) {
+	m.PresetNaming = true
+	m.PresetNameInput = ""
+	m.PresetMessage = ""
+}
+
+// HandlePresetNameKey handles typing the name of a preset. It returns false for keys that use
+// the normal handling (quit, save).
+func HandlePresetNameKey(m *model.Model, msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter":
+		SavePresetFromSlot(m)
+	case "esc":
+		m.PresetNaming = false
+	case "backspace":
+		if r := []rune(m.PresetNameInput); len(r) > 0 {
+			m.PresetNameInput = string(r[:len(r)-1])
+		}
+	default:
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace || msg.Alt {
+			return nil, false
+		}
+		if msg.Type == tea.KeySpace {
+			m.PresetNameInput += " "
+		} else {
+			m.PresetNameInput += string(msg.Runes)
+		}
+	}
+	return nil, true
+}
@@ -0,0 +1,66 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestPresetLibrary(t *testing.T) {
+	m := createTestModel()
+	m.PresetDir = t.TempDir()
+	m.SoundMakerEditingIndex = 3
+	m.SoundMakerSettings[3].Name = "SuperSaw"
+	m.SoundMakerSettings[3].InitializeParameters()
+	m.SoundMakerSettings[3].SetParameterValue("detune", 1.5)
+	m.ViewMode = types.SoundMakerView
+
+	// "/" opens the presets of the slot's SoundMaker
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	assert.Equal(t, types.PresetView, m.ViewMode)
+	assert.Equal(t, "SuperSaw", m.PresetSoundMaker)
+	assert.Empty(t, m.Presets)
+
+	// N starts naming; typed letters go into the name instead of their usual bindings
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	require.True(t, m.PresetNaming)
+	for _, r := range "wide pad" {
+		if r == ' ' {
+			HandleKeyInput(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+		} else {
+			HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.PresetNaming)
+	require.Len(t, m.Presets, 1)
+	assert.Equal(t, "wide pad", m.Presets[0].Name)
+
+	// Browsing another SoundMaker lists its own presets
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRight})
+	assert.NotEqual(t, "SuperSaw", m.PresetSoundMaker)
+	assert.Empty(t, m.Presets)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyLeft})
+	require.Len(t, m.Presets, 1)
+
+	// Loading into another slot copies the values and returns to the SoundMaker view
+	m.SoundMakerEditingIndex = 7
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+	assert.Equal(t, "SuperSaw", m.SoundMakerSettings[7].Name)
+	assert.Equal(t, float32(1.5), m.SoundMakerSettings[7].GetParameterValue("detune"))
+
+	// Auditioning leaves the slot as it was
+	m.SoundMakerSettings[7].SetParameterValue("detune", 0.1)
+	OpenPresets(m)
+	AuditionSelectedPreset(m)
+	assert.Equal(t, float32(0.1), m.SoundMakerSettings[7].GetParameterValue("detune"))
+
+	// Shift+Left goes back without loading
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.SoundMakerView, m.ViewMode)
+}
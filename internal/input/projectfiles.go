@@ -0,0 +1,146 @@
+package input
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func projectFilesViewConfig(row int) ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.ProjectFilesView,
+		Row:          row,
+		Col:          0,
+		ScrollOffset: 0,
+	}
+}
+
+// OpenProjectFiles checks the project's sample files and lists the issues found
+func OpenProjectFiles(m *model.Model) {
+	m.ProjectFileIssues = storage.AnalyzeProjectFiles(m)
+	switchToView(m, projectFilesViewConfig(0))
+	log.Printf("Project files: %d issues", len(m.ProjectFileIssues))
+}
+
+// CloseProjectFiles returns to the Preferences view
+func CloseProjectFiles(m *model.Model) {
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.SettingsView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// refreshProjectFiles checks the files again after a change and keeps the cursor in range
+func refreshProjectFiles(m *model.Model, row int) {
+	m.ProjectFileIssues = storage.AnalyzeProjectFiles(m)
+	m.ProjectFileDeleteConfirm = ""
+	row = clampInt(row, 0, max(0, len(m.ProjectFileIssues)-1))
+	switchToViewWithVisibilityCheck(m, projectFilesViewConfig(row))
+}
+
+// SelectedProjectFileIssue returns the issue under the cursor, or nil
+func SelectedProjectFileIssue(m *model.Model) *types.ProjectFileIssue {
+	if m.CurrentRow < 0 || m.CurrentRow >= len(m.ProjectFileIssues) {
+		return nil
+	}
+	return &m.ProjectFileIssues[m.CurrentRow]
+}
+
+// ResolveProjectFileIssue deletes, merges or drops the selected issue. An untracked file is
+// only deleted when it is resolved twice in a row.
+func ResolveProjectFileIssue(m *model.Model) {
+	issue := SelectedProjectFileIssue(m)
+	if issue == nil || issue.Kind == types.ProjectFileMissing {
+		return
+	}
+	var err error
+	if issue.Kind == types.ProjectFileUntracked {
+		if m.ProjectFileDeleteConfirm != issue.Path {
+			m.ProjectFileDeleteConfirm = issue.Path
+			return
+		}
+		err = storage.DeleteUntrackedFile(m, issue.Path)
+	} else {
+		err = storage.ResolveProjectFileIssue(m, *issue)
+	}
+	if err != nil {
+		log.Printf("Error resolving %s file %s: %v", issue.Kind, issue.Path, err)
+	}
+	refreshProjectFiles(m, m.CurrentRow)
+}
+
+// ResolveAllProjectFileIssues deletes all unused files, merges all duplicates and drops stale
+// metadata. Missing files are kept so that they can be relinked, and untracked files are
+// only deleted one by one.
+func ResolveAllProjectFileIssues(m *model.Model) {
+	resolved := 0
+	for _, issue := range m.ProjectFileIssues {
+		if issue.Kind == types.ProjectFileMissing || issue.Kind == types.ProjectFileUntracked {
+			continue
+		}
+		if err := storage.ResolveProjectFileIssue(m, issue); err != nil {
+			log.Printf("Error resolving %s file %s: %v", issue.Kind, issue.Path, err)
+			continue
+		}
+		resolved++
+	}
+	log.Printf("Consolidated project: %d issues resolved", resolved)
+	refreshProjectFiles(m, 0)
+}
+
+// BrowseRelinkFile opens the file browser to pick a new location for a missing file
+func BrowseRelinkFile(m *model.Model) {
+	issue := SelectedProjectFileIssue(m)
+	if issue == nil || issue.Kind != types.ProjectFileMissing {
+		return
+	}
+	m.FileSelectRow = m.CurrentRow
+	m.FileSelectCol = 0
+	m.FileSelectView = types.ProjectFilesView
+
+	// Start in the missing file's folder when it still exists
+	if dir := filepath.Dir(issue.Path); dir != "." && dir != "" {
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			m.CurrentDir = dir
+		}
+	}
+	m.ViewMode = types.FileView
+	m.CurrentRow = 0
+	m.CurrentCol = 0
+	m.ScrollOffset = 0
+	storage.LoadFiles(m)
+}
+
+// RelinkSelectedFile relinks the missing file the file browser was opened for to the picked
+// file and goes back to the project files view
+func RelinkSelectedFile(m *model.Model, fullPath string) {
+	if m.FileSelectRow >= 0 && m.FileSelectRow < len(m.ProjectFileIssues) {
+		storage.RelinkFile(m, m.ProjectFileIssues[m.FileSelectRow].Path, fullPath)
+	}
+	m.FileSelectView = types.PhraseView
+	refreshProjectFiles(m, m.FileSelectRow)
+}
+
+// CloseRelinkFileBrowser returns from the file browser to the project files view
+func CloseRelinkFileBrowser(m *model.Model) {
+	m.FileSelectView = types.PhraseView
+	switchToViewWithVisibilityCheck(m, projectFilesViewConfig(clampInt(m.FileSelectRow, 0, max(0, len(m.ProjectFileIssues)-1))))
+}
+
+// moveProjectFilesRow moves the cursor and keeps it on screen
+func moveProjectFilesRow(m *model.Model, delta int) {
+	m.ProjectFileDeleteConfirm = ""
+	m.CurrentRow = clampInt(m.CurrentRow+delta, 0, max(0, len(m.ProjectFileIssues)-1))
+	visibleRows := m.GetVisibleRows()
+	if m.CurrentRow < m.ScrollOffset {
+		m.ScrollOffset = m.CurrentRow
+	} else if m.CurrentRow >= m.ScrollOffset+visibleRows {
+		m.ScrollOffset = m.CurrentRow - visibleRows + 1
+	}
+}
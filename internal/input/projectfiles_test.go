@@ -0,0 +1,98 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestProjectFiles(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	breakA := filepath.Join("../getbpm", "Break078.wav")
+	breakB := filepath.Join("../getbpm", "Break104.wav")
+	missing := filepath.Join("../getbpm", "missing.wav")
+	m.SamplerPhrasesFiles = []string{breakA, breakB, missing}
+	m.SamplerPhrasesData[0][0][types.ColFilename] = 0
+	m.SamplerPhrasesData[0][1][types.ColFilename] = 2
+
+	// Shift+Right from Preferences checks the project's files
+	m.ViewMode = types.SettingsView
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftRight})
+	assert.Equal(t, types.ProjectFilesView, m.ViewMode)
+	require.Len(t, m.ProjectFileIssues, 2)
+	assert.Equal(t, types.ProjectFileIssue{Kind: types.ProjectFileUnused, Path: breakB}, m.ProjectFileIssues[0])
+	assert.Equal(t, types.ProjectFileIssue{Kind: types.ProjectFileMissing, Path: missing, Uses: 1}, m.ProjectFileIssues[1])
+
+	// Phrase editing keys do nothing here
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.Equal(t, 0, m.SamplerPhrasesData[0][0][types.ColFilename])
+
+	// J moves down only in vim mode, as everywhere else
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, 0, m.CurrentRow)
+	m.VimMode = true
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, 1, m.CurrentRow)
+	m.VimMode = false
+
+	// Missing files cannot be deleted, only relinked
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, m.CurrentRow)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Len(t, m.ProjectFileIssues, 2)
+
+	// Removing the unused entry renumbers the rows; the file itself is outside the project
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, []string{breakA, missing}, m.SamplerPhrasesFiles)
+	assert.Equal(t, 1, m.SamplerPhrasesData[0][1][types.ColFilename])
+	assert.FileExists(t, breakB)
+	require.Len(t, m.ProjectFileIssues, 1)
+
+	// Shift+Right browses for the missing file in its folder; Shift+Left comes back
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftRight})
+	assert.Equal(t, types.FileView, m.ViewMode)
+	assert.Equal(t, types.ProjectFilesView, m.FileSelectView)
+	assert.Equal(t, "../getbpm", m.CurrentDir)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.ProjectFilesView, m.ViewMode)
+	assert.Equal(t, types.PhraseView, m.FileSelectView)
+
+	// Picking a file relinks every use of the missing one
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftRight})
+	for i, file := range m.Files {
+		if file == "Break104.wav" {
+			m.CurrentRow = i
+			handleSpace(m)
+		}
+	}
+	assert.Equal(t, types.ProjectFilesView, m.ViewMode)
+	assert.Equal(t, []string{breakA, breakB}, m.SamplerPhrasesFiles)
+	assert.Equal(t, 1, m.SamplerPhrasesData[0][1][types.ColFilename])
+	assert.Empty(t, m.ProjectFileIssues)
+
+	// A recording in the save folder is listed but only deleted by a second Backspace
+	recording := filepath.Join(m.SaveFolder, "recording.wav")
+	require.NoError(t, os.WriteFile(recording, []byte("take"), 0644))
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	require.Equal(t, []types.ProjectFileIssue{{Kind: types.ProjectFileUntracked, Path: recording}}, m.ProjectFileIssues)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.FileExists(t, recording)
+	assert.Equal(t, recording, m.ProjectFileDeleteConfirm)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.NoFileExists(t, recording)
+	assert.Empty(t, m.ProjectFileIssues)
+
+	// Shift+Left returns to Preferences
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.SettingsView, m.ViewMode)
+}
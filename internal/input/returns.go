@@ -0,0 +1,69 @@
+package input
+
+import (
+	"log"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// OpenReturns opens the return buses from the mixer, on the return of the selected send row
+func OpenReturns(m *model.Model) {
+	row := max(0, min(m.CurrentMixerRow-types.MixerRowSend, types.ReturnCount-1))
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.ReturnsView,
+		Row:          row,
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// CloseReturns returns to the mixer
+func CloseReturns(m *model.Model) {
+	switchToView(m, mixerViewConfig())
+}
+
+// ModifyReturnValue moves the selected parameter of the selected return like the SoundMaker
+// view does
+func ModifyReturnValue(m *model.Model, delta float32) {
+	if m.CurrentRow < 0 || m.CurrentRow >= types.ReturnCount {
+		return
+	}
+	module := types.ReturnModules[m.CurrentRow]
+	if m.CurrentCol < 0 || m.CurrentCol >= len(module.Parameters) {
+		return
+	}
+	settings := &m.Returns[m.CurrentRow]
+	param := module.Parameters[m.CurrentCol]
+	newValue, _ := stepSoundMakerParameter(param, settings.Value(param), delta)
+	if settings.Parameters == nil {
+		settings.Parameters = make(map[string]float32)
+	}
+	settings.Parameters[param.Key] = newValue
+	log.Printf("Modified %s return %s: %f", module.Name, param.Key, newValue)
+	m.SendOSCReturnMessage(m.CurrentRow)
+	storage.AutoSave(m)
+}
+
+// ModifyMixerSend adjusts the send of the selected track to the return of the selected
+// mixer row, by 10% for coarse and 1% for fine steps
+func ModifyMixerSend(m *model.Model, delta float32) {
+	ret := m.CurrentMixerRow - types.MixerRowSend
+	if m.CurrentMixerTrack < 0 || m.CurrentMixerTrack >= len(m.TrackSends) || ret < 0 || ret >= types.ReturnCount {
+		return
+	}
+	step := float32(1)
+	if delta >= 1.0 || delta <= -1.0 {
+		step = 10
+	}
+	if delta < 0 {
+		step = -step
+	}
+	oldValue := m.TrackSends[m.CurrentMixerTrack][ret]
+	newValue := max(0, min(oldValue+step, 100))
+	m.TrackSends[m.CurrentMixerTrack][ret] = newValue
+	log.Printf("Modified mixer track %d %s send: %.0f%% -> %.0f%%", m.CurrentMixerTrack+1, types.ReturnModules[ret].Name, oldValue, newValue)
+	m.SendOSCTrackSendMessage(m.CurrentMixerTrack)
+	storage.AutoSave(m)
+}
@@ -0,0 +1,56 @@
+package input
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestMixerSends(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MixerView
+	m.CurrentMixerTrack = 1
+
+	// Below the set level are the sends of the track
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, types.MixerRowSend, m.CurrentMixerRow)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, types.MixerRowSend+types.ReturnDelay, m.CurrentMixerRow)
+
+	ModifyMixerSend(m, 1.0)
+	ModifyMixerSend(m, 1.0)
+	ModifyMixerSend(m, -0.05)
+	assert.Equal(t, float32(19), m.TrackSends[1][types.ReturnDelay])
+	ModifyMixerSend(m, -1.0)
+	ModifyMixerSend(m, -1.0)
+	assert.Equal(t, float32(0), m.TrackSends[1][types.ReturnDelay])
+	assert.Equal(t, float32(-6), m.TrackSetLevels[1])
+
+	// Shift+Right on a send opens its return
+	handleShiftRight(m)
+	assert.Equal(t, types.ReturnsView, m.ViewMode)
+	assert.Equal(t, types.ReturnDelay, m.CurrentRow)
+
+	delayTime := types.ReturnModules[types.ReturnDelay].Parameters[0]
+	ModifyReturnValue(m, 1.0)
+	assert.Equal(t, "1/4T", types.FormatDelayDivision(m.Returns[types.ReturnDelay].Value(delayTime)))
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyUp})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRight})
+	ModifyReturnValue(m, -1.0)
+	assert.Equal(t, float32(4500), m.Returns[types.ReturnReverb].Parameters["reverbDamping"])
+
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.MixerView, m.ViewMode)
+
+	// The Input track has no sends
+	m.CurrentMixerTrack = 7
+	handleRight(m)
+	assert.Equal(t, 8, m.CurrentMixerTrack)
+	assert.Equal(t, types.MixerRowSetLevel, m.CurrentMixerRow)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, types.MixerRowSetLevel, m.CurrentMixerRow)
+}
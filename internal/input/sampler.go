@@ -3,9 +3,13 @@ package input
 import (
 	"fmt"
 	"log"
+	"math"
+	"slices"
 
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/modulation"
 	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
 )
 
 func ModifyRetriggerValue(m *model.Model, baseDelta float32) {
@@ -271,6 +275,86 @@ func ModifyTimestrechValue(m *model.Model, baseDelta float32) {
 	storage.AutoSave(m)
 }
 
+// ModifyGranularValue changes the selected granular setting. Coarse steps (Ctrl+Up/Down) are
+// 10 ms, 10 grains/s, 10% and 1 semitone; fine steps (Ctrl+Left/Right) are 1 ms, 1 grain/s,
+// 1% and 0.1 semitone.
+func ModifyGranularValue(m *model.Model, baseDelta float32) {
+	if m.GranularEditingIndex < 0 || m.GranularEditingIndex >= 255 {
+		return
+	}
+	settings := &m.GranularSettings[m.GranularEditingIndex]
+
+	coarse := baseDelta == 1.0 || baseDelta == -1.0
+	sign := float32(1)
+	if baseDelta < 0 {
+		sign = -1
+	}
+	step := func(coarseStep, fineStep float32) float32 {
+		if coarse {
+			return sign * coarseStep
+		}
+		return sign * fineStep
+	}
+	// Round to the fine step so that repeated steps do not drift
+	snap := func(v, grid float32) float32 {
+		return float32(math.Round(float64(v/grid))) * grid
+	}
+	label := func(name string) string {
+		return fmt.Sprintf("granular %02X %s", m.GranularEditingIndex, name)
+	}
+
+	var modifier ValueModifier
+	var delta float32
+	switch types.GranularSettingsRow(m.CurrentRow) {
+	case types.GranularSettingsRowSize:
+		modifier = createFloatModifier(
+			func() float32 { return settings.Size },
+			func(v float32) { settings.Size = snap(v, 1) },
+			0, 1000, label("Size"),
+		)
+		delta = step(10, 1)
+	case types.GranularSettingsRowDensity:
+		modifier = createFloatModifier(
+			func() float32 { return settings.Density },
+			func(v float32) { settings.Density = snap(v, 1) },
+			1, 200, label("Density"),
+		)
+		delta = step(10, 1)
+	case types.GranularSettingsRowPosition:
+		modifier = createFloatModifier(
+			func() float32 { return settings.Position },
+			func(v float32) { settings.Position = snap(v, 0.01) },
+			0, 1, label("Position"),
+		)
+		delta = step(0.1, 0.01)
+	case types.GranularSettingsRowPositionJitter:
+		modifier = createFloatModifier(
+			func() float32 { return settings.PositionJitter },
+			func(v float32) { settings.PositionJitter = snap(v, 0.01) },
+			0, 1, label("Position jitter"),
+		)
+		delta = step(0.1, 0.01)
+	case types.GranularSettingsRowPitchJitter:
+		modifier = createFloatModifier(
+			func() float32 { return settings.PitchJitter },
+			func(v float32) { settings.PitchJitter = snap(v, 0.1) },
+			0, 24, label("Pitch jitter"),
+		)
+		delta = step(1, 0.1)
+	case types.GranularSettingsRowSpray:
+		modifier = createFloatModifier(
+			func() float32 { return settings.Spray },
+			func(v float32) { settings.Spray = snap(v, 0.01) },
+			0, 1, label("Spray"),
+		)
+		delta = step(0.1, 0.01)
+	default:
+		return
+	}
+	modifyValueWithBounds(modifier, delta)
+	storage.AutoSave(m)
+}
+
 func ModifyModulateValue(m *model.Model, baseDelta float32) {
 	if m.ModulateEditingIndex < 0 || m.ModulateEditingIndex >= 255 {
 		return
@@ -428,7 +512,8 @@ func ModifyModulateValue(m *model.Model, baseDelta float32) {
 		log.Printf("Modified modulate %02X ScaleRoot: %s -> %s", m.ModulateEditingIndex, oldNote, noteNames[newIndex])
 	} else if m.CurrentRow == 7 { // Scale
 		// Cycle through available scales
-		availableScales := []string{"all", "major", "minor", "dorian", "mixolydian", "pentatonic", "blues", "chromatic"}
+		// The scale of the project comes right after "all"
+		availableScales := slices.Insert(modulation.GetScaleNames(), 1, modulation.ProjectScale)
 		currentIndex := -1
 
 		// Find current scale index
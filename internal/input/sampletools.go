@@ -0,0 +1,141 @@
+package input
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/sample"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// SampleToolsPeakBuckets is the resolution of the waveform shown above the tools
+const SampleToolsPeakBuckets = 1024
+
+// sampleToolRates are the sample rates the resample tool steps through
+var sampleToolRates = []int{8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000}
+
+func sampleToolsViewConfig() ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.SampleToolsView,
+		Row:          0,
+		Col:          0,
+		ScrollOffset: 0,
+	}
+}
+
+// loadSampleToolsPeaks decodes the waveform of the file being edited
+func loadSampleToolsPeaks(m *model.Model) {
+	peaks, err := sample.LoadPeaks(m.SampleToolsFile, SampleToolsPeakBuckets)
+	if err != nil {
+		log.Printf("Could not decode waveform for %s: %v", m.SampleToolsFile, err)
+		peaks = nil
+	}
+	m.SampleToolsPeaks = peaks
+}
+
+// OpenSampleTools opens the sample tools for the file under the cursor in the file browser
+func OpenSampleTools(m *model.Model) {
+	if len(m.Files) == 0 || m.CurrentRow >= len(m.Files) {
+		return
+	}
+	selected := m.Files[m.CurrentRow]
+	if strings.HasSuffix(selected, "/") || selected == ".." {
+		return
+	}
+	m.SampleToolsFile = filepath.Join(m.CurrentDir, selected)
+	m.SampleToolsMessage = ""
+	loadSampleToolsPeaks(m)
+	switchToView(m, sampleToolsViewConfig())
+	log.Printf("Opening sample tools for file: %s", m.SampleToolsFile)
+}
+
+// CloseSampleTools returns to the file browser with the edited file selected
+func CloseSampleTools(m *model.Model) {
+	m.SampleToolsPeaks = nil
+	switchToView(m, fileViewConfig())
+	storage.LoadFiles(m)
+	name := filepath.Base(m.SampleToolsFile)
+	for i, file := range m.Files {
+		if file == name {
+			m.CurrentRow = i
+			if visibleRows := m.GetVisibleRows(); m.CurrentRow >= visibleRows {
+				m.ScrollOffset = m.CurrentRow - visibleRows + 1
+			}
+			break
+		}
+	}
+}
+
+// ApplySampleTool runs the selected tool on the file. Files outside the project are copied
+// into the save folder first, so from then on the tools edit the project's copy.
+func ApplySampleTool(m *model.Model) {
+	tool := types.SampleTool(m.CurrentRow)
+	path, err := storage.ProcessSampleFile(m, m.SampleToolsFile, tool, m.SampleToolsSettings)
+	if err != nil {
+		log.Printf("Sample tool %s failed: %v", tool, err)
+		m.SampleToolsMessage = fmt.Sprintf("%s failed: %v", tool, err)
+		return
+	}
+	m.SampleToolsFile = path
+	m.SampleToolsMessage = fmt.Sprintf("%s done", tool)
+	loadSampleToolsPeaks(m)
+	storage.AutoSave(m)
+}
+
+// ModifySampleToolValue changes the parameter of the selected tool. Coarse steps (+/-1.0)
+// are 1 dB, 6 dB for the silence threshold and 10 ms; fine steps (+/-0.05) are 0.1 dB and 1 ms.
+func ModifySampleToolValue(m *model.Model, delta float32) {
+	s := &m.SampleToolsSettings
+	coarse := delta == 1.0 || delta == -1.0
+	sign := 1
+	if delta < 0 {
+		sign = -1
+	}
+	dbStep := 0.1
+	msStep := 1
+	if coarse {
+		dbStep = 1
+		msStep = 10
+	}
+
+	switch types.SampleTool(m.CurrentRow) {
+	case types.SampleToolNormalizePeak:
+		s.PeakDB = clampDB(s.PeakDB+float64(sign)*dbStep, -30, 0)
+	case types.SampleToolNormalizeRMS:
+		s.RMSDB = clampDB(s.RMSDB+float64(sign)*dbStep, -40, 0)
+	case types.SampleToolTrimSilence:
+		step := 1.0
+		if coarse {
+			step = 6
+		}
+		s.SilenceDB = clampDB(s.SilenceDB+float64(sign)*step, -96, -12)
+	case types.SampleToolFadeIn:
+		s.FadeInMs = clampInt(s.FadeInMs+sign*msStep, 0, 10000)
+	case types.SampleToolFadeOut:
+		s.FadeOutMs = clampInt(s.FadeOutMs+sign*msStep, 0, 10000)
+	case types.SampleToolResample:
+		index := len(sampleToolRates) - 1
+		for i, rate := range sampleToolRates {
+			if rate >= s.SampleRate {
+				index = i
+				break
+			}
+		}
+		s.SampleRate = sampleToolRates[clampInt(index+sign, 0, len(sampleToolRates)-1)]
+	}
+}
+
+// clampDB clamps a level and rounds it to 0.1 dB
+func clampDB(db, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, math.Round(db*10)/10))
+}
+
+// SampleToolsBackups returns the backups that restore can step back through
+func SampleToolsBackups(m *model.Model) []string {
+	return storage.SampleBackups(m, filepath.Join(m.SaveFolder, filepath.Base(m.SampleToolsFile)))
+}
@@ -0,0 +1,69 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/sample"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestSampleTools(t *testing.T) {
+	m := createTestModel()
+	m.SaveFolder = t.TempDir()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "stab.wav")
+	stab := &sample.Buffer{SampleRate: 44100, NumChannels: 2, BitDepth: 16, Data: [][]float64{{0.1, 0.2, 0.1}, {0.1, 0.2, 0.1}}}
+	require.NoError(t, stab.Save(file))
+
+	// E in the file browser opens the tools for the file under the cursor
+	m.CurrentDir = dir
+	m.ViewMode = types.FileView
+	m.CurrentRow = 1 // ".." comes first
+	storage.LoadFiles(m)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	assert.Equal(t, types.SampleToolsView, m.ViewMode)
+	assert.Equal(t, file, m.SampleToolsFile)
+	assert.Len(t, m.SampleToolsPeaks, SampleToolsPeakBuckets)
+
+	// Ctrl+Arrows adjust the selected tool; phrase keys do nothing here
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlLeft})
+	assert.InDelta(t, -1.2, m.SampleToolsSettings.PeakDB, 1e-9)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	for i := 0; i < int(types.SampleToolResample); i++ {
+		HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyDown})
+	}
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyCtrlDown})
+	assert.Equal(t, 44100, m.SampleToolsSettings.SampleRate)
+
+	// Enter applies the tool to a copy in the project
+	m.CurrentRow = int(types.SampleToolMono)
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "To mono done", m.SampleToolsMessage)
+	assert.Equal(t, filepath.Join(m.SaveFolder, "stab.wav"), m.SampleToolsFile)
+	mono, err := sample.Load(m.SampleToolsFile)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mono.NumChannels)
+	assert.Len(t, SampleToolsBackups(m), 1)
+
+	// Failures are reported in the status line
+	m.CurrentRow = int(types.SampleToolTrimSilence)
+	m.SampleToolsSettings.SilenceDB = -12
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, m.SampleToolsMessage, "Trim silence failed")
+
+	// Shift+Left returns to the file browser on the edited file
+	HandleKeyInput(m, tea.KeyMsg{Type: tea.KeyShiftLeft})
+	assert.Equal(t, types.FileView, m.ViewMode)
+	assert.Nil(t, m.SampleToolsPeaks)
+	assert.Equal(t, "stab.wav", m.Files[m.CurrentRow])
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+}
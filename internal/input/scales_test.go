@@ -0,0 +1,61 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/modulation"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestProjectScaleNoteEditing(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 0
+	m.TrackTypes[0] = false // Instrument track
+	m.CurrentPhrase = 0
+	m.CurrentCol = int(types.InstrumentColNOT)
+	notes := func() int {
+		return m.InstrumentPhrasesData[0][m.CurrentRow][types.ColNote]
+	}
+
+	// Without a project scale notes move by semitones
+	m.CurrentRow = 0
+	ModifyValue(m, 1)
+	assert.Equal(t, 60, notes())
+	ModifyValue(m, 1)
+	assert.Equal(t, 61, notes())
+
+	// In D major a new note starts on the note of the scale from middle C, and fine
+	// steps move through the scale while octaves stay octaves
+	m.ProjectKey = 2
+	m.ProjectScale = "major"
+	m.CurrentRow = 1
+	ModifyValue(m, 1)
+	assert.Equal(t, 61, notes())
+	ModifyValue(m, 1)
+	assert.Equal(t, 62, notes())
+	ModifyValue(m, -1)
+	ModifyValue(m, -1)
+	assert.Equal(t, 59, notes())
+	ModifyValue(m, 16)
+	assert.Equal(t, 71, notes())
+}
+
+func TestModulateSettingsForProjectScale(t *testing.T) {
+	m := createTestModel()
+	settings := types.ModulateSettings{Seed: -1, ScaleRoot: 0, Scale: "major", Probability: 100}
+	assert.Equal(t, "major", modulateSettingsFor(m, settings).Scale)
+
+	// The project scale follows the key and scale of the project
+	settings.Scale = modulation.ProjectScale
+	converted := modulateSettingsFor(m, settings)
+	assert.Equal(t, "all", converted.Scale)
+	m.ProjectKey = 9
+	m.ProjectScale = "hirajoshi"
+	converted = modulateSettingsFor(m, settings)
+	assert.Equal(t, "hirajoshi", converted.Scale)
+	assert.Equal(t, 9, converted.ScaleRoot)
+	assert.Equal(t, 100, converted.Probability)
+}
@@ -1,8 +1,14 @@
 package input
 
 import (
+	"fmt"
+	"log"
+	"slices"
+
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/modulation"
 	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/tuning"
 	"github.com/schollz/collidertracker/internal/types"
 )
 
@@ -13,7 +19,12 @@ func ModifySettingsValue(m *model.Model, delta float32) {
 		case types.GlobalSettingsRowBPM: // BPM
 			modifier := createFloatModifier(
 				func() float32 { return m.BPM },
-				func(v float32) { m.BPM = v },
+				func(v float32) {
+					m.BPM = v
+					m.SendOSCLFOMessages()      // Tempo-synced LFOs follow the tempo
+					m.SendOSCInsertFXMessages() // So do tempo-synced delays
+					m.SendOSCReturnMessage(types.ReturnDelay)
+				},
 				1, 999, "BPM",
 			)
 			modifyValueWithBounds(modifier, delta)
@@ -128,6 +139,76 @@ func ModifySettingsValue(m *model.Model, delta float32) {
 			)
 			modifyValueWithBounds(modifier, delta)
 		}
+	} else if m.CurrentCol == 2 {
+		modifyTuningSetting(m, delta)
 	}
 	storage.AutoSave(m)
 }
+
+// settingsColumnMaxRow returns the last row of a column of the settings view
+func settingsColumnMaxRow(col int) int {
+	switch col {
+	case 0:
+		return int(types.GlobalSettingsRowShimmerPercent) // Global column: BPM(0) to Shimmer(8)
+	case 1:
+		return int(types.InputSettingsRowReverbSendPercent) // Input column: InputLevelDB(0) to ReverbSendPercent(1)
+	default:
+		return int(types.TuningSettingsRowScale) // Tuning column: Project(0), T1-T8(1-8), Degrees(9), Key(10), Scale(11)
+	}
+}
+
+// modifyTuningSetting steps the selected tuning through the tunings in the tuning folders.
+// Tracks can also follow the project tuning ("").
+func modifyTuningSetting(m *model.Model, delta float32) {
+	row := types.TuningSettingsRow(m.CurrentRow)
+	switch row {
+	case types.TuningSettingsRowDegrees:
+		m.ShowScaleDegrees = delta > 0
+		return
+	case types.TuningSettingsRowKey:
+		if delta > 0 {
+			m.ProjectKey = (m.ProjectKey + 1) % 12
+		} else {
+			m.ProjectKey = (m.ProjectKey + 11) % 12
+		}
+		log.Printf("Key of project: %s", modulation.NoteNames[m.ProjectKey])
+		return
+	case types.TuningSettingsRowScale:
+		// No scale ("") plays all notes, so "all" itself is left out
+		choices := append([]string{""}, modulation.GetScaleNames()[1:]...)
+		m.ProjectScale = choices[stepChoice(choices, m.ProjectScale, delta)]
+		log.Printf("Scale of project: %q", m.ProjectScale)
+		return
+	}
+
+	// Pick up tunings added since the last change
+	m.LoadTunings()
+	choices := append([]string{""}, tuning.Names(m.Tunings)...)
+	current := &m.Tuning
+	if row != types.TuningSettingsRowProject {
+		// Tracks choose between the project tuning (""), 12-TET and the others
+		choices = slices.Insert(choices, 1, tuning.EqualTemperament)
+		current = &m.TrackTunings[row-types.TuningSettingsRowTrack1]
+	}
+
+	*current = choices[stepChoice(choices, *current, delta)]
+	log.Printf("Tuning of %s: %q", tuningRowName(row), *current)
+}
+
+// stepChoice returns the index of the choice next to current in the direction of delta,
+// stopping at the ends
+func stepChoice(choices []string, current string, delta float32) int {
+	index := slices.Index(choices, current)
+	if delta > 0 {
+		return min(index+1, len(choices)-1)
+	}
+	return max(index-1, 0)
+}
+
+// tuningRowName names what a row of the Tuning column tunes
+func tuningRowName(row types.TuningSettingsRow) string {
+	if row == types.TuningSettingsRowProject {
+		return "project"
+	}
+	return fmt.Sprintf("track %d", int(row-types.TuningSettingsRowTrack1)+1)
+}
@@ -0,0 +1,73 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/tuning"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestTuningSettings(t *testing.T) {
+	m := createTestModel()
+	m.TuningDir = t.TempDir()
+	m.SaveFolder = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(m.TuningDir, "19-EDO.scl"),
+		[]byte("19 equal divisions\n1\n1200.0\n"), 0644))
+	m.ViewMode = types.SettingsView
+
+	// The Tuning column is right of the Input column, which has fewer rows
+	m.CurrentCol = 1
+	handleRight(m)
+	assert.Equal(t, 2, m.CurrentCol)
+	for range 12 {
+		handleDown(m)
+	}
+	assert.Equal(t, int(types.TuningSettingsRowScale), m.CurrentRow)
+	handleLeft(m)
+	assert.Equal(t, int(types.InputSettingsRowReverbSendPercent), m.CurrentRow)
+	handleRight(m)
+
+	// The project steps from 12-TET through the tunings in the folders
+	m.CurrentRow = int(types.TuningSettingsRowProject)
+	ModifySettingsValue(m, 1)
+	assert.Equal(t, "19-EDO", m.Tuning)
+	ModifySettingsValue(m, 1)
+	assert.Equal(t, "19-EDO", m.Tuning)
+	assert.NotNil(t, m.TuningForTrack(3))
+
+	// Tracks follow the project tuning until they pick their own
+	m.CurrentRow = int(types.TuningSettingsRowTrack1) + 3
+	ModifySettingsValue(m, 1)
+	assert.Equal(t, tuning.EqualTemperament, m.TrackTunings[3])
+	assert.Nil(t, m.TuningForTrack(3))
+	assert.NotNil(t, m.TuningForTrack(2))
+	ModifySettingsValue(m, -1)
+	assert.Equal(t, "", m.TrackTunings[3])
+
+	m.CurrentRow = int(types.TuningSettingsRowDegrees)
+	ModifySettingsValue(m, 1)
+	assert.True(t, m.ShowScaleDegrees)
+	ModifySettingsValue(m, -1)
+	assert.False(t, m.ShowScaleDegrees)
+
+	// The key wraps around the octave
+	m.CurrentRow = int(types.TuningSettingsRowKey)
+	ModifySettingsValue(m, -1)
+	assert.Equal(t, 11, m.ProjectKey)
+	ModifySettingsValue(m, 1)
+	assert.Equal(t, 0, m.ProjectKey)
+
+	// The scale steps from all notes through the scale library
+	m.CurrentRow = int(types.TuningSettingsRowScale)
+	ModifySettingsValue(m, -1)
+	assert.Equal(t, "", m.ProjectScale)
+	ModifySettingsValue(m, 1)
+	assert.Equal(t, "major", m.ProjectScale)
+	handleDown(m)
+	assert.Equal(t, int(types.TuningSettingsRowScale), m.CurrentRow)
+}
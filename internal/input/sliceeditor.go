@@ -0,0 +1,227 @@
+package input
+
+import (
+	"log"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/sample"
+	"github.com/schollz/collidertracker/internal/storage"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+const (
+	// SliceEditorPeakBuckets is the resolution of the cached peak envelope
+	SliceEditorPeakBuckets = 4096
+	// SliceEditorMaxZoom is the largest zoom factor in the slice editor
+	SliceEditorMaxZoom = 64
+	// sliceEditorMinGap keeps neighbouring markers from crossing each other
+	sliceEditorMinGap = float32(0.0005)
+)
+
+// getFileMetadataOrDefault returns the metadata for a file, or the default metadata if none exists
+func getFileMetadataOrDefault(m *model.Model, filename string) types.FileMetadata {
+	metadata, exists := m.FileMetadata[filename]
+	if !exists {
+		metadata = types.FileMetadata{BPM: 120.0, Slices: 16, Playthrough: 0, SyncToBPM: 1} // Default values
+	}
+	return metadata
+}
+
+// samplerRegion returns the playable region (as fractions of the file) for a slice,
+// honouring trim points and slice markers. In oneshot mode the region runs from the
+// slice to the trim end (or from the trim start to the slice end when reversed).
+func samplerRegion(metadata types.FileMetadata, sliceNumber, playthrough, reverse int) (float32, float32) {
+	start, end := metadata.SliceBounds(sliceNumber)
+	if playthrough == 1 {
+		if reverse == 1 {
+			start = metadata.TrimStart
+		} else {
+			end = metadata.EffectiveTrimEnd()
+		}
+	}
+	return start, end
+}
+
+// samplerRowRegion narrows the region of a slice to the ST and LN columns of a row (-1
+// when unset). Both are fractions of the slice (00-FE) measured in the direction of
+// playback, so a reversed row starts ST before the slice end and LN cuts it short
+// towards the slice start. Retriggers restart at the start of the narrowed region.
+func samplerRowRegion(metadata types.FileMetadata, sliceNumber, playthrough, reverse, offset, length int) (float32, float32) {
+	start, end := samplerRegion(metadata, sliceNumber, playthrough, reverse)
+	if offset < 0 && length < 0 {
+		return start, end
+	}
+	sliceStart, sliceEnd := metadata.SliceBounds(sliceNumber)
+	sliceLength := sliceEnd - sliceStart
+	// Keep at least 1/254 of the slice so that FE in ST still plays something
+	minLength := sliceLength / 254.0
+	skip := float32(clampInt(offset, 0, 253)) / 254.0 * sliceLength
+	keep := end - start
+	if length >= 0 {
+		keep = float32(clampInt(length, 1, 254)) / 254.0 * sliceLength
+	}
+	if reverse == 1 {
+		end = sliceEnd - skip
+		start = max(start, end-keep)
+	} else {
+		start = sliceStart + skip
+		end = min(end, start+keep)
+	}
+	if end-start < minLength {
+		if reverse == 1 {
+			start = end - minLength
+		} else {
+			end = start + minLength
+		}
+	}
+	return start, end
+}
+
+func sliceEditorConfig() ViewSwitchConfig {
+	return ViewSwitchConfig{
+		ViewMode:     types.SliceEditorView,
+		Row:          0,
+		Col:          0, // Start on the trim start marker
+		ScrollOffset: 0,
+	}
+}
+
+// OpenSliceEditor decodes the waveform of the file being edited and switches to the slice editor
+func OpenSliceEditor(m *model.Model) {
+	if m.MetadataEditingFile == "" {
+		return
+	}
+
+	peaks, err := sample.LoadPeaks(m.MetadataEditingFile, SliceEditorPeakBuckets)
+	if err != nil {
+		log.Printf("Could not decode waveform for %s: %v", m.MetadataEditingFile, err)
+		peaks = nil
+	}
+	m.SliceEditorPeaks = peaks
+	m.SliceEditorZoom = 1
+	switchToView(m, sliceEditorConfig())
+	log.Printf("Opening slice editor for file: %s", m.MetadataEditingFile)
+}
+
+// CloseSliceEditor returns to the file metadata view and drops the cached waveform
+func CloseSliceEditor(m *model.Model) {
+	m.SliceEditorPeaks = nil
+	switchToView(m, ViewSwitchConfig{
+		ViewMode:     types.FileMetadataView,
+		Row:          int(types.FileMetadataRowSlices),
+		Col:          0,
+		ScrollOffset: 0,
+	})
+}
+
+// SelectSliceMarker moves the marker selection by delta, staying within trim start..trim end
+func SelectSliceMarker(m *model.Model, delta int) {
+	metadata := getFileMetadataOrDefault(m, m.MetadataEditingFile)
+	maxMarker := len(metadata.Markers()) - 1
+	m.CurrentCol = clampInt(m.CurrentCol+delta, 0, maxMarker)
+}
+
+// ZoomSliceEditor doubles (delta > 0) or halves (delta < 0) the slice editor zoom
+func ZoomSliceEditor(m *model.Model, delta int) {
+	zoom := m.SliceEditorZoom
+	if zoom < 1 {
+		zoom = 1
+	}
+	if delta > 0 && zoom < SliceEditorMaxZoom {
+		zoom *= 2
+	} else if delta < 0 && zoom > 1 {
+		zoom /= 2
+	}
+	m.SliceEditorZoom = zoom
+}
+
+// MoveSliceMarker moves the selected marker. Coarse (+/-1.0) moves 2% of the visible
+// window and fine (+/-0.05) moves 0.1%, so steps get smaller as the zoom increases.
+func MoveSliceMarker(m *model.Model, delta float32) {
+	if m.MetadataEditingFile == "" {
+		return
+	}
+
+	metadata := getFileMetadataOrDefault(m, m.MetadataEditingFile)
+	markers := metadata.Markers()
+	last := len(markers) - 1
+	index := clampInt(m.CurrentCol, 0, last)
+
+	zoom := m.SliceEditorZoom
+	if zoom < 1 {
+		zoom = 1
+	}
+	step := delta * 0.02 / float32(zoom)
+
+	lower := float32(0)
+	if index > 0 {
+		lower = markers[index-1] + sliceEditorMinGap
+	}
+	upper := float32(1)
+	if index < last {
+		upper = markers[index+1] - sliceEditorMinGap
+	}
+
+	modifier := createFloatModifier(
+		func() float32 { return markers[index] },
+		func(v float32) { markers[index] = v },
+		lower, upper, "slice marker",
+	)
+	modifyValueWithBounds(modifier, step)
+
+	switch index {
+	case 0:
+		metadata.TrimStart = markers[0]
+	case last:
+		metadata.TrimEnd = markers[last]
+	default:
+		// Moving an inner marker pins all inner markers so equal slicing no longer applies
+		metadata.SliceMarkers = append([]float32(nil), markers[1:last]...)
+	}
+	if metadata.TrimEnd >= 1 {
+		metadata.TrimEnd = 0 // Store the end of the file as the default
+	}
+
+	m.FileMetadata[m.MetadataEditingFile] = metadata
+	storage.AutoSave(m)
+}
+
+// ResetSliceMarkers restores the trim points and equal slicing for the file being edited
+func ResetSliceMarkers(m *model.Model) {
+	if m.MetadataEditingFile == "" {
+		return
+	}
+	metadata, exists := m.FileMetadata[m.MetadataEditingFile]
+	if !exists {
+		return
+	}
+	metadata.TrimStart = 0
+	metadata.TrimEnd = 0
+	metadata.SliceMarkers = nil
+	m.FileMetadata[m.MetadataEditingFile] = metadata
+	log.Printf("Reset slice markers for %s", m.MetadataEditingFile)
+	storage.AutoSave(m)
+}
+
+// AuditionSlice plays the slice that starts at the selected marker
+func AuditionSlice(m *model.Model) {
+	if m.MetadataEditingFile == "" {
+		return
+	}
+
+	metadata := getFileMetadataOrDefault(m, m.MetadataEditingFile)
+	slices := len(metadata.Markers()) - 1
+	slice := clampInt(m.CurrentCol, 0, slices-1)
+
+	track := m.CurrentTrack
+	if track < 0 || track > 7 {
+		track = 0
+	}
+
+	// Slice duration 0 lets the sampler play the whole slice
+	params := model.NewSamplerOSCParams(m.MetadataEditingFile, track, slices, slice, metadata.BPM, m.BPM, 0, 0, 64)
+	params.SyncToBPM = metadata.SyncToBPM
+	params.SliceStart, params.SliceEnd = metadata.SliceBounds(slice)
+	m.SendOSCSamplerMessage(params)
+	log.Printf("Auditioning slice %d of %s (%.4f-%.4f)", slice, m.MetadataEditingFile, params.SliceStart, params.SliceEnd)
+}
@@ -0,0 +1,157 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestSliceEditorNavigation(t *testing.T) {
+	m := createTestModel()
+	m.MetadataEditingFile = "test.wav"
+	m.FileMetadata["test.wav"] = types.FileMetadata{BPM: 120, Slices: 4, SyncToBPM: 1}
+	m.ViewMode = types.FileMetadataView
+
+	// Shift+Right opens the editor even when the file cannot be decoded
+	handleShiftRight(m)
+	assert.Equal(t, types.SliceEditorView, m.ViewMode)
+	assert.Equal(t, 0, m.CurrentCol)
+	assert.Equal(t, 1, m.SliceEditorZoom)
+	assert.Nil(t, m.SliceEditorPeaks)
+
+	// Marker selection is bounded by trim start (0) and trim end (Slices)
+	handleLeft(m)
+	assert.Equal(t, 0, m.CurrentCol)
+	for i := 0; i < 10; i++ {
+		handleRight(m)
+	}
+	assert.Equal(t, 4, m.CurrentCol)
+
+	// Zoom doubles up to the maximum and halves back to 1
+	for i := 0; i < 10; i++ {
+		handleUp(m)
+	}
+	assert.Equal(t, SliceEditorMaxZoom, m.SliceEditorZoom)
+	for i := 0; i < 10; i++ {
+		handleDown(m)
+	}
+	assert.Equal(t, 1, m.SliceEditorZoom)
+
+	// Shift+Left returns to the metadata view
+	handleShiftLeft(m)
+	assert.Equal(t, types.FileMetadataView, m.ViewMode)
+}
+
+func TestMoveSliceMarker(t *testing.T) {
+	m := createTestModel()
+	m.MetadataEditingFile = "test.wav"
+	m.FileMetadata["test.wav"] = types.FileMetadata{BPM: 120, Slices: 4, SyncToBPM: 1}
+	m.ViewMode = types.SliceEditorView
+	m.SliceEditorZoom = 1
+
+	// Coarse move of trim start
+	m.CurrentCol = 0
+	MoveSliceMarker(m, 1.0)
+	metadata := m.FileMetadata["test.wav"]
+	assert.InDelta(t, 0.02, metadata.TrimStart, 1e-6)
+	assert.Empty(t, metadata.SliceMarkers)
+
+	// Trim start cannot move below the start of the file
+	MoveSliceMarker(m, -1.0)
+	MoveSliceMarker(m, -1.0)
+	assert.Equal(t, float32(0), m.FileMetadata["test.wav"].TrimStart)
+
+	// Moving an inner marker pins all inner markers
+	m.CurrentCol = 2
+	MoveSliceMarker(m, 0.05)
+	metadata = m.FileMetadata["test.wav"]
+	assert.Len(t, metadata.SliceMarkers, 3)
+	assert.InDelta(t, 0.501, metadata.SliceMarkers[1], 1e-6)
+
+	// Markers cannot cross their neighbours
+	for i := 0; i < 50; i++ {
+		MoveSliceMarker(m, 1.0)
+	}
+	metadata = m.FileMetadata["test.wav"]
+	assert.Less(t, metadata.SliceMarkers[1], metadata.SliceMarkers[2])
+
+	// Trim end moving back to the end of file stores the default
+	m.CurrentCol = 4
+	MoveSliceMarker(m, -1.0)
+	assert.InDelta(t, 0.98, m.FileMetadata["test.wav"].TrimEnd, 1e-6)
+	MoveSliceMarker(m, 1.0)
+	assert.Equal(t, float32(0), m.FileMetadata["test.wav"].TrimEnd)
+
+	// Backspace resets everything
+	handleBackspace(m)
+	assert.False(t, m.FileMetadata["test.wav"].HasCustomSlicing())
+
+	// Changing the slice count drops stale markers
+	m.CurrentCol = 1
+	MoveSliceMarker(m, 1.0)
+	assert.NotEmpty(t, m.FileMetadata["test.wav"].SliceMarkers)
+	m.ViewMode = types.FileMetadataView
+	m.CurrentRow = int(types.FileMetadataRowSlices)
+	ModifyFileMetadataValue(m, 1.0)
+	assert.Empty(t, m.FileMetadata["test.wav"].SliceMarkers)
+}
+
+func TestSamplerRegion(t *testing.T) {
+	metadata := types.FileMetadata{Slices: 4, TrimStart: 0.2, TrimEnd: 0.6}
+
+	// Sliced: just the slice
+	start, end := samplerRegion(metadata, 1, 0, 0)
+	assert.InDelta(t, 0.3, start, 1e-6)
+	assert.InDelta(t, 0.4, end, 1e-6)
+
+	// Oneshot forward: slice start to trim end
+	start, end = samplerRegion(metadata, 1, 1, 0)
+	assert.InDelta(t, 0.3, start, 1e-6)
+	assert.InDelta(t, 0.6, end, 1e-6)
+
+	// Oneshot reverse: trim start to slice end
+	start, end = samplerRegion(metadata, 1, 1, 1)
+	assert.InDelta(t, 0.2, start, 1e-6)
+	assert.InDelta(t, 0.4, end, 1e-6)
+}
+
+func TestSamplerRowRegion(t *testing.T) {
+	metadata := types.FileMetadata{Slices: 4}
+
+	// Unset columns leave the slice alone
+	start, end := samplerRowRegion(metadata, 1, 0, 0, -1, -1)
+	assert.InDelta(t, 0.25, start, 1e-6)
+	assert.InDelta(t, 0.5, end, 1e-6)
+
+	// Start from the middle of the slice
+	start, end = samplerRowRegion(metadata, 1, 0, 0, 127, -1)
+	assert.InDelta(t, 0.375, start, 1e-6)
+	assert.InDelta(t, 0.5, end, 1e-6)
+
+	// Truncate to half the slice
+	start, end = samplerRowRegion(metadata, 1, 0, 0, -1, 127)
+	assert.InDelta(t, 0.25, start, 1e-6)
+	assert.InDelta(t, 0.375, end, 1e-6)
+
+	// Length never runs past the slice end
+	start, end = samplerRowRegion(metadata, 1, 0, 0, 127, 254)
+	assert.InDelta(t, 0.375, start, 1e-6)
+	assert.InDelta(t, 0.5, end, 1e-6)
+
+	// Reversed rows measure from the slice end
+	start, end = samplerRowRegion(metadata, 1, 0, 1, 127, 64)
+	assert.InDelta(t, 0.375, end, 1e-6)
+	assert.InDelta(t, 0.375-64.0/254.0*0.25, start, 1e-6)
+
+	// Oneshot keeps playing to the trim end unless the length is set
+	start, end = samplerRowRegion(metadata, 1, 1, 0, 127, -1)
+	assert.InDelta(t, 0.375, start, 1e-6)
+	assert.InDelta(t, 1.0, end, 1e-6)
+
+	// A start at the very end still leaves a sliver to play
+	start, end = samplerRowRegion(metadata, 1, 0, 0, 254, 0)
+	assert.Greater(t, end, start)
+	assert.LessOrEqual(t, end, float32(0.5)+1e-6)
+}
@@ -0,0 +1,297 @@
+package library
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-audio/wav"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/schollz/collidertracker/internal/getbpm"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// IndexFile is the name of the library index inside the config folder
+const IndexFile = "library.json.gz"
+
+// loopMinSeconds is the length from which a file without a BPM in its name counts as a loop
+const loopMinSeconds = 2.0
+
+// Entry is the cached analysis of one sample file
+type Entry struct {
+	Path       string   `json:"path"`
+	Size       int64    `json:"size"`
+	ModTime    int64    `json:"mod_time"`    // Unix nanoseconds, unchanged files are not analyzed again
+	Duration   float64  `json:"duration"`    // Seconds, 0 when the file could not be read
+	SampleRate int64    `json:"sample_rate"` // Hz
+	Channels   int      `json:"channels"`
+	BPM        float64  `json:"bpm"`    // 0 for one-shots
+	Slices     int      `json:"slices"` // 2x beats, same as picking the file in the file browser
+	Loop       bool     `json:"loop"`   // BPM in the name or at least loopMinSeconds long
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// Name returns the file name of the entry
+func (e Entry) Name() string {
+	return filepath.Base(e.Path)
+}
+
+// IsLoop reports whether the entry is a loop. The tags "loop" and "oneshot" override the analysis.
+func (e Entry) IsLoop() bool {
+	if e.HasTag("loop") {
+		return true
+	}
+	if e.HasTag("oneshot") {
+		return false
+	}
+	return e.Loop
+}
+
+// HasTag reports whether the entry carries a tag (case-insensitive)
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Index is the persistent sample library: the folders to scan and what was found in them
+type Index struct {
+	Roots   []string `json:"roots"`
+	Entries []Entry  `json:"entries"` // Sorted by path
+}
+
+// DefaultPath returns where the library index is kept, e.g. ~/.config/collidertracker/library.json.gz
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", IndexFile)
+}
+
+// Load reads an index. A missing file gives an empty index.
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Index{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open library index: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library index: %w", err)
+	}
+	defer gz.Close()
+
+	var idx Index
+	if err := json.NewDecoder(gz).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("failed to decode library index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index, creating its folder if needed
+func (idx *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create library folder: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create library index: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if err := json.NewEncoder(gz).Encode(idx); err != nil {
+		gz.Close()
+		return fmt.Errorf("failed to encode library index: %w", err)
+	}
+	return gz.Close()
+}
+
+// HasRoot reports whether a folder is scanned
+func (idx *Index) HasRoot(root string) bool {
+	for _, r := range idx.Roots {
+		if r == filepath.Clean(root) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleRoot adds a folder to the scanned folders, or removes it if it is already there.
+// It returns true when the folder was added.
+func (idx *Index) ToggleRoot(root string) bool {
+	root = filepath.Clean(root)
+	for i, r := range idx.Roots {
+		if r == root {
+			idx.Roots = append(idx.Roots[:i], idx.Roots[i+1:]...)
+			return false
+		}
+	}
+	idx.Roots = append(idx.Roots, root)
+	sort.Strings(idx.Roots)
+	return true
+}
+
+// Contains reports whether a file is inside one of the roots
+func (idx *Index) Contains(path string) bool {
+	for _, root := range idx.Roots {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune drops the entries that are no longer inside any root
+func (idx *Index) Prune() {
+	kept := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if idx.Contains(e.Path) {
+			kept = append(kept, e)
+		}
+	}
+	idx.Entries = kept
+}
+
+// Find returns the entry of a file, or nil when it is not indexed
+func (idx *Index) Find(path string) *Entry {
+	i := sort.Search(len(idx.Entries), func(i int) bool { return idx.Entries[i].Path >= path })
+	if i < len(idx.Entries) && idx.Entries[i].Path == path {
+		return &idx.Entries[i]
+	}
+	return nil
+}
+
+// SetTags replaces the tags of a file. Tags are lower-cased, a leading '#' is dropped and
+// duplicates are removed.
+func (idx *Index) SetTags(path string, tags []string) {
+	entry := idx.Find(path)
+	if entry == nil {
+		return
+	}
+	entry.Tags = nil
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag != "" && !entry.HasTag(tag) {
+			entry.Tags = append(entry.Tags, tag)
+		}
+	}
+}
+
+// Scan walks the roots and returns the entries for all audio files found, sorted by path.
+// Files whose size and modification time match a previous entry reuse its analysis; tags are
+// always kept.
+func Scan(roots []string, previous []Entry) []Entry {
+	known := make(map[string]Entry, len(previous))
+	for _, e := range previous {
+		known[e.Path] = e
+	}
+
+	seen := make(map[string]bool)
+	var entries []Entry
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				log.Printf("Library scan: skipping %s: %v", path, err)
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !IsAudioFile(path) || seen[path] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			seen[path] = true
+
+			prev, ok := known[path]
+			if ok && prev.Size == info.Size() && prev.ModTime == info.ModTime().UnixNano() {
+				entries = append(entries, prev)
+				return nil
+			}
+			entry := Analyze(path)
+			entry.Size = info.Size()
+			entry.ModTime = info.ModTime().UnixNano()
+			entry.Tags = prev.Tags
+			entries = append(entries, entry)
+			return nil
+		})
+		if err != nil {
+			log.Printf("Library scan of %s failed: %v", root, err)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	log.Printf("Library scan found %d files in %d folders", len(entries), len(roots))
+	return entries
+}
+
+// IsAudioFile reports whether a file is listed by the library (same extensions as the file browser)
+func IsAudioFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".wav" || ext == ".flac"
+}
+
+// Analyze reads the length, format and tempo of a file. Files that cannot be decoded
+// (e.g. FLAC) are still returned so that they can be searched by name.
+func Analyze(path string) Entry {
+	entry := Entry{Path: path}
+
+	seconds, sampleRate, _, err := getbpm.Length(path)
+	if err != nil {
+		log.Printf("Library: could not read %s: %v", path, err)
+		return entry
+	}
+	entry.Duration = seconds
+	entry.SampleRate = sampleRate
+	entry.Channels = channels(path)
+
+	// Tempo is only meaningful for loops; one-shots keep BPM 0
+	_, _, nameErr := getbpm.FromName(path)
+	entry.Loop = nameErr == nil || seconds >= loopMinSeconds
+	if entry.Loop {
+		if beats, bpm, err := getbpm.GetBPM(path); err == nil {
+			entry.BPM = bpm
+			entry.Slices = int(2 * math.Round(beats))
+		}
+	}
+	return entry
+}
+
+// channels returns the channel count from the WAV header, or 0 if unknown
+func channels(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	d := wav.NewDecoder(f)
+	if !d.IsValidFile() {
+		return 0
+	}
+	return int(d.NumChans)
+}
@@ -0,0 +1,121 @@
+package library
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery("Amen #Dark is:loop bpm:160-180 dur:<1 ch:2 bpm:fast")
+	assert.Equal(t, []string{"amen", "bpm:fast"}, q.Terms)
+	assert.Equal(t, []string{"dark"}, q.Tags)
+	assert.Equal(t, "loop", q.Kind)
+	assert.Equal(t, Range{Min: 160, Max: 180, Set: true}, q.BPM)
+	assert.Equal(t, Range{Min: 0, Max: 1, Set: true}, q.Duration)
+	assert.Equal(t, 2, q.Channels)
+
+	q = ParseQuery("is:oneshot bpm:>150 dur:2-1")
+	assert.Equal(t, "oneshot", q.Kind)
+	assert.True(t, q.BPM.Contains(151))
+	assert.False(t, q.BPM.Contains(149))
+	assert.Equal(t, Range{Min: 1, Max: 2, Set: true}, q.Duration)
+
+	// A single value allows a small tolerance
+	q = ParseQuery("bpm:174")
+	assert.True(t, q.BPM.Contains(174.5))
+	assert.False(t, q.BPM.Contains(176))
+}
+
+func TestFuzzyScore(t *testing.T) {
+	_, ok := FuzzyScore("amn", "amen_break.wav")
+	assert.True(t, ok)
+	_, ok = FuzzyScore("nma", "amen_break.wav")
+	assert.False(t, ok)
+
+	// Consecutive characters at a word start beat scattered ones
+	tight, _ := FuzzyScore("brk", "brk_loop.wav")
+	loose, _ := FuzzyScore("brk", "b_r_k_loop.wav")
+	assert.Greater(t, tight, loose)
+}
+
+func TestSearch(t *testing.T) {
+	idx := &Index{Entries: []Entry{
+		{Path: "/s/drums/amen_bpm172.wav", Duration: 5.6, BPM: 172, Channels: 2, Loop: true},
+		{Path: "/s/drums/kick_01.wav", Duration: 0.4, Channels: 1, Tags: []string{"dark"}},
+		{Path: "/s/drums/snare_long.wav", Duration: 2.5, Channels: 1, Tags: []string{"oneshot"}},
+		{Path: "/s/amen/hats.wav", Duration: 3.0, BPM: 120, Channels: 2, Loop: true},
+	}}
+
+	assert.Equal(t, []int{0, 1, 2, 3}, idx.Search(""))
+
+	// Loops at 160-180 BPM
+	assert.Equal(t, []int{0}, idx.Search("is:loop bpm:160-180"))
+	// One-shots under 1s; the oneshot tag overrides the length
+	assert.Equal(t, []int{1}, idx.Search("is:oneshot dur:<1"))
+	assert.Equal(t, []int{1, 2}, idx.Search("is:oneshot"))
+	assert.Equal(t, []int{1}, idx.Search("#dark"))
+	assert.Equal(t, []int{1}, idx.Search("dark"))
+	assert.Equal(t, []int{0, 3}, idx.Search("ch:2"))
+
+	// File name matches rank above folder matches
+	assert.Equal(t, []int{0, 3}, idx.Search("amen"))
+}
+
+func TestIndexRootsAndTags(t *testing.T) {
+	idx := &Index{}
+	assert.True(t, idx.ToggleRoot("/b/"))
+	assert.True(t, idx.ToggleRoot("/a"))
+	assert.Equal(t, []string{"/a", "/b"}, idx.Roots)
+	assert.True(t, idx.HasRoot("/b"))
+	assert.True(t, idx.Contains("/b/x/y.wav"))
+	assert.False(t, idx.Contains("/bb/y.wav"))
+
+	idx.Entries = []Entry{{Path: "/a/1.wav"}, {Path: "/b/2.wav"}}
+	assert.False(t, idx.ToggleRoot("/b"))
+	idx.Prune()
+	assert.Equal(t, []Entry{{Path: "/a/1.wav"}}, idx.Entries)
+
+	idx.SetTags("/a/1.wav", []string{"#Kick", "dark", "kick", " "})
+	assert.Equal(t, []string{"kick", "dark"}, idx.Find("/a/1.wav").Tags)
+	assert.Nil(t, idx.Find("/a/missing.wav"))
+}
+
+func TestScanAndSave(t *testing.T) {
+	root, err := filepath.Abs("../getbpm")
+	require.NoError(t, err)
+
+	entries := Scan([]string{root}, nil)
+	require.NotEmpty(t, entries)
+	amen := filepath.Join(root, "amen_beats8_bpm172.wav")
+	idx := &Index{Roots: []string{root}, Entries: entries}
+	entry := idx.Find(amen)
+	require.NotNil(t, entry)
+	assert.InDelta(t, 2.79, entry.Duration, 0.01)
+	assert.Equal(t, int64(44100), entry.SampleRate)
+	assert.True(t, entry.Loop)
+	assert.Equal(t, 172.0, entry.BPM)
+	assert.Equal(t, 16, entry.Slices)
+	assert.Greater(t, entry.Channels, 0)
+
+	// Unchanged files reuse their analysis and keep their tags
+	idx.SetTags(amen, []string{"amen"})
+	idx.Find(amen).BPM = 1
+	rescanned := Scan(idx.Roots, idx.Entries)
+	idx.Entries = rescanned
+	assert.Equal(t, 1.0, idx.Find(amen).BPM)
+	assert.Equal(t, []string{"amen"}, idx.Find(amen).Tags)
+
+	path := filepath.Join(t.TempDir(), "config", IndexFile)
+	require.NoError(t, idx.Save(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, idx, loaded)
+
+	// A missing index is empty
+	empty, err := Load(filepath.Join(t.TempDir(), IndexFile))
+	require.NoError(t, err)
+	assert.Empty(t, empty.Entries)
+}
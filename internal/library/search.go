@@ -0,0 +1,211 @@
+package library
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search string. Plain words are fuzzy-matched against the file path and
+// the other fields filter the results:
+//
+//	#kick          has the tag "kick"
+//	is:loop        loops (is:oneshot for one-shots)
+//	bpm:160-180    BPM range, also bpm:>150, bpm:<100 or bpm:174 (within 1 BPM)
+//	dur:<1         length in seconds, same forms as bpm
+//	ch:1           channel count
+type Query struct {
+	Terms    []string
+	Tags     []string
+	Kind     string // "loop", "oneshot" or "" for both
+	BPM      Range
+	Duration Range
+	Channels int // 0 for any
+}
+
+// Range is an inclusive numeric filter; a zero Range matches everything
+type Range struct {
+	Min, Max float64
+	Set      bool
+}
+
+// Contains reports whether v is inside the range
+func (r Range) Contains(v float64) bool {
+	return !r.Set || (v >= r.Min && v <= r.Max)
+}
+
+// parseRange reads "a-b", ">a", "<b" or "a" (with a tolerance)
+func parseRange(s string, tolerance float64) (Range, bool) {
+	parse := func(v string) (float64, bool) {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(v, "s"), 64)
+		return f, err == nil
+	}
+	switch {
+	case strings.HasPrefix(s, ">"):
+		if v, ok := parse(s[1:]); ok {
+			return Range{Min: v, Max: 1e9, Set: true}, true
+		}
+	case strings.HasPrefix(s, "<"):
+		if v, ok := parse(s[1:]); ok {
+			return Range{Min: 0, Max: v, Set: true}, true
+		}
+	case strings.Contains(s, "-"):
+		parts := strings.SplitN(s, "-", 2)
+		lo, okLo := parse(parts[0])
+		hi, okHi := parse(parts[1])
+		if okLo && okHi {
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			return Range{Min: lo, Max: hi, Set: true}, true
+		}
+	default:
+		if v, ok := parse(s); ok {
+			return Range{Min: v - tolerance, Max: v + tolerance, Set: true}, true
+		}
+	}
+	return Range{}, false
+}
+
+// ParseQuery splits a search string into fuzzy terms and filters. Filters that cannot be
+// parsed are searched as plain text.
+func ParseQuery(s string) Query {
+	var q Query
+	for _, field := range strings.Fields(strings.ToLower(s)) {
+		switch {
+		case strings.HasPrefix(field, "#") && len(field) > 1:
+			q.Tags = append(q.Tags, field[1:])
+		case field == "is:loop" || field == "is:loops":
+			q.Kind = "loop"
+		case field == "is:oneshot" || field == "is:oneshots":
+			q.Kind = "oneshot"
+		case strings.HasPrefix(field, "bpm:"):
+			if r, ok := parseRange(field[4:], 1); ok {
+				q.BPM = r
+			} else {
+				q.Terms = append(q.Terms, field)
+			}
+		case strings.HasPrefix(field, "dur:"):
+			if r, ok := parseRange(field[4:], 0.05); ok {
+				q.Duration = r
+			} else {
+				q.Terms = append(q.Terms, field)
+			}
+		case strings.HasPrefix(field, "ch:"):
+			if n, err := strconv.Atoi(field[3:]); err == nil {
+				q.Channels = n
+			} else {
+				q.Terms = append(q.Terms, field)
+			}
+		default:
+			q.Terms = append(q.Terms, field)
+		}
+	}
+	return q
+}
+
+// Match reports whether an entry passes the query and how well its path matches the terms
+func (q Query) Match(e Entry) (int, bool) {
+	for _, tag := range q.Tags {
+		if !e.HasTag(tag) {
+			return 0, false
+		}
+	}
+	switch q.Kind {
+	case "loop":
+		if !e.IsLoop() {
+			return 0, false
+		}
+	case "oneshot":
+		if e.IsLoop() {
+			return 0, false
+		}
+	}
+	if q.BPM.Set && (e.BPM == 0 || !q.BPM.Contains(e.BPM)) {
+		return 0, false
+	}
+	if q.Duration.Set && (e.Duration == 0 || !q.Duration.Contains(e.Duration)) {
+		return 0, false
+	}
+	if q.Channels > 0 && e.Channels != q.Channels {
+		return 0, false
+	}
+
+	score := 0
+	path := strings.ToLower(e.Path)
+	name := strings.ToLower(e.Name())
+	for _, term := range q.Terms {
+		// Matches inside the file name rank above matches spread over the folders
+		if s, ok := FuzzyScore(term, name); ok {
+			score += s + 2*len(term)
+			continue
+		}
+		if s, ok := FuzzyScore(term, path); ok {
+			score += s
+			continue
+		}
+		// Tags are searched as text too
+		tagMatch := false
+		for _, tag := range e.Tags {
+			if strings.Contains(tag, term) {
+				tagMatch = true
+				break
+			}
+		}
+		if !tagMatch {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// FuzzyScore matches pattern as a subsequence of text (both lower case). Consecutive
+// characters and characters at the start of a word score higher.
+func FuzzyScore(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	p := []rune(pattern)
+	score := 0
+	pi := 0
+	prevMatch := -2
+	prev := rune(0)
+	for i, c := range []rune(text) {
+		if pi < len(p) && c == p[pi] {
+			score++
+			if prevMatch == i-1 {
+				score += 3
+			}
+			if i == 0 || !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			prevMatch = i
+			pi++
+		}
+		prev = c
+	}
+	return score, pi == len(p)
+}
+
+// Search returns the indices of the entries matching a search string, best matches first.
+// Entries with the same score stay in path order.
+func (idx *Index) Search(query string) []int {
+	q := ParseQuery(query)
+	type result struct {
+		index, score int
+	}
+	var results []result
+	for i, e := range idx.Entries {
+		if score, ok := q.Match(e); ok {
+			results = append(results, result{i, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	indices := make([]int, len(results))
+	for i, r := range results {
+		indices[i] = r.index
+	}
+	return indices
+}
@@ -5,6 +5,7 @@ package midiconnector
 import (
 	"fmt"
 	"log"
+	"maps"
 	"strings"
 	"sync"
 
@@ -17,8 +18,131 @@ var mutex sync.Mutex
 
 var devicesOpen map[string]drivers.Out
 
+// devicesWanted holds the devices the tracker opened, to reopen them when they come back
+var devicesWanted map[string]bool
+
+// virtualName is the name of the virtual output of the tracker, "" when it has none
+var virtualName string
+
+// lastListing holds the port of each output at the last Refresh, to skip it when nothing changed
+var lastListing map[string]int
+
 func init() {
 	devicesOpen = make(map[string]drivers.Out)
+	devicesWanted = make(map[string]bool)
+}
+
+// OpenVirtual creates a virtual output other software can connect to, e.g. a DAW. It is
+// listed with the devices under its name.
+func OpenVirtual(name string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if virtualName != "" {
+		return fmt.Errorf("virtual output %s is already open", virtualName)
+	}
+	drv, ok := drivers.Get().(interface {
+		OpenVirtualOut(name string) (drivers.Out, error)
+	})
+	if !ok {
+		return fmt.Errorf("MIDI driver %s cannot create virtual outputs", drivers.Get())
+	}
+	out, err := drv.OpenVirtualOut(name)
+	if err != nil {
+		return err
+	}
+	devicesOpen[name] = out
+	virtualName = name
+	log.Printf("Opened virtual MIDI output %s", name)
+	return nil
+}
+
+// Refresh lists the outputs again, for devices plugged in or out since the last time.
+// Devices the tracker opened are closed when they go away and reopened when they come back,
+// or when they come back on another port. It returns the outputs and the devices that
+// were reopened. Nothing is reopened while the outputs stay the same.
+func Refresh() (devices []string, reopened []string) {
+	outs := listOutPorts()
+	ports := make(map[string]drivers.Out, len(outs))
+	listing := make(map[string]int, len(outs))
+	for _, out := range outs {
+		ports[out.String()] = out
+		listing[out.String()] = out.Number()
+		devices = append(devices, out.String())
+	}
+
+	mutex.Lock()
+	if virtualName != "" {
+		devices = append(devices, virtualName)
+	}
+	if maps.Equal(listing, lastListing) {
+		mutex.Unlock()
+		return
+	}
+	lastListing = listing
+	var toOpen []drivers.Out
+	for name := range devicesWanted {
+		port, present := ports[name]
+		out, open := devicesOpen[name]
+		switch {
+		case open && !present:
+			out.Close()
+			delete(devicesOpen, name)
+			log.Printf("MIDI device %s went away", name)
+		case present && (!open || out.Number() != port.Number()):
+			if open {
+				out.Close()
+				delete(devicesOpen, name)
+			}
+			toOpen = append(toOpen, port)
+		}
+	}
+	mutex.Unlock()
+
+	// Opening a port can take a while, so notes keep going out in the meantime
+	for _, port := range toOpen {
+		name := port.String()
+		if err := port.Open(); err != nil {
+			log.Printf("Failed to reopen MIDI device %s: %v", name, err)
+			continue
+		}
+		mutex.Lock()
+		if _, open := devicesOpen[name]; open || !devicesWanted[name] {
+			// Opened by a note or closed by the tracker in the meantime
+			port.Close()
+		} else {
+			devicesOpen[name] = port
+			reopened = append(reopened, name)
+			log.Printf("MIDI device %s reopened on port %d", name, port.Number())
+		}
+		mutex.Unlock()
+	}
+	return
+}
+
+// listOutPorts returns the outputs without the virtual output of the tracker, which ALSA
+// lists again under the name of its client, e.g. "RtMidiOut Client:ColliderTracker 128:0"
+func listOutPorts() (outs midi.OutPorts) {
+	mutex.Lock()
+	virtual := virtualName
+	mutex.Unlock()
+	for _, out := range midi.GetOutPorts() {
+		if !isVirtualPort(out.String(), virtual) {
+			outs = append(outs, out)
+		}
+	}
+	return
+}
+
+// isVirtualPort reports whether a listed output is the virtual output with the given name
+func isVirtualPort(port, virtual string) bool {
+	if virtual == "" {
+		return false
+	}
+	if port == virtual {
+		return true
+	}
+	_, portName, ok := strings.Cut(port, ":")
+	return ok && (portName == virtual || strings.HasPrefix(portName, virtual+" "))
 }
 
 type Device struct {
@@ -95,11 +219,9 @@ func (d *Device) Open() (err error) {
 	out, err := midi.FindOutPort(d.name)
 	if err == nil {
 		devicesOpen[d.name] = out
+		devicesWanted[d.name] = true
 		err = out.Open()
 	}
-	if err == nil {
-	} else {
-	}
 	return
 }
 
@@ -110,10 +232,15 @@ func (d *Device) Close() (err error) {
 	}
 	mutex.Lock()
 	defer mutex.Unlock()
+	if d.name == virtualName {
+		// The virtual output stays open until the tracker quits
+		return
+	}
 	if out, ok := devicesOpen[d.name]; ok {
 		err = out.Close()
 		delete(devicesOpen, d.name)
 	}
+	delete(devicesWanted, d.name)
 	return
 }
 
@@ -160,10 +287,55 @@ func (d *Device) NoteOff(channel, note uint8) (err error) {
 	return
 }
 
+func (d *Device) ProgramChange(channel, program uint8) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if out, ok := devicesOpen[d.name]; ok {
+		err = out.Send([]byte{0xC0 | channel, program})
+		if err != nil {
+			// Log MIDI errors instead of letting them print to stderr
+			log.Printf("MIDI ProgramChange error for device %s: %v", d.name, err)
+		}
+	}
+	return
+}
+
+// PitchBend sends a 14-bit pitch bend value (0-16383, 8192 = center)
+func (d *Device) PitchBend(channel uint8, value uint16) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if out, ok := devicesOpen[d.name]; ok {
+		err = out.Send([]byte{0xE0 | channel, uint8(value & 0x7F), uint8((value >> 7) & 0x7F)})
+		if err != nil {
+			// Log MIDI errors instead of letting them print to stderr
+			log.Printf("MIDI PitchBend error for device %s: %v", d.name, err)
+		}
+	}
+	return
+}
+
+// ChannelPressure sends channel aftertouch
+func (d *Device) ChannelPressure(channel, pressure uint8) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if out, ok := devicesOpen[d.name]; ok {
+		err = out.Send([]byte{0xD0 | channel, pressure})
+		if err != nil {
+			// Log MIDI errors instead of letting them print to stderr
+			log.Printf("MIDI ChannelPressure error for device %s: %v", d.name, err)
+		}
+	}
+	return
+}
+
 func Devices() (devices []string) {
-	outs := midi.GetOutPorts()
-	for _, out := range outs {
+	for _, out := range listOutPorts() {
 		devices = append(devices, out.String())
 	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if virtualName != "" {
+		devices = append(devices, virtualName)
+	}
 	return
 }
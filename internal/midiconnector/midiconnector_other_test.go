@@ -0,0 +1,20 @@
+//go:build !windows
+
+package midiconnector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVirtualPort(t *testing.T) {
+	// ALSA lists the virtual output again under its client
+	assert.True(t, isVirtualPort("collidertracker", "collidertracker"))
+	assert.True(t, isVirtualPort("RtMidiOut Client:collidertracker 128:0", "collidertracker"))
+	assert.True(t, isVirtualPort("RtMidiOut Client:collidertracker", "collidertracker"))
+
+	assert.False(t, isVirtualPort("Midi Through:Midi Through Port-0 14:0", "collidertracker"))
+	assert.False(t, isVirtualPort("RtMidiOut Client:collidertracker2 128:0", "collidertracker"))
+	assert.False(t, isVirtualPort("collidertracker", ""))
+}
@@ -4,6 +4,7 @@ package midiconnector
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -59,6 +60,52 @@ func (d *Device) Open() (err error) {
 		return
 	}
 	devicesOpen[d.name] = hmo
+	devicesPort[d.name] = uint32(d.num)
+	devicesWanted[d.name] = true
+	return
+}
+
+// OpenVirtual is not supported by the Windows multimedia API, which cannot create ports
+func OpenVirtual(name string) error {
+	return fmt.Errorf("virtual MIDI outputs are not supported on Windows")
+}
+
+// Refresh lists the output devices and reopens the opened devices that were unplugged and
+// plugged back in, or that moved to another device number. It returns the devices and the
+// names of the reopened ones. Nothing is reopened while the devices stay the same.
+func Refresh() (devices []string, reopened []string) {
+	devices = Devices()
+	mutex.Lock()
+	defer mutex.Unlock()
+	if slices.Equal(devices, lastDevices) {
+		return
+	}
+	lastDevices = slices.Clone(devices)
+	for name := range devicesWanted {
+		num := slices.Index(devices, name)
+		hmo, open := devicesOpen[name]
+		if num < 0 {
+			if open {
+				midiOutClose(hmo)
+				delete(devicesOpen, name)
+			}
+			continue
+		}
+		if open && devicesPort[name] == uint32(num) {
+			continue
+		}
+		if open {
+			midiOutClose(hmo)
+			delete(devicesOpen, name)
+		}
+		var newHmo HMIDIOUT
+		if midiOutOpen(&newHmo, uint32(num), 0, 0, 0) != 0 {
+			continue
+		}
+		devicesOpen[name] = newHmo
+		devicesPort[name] = uint32(num)
+		reopened = append(reopened, name)
+	}
 	return
 }
 
@@ -72,6 +119,7 @@ func (d *Device) Close() (err error) {
 		midiOutClose(hmo)
 		delete(devicesOpen, d.name)
 	}
+	delete(devicesWanted, d.name)
 	return
 }
 
@@ -118,6 +166,48 @@ func (d *Device) NoteOff(channel, note uint8) (err error) {
 	return
 }
 
+func (d *Device) ProgramChange(channel, program uint8) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if hmo, ok := devicesOpen[d.name]; ok {
+		message := uint32(0xC0 | channel) // Program Change message for the specified channel
+		message |= uint32(program) << 8
+		if midiOutShortMsg(hmo, message) != 0 {
+			err = fmt.Errorf("failed to send Program Change message")
+		}
+	}
+	return
+}
+
+// PitchBend sends a 14-bit pitch bend value (0-16383, 8192 = center)
+func (d *Device) PitchBend(channel uint8, value uint16) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if hmo, ok := devicesOpen[d.name]; ok {
+		message := uint32(0xE0 | channel) // Pitch Bend message for the specified channel
+		message |= uint32(value&0x7F) << 8
+		message |= uint32((value>>7)&0x7F) << 16
+		if midiOutShortMsg(hmo, message) != 0 {
+			err = fmt.Errorf("failed to send Pitch Bend message")
+		}
+	}
+	return
+}
+
+// ChannelPressure sends channel aftertouch
+func (d *Device) ChannelPressure(channel, pressure uint8) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if hmo, ok := devicesOpen[d.name]; ok {
+		message := uint32(0xD0 | channel) // Channel Pressure message for the specified channel
+		message |= uint32(pressure) << 8
+		if midiOutShortMsg(hmo, message) != 0 {
+			err = fmt.Errorf("failed to send Channel Pressure message")
+		}
+	}
+	return
+}
+
 // Constants
 const (
 	MAXPNAMELEN  = 32
@@ -145,8 +235,19 @@ var (
 
 var devicesOpen map[string]HMIDIOUT
 
+// devicesPort holds the device number each open device was opened on
+var devicesPort map[string]uint32
+
+// devicesWanted holds the devices opened by the tracker, to reopen when plugged back in
+var devicesWanted map[string]bool
+
+// lastDevices holds the devices at the last Refresh, to skip it when nothing changed
+var lastDevices []string
+
 func init() {
 	devicesOpen = make(map[string]HMIDIOUT)
+	devicesPort = make(map[string]uint32)
+	devicesWanted = make(map[string]bool)
 }
 
 type HMIDIOUT uintptr
@@ -1,7 +1,6 @@
 package midiplayer
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -12,23 +11,32 @@ import (
 	"github.com/schollz/collidertracker/internal/midiconnector"
 )
 
-// NoteState tracks the state of a single note
-type NoteState struct {
-	Note     int
-	Velocity int
-	Cancel   context.CancelFunc // Cancellation function for the note-off goroutine
+// InstrumentState tracks a channel of a device
+type InstrumentState struct {
+	Player    *Player
+	Scheduler *Scheduler // Scheduler of the device, shared by its channels
+	Patch     *Patch     // Patch last selected on the channel, nil until one is sent
 }
 
-// InstrumentState tracks all notes for a single instrument
-type InstrumentState struct {
-	Player *Player
-	Notes  map[int]*NoteState // map of note -> NoteState
+// Patch is the sound a synth channel is set to. Fields that are -1 are left to the synth.
+type Patch struct {
+	BankMSB   int // Bank select MSB, CC 0 (0-127)
+	BankLSB   int // Bank select LSB, CC 32 (0-127)
+	Program   int // Program change (0-127)
+	BendRange int // Pitch bend range in semitones, sent as RPN 0
+}
+
+// IsEmpty reports whether a patch leaves everything to the synth
+func (p Patch) IsEmpty() bool {
+	return p.BankMSB < 0 && p.BankLSB < 0 && p.Program < 0 && p.BendRange < 0
 }
 
-// GlobalMidiState manages all MIDI instruments and their note states
+// GlobalMidiState manages all MIDI instruments and the schedulers of their devices
 type GlobalMidiState struct {
 	mu          sync.RWMutex
 	instruments map[string]*InstrumentState // map of "instrument:channel" -> InstrumentState
+	schedulers  map[string]*Scheduler       // map of device -> Scheduler
+	latencies   map[string]time.Duration    // map of device -> latency, also for devices not used yet
 }
 
 // Global state instance
@@ -94,6 +102,11 @@ func (m Player) String() string {
 	return m.Name
 }
 
+// Channel returns the 0-indexed channel of the player
+func (m *Player) Channel() int {
+	return int(m.channel)
+}
+
 func (m *Player) Close() (err error) {
 	if m.opened {
 		err = m.Device.Close()
@@ -116,6 +129,28 @@ func (m *Player) ControlChange(controller int, value int) (err error) {
 	return
 }
 
+func (m *Player) ProgramChange(program int) (err error) {
+	if m.opened {
+		err = m.Device.ProgramChange(m.channel, uint8(program))
+	}
+	return
+}
+
+// PitchBend sends a 14-bit pitch bend value (0-16383, 8192 = center)
+func (m *Player) PitchBend(value int) (err error) {
+	if m.opened {
+		err = m.Device.PitchBend(m.channel, uint16(value))
+	}
+	return
+}
+
+func (m *Player) ChannelPressure(pressure int) (err error) {
+	if m.opened {
+		err = m.Device.ChannelPressure(m.channel, uint8(pressure))
+	}
+	return
+}
+
 func (m *Player) NoteOff(note int) (err error) {
 	if m.opened {
 		err = m.Device.NoteOff(m.channel, uint8(note))
@@ -141,6 +176,8 @@ func getGlobalState() *GlobalMidiState {
 	globalOnce.Do(func() {
 		globalState = &GlobalMidiState{
 			instruments: make(map[string]*InstrumentState),
+			schedulers:  make(map[string]*Scheduler),
+			latencies:   make(map[string]time.Duration),
 		}
 		log.Printf("[MIDIPLAYER] Global MIDI state initialized")
 	})
@@ -210,10 +247,19 @@ func (gms *GlobalMidiState) getOrCreateInstrument(midiinstrument string, channel
 		return nil, fmt.Errorf("failed to create player for %s: %v", actualDeviceName, err)
 	}
 
+	// Channels of a device share its scheduler, so their messages go out in order
+	scheduler, exists := gms.schedulers[actualDeviceName]
+	if !exists {
+		scheduler = NewScheduler(actualDeviceName)
+		scheduler.SetLatency(gms.latencies[actualDeviceName])
+		scheduler.Start()
+		gms.schedulers[actualDeviceName] = scheduler
+	}
+
 	// Create instrument state
 	instrumentState := &InstrumentState{
-		Player: player,
-		Notes:  make(map[int]*NoteState),
+		Player:    player,
+		Scheduler: scheduler,
 	}
 
 	gms.instruments[instrumentKey] = instrumentState
@@ -243,80 +289,66 @@ func NoteOn(midiinstrument string, note float64, velocity float64, duration floa
 		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
 	}
 
-	gms.mu.Lock()
-	defer gms.mu.Unlock()
+	// The scheduler ends the note after its duration, and first ends it if it is still on
+	instrument.Scheduler.NoteOn(instrument.Player, noteInt, velocityInt, time.Duration(duration*float64(time.Second)))
+	return nil
+}
 
-	// Check if this note is already playing
-	if existingNote, exists := instrument.Notes[noteInt]; exists {
-		log.Printf("[MIDIPLAYER] Note %d already playing on %s, cancelling previous and sending note-off",
-			noteInt, midiinstrument)
+// ControlChange sends a MIDI Control Change message
+func ControlChange(midiinstrument string, controller int, value int, channel int) error {
+	// Early return for disabled MIDI to avoid initializing RtMidi
+	if midiinstrument == "None" || midiinstrument == "" {
+		return nil
+	}
 
-		// Cancel the existing note-off goroutine
-		existingNote.Cancel()
+	gms := getGlobalState()
 
-		// Send immediate note-off
-		err := instrument.Player.NoteOff(noteInt)
-		if err != nil {
-			log.Printf("[MIDIPLAYER] Error sending immediate note-off for note %d: %v", noteInt, err)
-		}
-	}
+	log.Printf("[MIDIPLAYER] ControlChange called: instrument=%s, controller=%d, value=%d, channel=%d",
+		midiinstrument, controller, value, channel)
 
-	// Send note-on
-	err = instrument.Player.NoteOn(noteInt, velocityInt)
+	// Get or create instrument
+	instrument, err := gms.getOrCreateInstrument(midiinstrument, channel)
 	if err != nil {
-		return fmt.Errorf("failed to send note-on for note %d: %v", noteInt, err)
+		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
 	}
 
-	log.Printf("[MIDIPLAYER] Note-on sent: instrument=%s, note=%d, velocity=%d",
-		midiinstrument, noteInt, velocityInt)
-
-	// Create cancellable context for note-off
-	ctx, cancel := context.WithCancel(context.Background())
+	instrument.Scheduler.schedule(instrument.Player, eventControlChange, controller, value)
+	return nil
+}
 
-	// Store note state
-	instrument.Notes[noteInt] = &NoteState{
-		Note:     noteInt,
-		Velocity: velocityInt,
-		Cancel:   cancel,
+// SelectPatch sets a channel to a patch when it was not set to it yet: on first use and after
+// the patch changes. force sends it anyway, e.g. to recall the patches of a song that loads.
+func SelectPatch(midiinstrument string, channel int, patch Patch, force bool) error {
+	// Early return for disabled MIDI to avoid initializing RtMidi
+	if midiinstrument == "None" || midiinstrument == "" || patch.IsEmpty() {
+		return nil
 	}
 
-	// Start goroutine for note-off after duration
-	go func() {
-		timer := time.NewTimer(time.Duration(duration * float64(time.Second)))
-		defer timer.Stop()
+	gms := getGlobalState()
 
-		select {
-		case <-timer.C:
-			// Duration elapsed, send note-off
-			log.Printf("[MIDIPLAYER] Duration elapsed, sending note-off: instrument=%s, note=%d",
-				midiinstrument, noteInt)
+	// Get or create instrument
+	instrument, err := gms.getOrCreateInstrument(midiinstrument, channel)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
+	}
 
-			err := instrument.Player.NoteOff(noteInt)
-			if err != nil {
-				log.Printf("[MIDIPLAYER] Error sending note-off for note %d: %v", noteInt, err)
-			}
+	gms.mu.Lock()
+	defer gms.mu.Unlock()
 
-			// Remove from active notes
-			gms.mu.Lock()
-			instrumentKey := fmt.Sprintf("%s:%d", midiinstrument, channel)
-			if inst, exists := gms.instruments[instrumentKey]; exists {
-				delete(inst.Notes, noteInt)
-				log.Printf("[MIDIPLAYER] Note %d removed from active notes for %s (channel %d)", noteInt, midiinstrument, channel)
-			}
-			gms.mu.Unlock()
+	if !force && instrument.Patch != nil && *instrument.Patch == patch {
+		return nil
+	}
+	instrument.Scheduler.SelectPatch(instrument.Player, patch)
+	instrument.Patch = &patch
 
-		case <-ctx.Done():
-			// Context was cancelled (overlapping note)
-			log.Printf("[MIDIPLAYER] Note-off cancelled for note %d on %s (channel %d, overlapping note)",
-				noteInt, midiinstrument, channel)
-		}
-	}()
+	log.Printf("[MIDIPLAYER] Patch selected: instrument=%s, channel=%d, bank=%d/%d, program=%d, bend range=%d",
+		midiinstrument, channel, patch.BankMSB, patch.BankLSB, patch.Program, patch.BendRange)
 
 	return nil
 }
 
-// ControlChange sends a MIDI Control Change message
-func ControlChange(midiinstrument string, controller int, value int, channel int) error {
+// PitchBend sends a 14-bit pitch bend value (0-16383, 8192 = center)
+func PitchBend(midiinstrument string, value int, channel int) error {
 	// Early return for disabled MIDI to avoid initializing RtMidi
 	if midiinstrument == "None" || midiinstrument == "" {
 		return nil
@@ -324,68 +356,105 @@ func ControlChange(midiinstrument string, controller int, value int, channel int
 
 	gms := getGlobalState()
 
-	log.Printf("[MIDIPLAYER] ControlChange called: instrument=%s, controller=%d, value=%d, channel=%d",
-		midiinstrument, controller, value, channel)
-
 	// Get or create instrument
 	instrument, err := gms.getOrCreateInstrument(midiinstrument, channel)
 	if err != nil {
 		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
 	}
 
-	gms.mu.Lock()
-	defer gms.mu.Unlock()
+	instrument.Scheduler.schedule(instrument.Player, eventPitchBend, value, 0)
+	return nil
+}
 
-	// Send control change
-	err = instrument.Player.ControlChange(controller, value)
-	if err != nil {
-		return fmt.Errorf("failed to send control change for controller %d: %v", controller, err)
+// ChannelPressure sends channel aftertouch (0-127)
+func ChannelPressure(midiinstrument string, pressure int, channel int) error {
+	// Early return for disabled MIDI to avoid initializing RtMidi
+	if midiinstrument == "None" || midiinstrument == "" {
+		return nil
 	}
 
-	log.Printf("[MIDIPLAYER] Control change sent: instrument=%s, controller=%d, value=%d",
-		midiinstrument, controller, value)
+	gms := getGlobalState()
+
+	// Get or create instrument
+	instrument, err := gms.getOrCreateInstrument(midiinstrument, channel)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
+	}
 
+	instrument.Scheduler.schedule(instrument.Player, eventChannelPressure, pressure, 0)
 	return nil
 }
 
-// StopAll stops all notes currently playing on the given instrument and channel
-func StopAll(midiinstrument string, channel int) {
-	gms := getGlobalState()
-	gms.mu.Lock()
-	defer gms.mu.Unlock()
+// CenterPitchBend centers the pitch bend of a channel that a row left bent
+func CenterPitchBend(midiinstrument string, channel int) error {
+	// Early return for disabled MIDI to avoid initializing RtMidi
+	if midiinstrument == "None" || midiinstrument == "" {
+		return nil
+	}
 
-	instrumentKey := fmt.Sprintf("%s:%d", midiinstrument, channel)
-	log.Printf("[MIDIPLAYER] StopAll called for instrument: %s (channel %d)", midiinstrument, channel)
+	instrument, err := getGlobalState().getOrCreateInstrument(midiinstrument, channel)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
+	}
 
-	instrument, exists := gms.instruments[instrumentKey]
-	if !exists {
-		log.Printf("[MIDIPLAYER] Instrument %s (channel %d) not found, nothing to stop", midiinstrument, channel)
-		return
+	instrument.Scheduler.CenterPitchBend(instrument.Player)
+	return nil
+}
+
+// ReleaseChannelPressure releases the channel aftertouch a row left on a channel
+func ReleaseChannelPressure(midiinstrument string, channel int) error {
+	// Early return for disabled MIDI to avoid initializing RtMidi
+	if midiinstrument == "None" || midiinstrument == "" {
+		return nil
 	}
 
-	if len(instrument.Notes) == 0 {
-		log.Printf("[MIDIPLAYER] No active notes for instrument %s (channel %d)", midiinstrument, channel)
-		return
+	instrument, err := getGlobalState().getOrCreateInstrument(midiinstrument, channel)
+	if err != nil {
+		return fmt.Errorf("failed to get instrument %s: %v", midiinstrument, err)
 	}
 
-	log.Printf("[MIDIPLAYER] Stopping %d active notes for instrument %s (channel %d)",
-		len(instrument.Notes), midiinstrument, channel)
+	instrument.Scheduler.ReleaseChannelPressure(instrument.Player)
+	return nil
+}
 
-	// Cancel all note-off goroutines and send immediate note-offs
-	for noteInt, noteState := range instrument.Notes {
-		log.Printf("[MIDIPLAYER] Stopping note %d on %s (channel %d)", noteInt, midiinstrument, channel)
+// StopAll drops the queued messages of every device and ends its notes with note-offs,
+// sustain off and all notes off, centering the pitch bend and releasing the pressure
+func StopAll() {
+	for _, scheduler := range schedulers() {
+		scheduler.Stop()
+	}
+	log.Printf("[MIDIPLAYER] All notes stopped")
+}
 
-		// Cancel the note-off goroutine
-		noteState.Cancel()
+// SetLatency sets how long the messages to a device wait, to line it up with the audio
+func SetLatency(device string, latency time.Duration) {
+	gms := getGlobalState()
+	gms.mu.Lock()
+	defer gms.mu.Unlock()
+	gms.latencies[device] = latency
+	if scheduler, exists := gms.schedulers[device]; exists {
+		scheduler.SetLatency(latency)
+	}
+}
 
-		// Send immediate note-off
-		err := instrument.Player.NoteOff(noteInt)
-		if err != nil {
-			log.Printf("[MIDIPLAYER] Error sending note-off for note %d: %v", noteInt, err)
-		}
+// HeldNotes returns the notes that are on at every device, for finding hanging notes
+func HeldNotes() []HeldNote {
+	var notes []HeldNote
+	for _, scheduler := range schedulers() {
+		notes = append(notes, scheduler.HeldNotes()...)
 	}
+	sortHeldNotes(notes)
+	return notes
+}
 
-	// Clear all notes
-	instrument.Notes = make(map[int]*NoteState)
-	log.Printf("[MIDIPLAYER] All notes stopped for instrument %s (channel %d)", midiinstrument, channel)
+// schedulers returns the schedulers of the devices used so far
+func schedulers() []*Scheduler {
+	gms := getGlobalState()
+	gms.mu.RLock()
+	defer gms.mu.RUnlock()
+	list := make([]*Scheduler, 0, len(gms.schedulers))
+	for _, scheduler := range gms.schedulers {
+		list = append(list, scheduler)
+	}
+	return list
 }
@@ -1,7 +1,6 @@
 package midiplayer
 
 import (
-	"context"
 	"strings"
 	"testing"
 
@@ -20,32 +19,24 @@ func TestPlayer(t *testing.T) {
 	})
 }
 
-func TestNoteState(t *testing.T) {
-	t.Run("note state creation", func(t *testing.T) {
-		_, cancel := context.WithCancel(context.Background())
-		defer cancel()
+func TestPatch(t *testing.T) {
+	assert.True(t, Patch{BankMSB: -1, BankLSB: -1, Program: -1, BendRange: -1}.IsEmpty())
+	assert.False(t, Patch{BankMSB: -1, BankLSB: -1, Program: 0, BendRange: -1}.IsEmpty())
+	assert.False(t, Patch{BankMSB: -1, BankLSB: -1, Program: -1, BendRange: 12}.IsEmpty())
 
-		ns := &NoteState{
-			Note:     60,
-			Velocity: 127,
-			Cancel:   cancel,
-		}
-
-		assert.Equal(t, 60, ns.Note)
-		assert.Equal(t, 127, ns.Velocity)
-		assert.NotNil(t, ns.Cancel)
-	})
+	// Disabled MIDI never sends a patch
+	assert.NoError(t, SelectPatch("None", 0, Patch{Program: 4}, true))
 }
 
 func TestInstrumentState(t *testing.T) {
 	t.Run("instrument state initialization", func(t *testing.T) {
 		is := &InstrumentState{
-			Player: nil, // No actual player for test
-			Notes:  make(map[int]*NoteState),
+			Player:    nil, // No actual player for test
+			Scheduler: NewScheduler("test"),
 		}
 
-		assert.NotNil(t, is.Notes)
-		assert.Equal(t, 0, len(is.Notes))
+		assert.Nil(t, is.Patch)
+		assert.Empty(t, is.Scheduler.HeldNotes())
 	})
 }
 
@@ -58,6 +49,7 @@ func TestGlobalMidiState(t *testing.T) {
 		// Should be the same instance
 		assert.Equal(t, gms1, gms2)
 		assert.NotNil(t, gms1.instruments)
+		assert.NotNil(t, gms1.schedulers)
 	})
 }
 
@@ -0,0 +1,402 @@
+package midiplayer
+
+import (
+	"container/heap"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxLatency is the longest a device can be delayed to line up with the audio
+const MaxLatency = 500 * time.Millisecond
+
+// PitchBendCenter is the 14-bit pitch bend value that leaves notes unbent
+const PitchBendCenter = 8192
+
+// hangingGrace is how long a note may stay on after its note-off was due before it counts as hanging
+const hangingGrace = 100 * time.Millisecond
+
+// Output sends the messages of one channel of a device; a Player is one
+type Output interface {
+	Channel() int
+	NoteOn(note int, velocity int) error
+	NoteOff(note int) error
+	ControlChange(controller int, value int) error
+	ProgramChange(program int) error
+	PitchBend(value int) error
+	ChannelPressure(pressure int) error
+}
+
+type eventKind int
+
+const (
+	eventNoteOn eventKind = iota
+	eventNoteOff
+	eventControlChange
+	eventProgramChange
+	eventPitchBend
+	eventChannelPressure
+)
+
+// event is a message waiting in the queue of a scheduler
+type event struct {
+	at        time.Time
+	seq       uint64 // Events due at the same time go out in the order they were scheduled
+	kind      eventKind
+	out       Output
+	data1     int
+	data2     int
+	off       *event // Note-off of a note-on
+	cancelled bool   // Note-offs of notes played again before they ended are skipped
+}
+
+// send sends the message of an event
+func (e *event) send() error {
+	switch e.kind {
+	case eventNoteOn:
+		return e.out.NoteOn(e.data1, e.data2)
+	case eventNoteOff:
+		return e.out.NoteOff(e.data1)
+	case eventControlChange:
+		return e.out.ControlChange(e.data1, e.data2)
+	case eventProgramChange:
+		return e.out.ProgramChange(e.data1)
+	case eventPitchBend:
+		return e.out.PitchBend(e.data1)
+	default:
+		return e.out.ChannelPressure(e.data1)
+	}
+}
+
+// eventQueue is a min-heap of events by time
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)   { *q = append(*q, x.(*event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// noteKey is a note on a channel
+type noteKey struct {
+	out  Output
+	note int
+}
+
+// HeldNote is a note a device was sent a note-on for and no note-off yet
+type HeldNote struct {
+	Device  string
+	Channel int // 0-indexed
+	Note    int
+	On      time.Time // When the note-on was sent
+	Off     time.Time // When the note-off is due
+}
+
+// Hanging reports whether a note is still on well after its note-off was due, e.g. because
+// the note-off could not be sent
+func (n HeldNote) Hanging(now time.Time) bool {
+	return now.After(n.Off.Add(hangingGrace))
+}
+
+// Scheduler sends the messages of one device from a single queue, in time order. Every
+// message waits for the latency of the device, so an external synth can be lined up with
+// the audio of the tracker.
+type Scheduler struct {
+	name    string
+	mu      sync.Mutex
+	latency time.Duration
+	queue   eventQueue
+	seq     uint64
+	stops   uint64                // Times Stop ran, so Dispatch can tell a stop while it sent
+	offs    map[noteKey]*event    // Pending note-offs of the notes scheduled on
+	held    map[noteKey]*HeldNote // Notes that are on at the device
+	outputs map[Output]bool       // Channels that were sent to, for stopping
+	bent    map[Output]bool       // Channels left bent, to center again
+	pressed map[Output]bool       // Channels left with channel pressure, to release again
+	wake    chan struct{}
+}
+
+// NewScheduler returns a scheduler for a device. Start runs it.
+func NewScheduler(name string) *Scheduler {
+	return &Scheduler{
+		name:    name,
+		offs:    make(map[noteKey]*event),
+		held:    make(map[noteKey]*HeldNote),
+		outputs: make(map[Output]bool),
+		bent:    make(map[Output]bool),
+		pressed: make(map[Output]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Start sends the events of the scheduler as they come due, until the program ends
+func (s *Scheduler) Start() {
+	go func() {
+		timer := time.NewTimer(time.Hour)
+		for {
+			wait := time.Hour
+			if next, ok := s.Dispatch(time.Now()); ok {
+				wait = time.Until(next)
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// Latency returns how long messages wait before they are sent
+func (s *Scheduler) Latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latency
+}
+
+// SetLatency sets how long messages wait before they are sent, up to MaxLatency
+func (s *Scheduler) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = min(max(latency, 0), MaxLatency)
+}
+
+// push queues an event; the caller holds the lock
+func (s *Scheduler) push(e *event) *event {
+	s.seq++
+	e.seq = s.seq
+	heap.Push(&s.queue, e)
+	s.outputs[e.out] = true
+	return e
+}
+
+// schedule queues a message for now plus the latency
+func (s *Scheduler) schedule(out Output, kind eventKind, data1, data2 int) {
+	s.mu.Lock()
+	s.push(&event{at: time.Now().Add(s.latency), kind: kind, out: out, data1: data1, data2: data2})
+	switch kind {
+	case eventPitchBend:
+		s.bent[out] = data1 != PitchBendCenter
+	case eventChannelPressure:
+		s.pressed[out] = data1 != 0
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// CenterPitchBend queues a centered pitch bend on a channel that was left bent, e.g. when
+// a row without a bend follows a bent one
+func (s *Scheduler) CenterPitchBend(out Output) {
+	s.mu.Lock()
+	bent := s.bent[out]
+	s.mu.Unlock()
+	if bent {
+		s.schedule(out, eventPitchBend, PitchBendCenter, 0)
+	}
+}
+
+// ReleaseChannelPressure queues no pressure on a channel that was left pressed, e.g. when
+// a row without aftertouch follows one with it
+func (s *Scheduler) ReleaseChannelPressure(out Output) {
+	s.mu.Lock()
+	pressed := s.pressed[out]
+	s.mu.Unlock()
+	if pressed {
+		s.schedule(out, eventChannelPressure, 0, 0)
+	}
+}
+
+// NoteOn queues a note from now plus the latency, for duration. A note that is still on is
+// ended first, and its pending note-off dropped.
+func (s *Scheduler) NoteOn(out Output, note, velocity int, duration time.Duration) {
+	s.mu.Lock()
+	s.noteOnAt(out, time.Now().Add(s.latency), note, velocity, duration)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// noteOnAt queues a note at a time; the caller holds the lock
+func (s *Scheduler) noteOnAt(out Output, at time.Time, note, velocity int, duration time.Duration) {
+	key := noteKey{out, note}
+	pending, scheduled := s.offs[key]
+	if scheduled {
+		pending.cancelled = true
+	}
+	if _, on := s.held[key]; on || scheduled {
+		s.push(&event{at: at, kind: eventNoteOff, out: out, data1: note})
+	}
+	on := s.push(&event{at: at, kind: eventNoteOn, out: out, data1: note, data2: velocity})
+	on.off = s.push(&event{at: at.Add(max(duration, 0)), kind: eventNoteOff, out: out, data1: note})
+	s.offs[key] = on.off
+}
+
+// SelectPatch queues the bank select, program change and pitch bend range (RPN 0) of a patch
+func (s *Scheduler) SelectPatch(out Output, patch Patch) {
+	s.mu.Lock()
+	at := time.Now().Add(s.latency)
+	if patch.BankMSB >= 0 {
+		s.push(&event{at: at, kind: eventControlChange, out: out, data1: 0, data2: patch.BankMSB})
+	}
+	if patch.BankLSB >= 0 {
+		s.push(&event{at: at, kind: eventControlChange, out: out, data1: 32, data2: patch.BankLSB})
+	}
+	if patch.Program >= 0 {
+		s.push(&event{at: at, kind: eventProgramChange, out: out, data1: patch.Program})
+	}
+	if patch.BendRange >= 0 {
+		// RPN 0 (pitch bend sensitivity), then the null RPN so data entry stops applying to it
+		for _, cc := range [][2]int{{101, 0}, {100, 0}, {6, patch.BendRange}, {38, 0}, {101, 127}, {100, 127}} {
+			s.push(&event{at: at, kind: eventControlChange, out: out, data1: cc[0], data2: cc[1]})
+		}
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Dispatch sends the events due at a time. It returns when the next event is due, if any.
+// The due events are taken from the queue under the lock and sent after it is released, so
+// a slow device does not hold up the tracker queueing the next notes.
+func (s *Scheduler) Dispatch(now time.Time) (next time.Time, ok bool) {
+	s.mu.Lock()
+	var due []*event
+	for len(s.queue) > 0 {
+		e := s.queue[0]
+		if e.at.After(now) {
+			next, ok = e.at, true
+			break
+		}
+		heap.Pop(&s.queue)
+		if e.cancelled {
+			continue
+		}
+		key := noteKey{e.out, e.data1}
+		if s.offs[key] == e {
+			delete(s.offs, key)
+		}
+		due = append(due, e)
+	}
+	stops := s.stops
+	s.mu.Unlock()
+
+	sent := due[:0]
+	for _, e := range due {
+		if err := e.send(); err != nil {
+			log.Printf("[MIDIPLAYER] Error sending to %s: %v", s.name, err)
+			continue // A note-off that fails leaves the note held, so it shows up as hanging
+		}
+		sent = append(sent, e)
+	}
+
+	s.mu.Lock()
+	stopped := s.stops != stops
+	for _, e := range sent {
+		key := noteKey{e.out, e.data1}
+		switch {
+		case stopped:
+			// Stop ran while the events were sent and did not see these notes held
+		case e.kind == eventNoteOn:
+			s.held[key] = &HeldNote{Device: s.name, Channel: e.out.Channel(), Note: e.data1, On: e.at, Off: e.off.at}
+		case e.kind == eventNoteOff:
+			delete(s.held, key)
+		}
+	}
+	s.mu.Unlock()
+	if stopped {
+		endNotes(s.name, sent)
+	}
+	return next, ok
+}
+
+// endNotes sends a note-off for each note-on of events sent while the scheduler was stopped
+func endNotes(name string, events []*event) {
+	for _, e := range events {
+		if e.kind != eventNoteOn {
+			continue
+		}
+		if err := e.out.NoteOff(e.data1); err != nil {
+			log.Printf("[MIDIPLAYER] Error sending note-off for note %d to %s: %v", e.data1, name, err)
+		}
+	}
+}
+
+// Stop drops the queued events and ends every note: a note-off for each held note, then
+// sustain off and all notes off on every channel that was played. The channels are also
+// left unbent and without pressure for the next notes.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops++
+	s.queue = nil
+	clear(s.offs)
+	now := time.Now()
+	for key, note := range s.held {
+		if note.Hanging(now) {
+			log.Printf("[MIDIPLAYER] Note %d hung on %s (channel %d) since %s", note.Note, s.name, note.Channel+1, note.On.Format(time.TimeOnly))
+		}
+		if err := key.out.NoteOff(key.note); err != nil {
+			log.Printf("[MIDIPLAYER] Error sending note-off for note %d to %s: %v", key.note, s.name, err)
+		}
+	}
+	clear(s.held)
+	for out := range s.outputs {
+		err := out.ControlChange(64, 0) // Sustain off
+		if err == nil {
+			err = out.ControlChange(123, 0) // All notes off
+		}
+		if err == nil {
+			err = out.PitchBend(PitchBendCenter)
+		}
+		if err == nil {
+			err = out.ChannelPressure(0)
+		}
+		if err != nil {
+			log.Printf("[MIDIPLAYER] Error stopping channel %d of %s: %v", out.Channel()+1, s.name, err)
+		}
+	}
+	clear(s.bent)
+	clear(s.pressed)
+	s.notify()
+}
+
+// HeldNotes returns the notes that are on at the device, by channel and note
+func (s *Scheduler) HeldNotes() []HeldNote {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notes := make([]HeldNote, 0, len(s.held))
+	for _, note := range s.held {
+		notes = append(notes, *note)
+	}
+	sortHeldNotes(notes)
+	return notes
+}
+
+// notify wakes the dispatch loop to look at the queue again
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func sortHeldNotes(notes []HeldNote) {
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].Device != notes[j].Device {
+			return notes[i].Device < notes[j].Device
+		}
+		if notes[i].Channel != notes[j].Channel {
+			return notes[i].Channel < notes[j].Channel
+		}
+		return notes[i].Note < notes[j].Note
+	})
+}
@@ -0,0 +1,180 @@
+package midiplayer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is an output that writes down what it is sent
+type recorder struct {
+	channel  int
+	messages []string
+	failOff  bool
+	sending  func() // Runs after a note-on is sent
+}
+
+func (r *recorder) Channel() int { return r.channel }
+func (r *recorder) NoteOn(note, velocity int) error {
+	r.messages = append(r.messages, fmt.Sprintf("on %d %d", note, velocity))
+	if r.sending != nil {
+		r.sending()
+	}
+	return nil
+}
+func (r *recorder) NoteOff(note int) error {
+	if r.failOff {
+		return fmt.Errorf("unplugged")
+	}
+	r.messages = append(r.messages, fmt.Sprintf("off %d", note))
+	return nil
+}
+func (r *recorder) ControlChange(controller, value int) error {
+	r.messages = append(r.messages, fmt.Sprintf("cc %d %d", controller, value))
+	return nil
+}
+func (r *recorder) ProgramChange(program int) error {
+	r.messages = append(r.messages, fmt.Sprintf("pc %d", program))
+	return nil
+}
+func (r *recorder) PitchBend(value int) error {
+	r.messages = append(r.messages, fmt.Sprintf("pb %d", value))
+	return nil
+}
+func (r *recorder) ChannelPressure(pressure int) error {
+	r.messages = append(r.messages, fmt.Sprintf("at %d", pressure))
+	return nil
+}
+
+func TestSchedulerLatency(t *testing.T) {
+	s := NewScheduler("Synth")
+	out := &recorder{}
+	s.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	s.schedule(out, eventControlChange, 74, 10)
+	s.NoteOn(out, 60, 100, 100*time.Millisecond)
+
+	// Nothing goes out before the latency
+	next, ok := s.Dispatch(start)
+	require.True(t, ok)
+	assert.Empty(t, out.messages)
+	assert.GreaterOrEqual(t, next.Sub(start), 50*time.Millisecond)
+
+	// Then the messages in the order they were scheduled, the note-off after the duration
+	next, ok = s.Dispatch(start.Add(60 * time.Millisecond))
+	require.True(t, ok)
+	assert.Equal(t, []string{"cc 74 10", "on 60 100"}, out.messages)
+	assert.Len(t, s.HeldNotes(), 1)
+	_, ok = s.Dispatch(next)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"cc 74 10", "on 60 100", "off 60"}, out.messages)
+	assert.Empty(t, s.HeldNotes())
+
+	// Latency is at most MaxLatency
+	s.SetLatency(time.Hour)
+	assert.Equal(t, MaxLatency, s.Latency())
+	s.SetLatency(-time.Second)
+	assert.Equal(t, time.Duration(0), s.Latency())
+}
+
+func TestSchedulerRetriggeredNote(t *testing.T) {
+	s := NewScheduler("Synth")
+	out := &recorder{}
+	start := time.Now()
+
+	// A note played again before it ends is ended first, and its first note-off dropped
+	s.noteOnAt(out, start, 60, 100, time.Second)
+	s.noteOnAt(out, start.Add(100*time.Millisecond), 60, 90, 200*time.Millisecond)
+	s.noteOnAt(out, start.Add(100*time.Millisecond), 64, 90, 200*time.Millisecond)
+	s.Dispatch(start.Add(2 * time.Second))
+	assert.Equal(t, []string{"on 60 100", "off 60", "on 60 90", "on 64 90", "off 60", "off 64"}, out.messages)
+	assert.Empty(t, s.HeldNotes())
+}
+
+func TestSchedulerStopAndHangingNotes(t *testing.T) {
+	s := NewScheduler("Synth")
+	piano := &recorder{channel: 0}
+	bass := &recorder{channel: 1, failOff: true}
+	start := time.Now().Add(-time.Second)
+
+	s.noteOnAt(piano, start, 60, 100, time.Second)
+	s.noteOnAt(bass, start, 36, 100, 10*time.Millisecond)
+	s.noteOnAt(piano, start.Add(2*time.Second), 62, 100, time.Second)
+	s.Dispatch(start.Add(500 * time.Millisecond))
+
+	// The note-off the bass could not be sent leaves its note hanging
+	notes := s.HeldNotes()
+	require.Len(t, notes, 2)
+	assert.Equal(t, HeldNote{Device: "Synth", Channel: 0, Note: 60, On: start, Off: start.Add(time.Second)}, notes[0])
+	assert.False(t, notes[0].Hanging(start.Add(500*time.Millisecond)))
+	assert.Equal(t, 36, notes[1].Note)
+	assert.True(t, notes[1].Hanging(start.Add(500*time.Millisecond)))
+
+	// Stop drops what is queued and ends every note and channel
+	s.Stop()
+	assert.Equal(t, []string{"on 60 100", "off 60", "cc 64 0", "cc 123 0", "pb 8192", "at 0"}, piano.messages)
+	assert.Equal(t, []string{"on 36 100", "cc 64 0", "cc 123 0", "pb 8192", "at 0"}, bass.messages)
+	assert.Empty(t, s.HeldNotes())
+	_, ok := s.Dispatch(start.Add(time.Hour))
+	assert.False(t, ok)
+}
+
+func TestSchedulerStopWhileSending(t *testing.T) {
+	s := NewScheduler("Synth")
+	out := &recorder{}
+	start := time.Now().Add(-time.Second)
+	s.noteOnAt(out, start, 60, 100, time.Hour)
+
+	// Notes can be queued and stopped while the device is being sent to. The note that was
+	// going out when Stop ran is ended once it has been sent.
+	out.sending = func() {
+		out.sending = nil
+		s.NoteOn(out, 62, 100, time.Second)
+		s.Stop()
+	}
+	s.Dispatch(start)
+	assert.Equal(t, []string{"on 60 100", "cc 64 0", "cc 123 0", "pb 8192", "at 0", "off 60"}, out.messages)
+	assert.Empty(t, s.HeldNotes())
+	_, ok := s.Dispatch(start.Add(time.Hour))
+	assert.False(t, ok)
+}
+
+func TestSchedulerExpressionReset(t *testing.T) {
+	s := NewScheduler("Synth")
+	out := &recorder{}
+
+	// Channels that were never bent or pressed are left alone
+	s.CenterPitchBend(out)
+	s.ReleaseChannelPressure(out)
+	s.Dispatch(time.Now())
+	assert.Empty(t, out.messages)
+
+	// A bent and pressed channel is centered and released once
+	s.schedule(out, eventPitchBend, 10000, 0)
+	s.schedule(out, eventChannelPressure, 64, 0)
+	s.CenterPitchBend(out)
+	s.CenterPitchBend(out)
+	s.ReleaseChannelPressure(out)
+	s.ReleaseChannelPressure(out)
+	s.Dispatch(time.Now())
+	assert.Equal(t, []string{"pb 10000", "at 64", "pb 8192", "at 0"}, out.messages)
+
+	// Stop centers the bend for the next notes too
+	out.messages = nil
+	s.schedule(out, eventPitchBend, 0, 0)
+	s.Stop()
+	s.CenterPitchBend(out)
+	s.Dispatch(time.Now())
+	assert.Equal(t, []string{"cc 64 0", "cc 123 0", "pb 8192", "at 0"}, out.messages)
+}
+
+func TestSchedulerPatch(t *testing.T) {
+	s := NewScheduler("Synth")
+	out := &recorder{}
+	s.SelectPatch(out, Patch{BankMSB: 1, BankLSB: -1, Program: 5, BendRange: 12})
+	s.Dispatch(time.Now())
+	assert.Equal(t, []string{"cc 0 1", "pc 5", "cc 101 0", "cc 100 0", "cc 6 12", "cc 38 0", "cc 101 127", "cc 100 127"}, out.messages)
+}
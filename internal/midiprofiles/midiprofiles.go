@@ -0,0 +1,161 @@
+// Package midiprofiles holds device profiles: the CCs a synth listens to, with names,
+// mapped onto the CC columns of instrument phrases.
+//
+// A profile is a JSON file in one of the profile folders, e.g. Minilogue.json:
+//
+//	{"name": "Minilogue", "ccs": [{"number": 43, "name": "Cutoff"}, {"number": 44, "name": "Resonance"}]}
+//
+// The CCs are in column order (A D S R RE CO PA LP HP); columns the profile leaves out
+// get their default CC.
+package midiprofiles
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Folder is the name of the profile folder in the config folder and in a project
+const Folder = "midiprofiles"
+
+// Profile maps the CC columns onto the controllers of a synth
+type Profile struct {
+	Name string         `json:"name"`
+	CCs  []types.MidiCC `json:"ccs"` // In column order, up to types.MidiCCColumns
+}
+
+// builtinProfiles are the profiles that ship with the tracker
+var builtinProfiles = []Profile{
+	{"General MIDI", []types.MidiCC{
+		{Number: 73, Name: "Attack"}, {Number: 75, Name: "Decay"}, {Number: 11, Name: "Expression"},
+		{Number: 72, Name: "Release"}, {Number: 91, Name: "Reverb"}, {Number: 93, Name: "Chorus"},
+		{Number: 10, Name: "Pan"}, {Number: 74, Name: "Cutoff"}, {Number: 71, Name: "Resonance"},
+	}},
+}
+
+// profiles holds the built-in and loaded profiles, in the order the MIDI view steps through them
+var profiles = slices.Clone(builtinProfiles)
+
+// UserDir returns the folder shared by all projects, e.g. ~/.config/collidertracker/midiprofiles
+func UserDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", Folder)
+}
+
+// ProjectDir returns the profile folder of a project
+func ProjectDir(saveFolder string) string {
+	return filepath.Join(saveFolder, Folder)
+}
+
+// LoadProfile reads a profile file. A profile without a name is named after its file.
+func LoadProfile(path string) (Profile, error) {
+	var profile Profile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(profile.CCs) > types.MidiCCColumns {
+		return profile, fmt.Errorf("%s: %d CCs, the phrase view has %d CC columns", filepath.Base(path), len(profile.CCs), types.MidiCCColumns)
+	}
+	for i, cc := range profile.CCs {
+		if cc.Number < 0 || cc.Number > 127 {
+			return profile, fmt.Errorf("%s: CC %d of column %d is not 0-127", filepath.Base(path), cc.Number, i+1)
+		}
+	}
+	return profile, nil
+}
+
+// LoadDir reads all profiles in a folder, sorted by file name. A missing folder has no
+// profiles; profiles that fail to load are returned as errors and skipped.
+func LoadDir(dir string) ([]Profile, []error) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(paths)
+	var loaded []Profile
+	var errs []error
+	for _, path := range paths {
+		profile, err := LoadProfile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded = append(loaded, profile)
+	}
+	return loaded, errs
+}
+
+// Register replaces the loaded profiles with the ones in the user folder and the
+// project's folder. A profile wins over one of the same name loaded before it, so project
+// profiles win over user ones and both over the built-in ones. It returns the number of
+// profiles loaded.
+func Register(saveFolder string) int {
+	profiles = slices.Clone(builtinProfiles)
+	count := 0
+	for _, dir := range []string{UserDir(), ProjectDir(saveFolder)} {
+		loaded, errs := LoadDir(dir)
+		for _, err := range errs {
+			log.Printf("Skipping MIDI profile: %v", err)
+		}
+		for _, profile := range loaded {
+			add(profile)
+			count++
+			log.Printf("Registered MIDI profile %s from %s", profile.Name, dir)
+		}
+	}
+	return count
+}
+
+// add registers a profile, replacing one of the same name
+func add(profile Profile) {
+	for i := range profiles {
+		if profiles[i].Name == profile.Name {
+			profiles[i] = profile
+			return
+		}
+	}
+	profiles = append(profiles, profile)
+}
+
+// Names returns the names of the profiles
+func Names() []string {
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// Get returns a profile by name
+func Get(name string) (Profile, bool) {
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Apply sets the CCs of MIDI settings to a profile
+func Apply(settings *types.MidiSettings, profile Profile) {
+	settings.CCs = types.DefaultMidiCCs()
+	copy(settings.CCs[:], profile.CCs)
+	settings.Profile = profile.Name
+}
@@ -0,0 +1,71 @@
+package midiprofiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func writeProfile(t *testing.T, dir, name, profile string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(profile), 0644))
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	writeProfile(t, dir, "Mono", `{"ccs": [{"number": 43, "name": "Cutoff"}, {"number": 44}]}`)
+	profile, err := LoadProfile(filepath.Join(dir, "Mono.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "Mono", profile.Name, "named after the file")
+	assert.Equal(t, []types.MidiCC{{Number: 43, Name: "Cutoff"}, {Number: 44}}, profile.CCs)
+
+	writeProfile(t, dir, "Bad", `{"ccs": [{"number": 128}]}`)
+	_, err = LoadProfile(filepath.Join(dir, "Bad.json"))
+	assert.Error(t, err)
+
+	writeProfile(t, dir, "Long", `{"ccs": [{}, {}, {}, {}, {}, {}, {}, {}, {}, {}]}`)
+	_, err = LoadProfile(filepath.Join(dir, "Long.json"))
+	assert.Error(t, err, "more CCs than columns")
+
+	profiles, errs := LoadDir(dir)
+	assert.Len(t, profiles, 1)
+	assert.Len(t, errs, 2)
+}
+
+func TestRegister(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	saveFolder := t.TempDir()
+	t.Cleanup(func() { Register(t.TempDir()) })
+
+	writeProfile(t, UserDir(), "Synth", `{"name": "Synth", "ccs": [{"number": 1}]}`)
+	writeProfile(t, ProjectDir(saveFolder), "Synth", `{"name": "Synth", "ccs": [{"number": 2}]}`)
+	writeProfile(t, ProjectDir(saveFolder), "Other", `{"ccs": [{"number": 3}]}`)
+
+	assert.Equal(t, 3, Register(saveFolder))
+	assert.Equal(t, []string{"General MIDI", "Synth", "Other"}, Names())
+
+	// The project profile wins over the user one
+	profile, ok := Get("Synth")
+	require.True(t, ok)
+	assert.Equal(t, 2, profile.CCs[0].Number)
+
+	// Columns the profile leaves out get their default CC
+	settings := types.NewMidiSettings()
+	settings.SetCCNumber(8, 100)
+	Apply(&settings, profile)
+	assert.Equal(t, "Synth", settings.Profile)
+	assert.Equal(t, types.MidiCC{Number: 2}, settings.CCs[0])
+	assert.Equal(t, types.MidiCC{Number: 8}, settings.CCs[8])
+
+	// Editing a CC leaves the profile
+	settings.SetCCNumber(0, 74)
+	assert.Equal(t, types.MidiCC{Number: 74, Name: "Cutoff"}, settings.CCs[0])
+	assert.Empty(t, settings.Profile)
+}
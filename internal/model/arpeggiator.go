@@ -0,0 +1,471 @@
+package model
+
+import (
+	"log"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// ArpeggioStep is one note of an arpeggio together with the step values of its row
+type ArpeggioStep struct {
+	Note     float32
+	Divisor  int  // The step lasts DeltaTime/Divisor
+	Velocity int  // 0 plays at the velocity of the row
+	Gate     int  // 0 keeps the gate and length of the row
+	Rest     bool // Keeps time without sounding
+	Ratchet  int  // Hits within the step; 0 and 1 hit once
+}
+
+// hits returns how many times the step is played
+func (s ArpeggioStep) hits() int {
+	return max(1, s.Ratchet)
+}
+
+// arpeggioVoice is the arpeggio playing on a track. Its steps are played by
+// AdvanceArpeggios as the playback scheduler reaches them.
+type arpeggioVoice struct {
+	params        InstrumentOSCParams
+	steps         []ArpeggioStep
+	step          int       // Step that plays next
+	hit           int       // Ratchet hit of that step
+	stepStart     time.Time // When that step starts
+	next          time.Time // When the next hit is due
+	arpeggioIndex int
+	latch         bool
+}
+
+// stepDuration returns how long a step lasts
+func (v *arpeggioVoice) stepDuration(step ArpeggioStep) time.Duration {
+	duration := time.Duration(float64(v.params.DeltaTime) / float64(max(1, step.Divisor)) * float64(time.Second))
+	return max(duration, time.Millisecond)
+}
+
+// schedule sets when the next hit is due
+func (v *arpeggioVoice) schedule() {
+	step := v.steps[v.step]
+	v.next = v.stepStart.Add(v.stepDuration(step) * time.Duration(v.hit) / time.Duration(step.hits()))
+}
+
+// stepParams returns the message for a hit of a step
+func (v *arpeggioVoice) stepParams(step ArpeggioStep) InstrumentOSCParams {
+	params := v.params
+	params.Notes = []float32{step.Note}
+	if step.Velocity > 0 {
+		params.Velocity = float32(step.Velocity)
+	}
+	if step.Gate > 0 || step.hits() > 1 {
+		// Gated and ratcheted steps last as long as their hits rather than the row
+		params.DeltaTime = float32(v.stepDuration(step).Seconds()) / float32(step.hits())
+		if step.Gate > 0 {
+			params.Gate = step.Gate
+		}
+	}
+	return params
+}
+
+// CancelArpeggioForTrack cancels any existing arpeggio on the given track and sends note-off for currently playing notes
+func (m *Model) CancelArpeggioForTrack(trackId int32) {
+	m.arpeggioMutex.Lock()
+	defer m.arpeggioMutex.Unlock()
+
+	if _, exists := m.arpeggioVoices[trackId]; exists {
+		log.Printf("DEBUG: CancelArpeggioForTrack - cancelling arpeggio on track %d", trackId)
+		delete(m.arpeggioVoices, trackId)
+	}
+
+	// Send note-off for any currently playing arpeggio notes
+	if currentNotes, exists := m.arpeggioCurrentNotes[trackId]; exists && len(currentNotes) > 0 {
+		log.Printf("DEBUG: CancelArpeggioForTrack - sending note-off for %d notes on track %d", len(currentNotes), trackId)
+
+		// Create note-off parameters based on the current notes
+		noteOffParams := InstrumentOSCParams{
+			TrackId: trackId,
+			NoteOn:  0, // 0 = note-off
+			Notes:   currentNotes,
+		}
+
+		// Send note-off message
+		m.sendOSCInstrumentMessage(noteOffParams)
+
+		// Clear the current notes
+		delete(m.arpeggioCurrentNotes, trackId)
+	}
+}
+
+// CancelAllArpeggios stops the arpeggios of every track, e.g. when playback stops
+func (m *Model) CancelAllArpeggios() {
+	m.arpeggioMutex.Lock()
+	tracks := make([]int32, 0, len(m.arpeggioVoices)+len(m.arpeggioCurrentNotes))
+	for trackId := range m.arpeggioVoices {
+		tracks = append(tracks, trackId)
+	}
+	for trackId := range m.arpeggioCurrentNotes {
+		tracks = append(tracks, trackId)
+	}
+	m.arpeggioMutex.Unlock()
+
+	for _, trackId := range tracks {
+		m.CancelArpeggioForTrack(trackId)
+	}
+}
+
+// SendOSCInstrumentMessageWithArpeggio is the high-level function that handles arpeggio logic
+func (m *Model) SendOSCInstrumentMessageWithArpeggio(params InstrumentOSCParams) {
+	log.Printf("DEBUG: SendOSCInstrumentMessageWithArpeggio called for track %d with notes %v, ArpeggioIndex=%d", params.TrackId, params.Notes, params.ArpeggioIndex)
+
+	steps := m.ArpeggioSteps(params)
+
+	// A latched arpeggio keeps its place and carries on with the notes of the new row
+	if len(steps) > 0 && m.relatchArpeggio(params, steps) {
+		log.Printf("DEBUG: Latched arpeggio %02X on track %d continues with notes %v", params.ArpeggioIndex, params.TrackId, params.Notes)
+		return
+	}
+
+	// Any other row cancels the arpeggio on this track (whether it has one or not)
+	m.CancelArpeggioForTrack(params.TrackId)
+
+	if len(steps) > 0 {
+		// Arpeggio is active - send only the root note initially
+		log.Printf("DEBUG: Arpeggio active - sending only root note for track %d: %v", params.TrackId, params.Notes[0:1])
+		rootOnlyParams := params
+		rootOnlyParams.Notes = []float32{params.Notes[0]} // Only send the root note
+		m.sendOSCInstrumentMessage(rootOnlyParams)
+
+		// Start the arpeggio
+		m.PlayArpeggio(params, steps, time.Now())
+	} else {
+		// No arpeggio - send the full chord/note as normal
+		log.Printf("DEBUG: No arpeggio - sending full chord/note for track %d: %v", params.TrackId, params.Notes)
+		m.sendOSCInstrumentMessage(params)
+	}
+}
+
+// relatchArpeggio hands the notes of a row to the latched arpeggio already playing on its
+// track. It returns false when the track has no latched arpeggio of the same index.
+func (m *Model) relatchArpeggio(params InstrumentOSCParams, steps []ArpeggioStep) bool {
+	m.arpeggioMutex.Lock()
+	defer m.arpeggioMutex.Unlock()
+
+	voice, exists := m.arpeggioVoices[params.TrackId]
+	if !exists || !voice.latch || voice.arpeggioIndex != params.ArpeggioIndex || !m.IsPlaying {
+		return false
+	}
+	voice.params = params
+	voice.steps = steps
+	voice.step %= len(steps)
+	voice.hit = min(voice.hit, steps[voice.step].hits()-1)
+	return true
+}
+
+// PlayArpeggio schedules the steps of an arpeggio on a track. The root note of the row was
+// sent at start and holds for the length of the first step; the steps follow one after the
+// other and are played by AdvanceArpeggios.
+func (m *Model) PlayArpeggio(params InstrumentOSCParams, steps []ArpeggioStep, start time.Time) {
+	if len(steps) == 0 || len(params.Notes) == 0 {
+		return
+	}
+	settings := m.ArpeggioSettings[params.ArpeggioIndex]
+	voice := &arpeggioVoice{
+		params:        params,
+		steps:         steps,
+		arpeggioIndex: params.ArpeggioIndex,
+		latch:         settings.Latch,
+	}
+	voice.stepStart = start.Add(voice.stepDuration(steps[0]))
+	voice.schedule()
+
+	m.arpeggioMutex.Lock()
+	m.arpeggioVoices[params.TrackId] = voice
+	// Initialize tracking with the root note (already sent)
+	m.arpeggioCurrentNotes[params.TrackId] = []float32{params.Notes[0]}
+	m.arpeggioMutex.Unlock()
+	log.Printf("DEBUG: PlayArpeggio - scheduled %d steps on track %d (latch %v)", len(steps), params.TrackId, voice.latch)
+}
+
+// NextArpeggioStep returns when the next arpeggio hit of any track is due
+func (m *Model) NextArpeggioStep() (time.Time, bool) {
+	m.arpeggioMutex.Lock()
+	defer m.arpeggioMutex.Unlock()
+
+	var next time.Time
+	for _, voice := range m.arpeggioVoices {
+		if next.IsZero() || voice.next.Before(next) {
+			next = voice.next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// AdvanceArpeggios plays every arpeggio hit that is due at the given time. A finished
+// arpeggio stops, unless it is latched and playback is running, in which case it starts
+// over without a gap.
+func (m *Model) AdvanceArpeggios(now time.Time) {
+	var messages []InstrumentOSCParams
+
+	m.arpeggioMutex.Lock()
+	for trackId, voice := range m.arpeggioVoices {
+		for !voice.next.After(now) {
+			step := voice.steps[voice.step]
+			if !step.Rest {
+				messages = append(messages, voice.stepParams(step))
+				m.arpeggioCurrentNotes[trackId] = []float32{step.Note}
+			}
+
+			voice.hit++
+			if voice.hit >= step.hits() {
+				voice.hit = 0
+				voice.stepStart = voice.stepStart.Add(voice.stepDuration(step))
+				voice.step++
+			}
+			if voice.step >= len(voice.steps) {
+				if !voice.latch || !m.IsPlaying {
+					delete(m.arpeggioVoices, trackId)
+					break
+				}
+				voice.step = 0
+			}
+			voice.schedule()
+		}
+	}
+	m.arpeggioMutex.Unlock()
+
+	for _, params := range messages {
+		m.sendOSCInstrumentMessage(params)
+	}
+}
+
+// ProcessArpeggio returns the notes of the arpeggio of a row and the divisor of each
+func (m *Model) ProcessArpeggio(params InstrumentOSCParams) (arpeggioNotes []float32, arpeggioDivisions []float32) {
+	for _, step := range m.ArpeggioSteps(params) {
+		arpeggioNotes = append(arpeggioNotes, step.Note)
+		arpeggioDivisions = append(arpeggioDivisions, float32(step.Divisor))
+	}
+	return arpeggioNotes, arpeggioDivisions
+}
+
+// ArpeggioSteps turns the arpeggio of a row into its steps. The notes are the chord as
+// played (transposed and voiced); its first note is the root, which the row plays itself.
+func (m *Model) ArpeggioSteps(params InstrumentOSCParams) []ArpeggioStep {
+	// Check if we have a valid arpeggio index
+	if params.ArpeggioIndex < 0 || params.ArpeggioIndex >= 255 || len(params.Notes) == 0 {
+		return nil
+	}
+
+	arpeggioSettings := m.ArpeggioSettings[params.ArpeggioIndex]
+	baseChord, span := types.ChordTones(params.Notes)
+	isChord := len(baseChord) > 1
+
+	// Start with the first played note as current position (the root, transposed and voiced)
+	currentNote := params.Notes[0]
+
+	var steps []ArpeggioStep
+	for _, row := range arpeggioSettings.Rows {
+		if row.Direction == 0 || row.Count < 0 || row.Divisor < 0 {
+			continue // Skip empty rows ("--" values)
+		}
+
+		direction := types.ArpeggioDirection(row.Direction)
+		pool := arpeggioPool(baseChord, span, row.Octaves)
+		if direction == types.ArpeggioDirectionAsPlayed {
+			pool = arpeggioPool(playedOrder(params.Notes), span, row.Octaves)
+		}
+		previous := slices.Index(pool, currentNote)
+
+		for i := 0; i < row.Count; i++ {
+			switch direction {
+			case types.ArpeggioDirectionUp, types.ArpeggioDirectionDown:
+				isUp := direction == types.ArpeggioDirectionUp
+				if row.Octaves > 0 {
+					// Wrap around within the octave range
+					currentNote = stepInPool(pool, currentNote, isUp)
+				} else if isChord {
+					// For chords: find current position in chord and move up/down through chord tones
+					currentNote = m.getNextChordNote(currentNote, baseChord, isUp)
+				} else if isUp {
+					// For single notes: move up/down by octaves (12 semitones)
+					currentNote += 12
+				} else {
+					currentNote -= 12
+				}
+			case types.ArpeggioDirectionRandom:
+				index := rand.Intn(len(pool))
+				if previous >= 0 {
+					// Any note but the one playing
+					index = rand.Intn(len(pool) - 1)
+					if index >= previous {
+						index++
+					}
+				}
+				previous = index
+				currentNote = pool[index]
+			default:
+				currentNote = pool[arpeggioPoolIndex(direction, i, len(pool))]
+			}
+
+			steps = append(steps, ArpeggioStep{
+				Note:     currentNote,
+				Divisor:  row.Divisor,
+				Velocity: row.Velocity,
+				Gate:     row.Gate,
+				Rest:     row.Rest == 1,
+				Ratchet:  row.Ratchet,
+			})
+		}
+	}
+	return steps
+}
+
+// arpeggioPool lists the notes a row walks: the chord tones repeated over the octave range,
+// starting from the lowest. A single note alternates with its octave.
+func arpeggioPool(tones []float32, span float32, octaves int) []float32 {
+	var pool []float32
+	for octave := range max(1, octaves) {
+		for _, tone := range tones {
+			pool = append(pool, tone+float32(octave)*span)
+		}
+	}
+	if len(pool) == 1 {
+		pool = append(pool, pool[0]+span)
+	}
+	return pool
+}
+
+// playedOrder returns the notes of a chord in the order they were voiced, without doubles
+func playedOrder(notes []float32) []float32 {
+	var order []float32
+	for _, note := range notes {
+		if !slices.Contains(order, note) {
+			order = append(order, note)
+		}
+	}
+	return order
+}
+
+// stepInPool moves from a note to the next note of the pool up or down, wrapping around at
+// either end
+func stepInPool(pool []float32, note float32, isUp bool) float32 {
+	current := 0
+	for i, poolNote := range pool {
+		if abs32(poolNote-note) < abs32(pool[current]-note) {
+			current = i
+		}
+	}
+	if isUp {
+		return pool[(current+1)%len(pool)]
+	}
+	return pool[(current-1+len(pool))%len(pool)]
+}
+
+func abs32(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// arpeggioPoolIndex returns which note of the pool the i-th note of a row plays. The root was
+// played by the row, so the patterns that start at the bottom continue from the next note.
+func arpeggioPoolIndex(direction types.ArpeggioDirection, i, n int) int {
+	switch direction {
+	case types.ArpeggioDirectionUpDown:
+		return bounce(i+1, n)
+	case types.ArpeggioDirectionDownUp:
+		return n - 1 - bounce(i, n)
+	case types.ArpeggioDirectionConverge:
+		// Bottom, top, second from the bottom, second from the top...
+		k := (i + 1) % n
+		if k%2 == 0 {
+			return k / 2
+		}
+		return n - 1 - k/2
+	default:
+		return (i + 1) % n
+	}
+}
+
+// bounce walks 0..n-1 and back without repeating the ends
+func bounce(k, n int) int {
+	if n < 2 {
+		return 0
+	}
+	period := 2*n - 2
+	k %= period
+	if k >= n {
+		return period - k
+	}
+	return k
+}
+
+// getNextChordNote finds the next note in the chord sequence when going up or down. The
+// sequence repeats every octave, or every few octaves for chords voiced wider than one.
+func (m *Model) getNextChordNote(currentNote float32, baseChord []float32, isUp bool) float32 {
+	baseChord, span := types.ChordTones(baseChord)
+	period := int(span)
+
+	// Find the current position in the chord - try exact match first
+	currentChordIndex := -1
+	octaveOffset := 0
+
+	for i, chordNote := range baseChord {
+		if chordNote == currentNote {
+			currentChordIndex = i
+			break
+		}
+	}
+
+	// If no exact match, find by note class (mod period) and calculate octave offset
+	if currentChordIndex == -1 {
+		baseNote := int(currentNote) % period
+		minDist := 1000
+		for i, chordNote := range baseChord {
+			if int(chordNote)%period == baseNote {
+				dist := int(currentNote - chordNote)
+				if dist < 0 {
+					dist = -dist
+				}
+				if dist < minDist {
+					minDist = dist
+					currentChordIndex = i
+					// Calculate how many periods above the base chord we are
+					octaveOffset = int(currentNote-chordNote) / period * period
+				}
+			}
+		}
+	}
+
+	// If still not found, find the closest note overall
+	if currentChordIndex == -1 {
+		minDist := float32(1000)
+		for i, chordNote := range baseChord {
+			dist := currentNote - chordNote
+			if dist < 0 {
+				dist = -dist
+			}
+			if dist < minDist {
+				minDist = dist
+				currentChordIndex = i
+				octaveOffset = int(currentNote-chordNote) / period * period
+			}
+		}
+	}
+
+	// Move to next chord tone - preserve octave relationships
+	if isUp {
+		nextIndex := currentChordIndex + 1
+		if nextIndex >= len(baseChord) {
+			// Wrap around to beginning and add a period
+			return baseChord[0] + float32(octaveOffset) + span
+		}
+		return baseChord[nextIndex] + float32(octaveOffset)
+	} else {
+		nextIndex := currentChordIndex - 1
+		if nextIndex < 0 {
+			// Wrap around to end and subtract a period
+			return baseChord[len(baseChord)-1] + float32(octaveOffset) - span
+		}
+		return baseChord[nextIndex] + float32(octaveOffset)
+	}
+}
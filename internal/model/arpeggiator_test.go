@@ -0,0 +1,182 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func arpeggioParams(arpeggioIndex int, notes ...float32) InstrumentOSCParams {
+	return InstrumentOSCParams{TrackId: 2, ArpeggioIndex: arpeggioIndex, Notes: notes, DeltaTime: 1, Gate: 128, Velocity: 64}
+}
+
+func TestArpeggioDirections(t *testing.T) {
+	m := NewModel(0, "", false)
+	cMajor := []float32{60, 64, 67}
+
+	tests := []struct {
+		name     string
+		row      types.ArpeggioRow
+		notes    []float32
+		expected []float32
+	}{
+		{"up-down", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionUpDown), Count: 6, Divisor: 1}, cMajor, []float32{64, 67, 64, 60, 64, 67}},
+		{"down-up", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionDownUp), Count: 6, Divisor: 1}, cMajor, []float32{67, 64, 60, 64, 67, 64}},
+		{"converge over two octaves", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionConverge), Count: 6, Divisor: 1, Octaves: 2}, cMajor, []float32{79, 64, 76, 67, 72, 60}},
+		{"as played", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionAsPlayed), Count: 4, Divisor: 1}, []float32{64, 60, 67}, []float32{60, 67, 64, 60}},
+		{"up wraps in its octave range", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionUp), Count: 4, Divisor: 1, Octaves: 1}, cMajor, []float32{64, 67, 60, 64}},
+		{"down starts from the top of the range", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionDown), Count: 3, Divisor: 1, Octaves: 2}, cMajor, []float32{79, 76, 72}},
+		{"single note alternates with its octave", types.ArpeggioRow{Direction: int(types.ArpeggioDirectionUpDown), Count: 3, Divisor: 1}, []float32{60}, []float32{72, 60, 72}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.ArpeggioSettings[30] = types.ArpeggioSettings{Rows: [16]types.ArpeggioRow{tt.row}}
+			notes, _ := m.ProcessArpeggio(arpeggioParams(30, tt.notes...))
+			assert.Equal(t, tt.expected, notes)
+		})
+	}
+
+	t.Run("random", func(t *testing.T) {
+		m.ArpeggioSettings[30] = types.ArpeggioSettings{Rows: [16]types.ArpeggioRow{
+			{Direction: int(types.ArpeggioDirectionRandom), Count: 32, Divisor: 1, Octaves: 2},
+		}}
+		notes, _ := m.ProcessArpeggio(arpeggioParams(30, cMajor...))
+		require.Len(t, notes, 32)
+		previous := float32(60)
+		for _, note := range notes {
+			assert.Contains(t, []float32{60, 64, 67, 72, 76, 79}, note)
+			assert.NotEqual(t, previous, note)
+			previous = note
+		}
+	})
+}
+
+func TestArpeggioStepValues(t *testing.T) {
+	m := NewModel(0, "", false)
+	m.ArpeggioSettings[31] = types.ArpeggioSettings{Rows: [16]types.ArpeggioRow{
+		{Direction: 1, Count: 1, Divisor: 2},
+		{Direction: 1, Count: 1, Divisor: 4, Velocity: 0x20, Gate: 0x40, Rest: 1, Ratchet: 3},
+	}}
+
+	steps := m.ArpeggioSteps(arpeggioParams(31, 60, 64, 67))
+	assert.Equal(t, []ArpeggioStep{
+		{Note: 64, Divisor: 2},
+		{Note: 67, Divisor: 4, Velocity: 0x20, Gate: 0x40, Rest: true, Ratchet: 3},
+	}, steps)
+
+	voice := &arpeggioVoice{params: arpeggioParams(31, 60, 64, 67), steps: steps}
+	params := voice.stepParams(steps[0])
+	assert.Equal(t, []float32{64}, params.Notes)
+	assert.Equal(t, float32(64), params.Velocity)
+	assert.Equal(t, float32(1), params.DeltaTime) // Steps without a gate keep the row length
+	assert.Equal(t, 128, params.Gate)
+
+	params = voice.stepParams(steps[1])
+	assert.Equal(t, float32(0x20), params.Velocity)
+	assert.InDelta(t, 0.25/3, params.DeltaTime, 1e-6) // One hit of a quarter row
+	assert.Equal(t, 0x40, params.Gate)
+}
+
+func TestArpeggioScheduling(t *testing.T) {
+	m := NewModel(0, "", false)
+	start := time.Unix(1000, 0)
+	at := func(seconds float64) time.Time {
+		return start.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	playing := func() []float32 {
+		return m.arpeggioCurrentNotes[2]
+	}
+
+	m.ArpeggioSettings[32] = types.ArpeggioSettings{Rows: [16]types.ArpeggioRow{
+		{Direction: 1, Count: 2, Divisor: 2},
+		{Direction: 1, Count: 1, Divisor: 2, Ratchet: 2},
+		{Direction: 1, Count: 1, Divisor: 2, Rest: 1},
+	}}
+	params := arpeggioParams(32, 60, 64, 67)
+	m.PlayArpeggio(params, m.ArpeggioSteps(params), start)
+
+	// The root holds for the first step, then each step follows on the clock
+	next, ok := m.NextArpeggioStep()
+	require.True(t, ok)
+	assert.Equal(t, at(0.5), next)
+	m.AdvanceArpeggios(at(0.4))
+	assert.Equal(t, []float32{60}, playing())
+	m.AdvanceArpeggios(at(0.5))
+	assert.Equal(t, []float32{64}, playing())
+	m.AdvanceArpeggios(at(1.0))
+	assert.Equal(t, []float32{67}, playing())
+
+	// A ratcheted step hits twice within its length
+	m.AdvanceArpeggios(at(1.5))
+	assert.Equal(t, []float32{72}, playing())
+	next, _ = m.NextArpeggioStep()
+	assert.Equal(t, at(1.75), next)
+	m.AdvanceArpeggios(at(1.75))
+
+	// A rest keeps time without playing, and the arpeggio ends after its last step
+	m.AdvanceArpeggios(at(2.0))
+	assert.Equal(t, []float32{72}, playing())
+	_, ok = m.NextArpeggioStep()
+	assert.False(t, ok)
+
+	t.Run("late clock catches up", func(t *testing.T) {
+		m.PlayArpeggio(params, m.ArpeggioSteps(params), start)
+		m.AdvanceArpeggios(at(1.2))
+		assert.Equal(t, []float32{67}, playing())
+		next, _ := m.NextArpeggioStep()
+		assert.Equal(t, at(1.5), next)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		m.PlayArpeggio(params, m.ArpeggioSteps(params), start)
+		m.CancelAllArpeggios()
+		_, ok := m.NextArpeggioStep()
+		assert.False(t, ok)
+		assert.Empty(t, playing())
+	})
+}
+
+func TestArpeggioLatch(t *testing.T) {
+	m := NewModel(0, "", false)
+	m.IsPlaying = true
+	m.ArpeggioSettings[33] = types.ArpeggioSettings{
+		Rows:  [16]types.ArpeggioRow{{Direction: 1, Count: 2, Divisor: 1}},
+		Latch: true,
+	}
+	start := time.Now()
+	params := arpeggioParams(33, 60, 64, 67)
+	m.PlayArpeggio(params, m.ArpeggioSteps(params), start)
+
+	// The pattern starts over after its last step
+	m.AdvanceArpeggios(start.Add(2 * time.Second))
+	assert.Equal(t, []float32{67}, m.arpeggioCurrentNotes[2])
+	next, ok := m.NextArpeggioStep()
+	require.True(t, ok)
+	assert.Equal(t, start.Add(3*time.Second), next)
+	m.AdvanceArpeggios(next)
+	assert.Equal(t, []float32{64}, m.arpeggioCurrentNotes[2])
+
+	// A row with the same arpeggio hands over its notes without restarting the pattern
+	m.SendOSCInstrumentMessageWithArpeggio(arpeggioParams(33, 62, 65, 69))
+	next, _ = m.NextArpeggioStep()
+	assert.Equal(t, start.Add(4*time.Second), next)
+	m.AdvanceArpeggios(next)
+	assert.Equal(t, []float32{69}, m.arpeggioCurrentNotes[2])
+
+	// A row without it ends the arpeggio
+	m.SendOSCInstrumentMessageWithArpeggio(arpeggioParams(-1, 60))
+	_, ok = m.NextArpeggioStep()
+	assert.False(t, ok)
+
+	// Stopped, a latched arpeggio plays once
+	m.IsPlaying = false
+	m.PlayArpeggio(params, m.ArpeggioSteps(params), start)
+	m.AdvanceArpeggios(start.Add(2 * time.Second))
+	_, ok = m.NextArpeggioStep()
+	assert.False(t, ok)
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// AutomationRow is a row a track started playing, placed within its phrase and chain
+type AutomationRow struct {
+	Phrase       types.AutomationOwner
+	PhraseTick   int                   // Ticks of the phrase before the row
+	Chain        types.AutomationOwner // ID is -1 when the phrase plays on its own
+	ChainTick    int                   // Ticks of the chain before the row
+	Ticks        int                   // Length of the row
+	TickDuration time.Duration
+	Start        time.Time
+}
+
+// AutomationLaneIndices returns the indices in AutomationLanes of the lanes of a phrase or chain
+func (m *Model) AutomationLaneIndices(owner types.AutomationOwner) []int {
+	var indices []int
+	for i, lane := range m.AutomationLanes {
+		if lane.Owner == owner {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// StartAutomationRow moves the automation of a track to a row that starts playing and
+// sends the values at its start
+func (m *Model) StartAutomationRow(track int, row AutomationRow) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	m.automationRows[track] = &row
+	m.sendAutomationValues(track, m.automationValues(row, row.Start))
+}
+
+// StopAutomationForTrack hands the parameters of a track back to its rows
+func (m *Model) StopAutomationForTrack(track int) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	m.automationRows[track] = nil
+	m.sendAutomationValues(track, nil)
+}
+
+// StopAutomation hands the parameters of every track back to their rows, e.g. when playback stops
+func (m *Model) StopAutomation() {
+	for track := range m.automationRows {
+		m.StopAutomationForTrack(track)
+	}
+}
+
+// AutomationPlaying reports whether a playing track can have automation to send
+func (m *Model) AutomationPlaying() bool {
+	if !m.IsPlaying || len(m.AutomationLanes) == 0 {
+		return false
+	}
+	for _, row := range m.automationRows {
+		if row != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AutomatedValue returns the value last sent for a target of a track
+func (m *Model) AutomatedValue(track int, id string) (float32, bool) {
+	if track < 0 || track >= 8 {
+		return 0, false
+	}
+	value, ok := m.automationSent[track][id]
+	return value, ok
+}
+
+// AutomatedColumn returns the value a lane gives a phrase column of the row a track
+// started playing
+func (m *Model) AutomatedColumn(track int, col types.PhraseColumn) (int, bool) {
+	for _, target := range types.AutomationColumnTargets {
+		if automated, _ := types.AutomationColumn(target.ID()); automated != col {
+			continue
+		}
+		if value, ok := m.AutomatedValue(track, target.ID()); ok {
+			return int(math.Round(float64(value))), true
+		}
+	}
+	return 0, false
+}
+
+// AdvanceAutomation sends the values the lanes of the playing tracks have moved to
+func (m *Model) AdvanceAutomation(now time.Time) {
+	for track, row := range m.automationRows {
+		if row != nil {
+			m.sendAutomationValues(track, m.automationValues(*row, now))
+		}
+	}
+}
+
+// automationValues returns the values of the lanes of the phrase and chain of a row, by
+// target ID. Lanes of the phrase win over lanes of the chain moving the same target.
+func (m *Model) automationValues(row AutomationRow, now time.Time) map[string]float32 {
+	elapsed := 0.0
+	if row.TickDuration > 0 {
+		elapsed = float64(now.Sub(row.Start)) / float64(row.TickDuration)
+	}
+	elapsed = max(0, min(elapsed, float64(row.Ticks)))
+
+	values := make(map[string]float32)
+	for _, scope := range []struct {
+		owner types.AutomationOwner
+		tick  int
+	}{{row.Chain, row.ChainTick}, {row.Phrase, row.PhraseTick}} {
+		for _, lane := range m.AutomationLanes {
+			if lane.Owner != scope.owner || len(lane.Points) == 0 {
+				continue
+			}
+			target, ok := types.FindAutomationTarget(lane.Target)
+			if !ok {
+				continue
+			}
+			values[lane.Target] = target.ValueFromHex(lane.ValueAt(float64(scope.tick) + elapsed))
+		}
+	}
+	return values
+}
+
+// sendAutomationValues sends the values of a track that changed, and releases the targets
+// that are no longer automated. Phrase columns are only kept for the rows to read.
+func (m *Model) sendAutomationValues(track int, values map[string]float32) {
+	if m.automationSent[track] == nil {
+		m.automationSent[track] = make(map[string]float32)
+	}
+	sent := m.automationSent[track]
+	for id, value := range values {
+		if last, ok := sent[id]; ok && last == value {
+			continue
+		}
+		if _, column := types.AutomationColumn(id); !column {
+			m.sendOSCAutomationMessage(track, id, value, true)
+		}
+		sent[id] = value
+	}
+	for id := range sent {
+		if _, ok := values[id]; !ok {
+			if _, column := types.AutomationColumn(id); !column {
+				m.sendOSCAutomationMessage(track, id, 0, false)
+			}
+			delete(sent, id)
+			log.Printf("Automation of %s on track %d released", id, track)
+		}
+	}
+}
+
+// sendOSCAutomationMessage sets an automated parameter of the voices of a track, or
+// releases it back to the rows
+func (m *Model) sendOSCAutomationMessage(track int, id string, value float32, active bool) {
+	target, ok := types.FindAutomationTarget(id)
+	if !ok {
+		target = types.LFOTarget{Key: id}
+	}
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/automation",
+		Parameters: []interface{}{int32(track), target.Key, target.SoundMaker, value, boolToInt32(active)},
+	})
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestAutomationValues(t *testing.T) {
+	m := NewModel(0, "", false)
+	phrase := types.AutomationOwner{Scope: types.AutomationScopePhrase, Sampler: true, ID: 3}
+	chain := types.AutomationOwner{Scope: types.AutomationScopeChain, Sampler: true, ID: 1}
+	m.AutomationLanes = []types.AutomationLane{
+		{Owner: chain, Target: "effectReverb", Points: []types.AutomationPoint{{Tick: 0, Value: 0}, {Tick: 32, Value: 254}}},
+		{Owner: chain, Target: "pan", Points: []types.AutomationPoint{{Tick: 0, Value: 0}}},
+		{Owner: phrase, Target: "pan", Points: []types.AutomationPoint{{Tick: 0, Value: 254}}},
+		{Owner: types.AutomationOwner{ID: 3}, Target: "pitch", Points: []types.AutomationPoint{{Tick: 0, Value: 254}}},
+	}
+	assert.Equal(t, []int{0, 1}, m.AutomationLaneIndices(chain))
+
+	// Half way through a row that starts 8 ticks into the chain
+	start := time.Now()
+	row := AutomationRow{Phrase: phrase, Chain: chain, ChainTick: 8, Ticks: 4, TickDuration: time.Second, Start: start}
+	values := m.automationValues(row, start.Add(2*time.Second))
+	assert.Equal(t, map[string]float32{"effectReverb": 10.0 / 32, "pan": 1}, values)
+
+	// Time past the end of the row holds its last tick
+	values = m.automationValues(row, start.Add(time.Minute))
+	assert.Equal(t, float32(0.375), values["effectReverb"])
+
+	// Targets a track stops automating are released
+	m.sendAutomationValues(2, values)
+	assert.Len(t, m.automationSent[2], 2)
+	m.IsPlaying = true
+	m.StartAutomationRow(2, AutomationRow{Phrase: phrase, Chain: types.AutomationOwner{ID: -1}, Ticks: 1, Start: start})
+	assert.Equal(t, map[string]float32{"pan": 1}, m.automationSent[2])
+	assert.True(t, m.AutomationPlaying())
+	m.StopAutomation()
+	assert.Empty(t, m.automationSent[2])
+
+	// Phrase columns are kept for the rows to read
+	m.AutomationLanes = append(m.AutomationLanes, types.AutomationLane{Owner: phrase, Target: "columnVelocity", Points: []types.AutomationPoint{{Tick: 0, Value: 0x80}}})
+	m.StartAutomationRow(2, AutomationRow{Phrase: phrase, Chain: types.AutomationOwner{ID: -1}, Ticks: 1, Start: start})
+	velocity, ok := m.AutomatedColumn(2, types.ColVelocity)
+	assert.True(t, ok)
+	assert.Equal(t, 64, velocity)
+	_, ok = m.AutomatedColumn(2, types.ColGate)
+	assert.False(t, ok)
+	m.StopAutomation()
+	_, ok = m.AutomatedColumn(2, types.ColVelocity)
+	assert.False(t, ok)
+	assert.False(t, m.AutomationPlaying())
+}
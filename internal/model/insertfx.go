@@ -0,0 +1,93 @@
+package model
+
+import (
+	"log"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// insertFXChainOSCParameters returns the arguments of the /insertfx_chain message of a
+// track: the track, then the SynthDef of each slot in order
+func (m *Model) insertFXChainOSCParameters(track int) []interface{} {
+	parameters := []interface{}{int32(track)}
+	for _, slot := range m.InsertFX[track].Slots {
+		if module, ok := types.GetInsertFXModule(slot.Module); ok {
+			parameters = append(parameters, module.SynthDef)
+		}
+	}
+	return parameters
+}
+
+// SendOSCInsertFXChain builds the insert chain of a track in SuperCollider and sets the
+// parameters of its slots. An unchanged chain keeps running, so delays keep their tails.
+func (m *Model) SendOSCInsertFXChain(track int) {
+	if track < 0 || track >= len(m.InsertFX) {
+		return
+	}
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/insertfx_chain",
+		Parameters: m.insertFXChainOSCParameters(track),
+		LogFormat:  "OSC insert FX chain sent: track %d, %d slots",
+		LogArgs:    []interface{}{track, len(m.InsertFX[track].Slots)},
+	})
+	for slot := range m.InsertFX[track].Slots {
+		m.SendOSCInsertFXSlot(track, slot)
+	}
+}
+
+// SendOSCInsertFXSlot sets every parameter of a slot of the insert chain of a track
+func (m *Model) SendOSCInsertFXSlot(track, slot int) {
+	if track < 0 || track >= len(m.InsertFX) || slot < 0 || slot >= len(m.InsertFX[track].Slots) {
+		return
+	}
+	settings := m.InsertFX[track].Slots[slot]
+	module, ok := types.GetInsertFXModule(settings.Module)
+	if !ok {
+		return
+	}
+	parameters := []interface{}{int32(track), int32(slot)}
+	for _, param := range module.Parameters {
+		parameters = append(parameters, param.Key, types.InsertFXOSCValue(module, param, settings.Value(param), m.BPM))
+	}
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/insertfx_set",
+		Parameters: parameters,
+		LogFormat:  "OSC insert FX sent: track %d slot %d %s %v",
+		LogArgs:    []interface{}{track, slot, settings.Module, settings.Parameters},
+	})
+}
+
+// SendOSCInsertFXColumn sets a parameter of the insert chain of a track from the FX and FV
+// columns of a row. The saved value of the parameter is left alone, so stopping playback
+// can restore it.
+func (m *Model) SendOSCInsertFXColumn(track, fx, fv int) {
+	if track < 0 || track >= len(m.InsertFX) || fx < 0 || fv < 0 {
+		return
+	}
+	slot, paramIndex := types.DecodeInsertFXColumn(fx)
+	if slot >= len(m.InsertFX[track].Slots) {
+		log.Printf("Insert FX %02X: track %d has no slot %d", fx, track+1, slot)
+		return
+	}
+	module, ok := types.GetInsertFXModule(m.InsertFX[track].Slots[slot].Module)
+	if !ok || paramIndex >= len(module.Parameters) {
+		log.Printf("Insert FX %02X: slot %d of track %d has no parameter %d", fx, slot, track+1, paramIndex)
+		return
+	}
+	param := module.Parameters[paramIndex]
+	value := types.InsertFXOSCValue(module, param, types.InsertFXHexToValue(param, fv), m.BPM)
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/insertfx_set",
+		Parameters: []interface{}{int32(track), int32(slot), param.Key, value},
+		LogFormat:  "OSC insert FX sent: track %d slot %d %s %.3f",
+		LogArgs:    []interface{}{track, slot, param.Key, value},
+	})
+}
+
+// SendOSCInsertFXMessages sends the insert chain of every track, e.g. on startup, when the
+// tempo changes or when playback stops
+func (m *Model) SendOSCInsertFXMessages() {
+	for track := range m.InsertFX {
+		m.SendOSCInsertFXChain(track)
+	}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// lfoOSCParameters returns the arguments of the /lfo message of an LFO:
+// index, active, track, key, SoundMaker, waveform, rate in Hz, depth, phase, retrigger,
+// min, max, span, exponential, center
+func (m *Model) lfoOSCParameters(index int) []interface{} {
+	settings := m.LFOSettings[index]
+	target, active := types.FindLFOTarget(settings.Target)
+	return []interface{}{
+		int32(index),
+		boolToInt32(active),
+		int32(settings.Track),
+		target.Key,
+		target.SoundMaker,
+		int32(settings.Waveform),
+		settings.RateHz(m.BPM),
+		float32(settings.Depth) / 100,
+		float32(settings.Phase) / 360,
+		boolToInt32(settings.Retrigger),
+		target.Min,
+		target.Max,
+		target.Span,
+		boolToInt32(target.Exponential),
+		target.Center,
+	}
+}
+
+// SendOSCLFOMessage starts, updates or stops an LFO in SuperCollider
+func (m *Model) SendOSCLFOMessage(index int) {
+	if index < 0 || index >= types.LFOCount {
+		return
+	}
+	settings := m.LFOSettings[index]
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/lfo",
+		Parameters: m.lfoOSCParameters(index),
+		LogFormat:  "OSC LFO message sent: /lfo %d track %d %q %s",
+		LogArgs:    []interface{}{index, settings.Track, settings.Target, settings.RateName()},
+	})
+}
+
+// SendOSCLFOMessages sends every LFO, e.g. on startup or when the tempo changes
+func (m *Model) SendOSCLFOMessages() {
+	for index := range m.LFOSettings {
+		m.SendOSCLFOMessage(index)
+	}
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFOOSCParameters(t *testing.T) {
+	m := NewModel(0, "", false)
+	m.BPM = 120
+
+	// LFOs start off
+	params := m.lfoOSCParameters(3)
+	assert.Equal(t, int32(3), params[0])
+	assert.Equal(t, int32(0), params[1])
+
+	m.LFOSettings[3].Target = "highPassFilter"
+	m.LFOSettings[3].Track = 5
+	m.LFOSettings[3].Sync = 5 // 1/4
+	m.LFOSettings[3].Depth = 40
+	m.LFOSettings[3].Phase = 90
+	m.LFOSettings[3].Retrigger = true
+	assert.Equal(t, []interface{}{
+		int32(3), int32(1), int32(5), "highPassFilter", "",
+		int32(0), float32(2), float32(0.4), float32(0.25), int32(1),
+		float32(20), float32(20000), float32(5), int32(1), float32(20),
+	}, m.lfoOSCParameters(3))
+}
@@ -1,7 +1,6 @@
 package model
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"math/rand"
@@ -13,7 +12,11 @@ import (
 
 	"github.com/hypebeast/go-osc/osc"
 
+	"github.com/schollz/collidertracker/internal/library"
 	"github.com/schollz/collidertracker/internal/midiplayer"
+	"github.com/schollz/collidertracker/internal/soundmakers"
+	"github.com/schollz/collidertracker/internal/supercollider"
+	"github.com/schollz/collidertracker/internal/tuning"
 	"github.com/schollz/collidertracker/internal/types"
 )
 
@@ -45,6 +48,7 @@ type Model struct {
 	CurrentTrack          int                 // Which track context we're viewing (0-7)
 	FileSelectRow         int                 // Which phrase row we're selecting a file for
 	FileSelectCol         int                 // Which phrase column we were on when navigating to file browser
+	FileSelectView        types.ViewMode      // View the file browser picks a file for (PhraseView, MultisampleView or ProjectFilesView)
 	Clipboard             types.ClipboardData // Cell clipboard
 	CurrentDir            string              // Current directory for file browser
 	Files                 []string            // Files in current directory
@@ -85,25 +89,45 @@ type Model struct {
 	// File metadata management
 	FileMetadata        map[string]types.FileMetadata // Map of filepath -> metadata
 	MetadataEditingFile string                        // Currently editing metadata for this file
+	// Slice editor state (peaks are decoded when the editor opens and not persisted)
+	SliceEditorPeaks []float64 // Peak envelope of MetadataEditingFile (0-1 per bucket)
+	SliceEditorZoom  int       // Zoom factor (1 = whole file)
 	// Retrigger settings management
 	RetriggerSettings     [255]types.RetriggerSettings // Array of retrigger settings (00-FE)
 	RetriggerEditingIndex int                          // Currently editing retrigger index
 	// Timestretch settings management
 	TimestrechSettings     [255]types.TimestrechSettings // Array of timestretch settings (00-FE)
 	TimestrechEditingIndex int                           // Currently editing timestretch index
+	// Granular settings management
+	GranularSettings     [255]types.GranularSettings // Array of granular settings (00-FE)
+	GranularEditingIndex int                         // Currently editing granular index
 	// Modulate settings management (separate pools for instrument and sampler tracks)
 	InstrumentModulateSettings [255]types.ModulateSettings // Array of modulate settings for instrument tracks (00-FE)
 	SamplerModulateSettings    [255]types.ModulateSettings // Array of modulate settings for sampler tracks (00-FE)
 	ModulateEditingIndex       int                         // Currently editing modulate index
 	// Arpeggio settings management
-	ArpeggioSettings       [255]types.ArpeggioSettings   // Array of arpeggio settings (00-FE)
-	ArpeggioEditingIndex   int                           // Currently editing arpeggio index
-	MidiSettings           [255]types.MidiSettings       // Array of MIDI settings (00-FE)
-	MidiEditingIndex       int                           // Currently editing MIDI index
-	SoundMakerSettings     [255]types.SoundMakerSettings // Array of SoundMaker settings (00-FE)
-	SoundMakerEditingIndex int                           // Currently editing SoundMaker index
-	DuckingSettings        [255]types.DuckingSettings    // Array of ducking settings (00-FE)
-	DuckingEditingIndex    int                           // Currently editing ducking index
+	ArpeggioSettings       [255]types.ArpeggioSettings       // Array of arpeggio settings (00-FE)
+	ArpeggioEditingIndex   int                               // Currently editing arpeggio index
+	MidiSettings           [255]types.MidiSettings           // Array of MIDI settings (00-FE)
+	MidiEditingIndex       int                               // Currently editing MIDI index
+	SoundMakerSettings     [255]types.SoundMakerSettings     // Array of SoundMaker settings (00-FE)
+	SoundMakerEditingIndex int                               // Currently editing SoundMaker index
+	DuckingSettings        [255]types.DuckingSettings        // Array of ducking settings (00-FE)
+	DuckingEditingIndex    int                               // Currently editing ducking index
+	LFOSettings            [types.LFOCount]types.LFOSettings // Pool of LFOs (0-F)
+	// Automation lanes of phrases and chains
+	AutomationLanes   []types.AutomationLane // Breakpoint envelopes, found by their owner
+	AutomationEditing types.AutomationOwner  // Phrase or chain whose lanes are being edited
+	AutomationLane    int                    // Lane of AutomationEditing being edited (0-3)
+	// Parameter locks of instrument rows
+	ParameterLocks   types.ParameterLocks // SoundMaker parameters locked on instrument phrase rows
+	ParameterLockRow int                  // Row of the current phrase whose locks are being edited
+	// Insert effects of the tracks
+	InsertFX             [8]types.InsertFXChain // Insert chain of each track
+	InsertFXEditingTrack int                    // Track whose insert chain is being edited
+	// Send/return buses of the mixer
+	Returns    [types.ReturnCount]types.ReturnSettings // Parameters of the return buses
+	TrackSends [8][types.ReturnCount]float32           // Send of each track to each return bus (0-100%)
 	// View navigation state
 	LastChainRow  int // Last selected row in chain view
 	LastPhraseRow int // Last selected row in phrase view
@@ -111,8 +135,15 @@ type Model struct {
 	LastSongRow   int // Last selected row in song view
 	LastSongTrack int // Last selected track in song view
 	// Column mode state - for toggleable columns
-	SOColumnMode  types.SOColumnMode // Current mode for SO/MI column (SO or MI mode)
-	MidiCCNumbers [9]int             // MIDI CC numbers for the 9 CC columns (default 0-8, range 0-127)
+	SOColumnMode types.SOColumnMode // Current mode for SO/MI column (SO or MI mode)
+	// Microtonal tuning (tunings are named after their Scala files)
+	Tuning           string                    // Tuning of the project ("" = 12-TET)
+	TrackTunings     [8]string                 // Tuning of each track ("" = the project tuning)
+	ShowScaleDegrees bool                      // Show notes as degrees of the tuning in the phrase view
+	Tunings          map[string]*tuning.Tuning // Tunings found in the tuning folders
+	TuningDir        string                    // Where tunings shared by all projects are kept
+	ProjectKey       int                       // Key of the project: 0-11 (C, C#, D, ... B)
+	ProjectScale     string                    // Scale of the project ("" = all notes)
 
 	// Song data structure (8 tracks × 16 rows)
 	SongData [8][16]int // [track][row] = chain ID (00-FE, -1 for empty)
@@ -129,12 +160,47 @@ type Model struct {
 	EffectStepCounter [8][255][255]int // [track][phrase][row] = step count for retrigger and timestretch Every logic
 	// Increment counter tracking - tracks increment counter values per track/phrase/row
 	IncrementCounters [8][255][255]int // [track][phrase][row] = increment counter (-1 means uninitialized/unused)
+	// Voice leading - the notes of the last chord played on each track
+	LastChordNotes [8][]int
 	// Save folder configuration
 	SaveFolder string // Path to the save folder
 	// Recording state
 	RecordingEnabled     bool   // Whether recording is queued/enabled
 	RecordingActive      bool   // Whether recording is currently active
 	CurrentRecordingFile string // Current recording filename
+	// Sample library state (the index is loaded when the library view is first opened)
+	Library           *library.Index // Persistent sample library index
+	LibraryPath       string         // Where the index is stored
+	LibraryQuery      string         // Search-as-you-type query
+	LibraryResults    []int          // Indices into Library.Entries matching the query, best first
+	LibraryScanning   bool           // Whether a rescan is running
+	LibraryTagEditing bool           // Whether typing edits the tags of the selected entry
+	LibraryTagInput   string         // Tags being edited, space separated
+	// Project file consolidation
+	ProjectFileIssues        []types.ProjectFileIssue // Issues listed in the project files view
+	ProjectFileDeleteConfirm string                   // Untracked file deleted by a second Backspace
+	// Offline sample tools
+	SampleToolsFile     string                   // File the sample tools edit
+	SampleToolsPeaks    []float64                // Peak envelope of SampleToolsFile (0-1 per bucket)
+	SampleToolsSettings types.SampleToolSettings // Tool parameters
+	SampleToolsMessage  string                   // Result of the last tool applied
+	// SoundMaker preset library
+	PresetDir        string               // Where presets are stored
+	PresetSoundMaker string               // SoundMaker whose presets are listed
+	Presets          []soundmakers.Preset // Presets of PresetSoundMaker, sorted by name
+	PresetNaming     bool                 // Whether typing edits the name to save the slot under
+	PresetNameInput  string               // Name being typed
+	PresetMessage    string               // Result of the last save or load
+	// DX7 SysEx bank import
+	DX7BankDir     string // Where imported banks are kept
+	DX7BankMessage string // Result of the last import
+	// Bounce state (a bounce records one pass of a phrase or chain into the save folder)
+	BounceActive   bool   // Whether a bounce is playing its pass
+	BounceWriting  bool   // Whether the pass is over and the recording rings out or is being written
+	BounceFile     string // File the bounce is written to
+	BounceRowsLeft int    // Rows left to play before the pass ends
+	BounceRowTicks []int  // Tick each row of the pass starts on (one slice per row)
+	BounceTicks    int    // Ticks of the pass
 	// Project selection state
 	ReturnToProjectSelector bool // Flag to indicate we should return to project selection
 	// Mixer state
@@ -145,10 +211,19 @@ type Model struct {
 	CurrentMixerRow   int        // Current row in mixer: 0 = level (track type now in Song view)
 	// MIDI functionality
 	AvailableMidiDevices []string
-	// Arpeggio cancellation tracking
-	arpeggioContexts     map[int32]context.CancelFunc // Per-track cancellation functions
-	arpeggioCurrentNotes map[int32][]float32          // Currently playing arpeggio notes for each track
-	arpeggioMutex        sync.Mutex                   // Mutex for safe access to arpeggio tracking
+	MidiLatencies        map[string]int // Latency of each MIDI device in ms, to line it up with the audio
+	// Arpeggio scheduling
+	arpeggioVoices       map[int32]*arpeggioVoice // Arpeggio playing on each track
+	arpeggioCurrentNotes map[int32][]float32      // Currently playing arpeggio notes for each track
+	arpeggioMutex        sync.Mutex               // Mutex for safe access to arpeggio tracking
+	ArpeggioTickAt       time.Time                // When the scheduled arpeggio tick fires (zero if none)
+	// Automation playback
+	automationRows   [8]*AutomationRow     // Row each track is playing, nil when its automation is off
+	automationSent   [8]map[string]float32 // Values last sent for each track, by target ID
+	AutomationTickAt time.Time             // When the scheduled automation tick fires (zero if none)
+	// Round-robin position per Multisample SoundMaker and group
+	multisampleRoundRobin map[int]int // [soundMakerIndex*256+group] = next zone to play
+	multisampleMutex      sync.Mutex  // Mutex for round-robin state
 	// Per-track random number generators for modulation
 	ModulateRngs [8]*rand.Rand // Per-track RNG for modulation (one per track)
 	// Vim mode configuration
@@ -177,6 +252,18 @@ func (m *Model) AppendPhrasesFile(filename string) int {
 	return len(m.SamplerPhrasesFiles) - 1
 }
 
+// AppendSamplerFile adds a file to the sampler file list regardless of the current track type
+// and returns its index. A file that is already in the list keeps its index.
+func (m *Model) AppendSamplerFile(filename string) int {
+	for i, existing := range m.SamplerPhrasesFiles {
+		if existing == filename {
+			return i
+		}
+	}
+	m.SamplerPhrasesFiles = append(m.SamplerPhrasesFiles, filename)
+	return len(m.SamplerPhrasesFiles) - 1
+}
+
 // GetCurrentModulateSettings returns the appropriate modulate settings based on current track type
 func (m *Model) GetCurrentModulateSettings() *[255]types.ModulateSettings {
 	if m.GetPhraseViewType() == types.InstrumentPhraseView {
@@ -311,6 +398,15 @@ func (m *Model) GetColumnMapping(uiColumn int) *ColumnMapping {
 				IsDeletable:     true,
 				DisplayName:     "T",
 			}
+		case int(types.InstrumentColV): // V - chord voicing column
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColChordVoicing),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "V",
+			}
 		case int(types.InstrumentColGT): // GT - gate column
 			return &ColumnMapping{
 				DataColumnIndex: int(types.ColGate),
@@ -530,6 +626,48 @@ func (m *Model) GetColumnMapping(uiColumn int) *ColumnMapping {
 				IsDeletable:     true,
 				DisplayName:     "DU",
 			}
+		case int(types.InstrumentColFX): // FX - Insert FX slot and parameter
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColInsertFX),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "FX",
+			}
+		case int(types.InstrumentColFV): // FV - Insert FX value
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColInsertFXValue),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "FV",
+			}
+		case int(types.InstrumentColPB): // PB - Pitch bend column (MI mode only)
+			if m.SOColumnMode != types.SOModeMIDI {
+				return nil
+			}
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColPitchBend),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "PB",
+			}
+		case int(types.InstrumentColAT): // AT - Channel aftertouch column (MI mode only)
+			if m.SOColumnMode != types.SOModeMIDI {
+				return nil
+			}
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColAftertouch),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "AT",
+			}
 		default:
 			return nil // Invalid column
 		}
@@ -591,6 +729,24 @@ func (m *Model) GetColumnMapping(uiColumn int) *ColumnMapping {
 				IsDeletable:     true,
 				DisplayName:     "GT",
 			}
+		case int(types.SamplerColST): // ST - Start offset
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColSampleStart),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "ST",
+			}
+		case int(types.SamplerColLN): // LN - Length
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColSampleLength),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "LN",
+			}
 		case int(types.SamplerColRT): // RT - Retrigger
 			return &ColumnMapping{
 				DataColumnIndex: int(types.ColRetrigger), // Now index 4
@@ -609,6 +765,15 @@ func (m *Model) GetColumnMapping(uiColumn int) *ColumnMapping {
 				IsDeletable:     true,
 				DisplayName:     "TS",
 			}
+		case int(types.SamplerColGR): // GR - Granular
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColGranular),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "GR",
+			}
 		case int(types.SamplerColMO): // MO - Modulate
 			return &ColumnMapping{
 				DataColumnIndex: int(types.ColModulate), // Now index 6
@@ -681,6 +846,24 @@ func (m *Model) GetColumnMapping(uiColumn int) *ColumnMapping {
 				IsDeletable:     true,
 				DisplayName:     "DU",
 			}
+		case int(types.SamplerColFX): // FX - Insert FX slot and parameter
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColInsertFX),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "FX",
+			}
+		case int(types.SamplerColFV): // FV - Insert FX value
+			return &ColumnMapping{
+				DataColumnIndex: int(types.ColInsertFXValue),
+				IsEditable:      true,
+				IsCopyable:      true,
+				IsPasteable:     true,
+				IsDeletable:     true,
+				DisplayName:     "FV",
+			}
 		case int(types.SamplerColFI): // FI - Filename
 			return &ColumnMapping{
 				DataColumnIndex: int(types.ColFilename), // Now index 14
@@ -726,13 +909,24 @@ func NewModel(oscPort int, saveFolder string, vimMode bool) *Model {
 		// Initialize file metadata
 		FileMetadata:        make(map[string]types.FileMetadata),
 		MetadataEditingFile: "",
-		// Initialize arpeggio contexts
-		arpeggioContexts:     make(map[int32]context.CancelFunc),
+		SliceEditorZoom:     1,
+		// Initialize sample tool parameters
+		SampleToolsSettings: types.DefaultSampleToolSettings(),
+		// Initialize arpeggio voices
+		arpeggioVoices:       make(map[int32]*arpeggioVoice),
 		arpeggioCurrentNotes: make(map[int32][]float32),
+		// Initialize multisample round-robin state
+		multisampleRoundRobin: make(map[int]int),
+		// Initialize parameter locks
+		ParameterLocks: make(types.ParameterLocks),
+		// Initialize MIDI device latencies
+		MidiLatencies: make(map[string]int),
 		// Initialize retrigger settings
 		RetriggerEditingIndex: 0,
 		// Initialize timestretch settings
 		TimestrechEditingIndex: 0,
+		// Initialize granular settings
+		GranularEditingIndex: 0,
 		// Initialize modulate settings
 		ModulateEditingIndex: 0,
 		// Initialize arpeggio settings
@@ -749,10 +943,14 @@ func NewModel(oscPort int, saveFolder string, vimMode bool) *Model {
 		LastPhraseCol: 0,
 		LastSongRow:   0,
 		LastSongTrack: 0,
-		// Initialize MIDI CC numbers to defaults (0-8)
-		MidiCCNumbers: [9]int{0, 1, 2, 3, 4, 5, 6, 7, 8},
 		// Set save folder
 		SaveFolder: saveFolder,
+		// Sample library index location
+		LibraryPath: library.DefaultPath(),
+		// SoundMaker preset library location
+		PresetDir:  soundmakers.PresetDir(),
+		DX7BankDir: supercollider.DX7BankDir(),
+		TuningDir:  tuning.UserDir(),
 		// Initialize recording state
 		RecordingEnabled:     false,
 		RecordingActive:      false,
@@ -804,6 +1002,9 @@ func (m *Model) initializeDefaultData() {
 			m.PhrasesData[p][i][types.ColGate] = -1                // Gate value (-1 displays "--", behaves as 80)
 			m.PhrasesData[p][i][types.ColRetrigger] = -1           // Retrigger index (-1 means no retrigger)
 			m.PhrasesData[p][i][types.ColTimestretch] = -1         // Timestretch index (-1 means no timestretch)
+			m.PhrasesData[p][i][types.ColGranular] = -1            // Granular index (-1 means no granular)
+			m.PhrasesData[p][i][types.ColSampleStart] = -1         // Start offset (-1 means slice start)
+			m.PhrasesData[p][i][types.ColSampleLength] = -1        // Length (-1 means whole slice)
 			m.PhrasesData[p][i][types.ColModulate] = -1            // Modulate index (-1 means no modulate)
 			m.PhrasesData[p][i][types.ColEffectReverse] = -1       // Reverse effect (-1 means no effect)
 			m.PhrasesData[p][i][types.ColPan] = -1                 // Pan (-1 = null, will use effective value or default to center)
@@ -834,6 +1035,7 @@ func (m *Model) initializeDefaultData() {
 			m.InstrumentPhrasesData[p][i][types.ColChord] = int(types.ChordNone)                   // Default: "-"
 			m.InstrumentPhrasesData[p][i][types.ColChordAddition] = int(types.ChordAddNone)        // Default: "-"
 			m.InstrumentPhrasesData[p][i][types.ColChordTransposition] = int(types.ChordTransNone) // Default: "-"
+			m.InstrumentPhrasesData[p][i][types.ColChordVoicing] = int(types.ChordVoicingClose)    // Default: "-"
 			m.InstrumentPhrasesData[p][i][types.ColArpeggio] = -1                                  // Default: "--" (no arpeggio)
 			m.InstrumentPhrasesData[p][i][types.ColModulate] = -1                                  // Default: "--" (no modulate)
 			m.InstrumentPhrasesData[p][i][types.ColMidi] = -1                                      // Default: "--" (sticky)
@@ -876,6 +1078,9 @@ func (m *Model) initializeDefaultData() {
 			m.SamplerPhrasesData[p][i][types.ColGate] = -1           // Gate value (-1 displays "--", behaves as 80)
 			m.SamplerPhrasesData[p][i][types.ColRetrigger] = -1      // Retrigger index (-1 means no retrigger)
 			m.SamplerPhrasesData[p][i][types.ColTimestretch] = -1    // Timestretch index (-1 means no timestretch)
+			m.SamplerPhrasesData[p][i][types.ColGranular] = -1       // Granular index (-1 means no granular)
+			m.SamplerPhrasesData[p][i][types.ColSampleStart] = -1    // Start offset (-1 means slice start)
+			m.SamplerPhrasesData[p][i][types.ColSampleLength] = -1   // Length (-1 means whole slice)
 			m.SamplerPhrasesData[p][i][types.ColModulate] = -1       // Modulate index (-1 means no modulate)
 			m.SamplerPhrasesData[p][i][types.ColEffectReverse] = -1  // Reverse effect (-1 means no effect)
 			m.SamplerPhrasesData[p][i][types.ColPan] = -1            // Pan (-1 = null, will use effective value or default to center)
@@ -935,6 +1140,14 @@ func (m *Model) initializeDefaultData() {
 			Probability: 100, // Default 100% probability
 		}
 	}
+
+	// Initialize granular settings with defaults (size 0 keeps a slot off)
+	for i := 0; i < 255; i++ {
+		m.GranularSettings[i] = types.GranularSettings{
+			Size:    0, // Default off
+			Density: types.DefaultGranularDensity,
+		}
+	}
 	// Initialize modulate settings with defaults for both instrument and sampler tracks
 	for i := 0; i < 255; i++ {
 		defaultSettings := types.ModulateSettings{
@@ -953,21 +1166,14 @@ func (m *Model) initializeDefaultData() {
 	for i := 0; i < 255; i++ {
 		var arpeggioSettings types.ArpeggioSettings
 		for row := 0; row < 16; row++ {
-			arpeggioSettings.Rows[row] = types.ArpeggioRow{
-				Direction: 0,  // Default direction (0 = "--")
-				Count:     -1, // Default count (-1 = "--")
-				Divisor:   -1, // Default divisor (-1 = "--")
-			}
+			arpeggioSettings.Rows[row] = types.NewArpeggioRow() // DI, CO and / "--"
 		}
 		m.ArpeggioSettings[i] = arpeggioSettings
 	}
 
 	// Initialize MIDI settings with defaults
 	for i := 0; i < 255; i++ {
-		m.MidiSettings[i] = types.MidiSettings{
-			Device:  "None",
-			Channel: "1", // Default to channel 1
-		}
+		m.MidiSettings[i] = types.NewMidiSettings()
 	}
 
 	// Initialize SoundMaker settings with defaults
@@ -994,6 +1200,11 @@ func (m *Model) initializeDefaultData() {
 		}
 	}
 
+	// LFOs start off
+	for i := range m.LFOSettings {
+		m.LFOSettings[i] = types.NewLFOSettings()
+	}
+
 	// Initialize song data (8 tracks × 16 rows, all empty initially)
 	for track := 0; track < 8; track++ {
 		for row := 0; row < 16; row++ {
@@ -1060,6 +1271,12 @@ type SamplerOSCParams struct {
 	TimestretchStart      float32 // Timestretch Settings "Start"
 	TimestretchEnd        float32 // Timestretch Settings "End"
 	TimestretchBeats      float32 // Timestretch Settings "Beats"
+	GranularSize          float32 // Granular Settings "Size" in milliseconds (0 = normal playback)
+	GranularDensity       float32 // Granular Settings "Density" in grains per second
+	GranularPosition      float32 // Granular Settings "Position" (0.0-1.0 of the slice)
+	GranularPosJitter     float32 // Granular Settings "Position jitter" (0.0-1.0 of the slice)
+	GranularPitchJitter   float32 // Granular Settings "Pitch jitter" in semitones
+	GranularSpray         float32 // Granular Settings "Spray" (0.0-1.0)
 	EffectReverse         int     // 0 or 1
 	Pan                   float32 // -1.0 to 1.0 (pan position)
 	LowPassFilter         float32 // Frequency in Hz (20Hz to 20kHz) or -1 for no filter
@@ -1070,34 +1287,45 @@ type SamplerOSCParams struct {
 	Velocity              int     // 0 .. 127 (0x00-0x7F)
 	Playthrough           int     // 0=Sliced, 1=Oneshot
 	SyncToBPM             int     // 0=No, 1=Yes
+	SliceStart            float32 // Start of the playable region as a fraction of the file (-1 = derive from slice number)
+	SliceEnd              float32 // End of the playable region as a fraction of the file (-1 = derive from slice number)
+	ClipToRegion          int     // 1 to end the voice at the end of the region even if the gate is longer (LN column)
+	LoopMode              int     // 0=Off, 1=Forward, 2=Ping-pong
+	LoopStart             int     // Loop start in frames
+	LoopEnd               int     // Loop end in frames (0 = end of file)
+	LoopXfadeMs           int     // Loop crossfade in milliseconds
+	Poly                  int     // 1 to layer with voices already playing on the track, 0 to replace them
 	Update                int     // 1 if this is an update to a playing row, 0 otherwise
 }
 
 type InstrumentOSCParams struct {
 	TrackId            int32 // Track ID
 	NoteOn             int32
-	Notes              []float32 // Note number (MIDI values, but can be fractional)
-	Velocity           float32   // Note velocity (0.0-1.0)
-	ChordType          int       // Chord type (C parameter)
-	ChordAddition      int       // Chord addition (A parameter)
-	ChordTransposition int       // Chord transposition (T parameter)
-	Gate               int       // Gate value (GT parameter, raw value)
-	DeltaTime          float32   // Delta time in seconds (DT parameter, time per row * DT)
-	Attack             float32   // Attack time in seconds (A parameter)
-	Decay              float32   // Decay time in seconds (D parameter)
-	Sustain            float32   // Sustain level (S parameter)
-	Release            float32   // Release time in seconds (R parameter)
-	Pan                float32   // -1.0 to 1.0 (pan position)
-	LowPassFilter      float32   // Frequency in Hz (20Hz to 20kHz) or -1 for no filter
-	HighPassFilter     float32   // Frequency in Hz (20Hz to 20kHz) or -1 for no filter
-	EffectComb         float32   // 0.0 .. 1.0
-	EffectReverb       float32   // 0.0 .. 1.0
-	ArpeggioIndex      int       // Arpeggio settings index (AR parameter)
-	MidiSettingsIndex  int       // MIDI settings index (MI parameter)
-	SoundMakerIndex    int       // SoundMaker settings index (SO parameter)
-	DuckingIndex       int       // Ducking settings index (DU parameter)
-	MidiCC             [9]int    // MIDI CC values 0-8 (-1 = not set)
-	Update             int       // 1 if this is an update to a playing row, 0 otherwise
+	Notes              []float32          // Note number (MIDI values, but can be fractional)
+	Velocity           float32            // Note velocity (0.0-1.0)
+	ChordType          int                // Chord type (C parameter)
+	ChordAddition      int                // Chord addition (A parameter)
+	ChordTransposition int                // Chord transposition (T parameter)
+	Gate               int                // Gate value (GT parameter, raw value)
+	DeltaTime          float32            // Delta time in seconds (DT parameter, time per row * DT)
+	Attack             float32            // Attack time in seconds (A parameter)
+	Decay              float32            // Decay time in seconds (D parameter)
+	Sustain            float32            // Sustain level (S parameter)
+	Release            float32            // Release time in seconds (R parameter)
+	Pan                float32            // -1.0 to 1.0 (pan position)
+	LowPassFilter      float32            // Frequency in Hz (20Hz to 20kHz) or -1 for no filter
+	HighPassFilter     float32            // Frequency in Hz (20Hz to 20kHz) or -1 for no filter
+	EffectComb         float32            // 0.0 .. 1.0
+	EffectReverb       float32            // 0.0 .. 1.0
+	ArpeggioIndex      int                // Arpeggio settings index (AR parameter)
+	MidiSettingsIndex  int                // MIDI settings index (MI parameter)
+	SoundMakerIndex    int                // SoundMaker settings index (SO parameter)
+	DuckingIndex       int                // Ducking settings index (DU parameter)
+	MidiCC             [9]int             // MIDI CC values 0-8 (-1 = not set)
+	PitchBend          int                // MIDI pitch bend (PB parameter, 00-FE, -1 = not set)
+	Aftertouch         int                // MIDI channel aftertouch (AT parameter, 00-7F, -1 = not set)
+	ParameterLocks     map[string]float32 // SoundMaker parameters locked on the row
+	Update             int                // 1 if this is an update to a playing row, 0 otherwise
 }
 
 // NewSamplerOSCParams creates sampler parameters with custom slice duration
@@ -1132,6 +1360,8 @@ func NewSamplerOSCParams(filename string, trackId int, sliceCount, sliceNumber i
 		Velocity:              velocity,
 		Playthrough:           0,  // Default Sliced (0)
 		SyncToBPM:             1,  // Default Yes (1)
+		SliceStart:            -1, // Default derive from slice number
+		SliceEnd:              -1, // Default derive from slice number
 		Update:                0,  // Default is not an update
 		DuckingIndex:          -1, // Default no ducking,
 	}
@@ -1171,6 +1401,8 @@ func NewSamplerOSCParamsWithRetrigger(filename string, trackId, sliceCount, slic
 		Playthrough:           0,         // Default Sliced (0)
 		SyncToBPM:             1,         // Default Yes (1)
 		DeltaTime:             deltaTime, // Delta time in seconds
+		SliceStart:            -1,        // Default derive from slice number
+		SliceEnd:              -1,        // Default derive from slice number
 		Update:                0,         // Default is not an update
 		DuckingIndex:          -1,        // Default no ducking,
 	}
@@ -1201,204 +1433,9 @@ func NewInstrumentOSCParams(trackId int32, velocity float32, chordType, chordAdd
 		SoundMakerIndex:    soundMakerIndex,
 		DuckingIndex:       duckingIndex,
 		MidiCC:             midiCC,
-		Update:             0, // Default is not an update
-	}
-}
-
-// CancelArpeggioForTrack cancels any existing arpeggio on the given track and sends note-off for currently playing notes
-func (m *Model) CancelArpeggioForTrack(trackId int32) {
-	log.Printf("DEBUG: CancelArpeggioForTrack called for track %d", trackId)
-	m.arpeggioMutex.Lock()
-	defer m.arpeggioMutex.Unlock()
-
-	// Cancel any existing arpeggio context
-	if cancelFunc, exists := m.arpeggioContexts[trackId]; exists {
-		log.Printf("DEBUG: cancelArpeggioForTrack - FOUND existing arpeggio context for track %d, cancelling", trackId)
-		delete(m.arpeggioContexts, trackId) // Remove before calling cancel
-		m.arpeggioMutex.Unlock()            // Unlock before calling cancel to prevent deadlock
-		cancelFunc()                        // Now safe to call
-		// Give goroutine a moment to terminate gracefully
-		time.Sleep(2 * time.Millisecond)
-		m.arpeggioMutex.Lock() // Re-lock for the rest of the function
-	} else {
-		log.Printf("DEBUG: cancelArpeggioForTrack - NO existing arpeggio context for track %d", trackId)
-	}
-
-	// Send note-off for any currently playing arpeggio notes
-	if currentNotes, exists := m.arpeggioCurrentNotes[trackId]; exists && len(currentNotes) > 0 {
-		log.Printf("DEBUG: cancelArpeggioForTrack - sending note-off for %d notes on track %d", len(currentNotes), trackId)
-
-		// Create note-off parameters based on the current notes
-		noteOffParams := InstrumentOSCParams{
-			TrackId: trackId,
-			NoteOn:  0, // 0 = note-off
-			Notes:   currentNotes,
-		}
-
-		// Send note-off message
-		m.sendOSCInstrumentMessage(noteOffParams)
-
-		// Clear the current notes
-		delete(m.arpeggioCurrentNotes, trackId)
-	}
-}
-
-// SendOSCInstrumentMessageWithArpeggio is the high-level function that handles arpeggio logic
-func (m *Model) SendOSCInstrumentMessageWithArpeggio(params InstrumentOSCParams) {
-	log.Printf("DEBUG: SendOSCInstrumentMessageWithArpeggio called for track %d with notes %v, ArpeggioIndex=%d", params.TrackId, params.Notes, params.ArpeggioIndex)
-
-	// ALWAYS cancel any existing arpeggio on this track (whether new note has arpeggio or not)
-	m.CancelArpeggioForTrack(params.TrackId)
-
-	// Check if we have an arpeggio
-	arpeggioNotes, arpeggioDivisions := m.ProcessArpeggio(params)
-
-	if len(arpeggioNotes) > 0 {
-		// Arpeggio is active - send only the root note initially
-		log.Printf("DEBUG: Arpeggio active - sending only root note for track %d: %v", params.TrackId, params.Notes[0:1])
-		rootOnlyParams := params
-		rootOnlyParams.Notes = []float32{params.Notes[0]} // Only send the root note
-		m.sendOSCInstrumentMessage(rootOnlyParams)
-
-		// Start the arpeggio
-		m.PlayArpeggio(params, arpeggioNotes, arpeggioDivisions)
-	} else {
-		// No arpeggio - send the full chord/note as normal
-		log.Printf("DEBUG: No arpeggio - sending full chord/note for track %d: %v", params.TrackId, params.Notes)
-		m.sendOSCInstrumentMessage(params)
-	}
-}
-
-func (m *Model) ProcessArpeggio(params InstrumentOSCParams) (arpeggioNotes []float32, arpeggioDivisions []float32) {
-	log.Printf("DEBUG: ProcessArpeggio called with ArpeggioIndex=%d, input notes=%v", params.ArpeggioIndex, params.Notes)
-
-	// Check if we have a valid arpeggio index
-	if params.ArpeggioIndex < 0 || params.ArpeggioIndex >= 255 {
-		log.Printf("DEBUG: ProcessArpeggio - invalid arpeggio index %d, returning empty", params.ArpeggioIndex)
-		return nil, nil
-	}
-
-	arpeggioSettings := m.ArpeggioSettings[params.ArpeggioIndex]
-	log.Printf("DEBUG: ProcessArpeggio - got arpeggio settings for index %d", params.ArpeggioIndex)
-
-	// Use the chord notes that are already calculated and transposed in params.Notes
-	// This avoids double-transposition since helpers.go already called GetChordNotes with transposition
-	baseChord := make([]float32, len(params.Notes))
-	copy(baseChord, params.Notes)
-
-	log.Printf("DEBUG: ProcessArpeggio - using pre-calculated transposed chord notes: %v", baseChord)
-
-	var resultNotes []float32
-	var resultDivisors []float32
-
-	// Start with the root note as current position (this is already transposed by GetChordNotes)
-	currentNote := baseChord[0]
-	isChord := len(baseChord) > 1
-
-	log.Printf("DEBUG: ProcessArpeggio - isChord: %v, baseChord: %v", isChord, baseChord)
-
-	// Process each arpeggio row
-	for rowIdx, row := range arpeggioSettings.Rows {
-		if row.Direction == 0 || row.Count < 0 || row.Divisor < 0 {
-			continue // Skip empty rows ("--" values)
-		}
-
-		log.Printf("DEBUG: ProcessArpeggio - processing row %d: direction=%d, count=%d, divisor=%d",
-			rowIdx, row.Direction, row.Count, row.Divisor)
-
-		isUp := row.Direction == 1 // 1="u-", 2="d-"
-
-		// Generate notes for this row
-		for i := 0; i < row.Count; i++ {
-			if isChord {
-				// For chords: find current position in chord and move up/down through chord tones
-				currentNote = m.getNextChordNote(currentNote, baseChord, isUp)
-			} else {
-				// For single notes: move up/down by octaves (12 semitones)
-				if isUp {
-					currentNote += 12
-				} else {
-					currentNote -= 12
-				}
-			}
-
-			resultNotes = append(resultNotes, currentNote)
-			resultDivisors = append(resultDivisors, float32(row.Divisor))
-
-			log.Printf("DEBUG: ProcessArpeggio - added note: %f, divisor: %d", currentNote, row.Divisor)
-		}
-	}
-
-	log.Printf("DEBUG: ProcessArpeggio - final result: notes=%v, divisors=%v",
-		resultNotes, resultDivisors)
-
-	return resultNotes, resultDivisors
-}
-
-// getNextChordNote finds the next note in the chord sequence when going up or down
-func (m *Model) getNextChordNote(currentNote float32, baseChord []float32, isUp bool) float32 {
-	// Find the current position in the chord - try exact match first
-	currentChordIndex := -1
-	octaveOffset := 0
-
-	for i, chordNote := range baseChord {
-		if chordNote == currentNote {
-			currentChordIndex = i
-			break
-		}
-	}
-
-	// If no exact match, find by note class (mod 12) and calculate octave offset
-	if currentChordIndex == -1 {
-		baseNote := int(currentNote) % 12
-		minDist := 1000
-		for i, chordNote := range baseChord {
-			if int(chordNote)%12 == baseNote {
-				dist := int(currentNote - chordNote)
-				if dist < 0 {
-					dist = -dist
-				}
-				if dist < minDist {
-					minDist = dist
-					currentChordIndex = i
-					// Calculate how many octaves above the base chord we are
-					octaveOffset = int(currentNote-chordNote) / 12 * 12
-				}
-			}
-		}
-	}
-
-	// If still not found, find the closest note overall
-	if currentChordIndex == -1 {
-		minDist := float32(1000)
-		for i, chordNote := range baseChord {
-			dist := currentNote - chordNote
-			if dist < 0 {
-				dist = -dist
-			}
-			if dist < minDist {
-				minDist = dist
-				currentChordIndex = i
-				octaveOffset = int(currentNote-chordNote) / 12 * 12
-			}
-		}
-	}
-
-	// Move to next chord tone - preserve octave relationships
-	if isUp {
-		nextIndex := currentChordIndex + 1
-		if nextIndex >= len(baseChord) {
-			// Wrap around to beginning and add octave
-			return baseChord[0] + float32(octaveOffset) + 12
-		}
-		return baseChord[nextIndex] + float32(octaveOffset)
-	} else {
-		nextIndex := currentChordIndex - 1
-		if nextIndex < 0 {
-			// Wrap around to end and subtract octave
-			return baseChord[len(baseChord)-1] + float32(octaveOffset) - 12
-		}
-		return baseChord[nextIndex] + float32(octaveOffset)
+		PitchBend:          -1, // Default no pitch bend
+		Aftertouch:         -1, // Default no aftertouch
+		Update:             0,  // Default is not an update
 	}
 }
 
@@ -1418,8 +1455,23 @@ func (m *Model) sendOSCInstrumentMessage(params InstrumentOSCParams) {
 		return
 	}
 
+	// Multisample SoundMakers play their zones through the sampler
+	if params.SoundMakerIndex > -1 && params.SoundMakerIndex < 255 &&
+		m.SoundMakerSettings[params.SoundMakerIndex].Name == types.MultisampleSoundMakerName {
+		m.sendOSCMultisampleMessage(params)
+		return
+	}
+
 	// Check if SoundMaker is configured (SoundMakerIndex != -1 means a SoundMaker is selected)
 	if params.SoundMakerIndex > -1 {
+		// Keys are retuned last, once chords and arpeggios have picked them
+		notes := m.TuningForTrack(int(params.TrackId)).Notes(params.Notes)
+		if len(notes) < len(params.Notes) {
+			log.Printf("Track %d: dropped notes that do not play in tuning %s", params.TrackId, m.TuningNameForTrack(int(params.TrackId)))
+			if len(notes) == 0 {
+				return
+			}
+		}
 
 		msg := osc.NewMessage("/instrument")
 		msg.Append(int32(params.TrackId)) // Track ID
@@ -1428,7 +1480,7 @@ func (m *Model) sendOSCInstrumentMessage(params InstrumentOSCParams) {
 		soundMakerSettings := m.SoundMakerSettings[params.SoundMakerIndex]
 		msg.Append(soundMakerSettings.Name)
 		// add all notes as float32
-		for _, note := range params.Notes {
+		for _, note := range notes {
 			msg.Append(float32(note))
 		}
 		msg.Append("trackVolume")
@@ -1490,7 +1542,7 @@ func (m *Model) sendOSCInstrumentMessage(params InstrumentOSCParams) {
 			// Get instrument definition and send all parameters as key-value pairs
 			if def, exists := types.GetInstrumentDefinition(soundMakerSettings.Name); exists {
 				for _, param := range def.Parameters {
-					value := soundMakerSettings.GetParameterValue(param.Key)
+					value := types.SoundMakerValue(&soundMakerSettings, params.ParameterLocks, param.Key)
 
 					// Append parameter key
 					msg.Append(param.Key)
@@ -1524,6 +1576,14 @@ func (m *Model) sendOSCInstrumentMessage(params InstrumentOSCParams) {
 				// Fallback if instrument definition not found
 				log.Printf("WARNING: No instrument definition found for %s", soundMakerSettings.Name)
 			}
+
+			// Voices imported from SysEx banks are not in DX7.afx, so their data goes along
+			if soundMakerSettings.Name == "DX7" {
+				if patch, ok := supercollider.GetDX7VoicePatchLine(int(types.SoundMakerValue(&soundMakerSettings, params.ParameterLocks, "preset"))); ok {
+					msg.Append("patch")
+					msg.Append(patch)
+				}
+			}
 		}
 
 		// Add update parameter when this is an update to a playing row
@@ -1542,6 +1602,85 @@ func (m *Model) sendOSCInstrumentMessage(params InstrumentOSCParams) {
 	}
 }
 
+// sendOSCMultisampleMessage plays instrument notes with the zones of a Multisample SoundMaker.
+// Each matching zone is sent as a sampler voice pitched from its root note; all voices of a
+// chord are layered and replace whatever was playing on the track.
+func (m *Model) sendOSCMultisampleMessage(params InstrumentOSCParams) {
+	if params.NoteOn == 0 || params.Update == 1 {
+		// Sampler voices end with their gate, and updates would swap the buffers of playing voices
+		return
+	}
+
+	settings := m.SoundMakerSettings[params.SoundMakerIndex]
+	oneshot := settings.GetParameterValue("oneshot") == 1
+	notes := params.Notes
+	if settings.GetParameterValue("monophonic") == 1 && len(notes) > 1 {
+		notes = notes[:1]
+	}
+
+	// Gate length in beats, as the sampler envelope runs in beats
+	duration := params.DeltaTime * float32(params.Gate) / 128.0
+	durationBeats := duration / (60.0 / m.BPM)
+
+	voices := 0
+	trackTuning := m.TuningForTrack(int(params.TrackId))
+	for _, note := range notes {
+		// Zones are picked by key and pitched to where the tuning puts it
+		tunedNote, ok := trackTuning.Note(note)
+		if !ok {
+			continue
+		}
+		zones := types.SelectMultisampleZones(settings.Zones, int(note+0.5), int(params.Velocity),
+			func(group, count int) int {
+				return m.nextMultisampleRoundRobin(params.SoundMakerIndex, group, count)
+			})
+		if len(zones) == 0 {
+			log.Printf("Multisample %02X has no zone for note %.0f velocity %.0f", params.SoundMakerIndex, note, params.Velocity)
+			continue
+		}
+		for _, zone := range zones {
+			samplerParams := NewSamplerOSCParams(zone.File, int(params.TrackId), 1, 0, m.BPM, m.BPM, durationBeats, params.DeltaTime, int(params.Velocity))
+			samplerParams.SyncToBPM = 0
+			samplerParams.Pitch = zone.Pitch(tunedNote)
+			samplerParams.Pan = params.Pan
+			samplerParams.LowPassFilter = params.LowPassFilter
+			samplerParams.HighPassFilter = params.HighPassFilter
+			samplerParams.EffectComb = params.EffectComb
+			samplerParams.EffectReverb = params.EffectReverb
+			samplerParams.DuckingIndex = params.DuckingIndex
+			if oneshot {
+				samplerParams.Playthrough = 1
+			}
+			// Sustain with the loop region of the file when it has one
+			if metadata, exists := m.FileMetadata[zone.File]; exists && metadata.LoopMode > 0 {
+				samplerParams.LoopMode = metadata.LoopMode
+				samplerParams.LoopStart = metadata.LoopStart
+				samplerParams.LoopEnd = metadata.LoopEnd
+				samplerParams.LoopXfadeMs = metadata.LoopXfadeMs
+			}
+			if voices > 0 {
+				samplerParams.Poly = 1
+			}
+			m.SendOSCSamplerMessage(samplerParams)
+			voices++
+		}
+	}
+}
+
+// nextMultisampleRoundRobin returns the zone to play within a round-robin group and advances it
+func (m *Model) nextMultisampleRoundRobin(soundMakerIndex, group, count int) int {
+	m.multisampleMutex.Lock()
+	defer m.multisampleMutex.Unlock()
+
+	if m.multisampleRoundRobin == nil {
+		m.multisampleRoundRobin = make(map[int]int)
+	}
+	key := soundMakerIndex*256 + group
+	index := m.multisampleRoundRobin[key] % count
+	m.multisampleRoundRobin[key] = index + 1
+	return index
+}
+
 // sendMIDIInstrumentMessage sends MIDI messages for the given instrument parameters if MIDI is configured
 func (m *Model) sendMIDIInstrumentMessage(params InstrumentOSCParams) {
 	// Check if MIDI is configured (MidiSettingsIndex != -1 means "--" is not set)
@@ -1558,18 +1697,15 @@ func (m *Model) sendMIDIInstrumentMessage(params InstrumentOSCParams) {
 		return
 	}
 
-	// Parse channel (convert from string to int, 1-indexed to 0-indexed)
-	channel, err := strconv.Atoi(midiSettings.Channel)
-	if err != nil {
-		log.Printf("ERROR: Failed to parse MIDI channel '%s': %v", midiSettings.Channel, err)
+	channel, ok := midiChannel(midiSettings)
+	if !ok {
 		return
 	}
-	// Convert from 1-indexed to 0-indexed
-	if channel < 1 || channel > 16 {
-		log.Printf("ERROR: Invalid MIDI channel %d, must be 1-16", channel)
-		return
+
+	// Set the channel to the patch of the settings on first use and after it changes
+	if err := midiplayer.SelectPatch(midiSettings.Device, channel, midiPatch(midiSettings), false); err != nil {
+		log.Printf("ERROR: Failed to select MIDI patch: %v", err)
 	}
-	channel = channel - 1
 
 	// Calculate duration same as OSC message
 	duration := float64(params.DeltaTime) * float64(params.Gate) / 128.0
@@ -1581,10 +1717,10 @@ func (m *Model) sendMIDIInstrumentMessage(params InstrumentOSCParams) {
 		midiSettings.Device, channel, params.Notes, velocity, duration)
 
 	// Send MIDI CC messages for each CC value that is not "--" (i.e., not -1)
-	// Use the MidiCCNumbers from the model to determine which CC number to use
+	// Each column sends the CC the MIDI settings map it to
 	for i := 0; i < 9; i++ {
 		if params.MidiCC[i] != -1 {
-			ccNumber := m.MidiCCNumbers[i]
+			ccNumber := midiSettings.CCs[i].Number
 			ccValue := params.MidiCC[i]
 			err := midiplayer.ControlChange(midiSettings.Device, int(ccNumber), ccValue, channel)
 			if err != nil {
@@ -1596,6 +1732,23 @@ func (m *Model) sendMIDIInstrumentMessage(params InstrumentOSCParams) {
 		}
 	}
 
+	// Bend before the notes so they start bent. PB is not sticky: a row without it centers
+	// a channel an earlier row left bent.
+	if params.PitchBend != -1 {
+		bend := types.PitchBendToMidi(params.PitchBend)
+		if err := midiplayer.PitchBend(midiSettings.Device, bend, channel); err != nil {
+			log.Printf("ERROR: Failed to send MIDI pitch bend %d: %v", bend, err)
+		}
+	} else if err := midiplayer.CenterPitchBend(midiSettings.Device, channel); err != nil {
+		log.Printf("ERROR: Failed to center MIDI pitch bend: %v", err)
+	}
+	// Neither is AT: a row without it releases the pressure of an earlier row
+	if params.Aftertouch == -1 {
+		if err := midiplayer.ReleaseChannelPressure(midiSettings.Device, channel); err != nil {
+			log.Printf("ERROR: Failed to release MIDI aftertouch: %v", err)
+		}
+	}
+
 	// Send MIDI note-on for each note (skip invalid notes like -1)
 	for _, note := range params.Notes {
 		// Skip invalid notes (e.g., when NOT column is not defined)
@@ -1611,107 +1764,96 @@ func (m *Model) sendMIDIInstrumentMessage(params InstrumentOSCParams) {
 				midiSettings.Device, note, velocity, duration, channel)
 		}
 	}
+
+	// Aftertouch presses on the notes that are sounding
+	if params.Aftertouch != -1 {
+		if err := midiplayer.ChannelPressure(midiSettings.Device, params.Aftertouch, channel); err != nil {
+			log.Printf("ERROR: Failed to send MIDI aftertouch %d: %v", params.Aftertouch, err)
+		}
+	}
+}
+
+// midiChannel returns the 0-indexed channel of MIDI settings
+func midiChannel(settings types.MidiSettings) (int, bool) {
+	// Parse channel (convert from string to int, 1-indexed to 0-indexed)
+	channel, err := strconv.Atoi(settings.Channel)
+	if err != nil {
+		log.Printf("ERROR: Failed to parse MIDI channel '%s': %v", settings.Channel, err)
+		return 0, false
+	}
+	// Convert from 1-indexed to 0-indexed
+	if channel < 1 || channel > 16 {
+		log.Printf("ERROR: Invalid MIDI channel %d, must be 1-16", channel)
+		return 0, false
+	}
+	return channel - 1, true
 }
 
-// PlayArpeggio plays an arpeggio sequence for the given track with cancellation support
-func (m *Model) PlayArpeggio(params InstrumentOSCParams, notes []float32, divisions []float32) {
-	log.Printf("DEBUG: PlayArpeggio called with trackId=%d, notes=%v, divisions=%v, deltaTime=%f",
-		params.TrackId, notes, divisions, params.DeltaTime)
+// midiPatch returns the patch MIDI settings select on their channel
+func midiPatch(settings types.MidiSettings) midiplayer.Patch {
+	return midiplayer.Patch{
+		BankMSB:   settings.BankMSB,
+		BankLSB:   settings.BankLSB,
+		Program:   settings.Program,
+		BendRange: settings.BendRange,
+	}
+}
 
-	if len(notes) == 0 {
-		log.Printf("DEBUG: PlayArpeggio - no notes provided, returning")
+// SendMidiPatch sets the channel of MIDI settings to their patch. force sends it even when the
+// channel was already set to it.
+func (m *Model) SendMidiPatch(index int, force bool) {
+	if index < 0 || index >= 255 {
 		return
 	}
-
-	if len(divisions) == 0 {
-		log.Printf("DEBUG: PlayArpeggio - no divisions provided, returning")
+	settings := m.MidiSettings[index]
+	if settings.Device == "None" || settings.Device == "" {
 		return
 	}
+	channel, ok := midiChannel(settings)
+	if !ok {
+		return
+	}
+	if err := midiplayer.SelectPatch(settings.Device, channel, midiPatch(settings), force); err != nil {
+		log.Printf("ERROR: Failed to select patch of MIDI %02X: %v", index, err)
+	}
+}
 
-	// Create new cancellable context and store it
-	ctx, cancel := context.WithCancel(context.Background())
-	m.arpeggioMutex.Lock()
-	// Ensure no old context exists before storing new one
-	if oldCancel, exists := m.arpeggioContexts[params.TrackId]; exists {
-		log.Printf("DEBUG: PlayArpeggio - WARNING: Found existing context for track %d, this shouldn't happen after cancellation!", params.TrackId)
-		oldCancel() // Cancel the old one just in case
-	}
-	m.arpeggioContexts[params.TrackId] = cancel
-	// Initialize tracking with the root note (already sent)
-	m.arpeggioCurrentNotes[params.TrackId] = []float32{params.Notes[0]}
-	m.arpeggioMutex.Unlock()
-
-	log.Printf("DEBUG: PlayArpeggio - starting goroutine for track %d", params.TrackId)
-
-	// Start arpeggio in goroutine
-	go func() {
-		defer func() {
-			// Clean up context when done
-			log.Printf("DEBUG: PlayArpeggio - cleaning up context for track %d", params.TrackId)
-			m.arpeggioMutex.Lock()
-			delete(m.arpeggioContexts, params.TrackId)
-			m.arpeggioMutex.Unlock()
-		}()
-
-		log.Printf("DEBUG: PlayArpeggio - calculating wait time for first note. DeltaTime=%f, divisions[0]=%f",
-			params.DeltaTime, divisions[0])
-
-		if len(notes) > 0 && len(divisions) > 0 {
-			waitTime := time.Duration(float64(params.DeltaTime) / float64(divisions[0]) * float64(time.Second))
-			log.Printf("DEBUG: PlayArpeggio - waiting %v before first arpeggio note", waitTime)
-			select {
-			case <-time.After(waitTime):
-				log.Printf("DEBUG: PlayArpeggio - wait completed for first note")
-			case <-ctx.Done():
-				log.Printf("DEBUG: PlayArpeggio - cancelled during first wait")
-				return
-			}
-		}
-
-		// Play remaining notes in the arpeggio
-		log.Printf("DEBUG: PlayArpeggio - starting loop for notes 1 to %d (total notes: %d, total divisions: %d)",
-			len(notes)-1, len(notes), len(divisions))
-
-		for i := 1; i < len(notes) && i < len(divisions); i++ {
-			select {
-			case <-ctx.Done():
-				log.Printf("DEBUG: PlayArpeggio - cancelled during note %d", i)
-				return
-			default:
-			}
-
-			log.Printf("DEBUG: PlayArpeggio - playing note %d: %f", i, notes[i])
-
-			// Create new params with the arpeggio note
-			arpeggioParams := params
-			arpeggioParams.Notes = []float32{notes[i]}
-
-			// Send OSC message for this arpeggio note
-			m.sendOSCInstrumentMessage(arpeggioParams)
+// SetMidiLatency sets how many ms the messages to a MIDI device wait, so the synth lines up
+// with the audio
+func (m *Model) SetMidiLatency(device string, latency int) {
+	if device == "None" || device == "" {
+		return
+	}
+	latency = min(max(latency, 0), int(midiplayer.MaxLatency/time.Millisecond))
+	if latency == 0 {
+		delete(m.MidiLatencies, device)
+	} else {
+		m.MidiLatencies[device] = latency
+	}
+	midiplayer.SetLatency(device, time.Duration(latency)*time.Millisecond)
+}
 
-			// Update currently playing note tracking
-			m.arpeggioMutex.Lock()
-			m.arpeggioCurrentNotes[params.TrackId] = []float32{notes[i]}
-			m.arpeggioMutex.Unlock()
+// ApplyMidiLatencies sets the MIDI devices to the latencies of the song, e.g. when it loads
+func (m *Model) ApplyMidiLatencies() {
+	for _, device := range m.AvailableMidiDevices {
+		midiplayer.SetLatency(device, time.Duration(m.MidiLatencies[device])*time.Millisecond)
+	}
+	for device, latency := range m.MidiLatencies {
+		midiplayer.SetLatency(device, time.Duration(latency)*time.Millisecond)
+	}
+}
 
-			// Wait for next note based on division
-			if i < len(divisions)-1 {
-				waitTime := time.Duration(float64(params.DeltaTime) / float64(divisions[i]) * float64(time.Second))
-				log.Printf("DEBUG: PlayArpeggio - waiting %v before note %d (division=%f)", waitTime, i+1, divisions[i])
-				select {
-				case <-time.After(waitTime):
-					log.Printf("DEBUG: PlayArpeggio - wait completed for note %d", i+1)
-				case <-ctx.Done():
-					log.Printf("DEBUG: PlayArpeggio - cancelled during wait for note %d", i+1)
-					return
-				}
-			} else {
-				log.Printf("DEBUG: PlayArpeggio - no more divisions, finishing after note %d", i)
-			}
-		}
+// StopMidi drops the MIDI messages that are waiting and ends the notes of every device
+func (m *Model) StopMidi() {
+	midiplayer.StopAll()
+}
 
-		log.Printf("DEBUG: PlayArpeggio - arpeggio sequence completed for track %d", params.TrackId)
-	}()
+// RecallMidiPatches sets external synths to the patches of every MIDI settings, e.g. when a
+// song loads
+func (m *Model) RecallMidiPatches() {
+	for i := range m.MidiSettings {
+		m.SendMidiPatch(i, true)
+	}
 }
 
 func (m *Model) SendOSCSamplerMessage(params SamplerOSCParams) {
@@ -1805,6 +1947,54 @@ func (m *Model) SendOSCSamplerMessage(params SamplerOSCParams) {
 	msg.Append("synctobpm")
 	msg.Append(int32(params.SyncToBPM))
 
+	// Add explicit slice region when the file has trim points or custom slice markers
+	if params.SliceStart >= 0 && params.SliceEnd > params.SliceStart {
+		msg.Append("sliceStart")
+		msg.Append(float32(params.SliceStart))
+		msg.Append("sliceEnd")
+		msg.Append(float32(params.SliceEnd))
+		if params.ClipToRegion == 1 {
+			msg.Append("clipToRegion")
+			msg.Append(int32(1))
+		}
+	}
+
+	// Add loop region so the voice sustains while the gate holds
+	if params.LoopMode > 0 {
+		msg.Append("loopMode")
+		msg.Append(int32(params.LoopMode))
+		msg.Append("loopStart")
+		msg.Append(int32(params.LoopStart))
+		msg.Append("loopEnd")
+		msg.Append(int32(params.LoopEnd))
+		msg.Append("loopXfadeMs")
+		msg.Append(int32(params.LoopXfadeMs))
+	}
+
+	// Play the row as a grain cloud with the granular SynthDef
+	if params.GranularSize > 0 {
+		msg.Append("granular")
+		msg.Append(int32(1))
+		msg.Append("grainSizeMs")
+		msg.Append(float32(params.GranularSize))
+		msg.Append("grainDensity")
+		msg.Append(float32(params.GranularDensity))
+		msg.Append("grainPosition")
+		msg.Append(float32(params.GranularPosition))
+		msg.Append("grainPositionJitter")
+		msg.Append(float32(params.GranularPosJitter))
+		msg.Append("grainPitchJitter")
+		msg.Append(float32(params.GranularPitchJitter))
+		msg.Append("grainSpray")
+		msg.Append(float32(params.GranularSpray))
+	}
+
+	// Layer with the voices already playing on the track (chords from multisample SoundMakers)
+	if params.Poly == 1 {
+		msg.Append("poly")
+		msg.Append(int32(1))
+	}
+
 	// Add update parameter when this is an update to a playing row
 	if params.Update == 1 {
 		msg.Append("update")
@@ -2074,6 +2264,52 @@ func (m *Model) SendOSCRecordMessage(filename string, recording bool, trackMask
 	m.sendOSCMessage(config)
 }
 
+// SendOSCSampleReloadMessage tells SuperCollider to drop its cached buffer of a file that
+// was changed on disk, so that it is read again the next time it plays
+func (m *Model) SendOSCSampleReloadMessage(filename string) {
+	// Convert filename to absolute path for SuperCollider
+	absolutePath, err := filepath.Abs(filename)
+	if err != nil {
+		log.Printf("Error converting filename to absolute path: %v", err)
+		absolutePath = filename // fallback to original filename
+	}
+
+	config := OSCMessageConfig{
+		Address:    "/sampler_reload",
+		Parameters: []interface{}{absolutePath},
+		LogFormat:  "OSC message sent: /sampler_reload '%s'",
+		LogArgs:    []interface{}{absolutePath},
+	}
+
+	m.sendOSCMessage(config)
+}
+
+// SendOSCSynthDefMessages asks SuperCollider to load the SynthDef files of the user SoundMakers
+func (m *Model) SendOSCSynthDefMessages() {
+	for _, name := range types.GetAvailableSoundMakers() {
+		def, _ := types.GetInstrumentDefinition(name)
+		if def.SynthDef == "" {
+			continue
+		}
+		absolutePath, err := filepath.Abs(def.SynthDef)
+		if err != nil {
+			absolutePath = def.SynthDef
+		}
+		m.sendOSCMessage(OSCMessageConfig{
+			Address:    "/synthdef",
+			Parameters: []interface{}{absolutePath},
+			LogFormat:  "OSC message sent: /synthdef '%s'",
+			LogArgs:    []interface{}{absolutePath},
+		})
+	}
+}
+
+// GenerateBounceFilename returns the path of a new bounce file in the save folder,
+// e.g. bounce-t1-phrase-03-2025-01-02-15-04-05.wav
+func (m *Model) GenerateBounceFilename(source string, id int) string {
+	return filepath.Join(m.SaveFolder, fmt.Sprintf("bounce-t%d-%s-%02X-%s", m.CurrentTrack+1, source, id, m.GenerateRecordingFilename()))
+}
+
 func (m *Model) GenerateRecordingFilename() string {
 	now := time.Now()
 	return fmt.Sprintf("%04d-%02d-%02d-%02d-%02d-%02d.wav",
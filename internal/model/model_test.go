@@ -31,6 +31,7 @@ func TestNewModel(t *testing.T) {
 	assert.Len(t, m.SamplerPhrasesData, 255)
 	assert.Len(t, m.RetriggerSettings, 255)
 	assert.Len(t, m.TimestrechSettings, 255)
+	assert.Len(t, m.GranularSettings, 255)
 	assert.Len(t, m.ArpeggioSettings, 255)
 	assert.Len(t, m.MidiSettings, 255)
 	assert.Len(t, m.SoundMakerSettings, 255)
@@ -161,6 +162,13 @@ func TestModelColumnMapping(t *testing.T) {
 	m.CurrentTrack = 6 // Sampler track
 	mapping = m.GetColumnMapping(0)
 	assert.NotNil(t, mapping)
+
+	// Start offset and length map to their own data columns
+	mapping = m.GetColumnMapping(int(types.SamplerColST))
+	assert.Equal(t, int(types.ColSampleStart), mapping.DataColumnIndex)
+	mapping = m.GetColumnMapping(int(types.SamplerColLN))
+	assert.Equal(t, int(types.ColSampleLength), mapping.DataColumnIndex)
+	assert.Equal(t, -1, m.SamplerPhrasesData[0][0][types.ColSampleLength])
 }
 
 func TestModelRecordingFilename(t *testing.T) {
@@ -434,6 +442,27 @@ func TestProcessArpeggio(t *testing.T) {
 	}
 }
 
+func TestProcessArpeggioWithVoicedChord(t *testing.T) {
+	model := NewModel(0, "", false)
+	model.ArpeggioSettings[12] = types.ArpeggioSettings{
+		Rows: [16]types.ArpeggioRow{
+			{Direction: 1, Count: 4, Divisor: 1},
+			{Direction: 2, Count: 2, Divisor: 1},
+		},
+	}
+
+	// Spread C major 7 spans two octaves: 48, 64, 67, 71
+	chordNotes := types.VoiceChord(types.GetChordNotes(60, types.ChordMajor7, types.ChordAddNone, types.ChordTransNone), types.ChordVoicingSpread, nil)
+	params := InstrumentOSCParams{ArpeggioIndex: 12}
+	for _, note := range chordNotes {
+		params.Notes = append(params.Notes, float32(note))
+	}
+
+	// The arpeggio walks the voiced chord upwards and repeats it two octaves higher
+	notes, _ := model.ProcessArpeggio(params)
+	assert.Equal(t, []float32{64, 67, 71, 72, 71, 67}, notes)
+}
+
 func TestProcessArpeggioWithChordAdditions(t *testing.T) {
 	model := NewModel(0, "", false)
 
@@ -727,3 +756,15 @@ func TestSendOSCInstrumentMessageWithArpeggioInitialNote(t *testing.T) {
 	// This should send the full chord since no arpeggio is active
 	model.SendOSCInstrumentMessageWithArpeggio(noArpeggioParams)
 }
+
+func TestNextMultisampleRoundRobin(t *testing.T) {
+	model := NewModel(0, "", false)
+
+	// Each group cycles through its zones independently
+	assert.Equal(t, 0, model.nextMultisampleRoundRobin(1, 1, 3))
+	assert.Equal(t, 1, model.nextMultisampleRoundRobin(1, 1, 3))
+	assert.Equal(t, 0, model.nextMultisampleRoundRobin(1, 2, 2))
+	assert.Equal(t, 2, model.nextMultisampleRoundRobin(1, 1, 3))
+	assert.Equal(t, 0, model.nextMultisampleRoundRobin(1, 1, 3))
+	assert.Equal(t, 0, model.nextMultisampleRoundRobin(2, 1, 3))
+}
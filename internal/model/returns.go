@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// SendOSCReturnMessage sets every parameter of a return bus on the out synth
+func (m *Model) SendOSCReturnMessage(ret int) {
+	if ret < 0 || ret >= types.ReturnCount {
+		return
+	}
+	module := types.ReturnModules[ret]
+	for _, param := range module.Parameters {
+		value := types.InsertFXOSCValue(module, param, m.Returns[ret].Value(param), m.BPM)
+		m.sendOSCMessage(OSCMessageConfig{
+			Address:    "/set",
+			Parameters: []interface{}{param.Key, value},
+			LogFormat:  "OSC return message sent: /set '%s' %.3f",
+			LogArgs:    []interface{}{param.Key, value},
+		})
+	}
+}
+
+// SendOSCTrackSendMessage sets the sends of a track to the return buses
+func (m *Model) SendOSCTrackSendMessage(track int) {
+	if track < 0 || track >= len(m.TrackSends) {
+		return
+	}
+	parameters := []interface{}{int32(track)}
+	for ret, key := range types.ReturnSendKeys {
+		// Normalize percentage (0-100) to 0.0-1.0 for SuperCollider
+		parameters = append(parameters, key, m.TrackSends[track][ret]/100.0)
+	}
+	m.sendOSCMessage(OSCMessageConfig{
+		Address:    "/track_send",
+		Parameters: parameters,
+		LogFormat:  "OSC track send message sent: track %d %v",
+		LogArgs:    []interface{}{track, m.TrackSends[track]},
+	})
+}
+
+// SendOSCReturnMessages sends the return buses and the sends of every track, e.g. on
+// startup or when the tempo changes
+func (m *Model) SendOSCReturnMessages() {
+	for ret := range m.Returns {
+		m.SendOSCReturnMessage(ret)
+	}
+	for track := range m.TrackSends {
+		m.SendOSCTrackSendMessage(track)
+	}
+}
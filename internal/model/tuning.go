@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/schollz/collidertracker/internal/tuning"
+)
+
+// LoadTunings reads the tunings of the user and of the project, the project ones
+// replacing user ones with the same name
+func (m *Model) LoadTunings() {
+	m.Tunings = tuning.LoadAll(m.TuningDir, tuning.ProjectDir(m.SaveFolder))
+}
+
+// TuningNameForTrack returns the name of the tuning a track plays in
+func (m *Model) TuningNameForTrack(track int) string {
+	name := m.Tuning
+	if track >= 0 && track < len(m.TrackTunings) && m.TrackTunings[track] != "" {
+		name = m.TrackTunings[track]
+	}
+	if name == "" {
+		return tuning.EqualTemperament
+	}
+	return name
+}
+
+// TuningForTrack returns the tuning a track plays in, or nil for 12-TET. A tuning whose
+// files are missing plays in 12-TET.
+func (m *Model) TuningForTrack(track int) *tuning.Tuning {
+	return m.Tunings[m.TuningNameForTrack(track)]
+}
+
+// ProjectScaleName returns the scale of the project, "all" if it has none
+func (m *Model) ProjectScaleName() string {
+	if m.ProjectScale == "" {
+		return "all"
+	}
+	return m.ProjectScale
+}
@@ -2,8 +2,11 @@ package modulation
 
 import (
 	"log"
+	"math"
 	"math/rand"
 	"time"
+
+	"github.com/schollz/collidertracker/internal/tuning"
 )
 
 // ModulateSettings represents the settings for a single modulation entry
@@ -19,60 +22,9 @@ type ModulateSettings struct {
 	Probability int    `json:"probability"` // Probability percentage: 0-100 (100 = always apply modulation)
 }
 
-// Scale represents a musical scale
-type Scale struct {
-	Name  string
-	Notes []int // MIDI note offsets within an octave (0-11)
-}
-
-// Predefined scales
-var Scales = map[string]Scale{
-	"all": {
-		Name:  "All Notes",
-		Notes: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
-	},
-	"major": {
-		Name:  "Major",
-		Notes: []int{0, 2, 4, 5, 7, 9, 11},
-	},
-	"minor": {
-		Name:  "Minor",
-		Notes: []int{0, 2, 3, 5, 7, 8, 10},
-	},
-	"dorian": {
-		Name:  "Dorian",
-		Notes: []int{0, 2, 3, 5, 7, 9, 10},
-	},
-	"mixolydian": {
-		Name:  "Mixolydian",
-		Notes: []int{0, 2, 4, 5, 7, 9, 10},
-	},
-	"pentatonic": {
-		Name:  "Pentatonic",
-		Notes: []int{0, 2, 4, 7, 9},
-	},
-	"blues": {
-		Name:  "Blues",
-		Notes: []int{0, 3, 5, 6, 7, 10},
-	},
-	"chromatic": {
-		Name:  "Chromatic",
-		Notes: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
-	},
-}
-
 // Note names for scale root selection
 var NoteNames = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
 
-// GetScaleNames returns a list of all available scale names
-func GetScaleNames() []string {
-	names := make([]string, 0, len(Scales))
-	for name := range Scales {
-		names = append(names, name)
-	}
-	return names
-}
-
 // GetNoteNames returns a list of all note names
 func GetNoteNames() []string {
 	return NoteNames
@@ -103,6 +55,12 @@ func ApplyIncrement(originalNote int, incrementCounter int, incrementValue int,
 
 // ApplyModulation applies modulation to a MIDI note value using the provided RNG
 func ApplyModulation(originalNote int, settings ModulateSettings, rng *rand.Rand) int {
+	return ApplyModulationInTuning(originalNote, settings, rng, nil)
+}
+
+// ApplyModulationInTuning applies modulation to a key of a track that plays in a tuning,
+// quantizing it to the keys whose pitches are closest to the scale (nil is 12-TET)
+func ApplyModulationInTuning(originalNote int, settings ModulateSettings, rng *rand.Rand, t *tuning.Tuning) int {
 	log.Printf("DEBUG: ApplyModulation - Seed=%d, IRandom=%d, Sub=%d, Add=%d, Probability=%d",
 		settings.Seed, settings.IRandom, settings.Sub, settings.Add, settings.Probability)
 
@@ -147,7 +105,11 @@ func ApplyModulation(originalNote int, settings ModulateSettings, rng *rand.Rand
 
 	// Step 4: Apply scale quantization if a scale is selected
 	if settings.Scale != "all" && settings.Scale != "" {
-		result = quantizeToScale(result, settings.Scale, settings.ScaleRoot)
+		if t != nil {
+			result = quantizeToTunedScale(result, settings.Scale, settings.ScaleRoot, t)
+		} else {
+			result = quantizeToScale(result, settings.Scale, settings.ScaleRoot)
+		}
 	}
 
 	return result
@@ -192,6 +154,48 @@ func quantizeToScale(note int, scaleName string, scaleRoot int) int {
 	return octave*12 + finalNote
 }
 
+// quantizeToTunedScale quantizes a key to the closest key whose degree is in the scale.
+// The notes of the scale are 12-TET pitches above the root, so each one picks the degree of
+// the tuning nearest to it, with the 12 semitones stretched over the period of the tuning.
+func quantizeToTunedScale(note int, scaleName string, scaleRoot int, t *tuning.Tuning) int {
+	scale, exists := Scales[scaleName]
+	if !exists {
+		return note
+	}
+
+	n := t.Size()
+	inScale := tunedScaleDegrees(scale, scaleRoot, t)
+
+	// Search outwards from the key, preferring the lower key on a tie like quantizeToScale
+	for distance := 0; distance <= 2*n; distance++ {
+		for _, key := range []int{note - distance, note + distance} {
+			if degree, ok := t.Degree(key); ok && inScale[((degree%n)+n)%n] {
+				return key
+			}
+		}
+	}
+	return note
+}
+
+// tunedScaleDegrees returns which degrees of a tuning are in a scale: for each note of the
+// scale, the degree closest to it
+func tunedScaleDegrees(scale Scale, scaleRoot int, t *tuning.Tuning) []bool {
+	n := t.Size()
+	period := t.DegreeCents(n)
+	inScale := make([]bool, n)
+	for _, scaleNote := range scale.Notes {
+		target := float64((scaleRoot+scaleNote)%12) * period / 12
+		closest, minDistance := 0, math.Inf(1)
+		for degree := 0; degree <= n; degree++ {
+			if distance := math.Abs(t.DegreeCents(degree) - target); distance < minDistance {
+				closest, minDistance = degree, distance
+			}
+		}
+		inScale[closest%n] = true
+	}
+	return inScale
+}
+
 // abs returns the absolute value of an integer
 func abs(x int) int {
 	if x < 0 {
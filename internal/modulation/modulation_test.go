@@ -2,7 +2,12 @@ package modulation
 
 import (
 	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/schollz/collidertracker/internal/tuning"
 )
 
 func TestNewModulateSettings(t *testing.T) {
@@ -243,24 +248,123 @@ func TestApplyModulationWithScaleRoot(t *testing.T) {
 func TestGetScaleNames(t *testing.T) {
 	names := GetScaleNames()
 
-	// Should include all defined scales
+	// The original scales come first, in their original order
 	expectedScales := []string{"all", "major", "minor", "dorian", "mixolydian", "pentatonic", "blues", "chromatic"}
+	if !slices.Equal(names[:len(expectedScales)], expectedScales) {
+		t.Errorf("Expected scale names to start with %v, got %v", expectedScales, names[:len(expectedScales)])
+	}
 
-	if len(names) != len(expectedScales) {
-		t.Errorf("Expected %d scale names, got %d", len(expectedScales), len(names))
+	// Modes, minor scales, symmetric scales and world scales follow
+	for _, expected := range []string{"phrygian", "lydian", "locrian", "harmonic-minor", "melodic-minor", "whole-tone", "diminished", "hirajoshi", "pelog", "double-harmonic"} {
+		if !slices.Contains(names, expected) {
+			t.Errorf("Scale '%s' not found in GetScaleNames()", expected)
+		}
 	}
 
-	// Check that all expected scales are present
-	for _, expected := range expectedScales {
-		found := false
-		for _, name := range names {
-			if name == expected {
-				found = true
-				break
+	// Every name has a scale of notes within the octave
+	for _, name := range names {
+		scale, exists := Scales[name]
+		if !exists {
+			t.Errorf("Scale '%s' has no notes", name)
+			continue
+		}
+		for _, note := range scale.Notes {
+			if note < 0 || note > 11 {
+				t.Errorf("Scale '%s' has note %d outside the octave", name, note)
 			}
 		}
-		if !found {
-			t.Errorf("Scale '%s' not found in GetScaleNames()", expected)
+	}
+}
+
+func TestLoadUserScales(t *testing.T) {
+	defer resetScales()
+	path := filepath.Join(t.TempDir(), UserScalesFile)
+	builtins := len(GetScaleNames())
+
+	count, err := LoadUserScales(path)
+	if err != nil || count != 0 {
+		t.Fatalf("Expected a missing file to load nothing, got %d, %v", count, err)
+	}
+
+	data := `{
+		"slendro": {"notes": [0, 2, 5, 7, 9]},
+		"major": {"name": "Major (wide)", "notes": [11, 0, 2, 4, 5, 7, 9, 9]},
+		"bad": {"notes": [0, 12]},
+		"project": {"notes": [0]}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	count, err = LoadUserScales(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 user scales, got %d", count)
+	}
+	names := GetScaleNames()
+	if len(names) != builtins+1 || names[len(names)-1] != "slendro" {
+		t.Errorf("Expected slendro to be added after the built-in scales, got %v", names[builtins:])
+	}
+	if got := Scales["major"].Notes; !slices.Equal(got, []int{0, 2, 4, 5, 7, 9, 11}) {
+		t.Errorf("Expected the user major scale to be sorted without duplicates, got %v", got)
+	}
+
+	// Loading again starts from the built-in scales
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadUserScales(path); err == nil {
+		t.Error("Expected an error for a broken file")
+	}
+	if _, exists := Scales["slendro"]; exists {
+		t.Error("Expected user scales to be removed when loading again")
+	}
+}
+
+func TestInScale(t *testing.T) {
+	tests := []struct {
+		key       int
+		scale     string
+		scaleRoot int
+		expected  bool
+	}{
+		{60, "major", 0, true},
+		{61, "major", 0, false},
+		{61, "major", 2, true}, // C# in D major
+		{66, "whole-tone", 0, true},
+		{65, "whole-tone", 0, false},
+		{61, "all", 0, true},
+		{61, "missing", 0, true},
+	}
+	for _, tt := range tests {
+		if got := InScale(tt.key, tt.scale, tt.scaleRoot, nil); got != tt.expected {
+			t.Errorf("InScale(%d, %s, %d) = %v, expected %v", tt.key, tt.scale, tt.scaleRoot, got, tt.expected)
+		}
+	}
+}
+
+func TestStepInScale(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       int
+		steps     int
+		scale     string
+		scaleRoot int
+		expected  int
+	}{
+		{"up a note of the scale", 64, 1, "major", 0, 65},
+		{"up a whole step", 60, 1, "major", 0, 62},
+		{"down across the octave", 60, -2, "major", 0, 57},
+		{"from outside the scale", 61, 1, "major", 0, 62},
+		{"pentatonic in A", 57, 2, "pentatonic", 9, 61},
+		{"stops at the top", 126, 3, "major", 0, 127},
+		{"stops at the bottom", 1, -3, "minor", 0, 0},
+		{"first note of the scale", -1, 1, "major", 2, 1},
+	}
+	for _, tt := range tests {
+		if got := StepInScale(tt.key, tt.steps, tt.scale, tt.scaleRoot, nil); got != tt.expected {
+			t.Errorf("%s: StepInScale(%d, %d) = %d, expected %d", tt.name, tt.key, tt.steps, got, tt.expected)
 		}
 	}
 }
@@ -304,6 +408,56 @@ func TestQuantizeToScale(t *testing.T) {
 	}
 }
 
+func TestQuantizeToTunedScale(t *testing.T) {
+	// equalDivisions splits the octave into n equal steps, with degree 0 on c-4
+	equalDivisions := func(n int) *tuning.Tuning {
+		scale := tuning.Scale{}
+		for i := 1; i <= n; i++ {
+			scale.Cents = append(scale.Cents, 1200*float64(i)/float64(n))
+		}
+		return &tuning.Tuning{Scale: scale, Mapping: tuning.DefaultKeyboardMapping()}
+	}
+
+	testCases := []struct {
+		name     string
+		tuning   *tuning.Tuning
+		input    int
+		scale    string
+		root     int
+		expected int
+	}{
+		{"12-TET in scale", equalDivisions(12), 64, "major", 0, 64},
+		{"12-TET below the octave", equalDivisions(12), 58, "major", 0, 57},
+		{"12-TET root", equalDivisions(12), 61, "major", 2, 61},
+		{"19-EDO in scale", equalDivisions(19), 63, "major", 0, 63},        // Degree 3 is the major second
+		{"19-EDO between degrees", equalDivisions(19), 61, "major", 0, 60}, // Degree 1 -> degree 0
+		{"19-EDO next degree", equalDivisions(19), 62, "major", 0, 63},     // Degree 2 -> degree 3
+		{"19-EDO next period", equalDivisions(19), 80, "major", 0, 79},     // Degree 1 above -> degree 0 above
+		{"19-EDO pentatonic", equalDivisions(19), 67, "pentatonic", 0, 66}, // Degree 7 -> degree 6 (major third)
+		{"19-EDO root on D", equalDivisions(19), 61, "major", 2, 62},       // D major has C# (degree 2)
+		{"19-EDO unknown scale", equalDivisions(19), 61, "unknown", 0, 61}, // Notes stay as they are
+	}
+
+	for _, tc := range testCases {
+		result := quantizeToTunedScale(tc.input, tc.scale, tc.root, tc.tuning)
+		if result != tc.expected {
+			t.Errorf("%s: quantizeToTunedScale(%d, %s, %d) = %d, expected %d",
+				tc.name, tc.input, tc.scale, tc.root, result, tc.expected)
+		}
+	}
+
+	// Without a tuning, modulation quantizes in 12-TET as before
+	rng := rand.New(rand.NewSource(1))
+	settings := NewModulateSettings()
+	settings.Scale = "major"
+	if result := ApplyModulationInTuning(61, settings, rng, nil); result != 60 {
+		t.Errorf("ApplyModulationInTuning(61) without a tuning = %d, expected 60", result)
+	}
+	if result := ApplyModulationInTuning(62, settings, rng, equalDivisions(19)); result != 63 {
+		t.Errorf("ApplyModulationInTuning(62) in 19-EDO = %d, expected 63", result)
+	}
+}
+
 func TestSeedBehavior(t *testing.T) {
 	// Test that Seed=0 is treated as "random" (time seeding), not fixed seed
 	settings0 := ModulateSettings{
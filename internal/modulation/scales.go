@@ -0,0 +1,225 @@
+package modulation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/schollz/collidertracker/internal/tuning"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// ProjectScale is the scale setting that follows the key and scale of the project
+const ProjectScale = "project"
+
+// UserScalesFile is the name of the file with user-defined scales in the config folder
+const UserScalesFile = "scales.json"
+
+// Scale represents a musical scale
+type Scale struct {
+	Name  string `json:"name"`
+	Notes []int  `json:"notes"` // MIDI note offsets within an octave (0-11)
+}
+
+// builtinScales is the scale library, in the order the scale settings step through it
+var builtinScales = []struct {
+	key   string
+	scale Scale
+}{
+	{"all", Scale{"All Notes", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}}},
+	{"major", Scale{"Major", []int{0, 2, 4, 5, 7, 9, 11}}},
+	{"minor", Scale{"Minor", []int{0, 2, 3, 5, 7, 8, 10}}},
+	{"dorian", Scale{"Dorian", []int{0, 2, 3, 5, 7, 9, 10}}},
+	{"mixolydian", Scale{"Mixolydian", []int{0, 2, 4, 5, 7, 9, 10}}},
+	{"pentatonic", Scale{"Pentatonic", []int{0, 2, 4, 7, 9}}},
+	{"blues", Scale{"Blues", []int{0, 3, 5, 6, 7, 10}}},
+	{"chromatic", Scale{"Chromatic", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}}},
+	// The other modes of the major scale
+	{"phrygian", Scale{"Phrygian", []int{0, 1, 3, 5, 7, 8, 10}}},
+	{"lydian", Scale{"Lydian", []int{0, 2, 4, 6, 7, 9, 11}}},
+	{"locrian", Scale{"Locrian", []int{0, 1, 3, 5, 6, 8, 10}}},
+	// Minor and major variants and their modes
+	{"harmonic-minor", Scale{"Harmonic Minor", []int{0, 2, 3, 5, 7, 8, 11}}},
+	{"melodic-minor", Scale{"Melodic Minor", []int{0, 2, 3, 5, 7, 9, 11}}},
+	{"harmonic-major", Scale{"Harmonic Major", []int{0, 2, 4, 5, 7, 8, 11}}},
+	{"phrygian-dominant", Scale{"Phrygian Dominant", []int{0, 1, 4, 5, 7, 8, 10}}},
+	{"lydian-dominant", Scale{"Lydian Dominant", []int{0, 2, 4, 6, 7, 9, 10}}},
+	{"altered", Scale{"Altered", []int{0, 1, 3, 4, 6, 8, 10}}},
+	{"minor-pentatonic", Scale{"Minor Pentatonic", []int{0, 3, 5, 7, 10}}},
+	{"major-blues", Scale{"Major Blues", []int{0, 2, 3, 4, 7, 9}}},
+	// Symmetric scales
+	{"whole-tone", Scale{"Whole Tone", []int{0, 2, 4, 6, 8, 10}}},
+	{"diminished", Scale{"Diminished (whole-half)", []int{0, 2, 3, 5, 6, 8, 9, 11}}},
+	{"diminished-hw", Scale{"Diminished (half-whole)", []int{0, 1, 3, 4, 6, 7, 9, 10}}},
+	{"augmented", Scale{"Augmented", []int{0, 3, 4, 7, 8, 11}}},
+	// Jazz and synthetic scales
+	{"bebop-dominant", Scale{"Bebop Dominant", []int{0, 2, 4, 5, 7, 9, 10, 11}}},
+	{"bebop-major", Scale{"Bebop Major", []int{0, 2, 4, 5, 7, 8, 9, 11}}},
+	{"prometheus", Scale{"Prometheus", []int{0, 2, 4, 6, 9, 10}}},
+	{"enigmatic", Scale{"Enigmatic", []int{0, 1, 4, 6, 8, 10, 11}}},
+	{"neapolitan-major", Scale{"Neapolitan Major", []int{0, 1, 3, 5, 7, 9, 11}}},
+	{"neapolitan-minor", Scale{"Neapolitan Minor", []int{0, 1, 3, 5, 7, 8, 11}}},
+	// World scales, as they are commonly approximated in 12-TET
+	{"hirajoshi", Scale{"Hirajoshi", []int{0, 2, 3, 7, 8}}},
+	{"in-sen", Scale{"In Sen", []int{0, 1, 5, 7, 10}}},
+	{"iwato", Scale{"Iwato", []int{0, 1, 5, 6, 10}}},
+	{"kumoi", Scale{"Kumoi", []int{0, 2, 3, 7, 9}}},
+	{"yo", Scale{"Yo", []int{0, 2, 5, 7, 9}}},
+	{"pelog", Scale{"Pelog", []int{0, 1, 3, 7, 8}}},
+	{"egyptian", Scale{"Egyptian", []int{0, 2, 5, 7, 10}}},
+	{"chinese", Scale{"Chinese", []int{0, 4, 6, 7, 11}}},
+	{"hungarian-minor", Scale{"Hungarian Minor", []int{0, 2, 3, 6, 7, 8, 11}}},
+	{"hungarian-major", Scale{"Hungarian Major", []int{0, 3, 4, 6, 7, 9, 10}}},
+	{"double-harmonic", Scale{"Double Harmonic", []int{0, 1, 4, 5, 7, 8, 11}}},
+	{"persian", Scale{"Persian", []int{0, 1, 4, 5, 6, 8, 11}}},
+	{"romanian-minor", Scale{"Romanian Minor", []int{0, 2, 3, 6, 7, 9, 10}}},
+	{"spanish", Scale{"Spanish 8-tone", []int{0, 1, 3, 4, 5, 6, 8, 10}}},
+	{"oriental", Scale{"Oriental", []int{0, 1, 4, 5, 6, 9, 10}}},
+	{"todi", Scale{"Raga Todi", []int{0, 1, 3, 6, 7, 8, 11}}},
+	{"marva", Scale{"Raga Marva", []int{0, 1, 4, 6, 7, 9, 11}}},
+	{"purvi", Scale{"Raga Purvi", []int{0, 1, 4, 6, 7, 8, 11}}},
+}
+
+// Scales holds the built-in and user-defined scales by name
+var Scales map[string]Scale
+
+// scaleOrder lists the names of Scales: the library first, then user scales by name
+var scaleOrder []string
+
+func init() {
+	resetScales()
+}
+
+// resetScales drops the user-defined scales
+func resetScales() {
+	Scales = make(map[string]Scale, len(builtinScales))
+	scaleOrder = scaleOrder[:0]
+	for _, builtin := range builtinScales {
+		Scales[builtin.key] = builtin.scale
+		scaleOrder = append(scaleOrder, builtin.key)
+	}
+}
+
+// GetScaleNames returns a list of all available scale names
+func GetScaleNames() []string {
+	return slices.Clone(scaleOrder)
+}
+
+// UserScalesPath returns the file with the user-defined scales, e.g.
+// ~/.config/collidertracker/scales.json
+func UserScalesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", UserScalesFile)
+}
+
+// validateScale sorts the notes of a scale and checks that they are within an octave
+func validateScale(scale *Scale) error {
+	notes := slices.Clone(scale.Notes)
+	slices.Sort(notes)
+	notes = slices.Compact(notes)
+	if len(notes) == 0 {
+		return fmt.Errorf("no notes")
+	}
+	if notes[0] < 0 || notes[len(notes)-1] > 11 {
+		return fmt.Errorf("notes must be between 0 and 11")
+	}
+	scale.Notes = notes
+	return nil
+}
+
+// LoadUserScales replaces the user-defined scales with the ones in a JSON file that maps
+// names to scales, e.g.
+//
+//	{"slendro": {"name": "Slendro", "notes": [0, 2, 5, 7, 9]}}
+//
+// A user scale with the name of a built-in one replaces it. It returns the number of
+// scales loaded; a missing file loads none.
+func LoadUserScales(path string) (int, error) {
+	resetScales()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var userScales map[string]Scale
+	if err := json.Unmarshal(data, &userScales); err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(userScales))
+	for name := range userScales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	count := 0
+	for _, name := range names {
+		scale := userScales[name]
+		if name == "" || name == ProjectScale {
+			log.Printf("Skipping user scale %q: the name is reserved", name)
+			continue
+		}
+		if err := validateScale(&scale); err != nil {
+			log.Printf("Skipping user scale %q: %v", name, err)
+			continue
+		}
+		if scale.Name == "" {
+			scale.Name = name
+		}
+		if _, exists := Scales[name]; !exists {
+			scaleOrder = append(scaleOrder, name)
+		}
+		Scales[name] = scale
+		count++
+	}
+	return count, nil
+}
+
+// InScale reports whether a key is in a scale. In a tuning (nil is 12-TET) the scale is
+// made of the degrees closest to its notes, as scale quantization picks them. Every key
+// is in "all" and in scales that do not exist.
+func InScale(key int, scaleName string, scaleRoot int, t *tuning.Tuning) bool {
+	scale, exists := Scales[scaleName]
+	if !exists {
+		return true
+	}
+	if t == nil {
+		return slices.Contains(scale.Notes, ((key-scaleRoot)%12+12)%12)
+	}
+	degree, ok := t.Degree(key)
+	if !ok {
+		return false
+	}
+	n := t.Size()
+	return tunedScaleDegrees(scale, scaleRoot, t)[((degree%n)+n)%n]
+}
+
+// StepInScale moves a key by a number of notes of a scale, keeping within 0-127. A key
+// outside the scale moves to the next note of the scale in the direction of the step.
+func StepInScale(key, steps int, scaleName string, scaleRoot int, t *tuning.Tuning) int {
+	direction := 1
+	if steps < 0 {
+		direction, steps = -1, -steps
+	}
+	for range steps {
+		next := key + direction
+		for next >= 0 && next <= 127 && !InScale(next, scaleName, scaleRoot, t) {
+			next += direction
+		}
+		if next < 0 || next > 127 {
+			break
+		}
+		key = next
+	}
+	return key
+}
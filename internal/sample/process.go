@@ -0,0 +1,215 @@
+package sample
+
+import "math"
+
+// resampleTaps is the half-width of the resampling filter in zero crossings
+const resampleTaps = 16
+
+// DBToGain converts decibels to a linear gain.
+func DBToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// Peak returns the absolute peak level across all channels.
+func (b *Buffer) Peak() float64 {
+	peak := 0.0
+	for ch := range b.Data {
+		for _, v := range b.Data[ch] {
+			peak = math.Max(peak, math.Abs(v))
+		}
+	}
+	return peak
+}
+
+// RMS returns the RMS level across all channels.
+func (b *Buffer) RMS() float64 {
+	sum := 0.0
+	n := 0
+	for ch := range b.Data {
+		for _, v := range b.Data[ch] {
+			sum += v * v
+		}
+		n += len(b.Data[ch])
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// Gain multiplies every sample by g.
+func (b *Buffer) Gain(g float64) {
+	for ch := range b.Data {
+		for i := range b.Data[ch] {
+			b.Data[ch][i] *= g
+		}
+	}
+}
+
+// NormalizePeak scales the buffer so that its peak reaches targetDB (dBFS) and
+// returns the gain applied. Silent buffers are left alone.
+func (b *Buffer) NormalizePeak(targetDB float64) float64 {
+	peak := b.Peak()
+	if peak == 0 {
+		return 1
+	}
+	g := DBToGain(targetDB) / peak
+	b.Gain(g)
+	return g
+}
+
+// NormalizeRMS scales the buffer so that its RMS level reaches targetDB (dBFS),
+// without letting the peak go above 0 dBFS, and returns the gain applied.
+func (b *Buffer) NormalizeRMS(targetDB float64) float64 {
+	rms := b.RMS()
+	if rms == 0 {
+		return 1
+	}
+	g := DBToGain(targetDB) / rms
+	if peak := b.Peak(); peak*g > 1 {
+		g = 1 / peak
+	}
+	b.Gain(g)
+	return g
+}
+
+// SilenceBounds returns the frame range [start, end) outside of which every
+// channel stays below thresholdDB. A silent buffer returns an empty range.
+func (b *Buffer) SilenceBounds(thresholdDB float64) (int, int) {
+	threshold := DBToGain(thresholdDB)
+	loud := func(i int) bool {
+		for ch := range b.Data {
+			if math.Abs(b.Data[ch][i]) > threshold {
+				return true
+			}
+		}
+		return false
+	}
+
+	frames := b.Frames()
+	start := 0
+	for start < frames && !loud(start) {
+		start++
+	}
+	end := frames
+	for end > start && !loud(end-1) {
+		end--
+	}
+	return start, end
+}
+
+// Crop keeps the frames [start, end).
+func (b *Buffer) Crop(start, end int) {
+	frames := b.Frames()
+	start = max(0, min(start, frames))
+	end = max(start, min(end, frames))
+	for ch := range b.Data {
+		b.Data[ch] = append([]float64(nil), b.Data[ch][start:end]...)
+	}
+}
+
+// FadeIn ramps the first n frames up from silence.
+func (b *Buffer) FadeIn(n int) {
+	n = min(n, b.Frames())
+	for ch := range b.Data {
+		for i := 0; i < n; i++ {
+			b.Data[ch][i] *= float64(i) / float64(n)
+		}
+	}
+}
+
+// FadeOut ramps the last n frames down to silence.
+func (b *Buffer) FadeOut(n int) {
+	frames := b.Frames()
+	n = min(n, frames)
+	for ch := range b.Data {
+		for i := 0; i < n; i++ {
+			b.Data[ch][frames-1-i] *= float64(i) / float64(n)
+		}
+	}
+}
+
+// Reverse plays the buffer backwards.
+func (b *Buffer) Reverse() {
+	for ch := range b.Data {
+		data := b.Data[ch]
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+}
+
+// ToMono mixes all channels down to one.
+func (b *Buffer) ToMono() {
+	if b.NumChannels <= 1 {
+		return
+	}
+	mono := make([]float64, b.Frames())
+	for ch := range b.Data {
+		for i, v := range b.Data[ch] {
+			mono[i] += v / float64(b.NumChannels)
+		}
+	}
+	b.Data = [][]float64{mono}
+	b.NumChannels = 1
+}
+
+// ToStereo copies a mono buffer to both channels, or keeps the first two
+// channels of a buffer with more.
+func (b *Buffer) ToStereo() {
+	switch {
+	case b.NumChannels == 1:
+		b.Data = [][]float64{b.Data[0], append([]float64(nil), b.Data[0]...)}
+	case b.NumChannels > 2:
+		b.Data = b.Data[:2]
+	default:
+		return
+	}
+	b.NumChannels = 2
+}
+
+// Resample converts the buffer to a new sample rate with a windowed-sinc filter,
+// which also removes content above the new Nyquist frequency when downsampling.
+func (b *Buffer) Resample(rate int) {
+	if rate <= 0 || rate == b.SampleRate || b.SampleRate <= 0 {
+		return
+	}
+	ratio := float64(rate) / float64(b.SampleRate)
+	cutoff := math.Min(1, ratio)
+	halfWidth := float64(resampleTaps) / cutoff
+	frames := b.Frames()
+	outFrames := int(math.Round(float64(frames) * ratio))
+
+	for ch := range b.Data {
+		in := b.Data[ch]
+		out := make([]float64, outFrames)
+		for i := range out {
+			t := float64(i) / ratio
+			first := max(0, int(math.Ceil(t-halfWidth)))
+			last := min(frames-1, int(math.Floor(t+halfWidth)))
+			sum := 0.0
+			for k := first; k <= last; k++ {
+				x := t - float64(k)
+				sum += in[k] * cutoff * sinc(cutoff*x) * blackman(x/halfWidth)
+			}
+			out[i] = sum
+		}
+		b.Data[ch] = out
+	}
+	b.SampleRate = rate
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman is the Blackman window over [-1, 1]
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
@@ -0,0 +1,217 @@
+package sample
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// Buffer holds decoded PCM audio as floats in [-1,1], one slice per channel.
+type Buffer struct {
+	SampleRate  int
+	NumChannels int
+	BitDepth    int
+	Data        [][]float64 // [channel][frame]
+}
+
+// WAV format codes of the fmt chunk
+const (
+	wavFormatPCM        = 1
+	wavFormatExtensible = 0xFFFE // The format is the sub format of the extension
+)
+
+// wavSubFormat returns the format code of the sub format GUID of a WAVE_FORMAT_EXTENSIBLE file
+func wavSubFormat(r io.ReaderAt) (int, error) {
+	header := make([]byte, 8)
+	for offset := int64(12); ; {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return 0, fmt.Errorf("no fmt chunk: %w", err)
+		}
+		size := int64(binary.LittleEndian.Uint32(header[4:]))
+		if string(header[:4]) == "fmt " {
+			if size < 26 {
+				return 0, fmt.Errorf("fmt chunk too short for an extensible format")
+			}
+			code := make([]byte, 2)
+			if _, err := r.ReadAt(code, offset+8+24); err != nil {
+				return 0, fmt.Errorf("read sub format: %w", err)
+			}
+			return int(binary.LittleEndian.Uint16(code)), nil
+		}
+		offset += 8 + size + size%2
+	}
+}
+
+// Load decodes an integer PCM WAV file into a Buffer. Other formats, e.g. 32-bit float, are
+// refused rather than decoded as noise.
+func Load(filename string) (*Buffer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	d := wav.NewDecoder(f)
+	if !d.IsValidFile() {
+		return nil, fmt.Errorf("invalid WAV file: %s", filename)
+	}
+	format := int(d.WavAudioFormat)
+	if format == wavFormatExtensible {
+		var err error
+		if format, err = wavSubFormat(f); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	if format != wavFormatPCM {
+		return nil, fmt.Errorf("only integer PCM WAV files are supported (format %d): %s", format, filename)
+	}
+
+	pcm, err := d.FullPCMBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("decode PCM: %w", err)
+	}
+	if pcm == nil || pcm.Format == nil || pcm.Format.NumChannels <= 0 {
+		return nil, fmt.Errorf("no PCM data: %s", filename)
+	}
+
+	chans := pcm.Format.NumChannels
+	bitDepth := int(d.BitDepth)
+	if bitDepth <= 0 {
+		bitDepth = 16
+	}
+	scale := math.Pow(2, float64(bitDepth-1))
+
+	frames := len(pcm.Data) / chans
+	data := make([][]float64, chans)
+	for ch := range data {
+		data[ch] = make([]float64, frames)
+	}
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < chans; ch++ {
+			data[ch][i] = float64(pcm.Data[i*chans+ch]) / scale
+		}
+	}
+
+	return &Buffer{
+		SampleRate:  pcm.Format.SampleRate,
+		NumChannels: chans,
+		BitDepth:    bitDepth,
+		Data:        data,
+	}, nil
+}
+
+// Save encodes the buffer as a PCM WAV file at its bit depth (at least 16 bits).
+// The file is written next to the target and renamed over it, so a failed write
+// never leaves a half-written sample behind.
+func (b *Buffer) Save(filename string) error {
+	if b == nil || b.NumChannels <= 0 || len(b.Data) != b.NumChannels {
+		return fmt.Errorf("no audio to save")
+	}
+	bitDepth := b.BitDepth
+	if bitDepth < 16 {
+		bitDepth = 16
+	} else if bitDepth > 32 {
+		bitDepth = 32
+	}
+	scale := math.Pow(2, float64(bitDepth-1))
+
+	frames := b.Frames()
+	data := make([]int, frames*b.NumChannels)
+	for i := 0; i < frames; i++ {
+		for ch := 0; ch < b.NumChannels; ch++ {
+			v := math.Round(b.Data[ch][i] * scale)
+			data[i*b.NumChannels+ch] = int(math.Max(-scale, math.Min(scale-1, v)))
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".tmp-*.wav")
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := wav.NewEncoder(tmp, b.SampleRate, bitDepth, b.NumChannels, 1)
+	err = enc.Write(&audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: b.NumChannels, SampleRate: b.SampleRate},
+		Data:           data,
+		SourceBitDepth: bitDepth,
+	})
+	if err == nil {
+		err = enc.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
+
+// Frames returns the number of frames (samples per channel) in the buffer.
+func (b *Buffer) Frames() int {
+	if b == nil || len(b.Data) == 0 {
+		return 0
+	}
+	return len(b.Data[0])
+}
+
+// Duration returns the length of the buffer in seconds.
+func (b *Buffer) Duration() float64 {
+	if b == nil || b.SampleRate == 0 {
+		return 0
+	}
+	return float64(b.Frames()) / float64(b.SampleRate)
+}
+
+// Peaks returns the absolute peak level (0-1) across all channels for n equal
+// buckets spanning the whole buffer.
+func (b *Buffer) Peaks(n int) []float64 {
+	frames := b.Frames()
+	if n <= 0 || frames == 0 {
+		return nil
+	}
+
+	peaks := make([]float64, n)
+	for i := 0; i < n; i++ {
+		start := i * frames / n
+		end := (i + 1) * frames / n
+		if end <= start {
+			end = start + 1
+		}
+		if end > frames {
+			end = frames
+		}
+		peak := 0.0
+		for ch := range b.Data {
+			for _, v := range b.Data[ch][start:end] {
+				if a := math.Abs(v); a > peak {
+					peak = a
+				}
+			}
+		}
+		if peak > 1 {
+			peak = 1
+		}
+		peaks[i] = peak
+	}
+	return peaks
+}
+
+// LoadPeaks decodes a WAV file and returns its peak envelope with n buckets.
+func LoadPeaks(filename string, n int) ([]float64, error) {
+	b, err := Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	return b.Peaks(n), nil
+}
@@ -0,0 +1,250 @@
+package sample
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestWav writes a mono 16-bit WAV with the given samples.
+func writeTestWav(t *testing.T, samples []int) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "test.wav")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	defer f.Close()
+
+	enc := wav.NewEncoder(f, 44100, 16, 1, 1)
+	buf := &audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: 1, SampleRate: 44100},
+		Data:           samples,
+		SourceBitDepth: 16,
+	}
+	require.NoError(t, enc.Write(buf))
+	require.NoError(t, enc.Close())
+	return filename
+}
+
+func TestLoad(t *testing.T) {
+	filename := writeTestWav(t, []int{0, 16384, -16384, 32767, 0, 0, 0, 0})
+
+	b, err := Load(filename)
+	require.NoError(t, err)
+	assert.Equal(t, 44100, b.SampleRate)
+	assert.Equal(t, 1, b.NumChannels)
+	assert.Equal(t, 8, b.Frames())
+	assert.InDelta(t, 0.5, b.Data[0][1], 0.001)
+	assert.InDelta(t, -0.5, b.Data[0][2], 0.001)
+	assert.InDelta(t, 1.0, b.Data[0][3], 0.001)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bad.wav")
+	require.NoError(t, os.WriteFile(filename, []byte("not a wav"), 0644))
+
+	_, err := Load(filename)
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.wav"))
+	assert.Error(t, err)
+}
+
+func TestLoadFormats(t *testing.T) {
+	// 32-bit float
+	filename := filepath.Join(t.TempDir(), "float.wav")
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	enc := wav.NewEncoder(f, 44100, 32, 1, 3)
+	require.NoError(t, enc.Write(&audio.IntBuffer{
+		Format:         &audio.Format{NumChannels: 1, SampleRate: 44100},
+		Data:           []int{0, 1, 2, 3},
+		SourceBitDepth: 32,
+	}))
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+	_, err = Load(filename)
+	assert.ErrorContains(t, err, "format 3")
+
+	// WAVE_FORMAT_EXTENSIBLE is read by the format of its extension
+	for subFormat, ok := range map[byte]bool{1: true, 3: false} {
+		filename := writeExtensibleWav(t, subFormat)
+		b, err := Load(filename)
+		if ok {
+			require.NoError(t, err)
+			assert.Equal(t, 2, b.Frames())
+			assert.InDelta(t, 0.5, b.Data[0][1], 0.001)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+}
+
+// writeExtensibleWav writes a mono 16-bit WAVE_FORMAT_EXTENSIBLE file with a sub format
+func writeExtensibleWav(t *testing.T, subFormat byte) string {
+	t.Helper()
+	le := func(v uint32, n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(v >> (8 * i))
+		}
+		return b
+	}
+	var fmtChunk []byte
+	fmtChunk = append(fmtChunk, le(0xFFFE, 2)...)
+	fmtChunk = append(fmtChunk, le(1, 2)...)     // Channels
+	fmtChunk = append(fmtChunk, le(44100, 4)...) // Sample rate
+	fmtChunk = append(fmtChunk, le(88200, 4)...) // Bytes per second
+	fmtChunk = append(fmtChunk, le(2, 2)...)     // Block align
+	fmtChunk = append(fmtChunk, le(16, 2)...)    // Bits per sample
+	fmtChunk = append(fmtChunk, le(22, 2)...)    // Extension size
+	fmtChunk = append(fmtChunk, le(16, 2)...)    // Valid bits
+	fmtChunk = append(fmtChunk, le(4, 4)...)     // Channel mask
+	fmtChunk = append(fmtChunk, subFormat, 0)    // Sub format GUID
+	fmtChunk = append(fmtChunk, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71)
+	data := append(le(0, 2), le(16384, 2)...)
+
+	var file []byte
+	file = append(file, "RIFF"...)
+	file = append(file, le(uint32(4+8+len(fmtChunk)+8+len(data)), 4)...)
+	file = append(file, "WAVE"...)
+	file = append(file, "fmt "...)
+	file = append(file, le(uint32(len(fmtChunk)), 4)...)
+	file = append(file, fmtChunk...)
+	file = append(file, "data"...)
+	file = append(file, le(uint32(len(data)), 4)...)
+	file = append(file, data...)
+
+	filename := filepath.Join(t.TempDir(), "extensible.wav")
+	require.NoError(t, os.WriteFile(filename, file, 0644))
+	return filename
+}
+
+func TestPeaks(t *testing.T) {
+	filename := writeTestWav(t, []int{0, 16384, -16384, 32767, 0, 0, 0, 8192})
+
+	peaks, err := LoadPeaks(filename, 4)
+	require.NoError(t, err)
+	require.Len(t, peaks, 4)
+	assert.InDelta(t, 0.5, peaks[0], 0.001)
+	assert.InDelta(t, 1.0, peaks[1], 0.001)
+	assert.InDelta(t, 0.0, peaks[2], 0.001)
+	assert.InDelta(t, 0.25, peaks[3], 0.001)
+
+	// More buckets than frames still yields one value per bucket
+	peaks, err = LoadPeaks(filename, 16)
+	require.NoError(t, err)
+	assert.Len(t, peaks, 16)
+
+	var empty *Buffer
+	assert.Nil(t, empty.Peaks(4))
+}
+
+func TestSave(t *testing.T) {
+	b := &Buffer{SampleRate: 22050, NumChannels: 2, BitDepth: 24, Data: [][]float64{{0, 0.5, -0.5, 2}, {0.25, 0, 0, -2}}}
+	filename := filepath.Join(t.TempDir(), "out.wav")
+	require.NoError(t, b.Save(filename))
+
+	loaded, err := Load(filename)
+	require.NoError(t, err)
+	assert.Equal(t, 22050, loaded.SampleRate)
+	assert.Equal(t, 2, loaded.NumChannels)
+	assert.Equal(t, 24, loaded.BitDepth)
+	require.Equal(t, 4, loaded.Frames())
+	assert.InDelta(t, 0.5, loaded.Data[0][1], 0.0001)
+	assert.InDelta(t, 0.25, loaded.Data[1][0], 0.0001)
+	// Out of range samples are clipped
+	assert.InDelta(t, 1.0, loaded.Data[0][3], 0.0001)
+	assert.InDelta(t, -1.0, loaded.Data[1][3], 0.0001)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestNormalize(t *testing.T) {
+	b := &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{{0.1, -0.25, 0.2}}}
+	b.NormalizePeak(-6)
+	assert.InDelta(t, DBToGain(-6), b.Peak(), 1e-9)
+
+	b = &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{{0.1, -0.1, 0.1, -0.1}}}
+	b.NormalizeRMS(-20)
+	assert.InDelta(t, 0.1, b.RMS(), 1e-9)
+	// The RMS target never pushes the peak over 0 dBFS
+	b = &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{{0.5, 0, 0, 0}}}
+	b.NormalizeRMS(-1)
+	assert.InDelta(t, 1.0, b.Peak(), 1e-9)
+
+	silent := &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{{0, 0}}}
+	assert.Equal(t, 1.0, silent.NormalizePeak(0))
+}
+
+func TestTrimFadeReverse(t *testing.T) {
+	b := &Buffer{SampleRate: 1000, NumChannels: 2, Data: [][]float64{
+		{0, 0.0001, 0.5, 1, 0.5, 0, 0},
+		{0, 0, 0, 0, 0, 0.3, 0},
+	}}
+	start, end := b.SilenceBounds(-60)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 6, end)
+	b.Crop(start, end)
+	assert.Equal(t, []float64{0.5, 1, 0.5, 0}, b.Data[0])
+	assert.Equal(t, []float64{0, 0, 0, 0.3}, b.Data[1])
+
+	b.Reverse()
+	assert.Equal(t, []float64{0, 0.5, 1, 0.5}, b.Data[0])
+
+	b.Data = [][]float64{{1, 1, 1, 1}, {1, 1, 1, 1}}
+	b.FadeIn(2)
+	b.FadeOut(2)
+	assert.Equal(t, []float64{0, 0.5, 0.5, 0}, b.Data[1])
+}
+
+func TestChannels(t *testing.T) {
+	b := &Buffer{SampleRate: 1000, NumChannels: 2, Data: [][]float64{{1, 0.5}, {0, 0.5}}}
+	b.ToMono()
+	assert.Equal(t, 1, b.NumChannels)
+	assert.Equal(t, [][]float64{{0.5, 0.5}}, b.Data)
+
+	b.ToStereo()
+	assert.Equal(t, 2, b.NumChannels)
+	assert.Equal(t, [][]float64{{0.5, 0.5}, {0.5, 0.5}}, b.Data)
+	b.Data[1][0] = 0
+	assert.Equal(t, 0.5, b.Data[0][0], "channels do not share memory")
+}
+
+func TestResample(t *testing.T) {
+	// One second of a 440 Hz tone
+	tone := func(rate int) []float64 {
+		data := make([]float64, rate)
+		for i := range data {
+			data[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/float64(rate))
+		}
+		return data
+	}
+	b := &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{tone(44100)}}
+	b.Resample(48000)
+	assert.Equal(t, 48000, b.SampleRate)
+	require.Equal(t, 48000, b.Frames())
+
+	// Away from the edges the result matches the tone generated at the new rate
+	want := tone(48000)
+	for i := 1000; i < 47000; i += 997 {
+		assert.InDelta(t, want[i], b.Data[0][i], 0.005, "frame %d", i)
+	}
+
+	// Downsampling removes content above the new Nyquist frequency
+	high := &Buffer{SampleRate: 44100, NumChannels: 1, Data: [][]float64{make([]float64, 4410)}}
+	for i := range high.Data[0] {
+		high.Data[0][i] = 0.5 * math.Sin(2*math.Pi*15000*float64(i)/44100)
+	}
+	high.Resample(22050)
+	assert.Less(t, math.Abs(high.Data[0][1000]), 0.01)
+	assert.Less(t, high.RMS(), 0.05)
+}
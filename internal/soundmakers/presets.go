@@ -0,0 +1,145 @@
+package soundmakers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/supercollider"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// PresetFolder is the name of the preset folder in the config folder
+const PresetFolder = "presets"
+
+// Preset is a named set of parameter values for one SoundMaker. Presets are kept as
+// indented JSON files, one folder per SoundMaker, so that they can be shared with git.
+type Preset struct {
+	Name       string             `json:"name"`
+	SoundMaker string             `json:"soundMaker"`
+	Parameters map[string]float32 `json:"parameters"`
+	Path       string             `json:"-"` // File the preset was loaded from
+}
+
+// PresetDir returns the preset library shared by all projects, e.g.
+// ~/.config/collidertracker/presets
+func PresetDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", PresetFolder)
+}
+
+// presetFileName turns a preset name into a file name that is safe on every platform
+func presetFileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	stem := strings.Trim(b.String(), " .")
+	if stem == "" {
+		stem = "preset"
+	}
+	return stem + ".json"
+}
+
+// presetFolder returns the folder with the presets of a SoundMaker
+func presetFolder(dir, soundMaker string) string {
+	return filepath.Join(dir, strings.TrimSuffix(presetFileName(soundMaker), ".json"))
+}
+
+// LoadPresets reads the presets of a SoundMaker, sorted by name. A missing folder has no
+// presets; files that cannot be read are skipped.
+func LoadPresets(dir, soundMaker string) []Preset {
+	paths, _ := filepath.Glob(filepath.Join(presetFolder(dir, soundMaker), "*.json"))
+	var presets []Preset
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var preset Preset
+		if err := json.Unmarshal(data, &preset); err != nil {
+			continue
+		}
+		if preset.Name == "" {
+			preset.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		preset.SoundMaker = soundMaker
+		preset.Path = path
+		presets = append(presets, preset)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return strings.ToLower(presets[i].Name) < strings.ToLower(presets[j].Name)
+	})
+	return presets
+}
+
+// SavePreset stores the parameters of a SoundMaker slot under a name, replacing a preset
+// of the same name. It returns the saved preset.
+func SavePreset(dir, name string, settings types.SoundMakerSettings) (Preset, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Preset{}, fmt.Errorf("preset needs a name")
+	}
+	if settings.Name == "" || settings.Name == "None" {
+		return Preset{}, fmt.Errorf("no SoundMaker selected")
+	}
+
+	preset := Preset{Name: name, SoundMaker: settings.Name, Parameters: make(map[string]float32)}
+	if def, exists := types.GetInstrumentDefinition(settings.Name); exists {
+		for _, param := range def.Parameters {
+			preset.Parameters[param.Key] = settings.GetParameterValue(param.Key)
+		}
+	} else {
+		for key, value := range settings.Parameters {
+			preset.Parameters[key] = value
+		}
+	}
+
+	folder := presetFolder(dir, settings.Name)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return Preset{}, fmt.Errorf("failed to create preset folder %s: %w", folder, err)
+	}
+	data, err := json.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return Preset{}, fmt.Errorf("failed to encode preset %s: %w", name, err)
+	}
+	preset.Path = filepath.Join(folder, presetFileName(name))
+	if err := os.WriteFile(preset.Path, append(data, '\n'), 0644); err != nil {
+		return Preset{}, fmt.Errorf("failed to write preset %s: %w", preset.Path, err)
+	}
+	return preset, nil
+}
+
+// Apply loads the preset into a SoundMaker slot. Parameters the SoundMaker no longer has
+// are ignored and parameters missing from the preset get their defaults. The patch of a DX7
+// follows its preset parameter.
+func (p Preset) Apply(settings *types.SoundMakerSettings) {
+	settings.Name = p.SoundMaker
+	settings.Parameters = make(map[string]float32)
+	def, exists := types.GetInstrumentDefinition(p.SoundMaker)
+	for key, value := range p.Parameters {
+		if !exists {
+			settings.Parameters[key] = value
+		} else if param, found := def.GetParameterByKey(key); found {
+			if value != -1 {
+				// -1 is "--"; anything else has to fit the current range
+				value = max(param.MinValue, min(param.MaxValue, value))
+			}
+			settings.Parameters[key] = value
+		}
+	}
+	settings.InitializeParameters()
+	settings.PatchName, settings.DX7Voice = "", ""
+	if settings.Name == "DX7" {
+		// Leaves the patch empty when the preset is "--"
+		supercollider.SetDX7PatchByIndex(settings, int(settings.GetParameterValue("preset")))
+	}
+}
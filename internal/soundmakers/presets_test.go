@@ -0,0 +1,65 @@
+package soundmakers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func TestPresets(t *testing.T) {
+	dir := t.TempDir()
+	settings := types.SoundMakerSettings{Name: "TB303"}
+	settings.InitializeParameters()
+	settings.SetParameterValue("resonance", 2.5)
+	settings.SetParameterValue("glide", 0.3)
+
+	// Saving writes a readable file in the SoundMaker's folder
+	preset, err := SavePreset(dir, " Acid/Squelch ", settings)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "TB303", "Acid_Squelch.json"), preset.Path)
+	data, err := os.ReadFile(preset.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"name": "Acid/Squelch"`)
+	assert.Contains(t, string(data), `"resonance": 2.5`)
+
+	_, err = SavePreset(dir, "Bass", settings)
+	require.NoError(t, err)
+	_, err = SavePreset(dir, "  ", settings)
+	assert.Error(t, err)
+	_, err = SavePreset(dir, "Empty", types.SoundMakerSettings{Name: "None"})
+	assert.Error(t, err)
+
+	// Presets are listed per SoundMaker, sorted by name
+	presets := LoadPresets(dir, "TB303")
+	require.Len(t, presets, 2)
+	assert.Equal(t, "Acid/Squelch", presets[0].Name)
+	assert.Equal(t, "Bass", presets[1].Name)
+	assert.Empty(t, LoadPresets(dir, "SuperSaw"))
+
+	// Saving under the same name replaces the preset
+	settings.SetParameterValue("resonance", 0.5)
+	_, err = SavePreset(dir, "Bass", settings)
+	require.NoError(t, err)
+	presets = LoadPresets(dir, "TB303")
+	require.Len(t, presets, 2)
+	assert.Equal(t, float32(0.5), presets[1].Parameters["resonance"])
+
+	// Applying replaces the slot's SoundMaker and clamps values to the current ranges
+	presets[0].Parameters["drive"] = 99
+	delete(presets[0].Parameters, "mixWave")
+	presets[0].Parameters["removed"] = 1
+	slot := types.SoundMakerSettings{Name: "SuperSaw"}
+	slot.InitializeParameters()
+	presets[0].Apply(&slot)
+	assert.Equal(t, "TB303", slot.Name)
+	assert.Equal(t, float32(2.5), slot.GetParameterValue("resonance"))
+	assert.Equal(t, float32(10), slot.GetParameterValue("drive"))
+	assert.Equal(t, float32(0.5), slot.GetParameterValue("mixWave"))
+	assert.NotContains(t, slot.Parameters, "removed")
+	assert.NotContains(t, slot.Parameters, "vibrRate")
+}
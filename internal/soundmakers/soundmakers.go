@@ -0,0 +1,189 @@
+// Package soundmakers loads user-defined SoundMakers: a JSON definition of the parameters
+// next to a SuperCollider file with the SynthDef that plays them.
+//
+// A SoundMaker called "Pluck" is the pair Pluck.json and Pluck.scd in one of the
+// SoundMaker folders. The SynthDef in Pluck.scd must be named "Pluck" and is added to the
+// server when the tracker connects to it, e.g.
+//
+//	SynthDef("Pluck", { ... }).add;
+package soundmakers
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/schollz/collidertracker/internal/supercollider"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Folder is the name of the SoundMaker folder in the config folder and in a project
+const Folder = "soundmakers"
+
+// UserDir returns the folder shared by all projects, e.g. ~/.config/collidertracker/soundmakers
+func UserDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", Folder)
+}
+
+// ProjectDir returns the SoundMaker folder of a project
+func ProjectDir(saveFolder string) string {
+	return filepath.Join(saveFolder, Folder)
+}
+
+// LoadDefinition reads a definition file and the SynthDef file next to it, and checks
+// that they agree
+func LoadDefinition(path string) (types.InstrumentDefinition, error) {
+	var def types.InstrumentDefinition
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return def, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return def, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if def.Name == "" {
+		def.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	// The SynthDef defaults to the .scd file with the same name as the definition. It must
+	// be next to the definition: SuperCollider only loads files from SoundMaker folders.
+	if def.SynthDef == "" {
+		def.SynthDef = strings.TrimSuffix(path, filepath.Ext(path)) + ".scd"
+	} else if filepath.Base(def.SynthDef) != def.SynthDef || def.SynthDef == ".." || !strings.HasSuffix(def.SynthDef, ".scd") {
+		return def, fmt.Errorf("SynthDef of %s must be a .scd file next to %s, not %q", def.Name, filepath.Base(path), def.SynthDef)
+	} else {
+		def.SynthDef = filepath.Join(filepath.Dir(path), def.SynthDef)
+	}
+	code, err := os.ReadFile(def.SynthDef)
+	if err != nil {
+		return def, fmt.Errorf("failed to read SynthDef of %s: %w", def.Name, err)
+	}
+	if !slices.Contains(supercollider.ExtractSynthDefNames(string(code)), def.Name) {
+		return def, fmt.Errorf("%s does not define SynthDef(\"%s\")", filepath.Base(def.SynthDef), def.Name)
+	}
+
+	if err := validate(&def); err != nil {
+		return def, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return def, nil
+}
+
+// validate checks the parameters of a definition and fills in what the tracker relies on
+func validate(def *types.InstrumentDefinition) error {
+	if types.IsBuiltinInstrument(def.Name) {
+		return fmt.Errorf("SoundMaker %s is built in", def.Name)
+	}
+	keys := make(map[string]bool)
+	for i := range def.Parameters {
+		param := &def.Parameters[i]
+		if param.Key == "" {
+			return fmt.Errorf("parameter %d has no key", i+1)
+		}
+		if keys[param.Key] {
+			return fmt.Errorf("parameter %s is defined twice", param.Key)
+		}
+		keys[param.Key] = true
+		if param.Type < types.ParameterTypeHex || param.Type > types.ParameterTypeFloat {
+			return fmt.Errorf("parameter %s has an unknown type", param.Key)
+		}
+		if param.Type == types.ParameterTypeHex && param.MaxValue == 0 {
+			param.MaxValue = 254
+		}
+		if param.MinValue > param.MaxValue {
+			return fmt.Errorf("parameter %s has a minimum above its maximum", param.Key)
+		}
+		if param.Column != 0 && param.Column != 1 {
+			return fmt.Errorf("parameter %s must be in column 0 or 1", param.Key)
+		}
+		if param.DisplayName == "" {
+			param.DisplayName = param.Key
+		}
+		if param.DisplayFormat == "yesno" {
+			param.DisplayFormat = ""
+			param.DisplayFormatter = types.FormatYesNo
+		}
+	}
+
+	// Every SoundMaker can be played monophonically
+	if !keys["monophonic"] {
+		order := 0
+		for _, param := range def.Parameters {
+			if param.Column == 1 {
+				order = max(order, param.Order+1)
+			}
+		}
+		def.Parameters = append(def.Parameters, types.InstrumentParameterDef{
+			Key: "monophonic", DisplayName: "Monophonic", Type: types.ParameterTypeInt,
+			MinValue: 0, MaxValue: 1, DefaultValue: 0, Default: 0, Column: 1, Order: order,
+			DisplayFormatter: types.FormatYesNo,
+		})
+	}
+	return nil
+}
+
+// LoadDir reads all definitions in a folder, sorted by file name. A missing folder has
+// no definitions; definitions that fail to load are returned as errors and skipped.
+func LoadDir(dir string) ([]types.InstrumentDefinition, []error) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(paths)
+	var defs []types.InstrumentDefinition
+	var errs []error
+	for _, path := range paths {
+		def, err := LoadDefinition(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return defs, errs
+}
+
+// Register replaces the user SoundMakers in the registry with the ones in the user folder
+// and, when projectSoundMakers is set, the project's folder. A project SoundMaker wins over
+// a user one of the same name. It returns the names that were registered.
+//
+// The SynthDef files are run as code by SuperCollider, so the SoundMakers shipped with a
+// project are only loaded when the user asks for them.
+func Register(saveFolder string, projectSoundMakers bool) []string {
+	types.UnregisterUserInstruments()
+	var names []string
+	for i, dir := range []string{UserDir(), ProjectDir(saveFolder)} {
+		if i == 1 && !projectSoundMakers {
+			if paths, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(paths) > 0 {
+				log.Printf("Not loading %d SoundMakers shipped with the project in %s; start with --project-soundmakers to load them", len(paths), dir)
+			}
+			continue
+		}
+		defs, errs := LoadDir(dir)
+		for _, err := range errs {
+			log.Printf("Skipping user SoundMaker: %v", err)
+		}
+		if i == 1 && len(defs) > 0 {
+			log.Printf("WARNING: loading %d SoundMakers shipped with the project from %s; their SynthDef files run as SuperCollider code", len(defs), dir)
+		}
+		for _, def := range defs {
+			if err := types.RegisterInstrument(def); err != nil {
+				log.Printf("Skipping user SoundMaker: %v", err)
+				continue
+			}
+			if !slices.Contains(names, def.Name) {
+				names = append(names, def.Name)
+			}
+			log.Printf("Registered user SoundMaker %s from %s", def.Name, dir)
+		}
+	}
+	return names
+}
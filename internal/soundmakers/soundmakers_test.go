@@ -0,0 +1,144 @@
+package soundmakers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+const pluckDefinition = `{
+	"name": "Pluck",
+	"description": "Karplus-Strong pluck",
+	"parameters": [
+		{"key": "decay", "displayName": "Decay", "type": "float", "minValue": 0.1, "maxValue": 10,
+		 "default": 2, "column": 0, "order": 0, "coarseStep": 1, "fineStep": 0.1, "displayFormat": "%.1f s"},
+		{"key": "color", "type": "hex", "defaultValue": -1, "column": 1, "order": 0}
+	]
+}`
+
+const pluckSynthDef = `(
+SynthDef("Pluck", { |note=60, decay=2, color=0.5|
+	Out.ar(\trackOut.kr(0), Pluck.ar(WhiteNoise.ar, 1, 0.2, note.midicps.reciprocal, decay, color) ! 2);
+}).add;
+)`
+
+func writeSoundMaker(t *testing.T, dir, name, definition, synthDef string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(definition), 0644))
+	if synthDef != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".scd"), []byte(synthDef), 0644))
+	}
+}
+
+func TestLoadDefinition(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid definition", func(t *testing.T) {
+		writeSoundMaker(t, dir, "Pluck", pluckDefinition, pluckSynthDef)
+		def, err := LoadDefinition(filepath.Join(dir, "Pluck.json"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "Pluck", def.Name)
+		assert.Equal(t, filepath.Join(dir, "Pluck.scd"), def.SynthDef)
+		require.Len(t, def.Parameters, 3)
+		assert.Equal(t, types.ParameterTypeFloat, def.Parameters[0].Type)
+		assert.Equal(t, "%.1f s", def.Parameters[0].DisplayFormat)
+		assert.Equal(t, types.ParameterTypeHex, def.Parameters[1].Type)
+		assert.Equal(t, float32(254), def.Parameters[1].MaxValue)
+		assert.Equal(t, "color", def.Parameters[1].DisplayName)
+
+		// Monophonic is added after the other parameters of the second column
+		mono := def.Parameters[2]
+		assert.Equal(t, "monophonic", mono.Key)
+		assert.Equal(t, 1, mono.Column)
+		assert.Equal(t, 1, mono.Order)
+		assert.NotNil(t, mono.DisplayFormatter)
+	})
+
+	t.Run("SynthDef name must match", func(t *testing.T) {
+		writeSoundMaker(t, dir, "Other", `{"name": "Other", "parameters": []}`, pluckSynthDef)
+		_, err := LoadDefinition(filepath.Join(dir, "Other.json"))
+		assert.ErrorContains(t, err, `SynthDef("Other")`)
+	})
+
+	t.Run("missing SynthDef file", func(t *testing.T) {
+		writeSoundMaker(t, dir, "Lonely", `{"parameters": []}`, "")
+		_, err := LoadDefinition(filepath.Join(dir, "Lonely.json"))
+		assert.Error(t, err)
+	})
+
+	t.Run("SynthDef must be next to the definition", func(t *testing.T) {
+		writeSoundMaker(t, dir, "Elsewhere", `{"parameters": []}`, `SynthDef("Elsewhere", {}).add;`)
+		for _, synthDef := range []string{filepath.Join(dir, "Elsewhere.scd"), "../Elsewhere.scd", "sub/Elsewhere.scd", "Elsewhere.txt"} {
+			data, err := json.Marshal(map[string]interface{}{"synthDef": synthDef, "parameters": []string{}})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "Elsewhere.json"), data, 0644))
+			_, err = LoadDefinition(filepath.Join(dir, "Elsewhere.json"))
+			assert.ErrorContains(t, err, "must be a .scd file", synthDef)
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Elsewhere.json"), []byte(`{"synthDef": "Elsewhere.scd", "parameters": []}`), 0644))
+		def, err := LoadDefinition(filepath.Join(dir, "Elsewhere.json"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "Elsewhere.scd"), def.SynthDef)
+	})
+
+	t.Run("built-in names are rejected", func(t *testing.T) {
+		writeSoundMaker(t, dir, "TB303", `{"name": "TB303", "parameters": []}`, `SynthDef("TB303", {}).add;`)
+		_, err := LoadDefinition(filepath.Join(dir, "TB303.json"))
+		assert.ErrorContains(t, err, "built in")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for name, params := range map[string]string{
+			"Dupe":   `[{"key": "a"}, {"key": "a"}]`,
+			"Range":  `[{"key": "a", "type": "int", "minValue": 5, "maxValue": 1}]`,
+			"Column": `[{"key": "a", "column": 2}]`,
+			"Kind":   `[{"key": "a", "type": "bool"}]`,
+		} {
+			writeSoundMaker(t, dir, name, `{"parameters": `+params+`}`, `SynthDef("`+name+`", {}).add;`)
+			_, err := LoadDefinition(filepath.Join(dir, name+".json"))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	defer types.UnregisterUserInstruments()
+
+	userDir := UserDir()
+	project := t.TempDir()
+	writeSoundMaker(t, userDir, "Pluck", pluckDefinition, pluckSynthDef)
+	writeSoundMaker(t, userDir, "Broken", `{"name": "Broken"}`, `SynthDef("Nope", {}).add;`)
+	projectPluck := `{"name": "Pluck", "description": "Project pluck", "parameters": []}`
+	writeSoundMaker(t, ProjectDir(project), "Pluck", projectPluck, pluckSynthDef)
+
+	// Project SoundMakers are left out unless asked for
+	names := Register(project, false)
+	assert.Equal(t, []string{"Pluck"}, names)
+	def, exists := types.GetInstrumentDefinition("Pluck")
+	require.True(t, exists)
+	assert.NotEqual(t, "Project pluck", def.Description)
+
+	names = Register(project, true)
+	assert.Equal(t, []string{"Pluck"}, names)
+	def, exists = types.GetInstrumentDefinition("Pluck")
+	require.True(t, exists)
+	assert.Equal(t, "Project pluck", def.Description)
+	assert.Contains(t, types.GetAvailableSoundMakers(), "Pluck")
+	assert.NotContains(t, types.GetAvailableSoundMakers(), "Broken")
+
+	// Registering again for another project drops the SoundMakers of the previous one
+	os.RemoveAll(userDir)
+	assert.Empty(t, Register(t.TempDir(), true))
+	_, exists = types.GetInstrumentDefinition("Pluck")
+	assert.False(t, exists)
+	_, exists = types.GetInstrumentDefinition("TB303")
+	assert.True(t, exists)
+}
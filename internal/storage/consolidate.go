@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// fileUses counts the phrase rows and Multisample zones that play each file
+func fileUses(m *model.Model) map[string]int {
+	uses := make(map[string]int)
+	for p := 0; p < 255; p++ {
+		for _, row := range m.SamplerPhrasesData[p] {
+			if int(types.ColFilename) >= len(row) {
+				continue
+			}
+			index := row[types.ColFilename]
+			if index >= 0 && index < len(m.SamplerPhrasesFiles) && m.SamplerPhrasesFiles[index] != "" {
+				uses[m.SamplerPhrasesFiles[index]]++
+			}
+		}
+	}
+	for i := range m.SoundMakerSettings {
+		for _, zone := range m.SoundMakerSettings[i].Zones {
+			if zone.File != "" {
+				uses[zone.File]++
+			}
+		}
+	}
+	return uses
+}
+
+// inSaveFolder reports whether a file lives directly in the project's save folder
+func inSaveFolder(m *model.Model, path string) bool {
+	folder, err1 := filepath.Abs(m.SaveFolder)
+	abs, err2 := filepath.Abs(path)
+	return err1 == nil && err2 == nil && filepath.Dir(abs) == folder
+}
+
+// hashFile returns the SHA-256 of a file's content
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AnalyzeProjectFiles lists the project's unused, missing and duplicate sample files,
+// metadata left behind for files the project no longer uses and files in the save folder
+// the project does not list, such as recordings
+func AnalyzeProjectFiles(m *model.Model) []types.ProjectFileIssue {
+	uses := fileUses(m)
+	var issues []types.ProjectFileIssue
+	listed := make(map[string]bool)
+
+	// Files in the sampler list that nothing plays
+	for _, path := range m.SamplerPhrasesFiles {
+		if path == "" || listed[path] {
+			continue
+		}
+		listed[path] = true
+		if uses[path] == 0 {
+			issues = append(issues, types.ProjectFileIssue{Kind: types.ProjectFileUnused, Path: path})
+		}
+	}
+
+	// Other samples in the save folder were not imported by the project. Saving copies
+	// files from elsewhere into the save folder under their own name, so those copies are
+	// tracked through the listed or played file.
+	bundled := make(map[string]bool)
+	for path := range listed {
+		bundled[filepath.Base(path)] = true
+	}
+	for path := range uses {
+		bundled[filepath.Base(path)] = true
+	}
+	if entries, err := os.ReadDir(m.SaveFolder); err == nil {
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.IsDir() || (ext != ".wav" && ext != ".flac") || bundled[entry.Name()] {
+				continue
+			}
+			path := filepath.Join(m.SaveFolder, entry.Name())
+			listed[path] = true
+			issues = append(issues, types.ProjectFileIssue{Kind: types.ProjectFileUntracked, Path: path})
+		}
+	}
+
+	// Played files, in a stable order so that the kept duplicate is predictable:
+	// files already in the save folder first, then by path
+	var played []string
+	for path := range uses {
+		played = append(played, path)
+	}
+	sort.Slice(played, func(i, j int) bool {
+		iIn, jIn := inSaveFolder(m, played[i]), inSaveFolder(m, played[j])
+		if iIn != jIn {
+			return iIn
+		}
+		return played[i] < played[j]
+	})
+
+	kept := make(map[string]string) // content hash -> kept file
+	for _, path := range played {
+		if _, err := os.Stat(path); err != nil {
+			issues = append(issues, types.ProjectFileIssue{Kind: types.ProjectFileMissing, Path: path, Uses: uses[path]})
+			continue
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			log.Printf("Could not hash %s: %v", path, err)
+			continue
+		}
+		if original, ok := kept[hash]; ok {
+			issues = append(issues, types.ProjectFileIssue{Kind: types.ProjectFileDuplicate, Path: path, Original: original, Uses: uses[path]})
+			continue
+		}
+		kept[hash] = path
+	}
+
+	// Metadata of files that are neither listed nor played
+	var stale []string
+	for path := range m.FileMetadata {
+		if !listed[path] && uses[path] == 0 {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	for _, path := range stale {
+		issues = append(issues, types.ProjectFileIssue{Kind: types.ProjectFileStaleMetadata, Path: path})
+	}
+
+	return issues
+}
+
+// remapSamplerFiles removes the sampler file list entries for which drop returns true and
+// rewrites the phrase rows and the clipboard to the new indices. Rows playing a dropped entry
+// are pointed at replace[path] when given, at a remaining entry for the same path, or cleared.
+func remapSamplerFiles(m *model.Model, drop func(path string) bool, replace map[string]string) {
+	var files []string
+	newIndex := make([]int, len(m.SamplerPhrasesFiles))
+	indexOf := make(map[string]int)
+	for i, path := range m.SamplerPhrasesFiles {
+		if drop(path) {
+			newIndex[i] = -1
+			continue
+		}
+		newIndex[i] = len(files)
+		indexOf[path] = len(files)
+		files = append(files, path)
+	}
+	for i, path := range m.SamplerPhrasesFiles {
+		if newIndex[i] != -1 {
+			continue
+		}
+		target, replaced := replace[path]
+		if !replaced {
+			target = path
+		}
+		if index, listed := indexOf[target]; listed {
+			newIndex[i] = index
+		} else if replaced {
+			newIndex[i] = len(files)
+			indexOf[target] = len(files)
+			files = append(files, target)
+		}
+	}
+
+	for p := 0; p < 255; p++ {
+		for _, row := range m.SamplerPhrasesData[p] {
+			if int(types.ColFilename) >= len(row) {
+				continue
+			}
+			index := row[types.ColFilename]
+			if index >= 0 && index < len(newIndex) {
+				row[types.ColFilename] = newIndex[index]
+			}
+		}
+	}
+	remapClipboard(m, newIndex, files)
+	m.SamplerPhrasesFiles = files
+}
+
+// remapClipboard points a copied sampler row or filename cell at the renumbered file list.
+// A copied filename cell whose file was dropped is cleared.
+func remapClipboard(m *model.Model, newIndex []int, files []string) {
+	clipboard := &m.Clipboard
+	if !clipboard.HasData {
+		return
+	}
+	switch {
+	case clipboard.Mode == types.RowMode && clipboard.RowFilename != "":
+		// Only sampler rows carry a filename
+		if int(types.ColFilename) >= len(clipboard.RowData) {
+			return
+		}
+		index := clipboard.RowData[types.ColFilename]
+		if index < 0 || index >= len(newIndex) {
+			return
+		}
+		clipboard.RowData[types.ColFilename] = newIndex[index]
+		if newIndex[index] == -1 {
+			clipboard.RowFilename = ""
+		} else {
+			clipboard.RowFilename = files[newIndex[index]]
+		}
+	case clipboard.Mode == types.CellMode && clipboard.CellType == types.FilenameCell:
+		if clipboard.Value < 0 || clipboard.Value >= len(newIndex) {
+			return
+		}
+		if newIndex[clipboard.Value] == -1 {
+			m.Clipboard = types.ClipboardData{}
+			return
+		}
+		clipboard.Value = newIndex[clipboard.Value]
+	}
+}
+
+// removeProjectFile deletes a file and its metadata sidecar when it is inside the save folder.
+// Files elsewhere (e.g. a sample library) are never deleted.
+func removeProjectFile(m *model.Model, path string) error {
+	if !inSaveFolder(m, path) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	sidecar := strings.TrimSuffix(path, filepath.Ext(path)) + ".metadata.json"
+	if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", sidecar, err)
+	}
+	log.Printf("Deleted project file %s", path)
+	return nil
+}
+
+// DeleteUnusedFile drops an unused file from the sampler list and its metadata, and deletes it
+// from disk if it is inside the save folder
+func DeleteUnusedFile(m *model.Model, path string) error {
+	remapSamplerFiles(m, func(p string) bool { return p == path }, nil)
+	delete(m.FileMetadata, path)
+	return removeProjectFile(m, path)
+}
+
+// DeleteUntrackedFile deletes a file in the save folder that the project does not list, and
+// its metadata
+func DeleteUntrackedFile(m *model.Model, path string) error {
+	delete(m.FileMetadata, path)
+	return removeProjectFile(m, path)
+}
+
+// RelinkFile points every use of a file at a new location and moves its metadata.
+// It returns the number of sampler list entries and zones changed.
+func RelinkFile(m *model.Model, oldPath, newPath string) int {
+	changed := 0
+	for i, path := range m.SamplerPhrasesFiles {
+		if path == oldPath {
+			m.SamplerPhrasesFiles[i] = newPath
+			changed++
+		}
+	}
+	for i := range m.SoundMakerSettings {
+		for z := range m.SoundMakerSettings[i].Zones {
+			if m.SoundMakerSettings[i].Zones[z].File == oldPath {
+				m.SoundMakerSettings[i].Zones[z].File = newPath
+				changed++
+			}
+		}
+	}
+	moveFileMetadata(m, oldPath, newPath)
+	// Two entries may now point at the same file
+	remapSamplerFiles(m, duplicateEntry(), nil)
+	log.Printf("Relinked %s to %s (%d uses)", oldPath, newPath, changed)
+	return changed
+}
+
+// duplicateEntry returns a drop function for remapSamplerFiles that keeps the first entry of each path
+func duplicateEntry() func(string) bool {
+	seen := make(map[string]bool)
+	return func(path string) bool {
+		if path != "" && seen[path] {
+			return true
+		}
+		seen[path] = true
+		return false
+	}
+}
+
+// moveFileMetadata moves metadata to a new key unless the new file already has its own
+func moveFileMetadata(m *model.Model, oldPath, newPath string) {
+	metadata, ok := m.FileMetadata[oldPath]
+	if !ok {
+		return
+	}
+	if _, exists := m.FileMetadata[newPath]; !exists {
+		m.FileMetadata[newPath] = metadata
+	}
+	delete(m.FileMetadata, oldPath)
+}
+
+// MergeDuplicateFile makes everything that plays duplicate play original instead, then removes
+// the duplicate like an unused file
+func MergeDuplicateFile(m *model.Model, duplicate, original string) error {
+	// Sampler list entries for the duplicate become entries for the original
+	remapSamplerFiles(m, func(p string) bool { return p == duplicate }, map[string]string{duplicate: original})
+	for i := range m.SoundMakerSettings {
+		for z := range m.SoundMakerSettings[i].Zones {
+			if m.SoundMakerSettings[i].Zones[z].File == duplicate {
+				m.SoundMakerSettings[i].Zones[z].File = original
+			}
+		}
+	}
+	moveFileMetadata(m, duplicate, original)
+	log.Printf("Merged duplicate %s into %s", duplicate, original)
+	return removeProjectFile(m, duplicate)
+}
+
+// ResolveProjectFileIssue fixes one issue: unused files are deleted, duplicates merged and
+// stale metadata dropped. Missing files need a new location and are left alone, as are
+// untracked files, which are only deleted with DeleteUntrackedFile.
+func ResolveProjectFileIssue(m *model.Model, issue types.ProjectFileIssue) error {
+	switch issue.Kind {
+	case types.ProjectFileUnused:
+		return DeleteUnusedFile(m, issue.Path)
+	case types.ProjectFileDuplicate:
+		return MergeDuplicateFile(m, issue.Path, issue.Original)
+	case types.ProjectFileStaleMetadata:
+		delete(m.FileMetadata, issue.Path)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createConsolidateProject builds a project whose sampler files have one issue of each kind
+func createConsolidateProject(t *testing.T) (*model.Model, string) {
+	tmpDir := t.TempDir()
+	saveFolder := filepath.Join(tmpDir, "song")
+	external := filepath.Join(tmpDir, "samples")
+	require.NoError(t, os.MkdirAll(saveFolder, 0755))
+	require.NoError(t, os.MkdirAll(external, 0755))
+
+	write := func(path, content string) string {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	write(filepath.Join(saveFolder, "kick.wav"), "kick")
+	write(filepath.Join(saveFolder, "kick.metadata.json"), "{}")
+	write(filepath.Join(saveFolder, "kick2.wav"), "kick")
+	write(filepath.Join(external, "kick-copy.wav"), "kick")
+	write(filepath.Join(external, "snare.wav"), "snare")
+	write(filepath.Join(saveFolder, "leftover.wav"), "leftover")
+
+	m := model.NewModel(0, saveFolder, false)
+	m.SamplerPhrasesFiles = []string{
+		filepath.Join(saveFolder, "kick.wav"),
+		filepath.Join(external, "kick-copy.wav"),
+		filepath.Join(saveFolder, "kick2.wav"),
+		filepath.Join(external, "snare.wav"),
+		filepath.Join(external, "gone.wav"),
+	}
+	for row, index := range []int{0, 1, 2, 4} {
+		m.SamplerPhrasesData[0][row][types.ColFilename] = index
+	}
+	m.SoundMakerSettings[0].Zones = []types.MultisampleZone{{File: filepath.Join(external, "kick-copy.wav")}}
+	m.FileMetadata[filepath.Join(saveFolder, "kick2.wav")] = types.FileMetadata{BPM: 120}
+	m.FileMetadata[filepath.Join(external, "old.wav")] = types.FileMetadata{BPM: 90}
+	return m, tmpDir
+}
+
+func TestAnalyzeProjectFiles(t *testing.T) {
+	m, tmpDir := createConsolidateProject(t)
+	saveFolder := filepath.Join(tmpDir, "song")
+	external := filepath.Join(tmpDir, "samples")
+	// The copy saving made of a file from elsewhere is not untracked
+	require.NoError(t, os.WriteFile(filepath.Join(saveFolder, "snare.wav"), []byte("snare"), 0644))
+
+	issues := AnalyzeProjectFiles(m)
+	assert.Equal(t, []types.ProjectFileIssue{
+		{Kind: types.ProjectFileUnused, Path: filepath.Join(external, "snare.wav")},
+		// Samples in the save folder the project does not list may be recordings
+		{Kind: types.ProjectFileUntracked, Path: filepath.Join(saveFolder, "leftover.wav")},
+		// Files in the save folder are kept over copies elsewhere
+		{Kind: types.ProjectFileDuplicate, Path: filepath.Join(saveFolder, "kick2.wav"), Original: filepath.Join(saveFolder, "kick.wav"), Uses: 1},
+		{Kind: types.ProjectFileMissing, Path: filepath.Join(external, "gone.wav"), Uses: 1},
+		{Kind: types.ProjectFileDuplicate, Path: filepath.Join(external, "kick-copy.wav"), Original: filepath.Join(saveFolder, "kick.wav"), Uses: 2},
+		{Kind: types.ProjectFileStaleMetadata, Path: filepath.Join(external, "old.wav")},
+	}, issues)
+}
+
+func TestResolveProjectFileIssues(t *testing.T) {
+	m, tmpDir := createConsolidateProject(t)
+	saveFolder := filepath.Join(tmpDir, "song")
+	external := filepath.Join(tmpDir, "samples")
+	kick := filepath.Join(saveFolder, "kick.wav")
+
+	for _, issue := range AnalyzeProjectFiles(m) {
+		require.NoError(t, ResolveProjectFileIssue(m, issue))
+	}
+
+	// Only the kept kick and the missing file remain, and rows play the new indices
+	assert.Equal(t, []string{kick, filepath.Join(external, "gone.wav")}, m.SamplerPhrasesFiles)
+	for row, index := range []int{0, 0, 0, 1} {
+		assert.Equal(t, index, m.SamplerPhrasesData[0][row][types.ColFilename], "row %d", row)
+	}
+	assert.Equal(t, kick, m.SoundMakerSettings[0].Zones[0].File)
+
+	// Metadata moves to the kept file and stale metadata is dropped
+	assert.Equal(t, map[string]types.FileMetadata{kick: {BPM: 120}}, m.FileMetadata)
+
+	// Files in the save folder are deleted, files elsewhere and untracked files are not
+	assert.NoFileExists(t, filepath.Join(saveFolder, "kick2.wav"))
+	assert.FileExists(t, filepath.Join(saveFolder, "leftover.wav"))
+	assert.FileExists(t, kick)
+	assert.FileExists(t, filepath.Join(saveFolder, "kick.metadata.json"))
+	assert.FileExists(t, filepath.Join(external, "kick-copy.wav"))
+	assert.FileExists(t, filepath.Join(external, "snare.wav"))
+
+	// Only the missing and the untracked file are left
+	assert.Equal(t, []types.ProjectFileIssue{
+		{Kind: types.ProjectFileUntracked, Path: filepath.Join(saveFolder, "leftover.wav")},
+		{Kind: types.ProjectFileMissing, Path: filepath.Join(external, "gone.wav"), Uses: 1},
+	}, AnalyzeProjectFiles(m))
+
+	// Untracked files are deleted only on request
+	require.NoError(t, DeleteUntrackedFile(m, filepath.Join(saveFolder, "leftover.wav")))
+	assert.NoFileExists(t, filepath.Join(saveFolder, "leftover.wav"))
+}
+
+func TestRelinkFile(t *testing.T) {
+	m, tmpDir := createConsolidateProject(t)
+	external := filepath.Join(tmpDir, "samples")
+	kickCopy := filepath.Join(external, "kick-copy.wav")
+	gone := filepath.Join(external, "gone.wav")
+	m.FileMetadata[gone] = types.FileMetadata{BPM: 100}
+
+	// Relinking onto a file already in the list merges the two entries
+	assert.Equal(t, 1, RelinkFile(m, gone, kickCopy))
+	assert.NotContains(t, m.SamplerPhrasesFiles, gone)
+	assert.Len(t, m.SamplerPhrasesFiles, 4)
+	assert.Equal(t, 1, m.SamplerPhrasesData[0][1][types.ColFilename])
+	assert.Equal(t, 1, m.SamplerPhrasesData[0][3][types.ColFilename])
+	assert.Equal(t, types.FileMetadata{BPM: 100}, m.FileMetadata[kickCopy])
+	assert.NotContains(t, m.FileMetadata, gone)
+}
+
+func TestRemapSamplerFilesClipboard(t *testing.T) {
+	m, tmpDir := createConsolidateProject(t)
+	external := filepath.Join(tmpDir, "samples")
+	kickCopy := filepath.Join(external, "kick-copy.wav")
+	snare := filepath.Join(external, "snare.wav")
+	gone := filepath.Join(external, "gone.wav")
+
+	// A copied row playing the missing file follows it to its relinked index
+	row := make([]int, types.ColCount)
+	row[types.ColFilename] = 4
+	m.Clipboard = types.ClipboardData{RowData: row, RowFilename: gone, SourceView: types.PhraseView, Mode: types.RowMode, HasData: true}
+	assert.Equal(t, 1, RelinkFile(m, gone, kickCopy))
+	assert.Equal(t, 1, m.Clipboard.RowData[types.ColFilename])
+	assert.Equal(t, kickCopy, m.Clipboard.RowFilename)
+
+	// A copied filename cell is renumbered, and cleared once its file is dropped
+	m.Clipboard = types.ClipboardData{Value: 3, CellType: types.FilenameCell, Mode: types.CellMode, HasData: true}
+	remapSamplerFiles(m, func(path string) bool { return path == kickCopy }, nil)
+	assert.Equal(t, 2, m.Clipboard.Value)
+	assert.Equal(t, snare, m.SamplerPhrasesFiles[m.Clipboard.Value])
+	remapSamplerFiles(m, func(path string) bool { return path == snare }, nil)
+	assert.False(t, m.Clipboard.HasData)
+}
@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/sample"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+const (
+	// SampleBackupFolder is the folder inside the save folder that keeps the previous
+	// versions of samples changed by the sample tools
+	SampleBackupFolder = "backup"
+	// sampleBackupTimeFormat is the timestamp appended to backup file names
+	sampleBackupTimeFormat = "20060102-150405.000"
+)
+
+// projectSamplePath returns where a sample lives inside the save folder
+func projectSamplePath(m *model.Model, path string) string {
+	return filepath.Join(m.SaveFolder, filepath.Base(path))
+}
+
+// importSampleFile makes sure a sample is played from the save folder before it is changed,
+// copying it there if needed. A different file of the same name is never overwritten: the
+// sample is imported under a new name instead. Entries elsewhere with the same content are
+// relinked too, since saving would otherwise copy them over the changed file.
+func importSampleFile(m *model.Model, path string) (string, error) {
+	hash, err := hashFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	target := projectSamplePath(m, path)
+	if !inSaveFolder(m, path) {
+		if err := os.MkdirAll(m.SaveFolder, 0755); err != nil {
+			return "", fmt.Errorf("failed to create save folder %s: %w", m.SaveFolder, err)
+		}
+		target = importTarget(m, target, hash)
+		if err := copyFile(path, target); err != nil {
+			return "", err
+		}
+		log.Printf("Copied %s into the project as %s", path, target)
+	}
+
+	var relink []string
+	for _, p := range m.SamplerPhrasesFiles {
+		if p == "" || p == target || p == path || filepath.Base(p) != filepath.Base(target) {
+			continue
+		}
+		if h, err := hashFile(p); err == nil && h == hash {
+			relink = append(relink, p)
+		}
+	}
+	if path != target {
+		relink = append(relink, path)
+	}
+	for _, p := range relink {
+		RelinkFile(m, p, target)
+	}
+	return target, nil
+}
+
+// importTarget returns where a sample with a content hash can be copied into the save
+// folder: its own name, or "name-2.wav", "name-3.wav"... when a different file already
+// has the name, in the folder or in the project
+func importTarget(m *model.Model, target, hash string) string {
+	ext := filepath.Ext(target)
+	stem := strings.TrimSuffix(target, ext)
+	for n := 2; ; n++ {
+		if sameContentOrFree(m, target, hash) {
+			return target
+		}
+		target = fmt.Sprintf("%s-%d%s", stem, n, ext)
+	}
+}
+
+// sameContentOrFree reports whether a file with a content hash can take a path in the save
+// folder without replacing a different file there or one the project saves to that name
+func sameContentOrFree(m *model.Model, target, hash string) bool {
+	if h, err := hashFile(target); err == nil && h != hash {
+		return false
+	} else if err != nil && !os.IsNotExist(err) {
+		return false
+	}
+	for _, p := range m.SamplerPhrasesFiles {
+		if p == "" || p == target || filepath.Base(p) != filepath.Base(target) {
+			continue
+		}
+		if h, err := hashFile(p); err != nil || h != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// SampleBackups returns the backups of a project sample, oldest first
+func SampleBackups(m *model.Model, path string) []string {
+	dir := filepath.Join(m.SaveFolder, SampleBackupFolder)
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(filepath.Base(path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(sampleBackupTimeFormat, stamp); err == nil {
+			backups = append(backups, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(backups)
+	return backups
+}
+
+// backupSampleFile copies a project sample and its metadata into the backup folder
+func backupSampleFile(m *model.Model, path string) (string, error) {
+	dir := filepath.Join(m.SaveFolder, SampleBackupFolder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup folder %s: %w", dir, err)
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+	stamp := time.Now()
+	backup := filepath.Join(dir, stem+"-"+stamp.Format(sampleBackupTimeFormat)+ext)
+	for {
+		// Keep backups made within the same millisecond apart
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			break
+		}
+		stamp = stamp.Add(time.Millisecond)
+		backup = filepath.Join(dir, stem+"-"+stamp.Format(sampleBackupTimeFormat)+ext)
+	}
+	if err := copyFile(path, backup); err != nil {
+		return "", err
+	}
+	if metadata, exists := m.FileMetadata[path]; exists {
+		if err := saveFileMetadata(dir, backup, metadata); err != nil {
+			return "", err
+		}
+	}
+	log.Printf("Backed up %s to %s", path, backup)
+	return backup, nil
+}
+
+// sampleFileChanged updates everything that caches a sample after it changed on disk
+func sampleFileChanged(m *model.Model, path string) {
+	if metadata, exists := m.FileMetadata[path]; exists {
+		metadata.Frames = 0 // Read again from the changed file
+		m.FileMetadata[path] = metadata
+	}
+	if err := SaveMetadataForFile(path, m.FileMetadata); err != nil {
+		log.Printf("Error saving metadata for %s: %v", path, err)
+	}
+	if m.MetadataEditingFile == path && m.SliceEditorPeaks != nil {
+		if peaks, err := sample.LoadPeaks(path, len(m.SliceEditorPeaks)); err == nil {
+			m.SliceEditorPeaks = peaks
+		}
+	}
+	m.SendOSCSampleReloadMessage(path)
+}
+
+// ProcessSampleFile applies a sample tool to a WAV file. The file is first moved into the
+// project's save folder if needed and backed up, and its metadata is adjusted to the new
+// audio. It returns the path of the changed file.
+func ProcessSampleFile(m *model.Model, path string, tool types.SampleTool, settings types.SampleToolSettings) (string, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".wav") {
+		return "", fmt.Errorf("only WAV files can be processed: %s", filepath.Base(path))
+	}
+	if tool == types.SampleToolRestore {
+		return RestoreSampleBackup(m, path)
+	}
+
+	b, err := sample.Load(path)
+	if err != nil {
+		return "", err
+	}
+	frames := b.Frames()
+	msFrames := func(ms int) int { return ms * b.SampleRate / 1000 }
+
+	var remap func(types.FileMetadata) types.FileMetadata
+	switch tool {
+	case types.SampleToolNormalizePeak:
+		b.NormalizePeak(settings.PeakDB)
+	case types.SampleToolNormalizeRMS:
+		b.NormalizeRMS(settings.RMSDB)
+	case types.SampleToolTrimSilence:
+		start, end := b.SilenceBounds(settings.SilenceDB)
+		if start >= end {
+			return "", fmt.Errorf("nothing above %.0f dB in %s", settings.SilenceDB, filepath.Base(path))
+		}
+		if start == 0 && end == frames {
+			return path, nil
+		}
+		b.Crop(start, end)
+		remap = func(fm types.FileMetadata) types.FileMetadata { return fm.Cropped(start, end, frames) }
+	case types.SampleToolFadeIn:
+		b.FadeIn(msFrames(settings.FadeInMs))
+	case types.SampleToolFadeOut:
+		b.FadeOut(msFrames(settings.FadeOutMs))
+	case types.SampleToolReverse:
+		b.Reverse()
+		remap = func(fm types.FileMetadata) types.FileMetadata { return fm.Reversed(frames) }
+	case types.SampleToolMono:
+		if b.NumChannels == 1 {
+			return path, nil
+		}
+		b.ToMono()
+	case types.SampleToolStereo:
+		if b.NumChannels == 2 {
+			return path, nil
+		}
+		b.ToStereo()
+	case types.SampleToolResample:
+		if settings.SampleRate == b.SampleRate {
+			return path, nil
+		}
+		ratio := float64(settings.SampleRate) / float64(b.SampleRate)
+		b.Resample(settings.SampleRate)
+		remap = func(fm types.FileMetadata) types.FileMetadata { return fm.Resampled(ratio) }
+	default:
+		return "", fmt.Errorf("unknown sample tool %d", tool)
+	}
+
+	target, err := importSampleFile(m, path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := backupSampleFile(m, target); err != nil {
+		return "", err
+	}
+	if err := b.Save(target); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if metadata, exists := m.FileMetadata[target]; exists && remap != nil {
+		m.FileMetadata[target] = remap(metadata)
+	}
+	sampleFileChanged(m, target)
+	log.Printf("%s: %s (%d -> %d frames, %d Hz, %d channels, peak %.1f dB)", tool, target, frames, b.Frames(),
+		b.SampleRate, b.NumChannels, 20*math.Log10(math.Max(b.Peak(), 1e-9)))
+	return target, nil
+}
+
+// RestoreSampleBackup puts back the latest backup of a project sample, with the metadata it
+// had, and removes that backup so that restoring again steps further back
+func RestoreSampleBackup(m *model.Model, path string) (string, error) {
+	target := projectSamplePath(m, path)
+	backups := SampleBackups(m, target)
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backup of %s", filepath.Base(target))
+	}
+	latest := backups[len(backups)-1]
+
+	if err := copyFile(latest, target); err != nil {
+		return "", err
+	}
+	target, err := importSampleFile(m, target)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(latest)
+	sidecar := strings.TrimSuffix(latest, filepath.Ext(latest)) + ".metadata.json"
+	if _, err := os.Stat(sidecar); err == nil {
+		metadata, err := loadFileMetadata(dir, filepath.Base(latest))
+		if err != nil {
+			return "", err
+		}
+		m.FileMetadata[target] = metadata
+		os.Remove(sidecar)
+	}
+	if err := os.Remove(latest); err != nil {
+		log.Printf("Error removing backup %s: %v", latest, err)
+	}
+	sampleFileChanged(m, target)
+	log.Printf("Restored %s from %s", target, latest)
+	return target, nil
+}
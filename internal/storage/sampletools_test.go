@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/sample"
+	"github.com/schollz/collidertracker/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessSampleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	saveFolder := filepath.Join(tmpDir, "song")
+	external := filepath.Join(tmpDir, "samples", "hit.wav")
+	require.NoError(t, os.MkdirAll(filepath.Dir(external), 0755))
+
+	// 100 frames of silence around 800 frames of sound
+	data := make([]float64, 1000)
+	for i := 100; i < 900; i++ {
+		data[i] = 0.25
+	}
+	original := &sample.Buffer{SampleRate: 1000, NumChannels: 1, BitDepth: 16, Data: [][]float64{data}}
+	require.NoError(t, original.Save(external))
+
+	m := model.NewModel(0, saveFolder, false)
+	m.SamplerPhrasesFiles = []string{external}
+	m.SamplerPhrasesData[0][0][types.ColFilename] = 0
+	m.FileMetadata[external] = types.FileMetadata{BPM: 120, Slices: 4, LoopStart: 500, Frames: 2000}
+	settings := types.DefaultSampleToolSettings()
+
+	// The file is copied into the project, relinked and trimmed; the original is untouched
+	path, err := ProcessSampleFile(m, external, types.SampleToolTrimSilence, settings)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(saveFolder, "hit.wav"), path)
+	assert.Equal(t, []string{path}, m.SamplerPhrasesFiles)
+	assert.Equal(t, 400, m.FileMetadata[path].LoopStart)
+	assert.Zero(t, m.FileMetadata[path].Frames, "the length is read again from the trimmed file")
+	assert.NotContains(t, m.FileMetadata, external)
+	assert.FileExists(t, filepath.Join(saveFolder, "hit.metadata.json"))
+
+	trimmed, err := sample.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 800, trimmed.Frames())
+	untouched, err := sample.Load(external)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, untouched.Frames())
+
+	// Every change is backed up
+	settings.PeakDB = -6
+	_, err = ProcessSampleFile(m, path, types.SampleToolNormalizePeak, settings)
+	require.NoError(t, err)
+	normalized, err := sample.Load(path)
+	require.NoError(t, err)
+	assert.InDelta(t, sample.DBToGain(-6), normalized.Peak(), 0.001)
+	require.Len(t, SampleBackups(m, path), 2)
+
+	// Restoring steps back through the backups, metadata included
+	_, err = ProcessSampleFile(m, path, types.SampleToolRestore, settings)
+	require.NoError(t, err)
+	restored, err := sample.Load(path)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.25, restored.Peak(), 0.001)
+	assert.Equal(t, 400, m.FileMetadata[path].LoopStart)
+
+	_, err = RestoreSampleBackup(m, path)
+	require.NoError(t, err)
+	restored, err = sample.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, restored.Frames())
+	assert.Equal(t, 500, m.FileMetadata[path].LoopStart)
+	assert.Empty(t, SampleBackups(m, path))
+
+	_, err = RestoreSampleBackup(m, path)
+	assert.Error(t, err)
+
+	// Only WAV files can be processed
+	_, err = ProcessSampleFile(m, filepath.Join(tmpDir, "loop.flac"), types.SampleToolReverse, settings)
+	assert.Error(t, err)
+
+	// Silent files cannot be trimmed
+	silent := filepath.Join(saveFolder, "silent.wav")
+	require.NoError(t, (&sample.Buffer{SampleRate: 1000, NumChannels: 1, Data: [][]float64{make([]float64, 10)}}).Save(silent))
+	_, err = ProcessSampleFile(m, silent, types.SampleToolTrimSilence, settings)
+	assert.Error(t, err)
+
+	// A different file of the same name is imported under a new name, leaving the
+	// project's file and the entries of other files alone
+	other := filepath.Join(tmpDir, "other", "hit.wav")
+	require.NoError(t, os.MkdirAll(filepath.Dir(other), 0755))
+	require.NoError(t, (&sample.Buffer{SampleRate: 1000, NumChannels: 1, BitDepth: 16, Data: [][]float64{data[50:]}}).Save(other))
+	m.SamplerPhrasesFiles = append(m.SamplerPhrasesFiles, other)
+	before, err := hashFile(path)
+	require.NoError(t, err)
+	imported, err := ProcessSampleFile(m, other, types.SampleToolReverse, settings)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(saveFolder, "hit-2.wav"), imported)
+	after, err := hashFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.Contains(t, m.SamplerPhrasesFiles, path)
+	assert.Contains(t, m.SamplerPhrasesFiles, imported)
+	assert.NotContains(t, m.SamplerPhrasesFiles, other)
+	assert.Empty(t, SampleBackups(m, path))
+
+	// Backups are not reported as unused project files
+	for _, issue := range AnalyzeProjectFiles(m) {
+		assert.NotContains(t, issue.Path, SampleBackupFolder)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/supercollider"
 	"github.com/schollz/collidertracker/internal/types"
 )
 
@@ -61,6 +63,7 @@ func DoSave(m *model.Model) {
 		log.Printf("Created save folder: %s", m.SaveFolder)
 	}
 	log.Printf("Relative paths for save: %v", relativePaths)
+	soundMakerSettings := portableSoundMakerSettings(m.SaveFolder, m.SoundMakerSettings, m.FileMetadata)
 
 	saveData := types.SaveData{
 		ViewMode:      m.ViewMode,
@@ -99,11 +102,13 @@ func DoSave(m *model.Model) {
 		RecordingEnabled:           m.RecordingEnabled,
 		RetriggerSettings:          m.RetriggerSettings,
 		TimestrechSettings:         m.TimestrechSettings,
+		GranularSettings:           m.GranularSettings,
 		InstrumentModulateSettings: m.InstrumentModulateSettings,
 		SamplerModulateSettings:    m.SamplerModulateSettings,
 		ArpeggioSettings:           m.ArpeggioSettings,
 		MidiSettings:               m.MidiSettings,
-		SoundMakerSettings:         m.SoundMakerSettings,
+		MidiLatencies:              m.MidiLatencies,
+		SoundMakerSettings:         soundMakerSettings, // Zone files relative to the save folder
 		SongData:                   m.SongData,
 		LastSongRow:                m.LastSongRow,
 		LastSongTrack:              m.LastSongTrack,
@@ -113,9 +118,19 @@ func DoSave(m *model.Model) {
 		TrackTypes:                 m.TrackTypes,
 		CurrentMixerTrack:          m.CurrentMixerTrack,
 		DuckingSettings:            m.DuckingSettings,
+		LFOSettings:                m.LFOSettings,
+		InsertFX:                   m.InsertFX,
+		Returns:                    m.Returns,
+		TrackSends:                 m.TrackSends,
+		AutomationLanes:            m.AutomationLanes,
+		ParameterLocks:             m.ParameterLocks,
 		DuckingEditingIndex:        m.DuckingEditingIndex,
 		SOColumnMode:               m.SOColumnMode,
-		MidiCCNumbers:              m.MidiCCNumbers,
+		Tuning:                     m.Tuning,
+		TrackTunings:               m.TrackTunings,
+		ShowScaleDegrees:           m.ShowScaleDegrees,
+		ProjectKey:                 m.ProjectKey,
+		ProjectScale:               m.ProjectScale,
 	}
 
 	data, err := json.Marshal(saveData)
@@ -167,6 +182,10 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	}
 
 	var saveData types.SaveData
+	// Saves from before patches leave them to the synth
+	for i := range saveData.MidiSettings {
+		saveData.MidiSettings[i] = types.NewMidiSettings()
+	}
 	if err := json.Unmarshal(data, &saveData); err != nil {
 		return err
 	}
@@ -175,8 +194,20 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	if saveData.ViewMode == types.FileView ||
 		saveData.ViewMode == types.SettingsView ||
 		saveData.ViewMode == types.FileMetadataView ||
+		saveData.ViewMode == types.SliceEditorView ||
+		saveData.ViewMode == types.MultisampleView ||
+		saveData.ViewMode == types.LibraryView ||
+		saveData.ViewMode == types.ProjectFilesView ||
+		saveData.ViewMode == types.SampleToolsView ||
 		saveData.ViewMode == types.RetriggerView ||
-		saveData.ViewMode == types.TimestrechView {
+		saveData.ViewMode == types.TimestrechView ||
+		saveData.ViewMode == types.GranularView ||
+		saveData.ViewMode == types.PresetView ||
+		saveData.ViewMode == types.LFOView ||
+		saveData.ViewMode == types.AutomationView ||
+		saveData.ViewMode == types.ParameterLockView ||
+		saveData.ViewMode == types.InsertFXView ||
+		saveData.ViewMode == types.ReturnsView {
 		saveData.ViewMode = types.PhraseView
 		saveData.CurrentCol = int(types.ColFilename)
 	}
@@ -209,8 +240,30 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	m.RecordingEnabled = saveData.RecordingEnabled
 	m.RetriggerSettings = saveData.RetriggerSettings
 	m.TimestrechSettings = saveData.TimestrechSettings
+	m.GranularSettings = saveData.GranularSettings
+	// Saves from before granular mode have no granular pool; give its slots the default density
+	for i := range m.GranularSettings {
+		if m.GranularSettings[i].Density == 0 {
+			m.GranularSettings[i].Density = types.DefaultGranularDensity
+		}
+	}
 	m.DuckingSettings = saveData.DuckingSettings
 	m.DuckingEditingIndex = saveData.DuckingEditingIndex
+	m.AutomationLanes = saveData.AutomationLanes
+	m.ParameterLocks = saveData.ParameterLocks
+	if m.ParameterLocks == nil {
+		m.ParameterLocks = make(types.ParameterLocks)
+	}
+	m.LFOSettings = saveData.LFOSettings
+	// Saves from before the LFO pool have empty LFOs; give them the defaults
+	for i := range m.LFOSettings {
+		if m.LFOSettings[i] == (types.LFOSettings{}) {
+			m.LFOSettings[i] = types.NewLFOSettings()
+		}
+	}
+	m.InsertFX = saveData.InsertFX
+	m.Returns = saveData.Returns
+	m.TrackSends = saveData.TrackSends
 
 	// Handle modulation settings with backward compatibility
 	if len(saveData.InstrumentModulateSettings) > 0 || len(saveData.SamplerModulateSettings) > 0 {
@@ -254,6 +307,10 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	m.ArpeggioSettings = saveData.ArpeggioSettings
 	m.MidiSettings = saveData.MidiSettings
 	m.SoundMakerSettings = saveData.SoundMakerSettings
+	resolvePortableZones(saveFolder, &m.SoundMakerSettings)
+	for i := range m.SoundMakerSettings {
+		supercollider.ResolveDX7Voice(&m.SoundMakerSettings[i])
+	}
 	m.SongData = saveData.SongData
 	m.LastSongRow = saveData.LastSongRow
 	m.LastSongTrack = saveData.LastSongTrack
@@ -263,15 +320,24 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	m.TrackTypes = saveData.TrackTypes
 	m.CurrentMixerTrack = saveData.CurrentMixerTrack
 	m.SOColumnMode = saveData.SOColumnMode
-
-	// Load MIDI CC numbers with defaults (0-8) for backward compatibility
-	if saveData.MidiCCNumbers == [9]int{} {
-		// Initialize with defaults 0-8
-		for i := 0; i < 9; i++ {
-			m.MidiCCNumbers[i] = i
+	m.Tuning = saveData.Tuning
+	m.TrackTunings = saveData.TrackTunings
+	m.ShowScaleDegrees = saveData.ShowScaleDegrees
+	m.ProjectKey = saveData.ProjectKey
+	m.ProjectScale = saveData.ProjectScale
+
+	m.MidiLatencies = saveData.MidiLatencies
+	if m.MidiLatencies == nil {
+		m.MidiLatencies = make(map[string]int)
+	}
+
+	// Saves from before per-device CCs had one set of CC numbers for every device
+	if saveData.MidiCCNumbers != nil && *saveData.MidiCCNumbers != [9]int{} {
+		for i := range m.MidiSettings {
+			for column, number := range saveData.MidiCCNumbers {
+				m.MidiSettings[i].CCs[column] = types.MidiCC{Number: number}
+			}
 		}
-	} else {
-		m.MidiCCNumbers = saveData.MidiCCNumbers
 	}
 
 	// Bulk-assign arrays
@@ -328,6 +394,13 @@ func LoadState(m *model.Model, oscPort int, saveFolder string) error {
 	for track := 0; track < 8; track++ {
 		m.SendOSCTrackSetLevelMessage(track)
 	}
+	m.SendOSCLFOMessages()
+	m.SendOSCInsertFXMessages()
+	m.SendOSCReturnMessages()
+
+	// Set external synths to the latencies and patches of the song
+	m.ApplyMidiLatencies()
+	m.RecallMidiPatches()
 
 	// Initialize per-track RNGs for modulation (if not already initialized)
 	if m.ModulateRngs[0] == nil {
@@ -368,7 +441,12 @@ func LoadFiles(m *model.Model) {
 		}
 	}
 
-	// Add audio files (including symlinked audio files)
+	// Add audio files (including symlinked audio files), or DX7 banks when picking one
+	// for the SoundMaker view
+	extensions := []string{".wav", ".flac"}
+	if m.FileSelectView == types.SoundMakerView {
+		extensions = []string{".syx"}
+	}
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			fullPath := filepath.Join(m.CurrentDir, entry.Name())
@@ -376,7 +454,7 @@ func LoadFiles(m *model.Model) {
 			// Check if it's a regular file or a symlink to a file
 			if stat, err := os.Stat(fullPath); err == nil && !stat.IsDir() {
 				ext := strings.ToLower(filepath.Ext(entry.Name()))
-				if ext == ".wav" || ext == ".flac" {
+				if slices.Contains(extensions, ext) {
 					files = append(files, entry.Name())
 				}
 			}
@@ -449,6 +527,44 @@ func createSaveFolder(saveFolder string, samplerFiles []string, fileMetadata map
 	return relativePaths, nil
 }
 
+// portableSoundMakerSettings returns a copy of the SoundMaker settings for saving, with the
+// Multisample zone files copied into the save folder like the sampler files and stored
+// relative to it
+func portableSoundMakerSettings(saveFolder string, settings [255]types.SoundMakerSettings, fileMetadata map[string]types.FileMetadata) [255]types.SoundMakerSettings {
+	for i := range settings {
+		if len(settings[i].Zones) == 0 {
+			continue
+		}
+		files := make([]string, len(settings[i].Zones))
+		for z, zone := range settings[i].Zones {
+			files[z] = zone.File
+		}
+		relativePaths, err := createSaveFolder(saveFolder, files, fileMetadata)
+		if err != nil {
+			log.Printf("Error copying zone files of SoundMaker %02X: %v", i, err)
+			continue
+		}
+		// Copy the zones so the model keeps its paths
+		zones := append([]types.MultisampleZone(nil), settings[i].Zones...)
+		for z := range zones {
+			zones[z].File = relativePaths[z]
+		}
+		settings[i].Zones = zones
+	}
+	return settings
+}
+
+// resolvePortableZones converts the zone files of loaded SoundMaker settings back to paths
+// in the save folder
+func resolvePortableZones(saveFolder string, settings *[255]types.SoundMakerSettings) {
+	for i := range settings {
+		for z := range settings[i].Zones {
+			zone := &settings[i].Zones[z]
+			zone.File = resolvePortablePaths(saveFolder, []string{zone.File})[0]
+		}
+	}
+}
+
 // copyFile copies a file from source to destination
 func copyFile(src, dst string) error {
 	// Open source file
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"bytes"
+	"compress/gzip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -67,6 +69,167 @@ func TestLoadState(t *testing.T) {
 		assert.Equal(t, types.ChainView, m2.ViewMode)
 	})
 
+	t.Run("granular settings round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_granular")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.GranularSettings[3] = types.GranularSettings{Size: 120, Density: 40, Position: 0.25, Spray: 0.5}
+		m1.GranularSettings[4].Density = 0 // As in saves from before granular mode
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.GranularSettings[3], m2.GranularSettings[3])
+		assert.Equal(t, float32(types.DefaultGranularDensity), m2.GranularSettings[4].Density)
+	})
+
+	t.Run("LFO settings round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_lfo")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.LFOSettings[1] = types.LFOSettings{Waveform: 2, Rate: 0.25, Depth: 70, Phase: 180, Retrigger: true, Track: 4, Target: "effectComb"}
+		m1.LFOSettings[2] = types.LFOSettings{} // As in saves from before the LFO pool
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.LFOSettings[1], m2.LFOSettings[1])
+		assert.Equal(t, types.NewLFOSettings(), m2.LFOSettings[2])
+	})
+
+	t.Run("insert FX round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_insertfx")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.InsertFX[2].Add("Compressor")
+		m1.InsertFX[2].Add("Delay")
+		m1.InsertFX[2].Slots[1].Parameters["feedback"] = 0.7
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.InsertFX, m2.InsertFX)
+		assert.Empty(t, m2.InsertFX[0].Slots)
+	})
+
+	t.Run("returns and track sends round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_returns")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.Returns[types.ReturnDelay].Parameters = map[string]float32{"delayTime": 8}
+		m1.TrackSends[5][types.ReturnReverb] = 40
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.Returns, m2.Returns)
+		assert.Equal(t, m1.TrackSends, m2.TrackSends)
+	})
+
+	t.Run("automation lanes round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_automation")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.AutomationLanes = []types.AutomationLane{{
+			Owner:  types.AutomationOwner{Scope: types.AutomationScopeChain, ID: 7},
+			Target: "PolyPerc/A",
+			Points: []types.AutomationPoint{{Tick: 0, Value: 0x20, Curve: types.AutomationCurveLogarithmic}, {Tick: 0x30, Value: 0xC0}},
+		}}
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.AutomationLanes, m2.AutomationLanes)
+	})
+
+	t.Run("parameter locks round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_parameter_locks")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.ParameterLocks.Set(0x0A, 0x05, "A", 0x80)
+		m1.ParameterLocks.Set(0x0A, 0x05, "preset", 12)
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.ParameterLocks, m2.ParameterLocks)
+	})
+
+	t.Run("MIDI patches round trip", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_midi_patches")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.MidiSettings[3].Program = 40
+		m1.MidiSettings[3].BankMSB = 1
+		m1.MidiSettings[3].BendRange = 12
+		m1.MidiLatencies["Synth"] = 25
+		DoSave(m1)
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		assert.Equal(t, m1.MidiSettings[3], m2.MidiSettings[3])
+		assert.Equal(t, -1, m2.MidiSettings[3].BankLSB)
+		assert.Equal(t, types.NewMidiSettings(), m2.MidiSettings[4])
+		assert.Equal(t, map[string]int{"Synth": 25}, m2.MidiLatencies)
+	})
+
+	t.Run("CC numbers of saves before per-device CCs", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_legacy_ccs")
+
+		m1 := model.NewModel(0, saveFolder, false)
+		DoSave(m1)
+
+		// Rewrite the save the way older versions wrote it: one set of CC numbers for every device
+		path := filepath.Join(saveFolder, "data.json.gz")
+		file, err := os.Open(path)
+		assert.NoError(t, err)
+		gzReader, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+		var saveData map[string]any
+		assert.NoError(t, json.NewDecoder(gzReader).Decode(&saveData))
+		file.Close()
+		for _, settings := range saveData["midiSettings"].([]any) {
+			delete(settings.(map[string]any), "ccs")
+		}
+		saveData["midiCCNumbers"] = []int{74, 71, 2, 3, 4, 5, 6, 7, 8}
+		var buf bytes.Buffer
+		gzWriter := gzip.NewWriter(&buf)
+		assert.NoError(t, json.NewEncoder(gzWriter).Encode(saveData))
+		assert.NoError(t, gzWriter.Close())
+		assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		m2 := model.NewModel(0, saveFolder, false)
+		assert.NoError(t, LoadState(m2, 0, saveFolder))
+		for _, settings := range []types.MidiSettings{m2.MidiSettings[0], m2.MidiSettings[0xFE]} {
+			assert.Equal(t, types.MidiCC{Number: 74}, settings.CCs[0])
+			assert.Equal(t, types.MidiCC{Number: 71}, settings.CCs[1])
+			assert.Equal(t, types.MidiCC{Number: 8}, settings.CCs[8])
+		}
+	})
+
+	t.Run("multisample zone files are bundled", func(t *testing.T) {
+		saveFolder := filepath.Join(t.TempDir(), "test_zones")
+		external := t.TempDir()
+		piano := filepath.Join(external, "piano_C4.wav")
+		assert.NoError(t, os.WriteFile(piano, []byte("RIFF"), 0644))
+
+		m1 := model.NewModel(0, saveFolder, false)
+		m1.SoundMakerSettings[2].Name = types.MultisampleSoundMakerName
+		m1.SoundMakerSettings[2].SetZoneFile(0, piano)
+		DoSave(m1)
+		assert.FileExists(t, filepath.Join(saveFolder, "piano_C4.wav"))
+		assert.Equal(t, piano, m1.SoundMakerSettings[2].Zones[0].File, "the model keeps its path")
+
+		// The zone points into the save folder, wherever the project is opened
+		moved := filepath.Join(t.TempDir(), "moved")
+		assert.NoError(t, os.Rename(saveFolder, moved))
+		assert.NoError(t, os.Remove(piano))
+		m2 := model.NewModel(0, moved, false)
+		assert.NoError(t, LoadState(m2, 0, moved))
+		assert.Equal(t, filepath.Join(moved, "piano_C4.wav"), m2.SoundMakerSettings[2].Zones[0].File)
+		assert.Equal(t, 60, m2.SoundMakerSettings[2].Zones[0].RootNote)
+	})
+
 	t.Run("load nonexistent file", func(t *testing.T) {
 		m := model.NewModel(0, "", false)
 		err := LoadState(m, 0, "/path/that/does/not/exist")
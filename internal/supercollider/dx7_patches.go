@@ -27,12 +27,15 @@ func init() {
 }
 
 func GetDX7PatchCount() int {
-	return len(dx7Patches)
+	return len(dx7Patches) + len(dx7ImportedVoices)
 }
 
 func GetDX7PatchName(index int) (string, error) {
-	if index < 0 || index >= len(dx7Patches) {
-		return "", fmt.Errorf("patch index %d out of range [0, %d]", index, len(dx7Patches)-1)
+	if index < 0 || index >= GetDX7PatchCount() {
+		return "", fmt.Errorf("patch index %d out of range [0, %d]", index, GetDX7PatchCount()-1)
+	}
+	if index >= len(dx7Patches) {
+		return dx7ImportedVoices[index-len(dx7Patches)].Name, nil
 	}
 	return dx7Patches[index], nil
 }
@@ -52,8 +55,11 @@ func GetDX7PatchIndex(name string) (int, error) {
 }
 
 func GetAllDX7PatchNames() []string {
-	result := make([]string, len(dx7Patches))
+	result := make([]string, len(dx7Patches), GetDX7PatchCount())
 	copy(result, dx7Patches)
+	for _, voice := range dx7ImportedVoices {
+		result = append(result, voice.Name)
+	}
 	return result
 }
 
@@ -69,6 +75,7 @@ func SetDX7PatchByName(settings *types.SoundMakerSettings, patchName string) err
 
 	settings.SetParameterValue("preset", float32(index))
 	settings.PatchName = patchName
+	settings.DX7Voice = DX7VoiceRef(index)
 	return nil
 }
 
@@ -84,5 +91,6 @@ func SetDX7PatchByIndex(settings *types.SoundMakerSettings, index int) error {
 
 	settings.SetParameterValue("preset", float32(index))
 	settings.PatchName = patchName
+	settings.DX7Voice = DX7VoiceRef(index)
 	return nil
 }
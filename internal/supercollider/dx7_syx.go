@@ -0,0 +1,329 @@
+package supercollider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// DX7BankFolder is the name of the folder with imported DX7 banks in the config folder
+const DX7BankFolder = "dx7"
+
+// dx7BuiltinPresetMax is the highest preset of the embedded list that can be dialed in
+// while no banks are imported
+const dx7BuiltinPresetMax = 1000
+
+const (
+	dx7BankVoices     = 32
+	dx7PackedVoiceLen = 128
+	dx7BankDataLen    = dx7BankVoices * dx7PackedVoiceLen
+	dx7BankSysExLen   = 6 + dx7BankDataLen + 2 // header, voices, checksum and F7
+	dx7VoiceParamLen  = 145                    // parameters per patch line of DX7.afx
+)
+
+// DX7Voice is a voice of a SysEx bank in the parameter order of the patch lines in
+// DX7.afx, which is the order of cirklonCCparse in DX7.scd
+type DX7Voice struct {
+	Name       string
+	Parameters [dx7VoiceParamLen]int
+	Bank       string // Hash of the bank the voice came from
+	Slot       int    // Position of the voice in its bank (0-31)
+}
+
+// Imported voices follow the embedded patches, so the first one is preset 16384
+var dx7ImportedVoices []DX7Voice
+
+// DX7BankDir returns the folder the imported banks are kept in, e.g.
+// ~/.config/collidertracker/dx7
+func DX7BankDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", DX7BankFolder)
+}
+
+// ParseDX7Bank reads a 32-voice bulk dump (format 9) and unpacks its voices
+func ParseDX7Bank(data []byte) ([]DX7Voice, error) {
+	if len(data) < dx7BankSysExLen || data[0] != 0xF0 || data[1] != 0x43 || data[2]&0xF0 != 0 {
+		return nil, fmt.Errorf("not a Yamaha SysEx dump")
+	}
+	if data[3] != 0x09 || data[4] != 0x20 || data[5] != 0x00 {
+		return nil, fmt.Errorf("not a 32-voice DX7 bank (format %d)", data[3])
+	}
+	voiceData := data[6 : 6+dx7BankDataLen]
+	if data[6+dx7BankDataLen+1] != 0xF7 {
+		return nil, fmt.Errorf("SysEx dump is not terminated")
+	}
+	sum := 0
+	for _, b := range voiceData {
+		sum += int(b)
+	}
+	if checksum := byte(-sum) & 0x7F; data[6+dx7BankDataLen] != checksum {
+		return nil, fmt.Errorf("checksum %02X does not match %02X", data[6+dx7BankDataLen], checksum)
+	}
+
+	hash := sha256.Sum256(data[:dx7BankSysExLen])
+	bank := hex.EncodeToString(hash[:8])
+	voices := make([]DX7Voice, dx7BankVoices)
+	for i := range voices {
+		voices[i] = unpackDX7Voice(voiceData[i*dx7PackedVoiceLen : (i+1)*dx7PackedVoiceLen])
+		if voices[i].Name == "" {
+			voices[i].Name = fmt.Sprintf("Voice %d", i+1)
+		}
+		voices[i].Bank = bank
+		voices[i].Slot = i
+	}
+	return voices, nil
+}
+
+// unpackDX7Voice turns a packed voice (VMEM) into DX7.afx parameter order. The packed
+// voice stores operator 6 first; DX7.afx starts at operator 1.
+func unpackDX7Voice(v []byte) DX7Voice {
+	var voice DX7Voice
+	p := voice.Parameters[:0]
+	p = append(p,
+		int(v[110]&0x1F),                                   // algorithm
+		int(v[111]&0x07),                                   // feedback
+		int(v[117]),                                        // transpose
+		int(v[116]>>1&0x07),                                // LFO wave
+		int(v[112]),                                        // LFO speed
+		int(v[116]>>4&0x07),                                // pitch modulation sensitivity
+		int(v[114]),                                        // LFO pitch modulation depth
+		int(v[115]),                                        // LFO amplitude modulation depth
+		int(v[116]&0x01),                                   // LFO key sync
+		int(v[113]),                                        // LFO delay
+		int(v[111]>>3&0x01),                                // oscillator key sync
+		int(v[102]), int(v[103]), int(v[104]), int(v[105]), // pitch EG rates
+		int(v[106]), int(v[107]), int(v[108]), int(v[109]), // pitch EG levels
+	)
+	for op := range 6 {
+		o := v[(5-op)*17 : (6-op)*17]
+		p = append(p,
+			int(o[15]&0x01),    // oscillator mode (fixed frequency)
+			int(o[15]>>1&0x1F), // frequency coarse
+			int(o[16]),         // frequency fine
+			int(o[12]>>3&0x0F), // detune
+			int(o[12]&0x07),    // rate scaling
+			int(o[0]),          // EG rate 1
+			int(o[1]),          // EG rate 2
+			int(o[2]),          // EG rate 3
+			int(o[3]),          // EG rate 4
+			int(o[4]),          // EG level 1
+			int(o[5]),          // EG level 2
+			int(o[6]),          // EG level 3
+			int(o[7]),          // EG level 4
+			int(o[14]),         // output level
+			int(o[9]),          // level scaling left depth
+			int(o[10]),         // level scaling right depth
+			int(o[11]&0x03),    // level scaling left curve
+			int(o[11]>>2&0x03), // level scaling right curve
+			int(o[8]),          // level scaling break point
+			int(o[13]>>2&0x07), // key velocity sensitivity
+			int(o[13]&0x03),    // amplitude modulation sensitivity
+		)
+	}
+	for i := range voice.Parameters {
+		voice.Parameters[i] = max(0, min(99, voice.Parameters[i]&0x7F))
+	}
+
+	name := []byte(string(v[118:128]))
+	for i, c := range name {
+		if c < 32 || c > 126 {
+			name[i] = ' '
+		}
+	}
+	voice.Name = strings.TrimSpace(string(name))
+	return voice
+}
+
+// PatchLine returns the voice as a patch line of DX7.afx: two digits per parameter
+func (v DX7Voice) PatchLine() string {
+	var b strings.Builder
+	for _, value := range v.Parameters {
+		fmt.Fprintf(&b, "%02d", value)
+	}
+	return b.String()
+}
+
+// GetDX7VoicePatchLine returns the patch line of an imported voice, or false if the preset
+// is one of the embedded patches that DX7.scd reads from DX7.afx itself
+func GetDX7VoicePatchLine(index int) (string, bool) {
+	index -= len(dx7Patches)
+	if index < 0 || index >= len(dx7ImportedVoices) {
+		return "", false
+	}
+	return dx7ImportedVoices[index].PatchLine(), true
+}
+
+// DX7VoiceRef returns the bank and slot of an imported voice, e.g. "3f2a9c0d1e5b7a42/5", or
+// "" for the embedded patches. Unlike the preset, it does not depend on which banks were
+// imported before.
+func DX7VoiceRef(index int) string {
+	index -= len(dx7Patches)
+	if index < 0 || index >= len(dx7ImportedVoices) {
+		return ""
+	}
+	voice := dx7ImportedVoices[index]
+	return fmt.Sprintf("%s/%d", voice.Bank, voice.Slot)
+}
+
+// FindDX7Voice returns the preset of the imported voice with a reference from DX7VoiceRef
+func FindDX7Voice(ref string) (int, bool) {
+	for i, voice := range dx7ImportedVoices {
+		if fmt.Sprintf("%s/%d", voice.Bank, voice.Slot) == ref {
+			return len(dx7Patches) + i, true
+		}
+	}
+	return -1, false
+}
+
+// ResolveDX7Voice points the preset of a loaded DX7 at its imported voice again. The presets
+// of imported voices depend on the banks of this machine and their order, so the voice is
+// found by its bank and slot.
+func ResolveDX7Voice(settings *types.SoundMakerSettings) {
+	if settings.Name != "DX7" || settings.DX7Voice == "" {
+		return
+	}
+	index, ok := FindDX7Voice(settings.DX7Voice)
+	if !ok {
+		log.Printf("DX7 voice %s (%s) is not in the imported banks; import its bank to play it", settings.PatchName, settings.DX7Voice)
+		return
+	}
+	settings.SetParameterValue("preset", float32(index))
+}
+
+// GetDX7ImportedVoiceCount returns how many voices were imported from banks
+func GetDX7ImportedVoiceCount() int {
+	return len(dx7ImportedVoices)
+}
+
+// addDX7Voices appends imported voices to the patch list and returns the preset of the
+// first one. A name that is already taken keeps pointing at the earlier patch.
+func addDX7Voices(voices []DX7Voice) int {
+	first := GetDX7PatchCount()
+	for i, voice := range voices {
+		if _, exists := dx7PatchMap[voice.Name]; !exists {
+			dx7PatchMap[voice.Name] = first + i
+		}
+	}
+	dx7ImportedVoices = append(dx7ImportedVoices, voices...)
+	updateDX7PresetRange()
+	return first
+}
+
+// updateDX7PresetRange lets the DX7 preset parameter reach the imported voices
+func updateDX7PresetRange() {
+	def, exists := types.InstrumentRegistry["DX7"]
+	if !exists {
+		return
+	}
+	maxValue := float32(dx7BuiltinPresetMax)
+	if len(dx7ImportedVoices) > 0 {
+		maxValue = float32(GetDX7PatchCount() - 1)
+	}
+	for i := range def.Parameters {
+		if def.Parameters[i].Key == "preset" {
+			def.Parameters[i].MaxValue = maxValue
+		}
+	}
+}
+
+// resetDX7Voices drops all imported voices
+func resetDX7Voices() {
+	for _, voice := range dx7ImportedVoices {
+		if index := dx7PatchMap[voice.Name]; index >= len(dx7Patches) {
+			delete(dx7PatchMap, voice.Name)
+		}
+	}
+	dx7ImportedVoices = nil
+	updateDX7PresetRange()
+}
+
+// dx7BankPaths lists the banks in a folder in import order
+func dx7BankPaths(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".syx") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// LoadDX7Banks replaces the imported voices with the banks in a folder and returns the
+// number of voices loaded. Banks are numbered in import order so that the presets of
+// their voices stay the same from one run to the next.
+func LoadDX7Banks(dir string) int {
+	resetDX7Voices()
+	for _, path := range dx7BankPaths(dir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Skipping DX7 bank %s: %v", path, err)
+			continue
+		}
+		voices, err := ParseDX7Bank(data)
+		if err != nil {
+			log.Printf("Skipping DX7 bank %s: %v", path, err)
+			continue
+		}
+		addDX7Voices(voices)
+	}
+	return len(dx7ImportedVoices)
+}
+
+// ImportDX7Bank copies a bank into the bank folder and adds its voices to the patch list.
+// It returns the preset of the first voice of the bank; importing a bank a second time
+// returns the voices imported the first time.
+func ImportDX7Bank(dir, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return -1, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	voices, err := ParseDX7Bank(data)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	data = data[:dx7BankSysExLen]
+
+	first := len(dx7Patches)
+	number := 1
+	for _, existing := range dx7BankPaths(dir) {
+		var n int
+		if _, err := fmt.Sscanf(filepath.Base(existing), "%d", &n); err == nil {
+			number = max(number, n+1)
+		}
+		existingData, err := os.ReadFile(existing)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(existingData, data) {
+			return first, nil
+		}
+		if _, err := ParseDX7Bank(existingData); err == nil {
+			first += dx7BankVoices
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return -1, fmt.Errorf("failed to create bank folder %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("%03d %s", number, filepath.Base(path))
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return -1, fmt.Errorf("failed to save bank %s: %w", name, err)
+	}
+	return addDX7Voices(voices), nil
+}
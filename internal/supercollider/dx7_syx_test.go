@@ -0,0 +1,170 @@
+package supercollider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// testDX7Bank builds a bank whose voices are named name1, name2, ...
+func testDX7Bank(names ...string) []byte {
+	data := []byte{0xF0, 0x43, 0x00, 0x09, 0x20, 0x00}
+	voices := make([]byte, dx7BankDataLen)
+	for i := range dx7BankVoices {
+		v := voices[i*dx7PackedVoiceLen : (i+1)*dx7PackedVoiceLen]
+		for op := range 6 {
+			o := v[op*17 : (op+1)*17]
+			o[14] = byte(90 + op)   // output level: OP6 is stored first
+			o[15] = byte(op+1) << 1 // coarse
+		}
+		v[0] = 99         // OP6 EG rate 1
+		v[110] = 21       // algorithm 22
+		v[111] = 0x08 | 5 // key sync and feedback 5
+		v[116] = 0x21     // LFO pitch mod sensitivity 2, LFO sync
+		v[117] = 24       // transpose C3
+		copy(v[118:128], "          ")
+		if i < len(names) {
+			copy(v[118:128], names[i])
+		}
+	}
+	sum := 0
+	for _, b := range voices {
+		sum += int(b)
+	}
+	data = append(data, voices...)
+	return append(data, byte(-sum)&0x7F, 0xF7)
+}
+
+func TestParseDX7Bank(t *testing.T) {
+	voices, err := ParseDX7Bank(testDX7Bank("E.PIANO 1 ", "BASS\x001"))
+	require.NoError(t, err)
+	require.Len(t, voices, 32)
+
+	assert.Equal(t, "E.PIANO 1", voices[0].Name)
+	assert.Equal(t, "BASS 1", voices[1].Name)
+	assert.Equal(t, "Voice 3", voices[2].Name)
+
+	p := voices[0].Parameters
+	assert.Equal(t, 21, p[0])   // algorithm
+	assert.Equal(t, 5, p[1])    // feedback
+	assert.Equal(t, 24, p[2])   // transpose
+	assert.Equal(t, 2, p[5])    // pitch modulation sensitivity
+	assert.Equal(t, 1, p[8])    // LFO sync
+	assert.Equal(t, 1, p[10])   // oscillator key sync
+	assert.Equal(t, 6, p[20])   // OP1 coarse comes from the last packed operator
+	assert.Equal(t, 95, p[32])  // OP1 output level
+	assert.Equal(t, 1, p[125])  // OP6 coarse
+	assert.Equal(t, 99, p[129]) // OP6 EG rate 1
+	assert.Equal(t, 90, p[137]) // OP6 output level
+
+	line := voices[0].PatchLine()
+	assert.Len(t, line, 290)
+	assert.Equal(t, "210524", line[:6])
+
+	t.Run("rejects other dumps", func(t *testing.T) {
+		bank := testDX7Bank()
+		bank[6+dx7BankDataLen]++
+		_, err := ParseDX7Bank(bank)
+		assert.ErrorContains(t, err, "checksum")
+
+		bank = testDX7Bank()
+		bank[3] = 0x00
+		_, err = ParseDX7Bank(bank)
+		assert.ErrorContains(t, err, "32-voice")
+
+		_, err = ParseDX7Bank([]byte("RIFF"))
+		assert.Error(t, err)
+	})
+}
+
+func TestImportDX7Bank(t *testing.T) {
+	t.Cleanup(resetDX7Voices)
+	dir := filepath.Join(t.TempDir(), "dx7")
+	source := t.TempDir()
+	first := filepath.Join(source, "rom1a.syx")
+	second := filepath.Join(source, "brass.syx")
+	require.NoError(t, os.WriteFile(first, testDX7Bank("ROM PIANO"), 0644))
+	require.NoError(t, os.WriteFile(second, testDX7Bank("ROM BRASS"), 0644))
+
+	preset, err := ImportDX7Bank(dir, first)
+	require.NoError(t, err)
+	assert.Equal(t, len(dx7Patches), preset)
+	name, err := GetDX7PatchName(preset)
+	require.NoError(t, err)
+	assert.Equal(t, "ROM PIANO", name)
+	index, err := GetDX7PatchIndex("rom piano")
+	require.NoError(t, err)
+	assert.Equal(t, preset, index)
+	line, ok := GetDX7VoicePatchLine(preset)
+	assert.True(t, ok)
+	assert.Len(t, line, 290)
+	_, ok = GetDX7VoicePatchLine(0)
+	assert.False(t, ok, "embedded patches are read from DX7.afx")
+
+	def, _ := types.GetInstrumentDefinition("DX7")
+	param, _ := def.GetParameterByKey("preset")
+	assert.Equal(t, float32(len(dx7Patches)+31), param.MaxValue)
+
+	// A second bank follows the first; importing the first again changes nothing
+	preset, err = ImportDX7Bank(dir, second)
+	require.NoError(t, err)
+	assert.Equal(t, len(dx7Patches)+32, preset)
+	preset, err = ImportDX7Bank(dir, first)
+	require.NoError(t, err)
+	assert.Equal(t, len(dx7Patches), preset)
+	assert.Equal(t, 64, GetDX7ImportedVoiceCount())
+
+	// The copies in the bank folder come back in the same order on the next run
+	assert.Len(t, dx7BankPaths(dir), 2)
+	resetDX7Voices()
+	assert.Equal(t, 64, LoadDX7Banks(dir))
+	name, _ = GetDX7PatchName(len(dx7Patches) + 32)
+	assert.Equal(t, "ROM BRASS", name)
+
+	resetDX7Voices()
+	def, _ = types.GetInstrumentDefinition("DX7")
+	param, _ = def.GetParameterByKey("preset")
+	assert.Equal(t, float32(dx7BuiltinPresetMax), param.MaxValue)
+	_, err = GetDX7PatchIndex("ROM PIANO")
+	assert.Error(t, err)
+
+	_, err = ImportDX7Bank(dir, filepath.Join(source, "missing.syx"))
+	assert.Error(t, err)
+}
+
+func TestDX7VoiceRef(t *testing.T) {
+	t.Cleanup(resetDX7Voices)
+	source := t.TempDir()
+	first := filepath.Join(source, "rom1a.syx")
+	second := filepath.Join(source, "brass.syx")
+	require.NoError(t, os.WriteFile(first, testDX7Bank("ROM PIANO"), 0644))
+	require.NoError(t, os.WriteFile(second, testDX7Bank("ROM BRASS", "ROM HORN"), 0644))
+
+	_, err := ImportDX7Bank(filepath.Join(t.TempDir(), "dx7"), first)
+	require.NoError(t, err)
+	preset, err := ImportDX7Bank(filepath.Join(t.TempDir(), "dx7"), second)
+	require.NoError(t, err)
+	settings := types.SoundMakerSettings{Name: "DX7", Parameters: map[string]float32{}}
+	require.NoError(t, SetDX7PatchByIndex(&settings, preset+1))
+	assert.Equal(t, "ROM HORN", settings.PatchName)
+	assert.NotEmpty(t, settings.DX7Voice)
+	assert.Empty(t, DX7VoiceRef(0), "embedded patches have no bank")
+
+	// On a machine with only the second bank the voice has another preset
+	resetDX7Voices()
+	dir := filepath.Join(t.TempDir(), "dx7")
+	_, err = ImportDX7Bank(dir, second)
+	require.NoError(t, err)
+	ResolveDX7Voice(&settings)
+	assert.Equal(t, float32(len(dx7Patches)+1), settings.GetParameterValue("preset"))
+
+	// Without the bank the preset is left alone
+	resetDX7Voices()
+	ResolveDX7Voice(&settings)
+	assert.Equal(t, float32(len(dx7Patches)+1), settings.GetParameterValue("preset"))
+}
@@ -267,6 +267,7 @@ func ExtractSynthDefNames(scdContent string) []string {
 		"playback":      true,
 		"diskout":       true,
 		"out":           true,
+		"lfo":           true,
 	}
 	var filteredNames []string
 	for _, name := range names {
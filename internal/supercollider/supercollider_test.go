@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/schollz/collidertracker/internal/types"
 )
 
 func TestFileExists(t *testing.T) {
@@ -241,4 +243,12 @@ func TestGetSynthDefNames(t *testing.T) {
 		// Should not be empty
 		assert.NotEmpty(t, result)
 	})
+
+	t.Run("has a SynthDef for every insert FX module", func(t *testing.T) {
+		result := GetSynthDefNames()
+		assert.Contains(t, result, "insertOut")
+		for _, module := range types.InsertFXModules {
+			assert.Contains(t, result, module.SynthDef, module.Name)
+		}
+	})
 }
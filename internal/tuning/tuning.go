@@ -0,0 +1,412 @@
+// Package tuning loads microtonal tunings from Scala files and maps the keys of the
+// tracker onto them.
+//
+// A tuning called "Bohlen-Pierce" is the scale Bohlen-Pierce.scl in one of the tuning
+// folders, optionally with a keyboard mapping Bohlen-Pierce.kbm next to it. Without a
+// mapping, degree 0 of the scale is on key 60 (c-4) at 261.63 Hz and every following key
+// plays the next degree. See https://www.huygens-fokker.org/scala/scl_format.html for
+// the file formats.
+package tuning
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Folder is the name of the tuning folder in the config folder and in a project
+const Folder = "tunings"
+
+// EqualTemperament is the name of the standard tuning, which needs no file
+const EqualTemperament = "12-TET"
+
+// Scale is a Scala scale: the pitches of its degrees above the 1/1 in cents. The last
+// pitch is the period the scale repeats at, usually the octave (1200 cents).
+type Scale struct {
+	Description string
+	Cents       []float64
+}
+
+// KeyboardMapping is a Scala keyboard mapping: which degree of the scale each key plays
+type KeyboardMapping struct {
+	Size               int     // Keys in the repeating pattern (0 = each key plays the next degree)
+	FirstNote          int     // Lowest key that is retuned
+	LastNote           int     // Highest key that is retuned
+	MiddleNote         int     // Key that plays degree 0 (the first key of the pattern)
+	ReferenceNote      int     // Key whose frequency is given
+	ReferenceFrequency float64 // Frequency of ReferenceNote in Hz
+	OctaveDegree       int     // Degree the pattern moves up by each time it repeats (0 = period of the scale)
+	Mapping            []int   // Degree of each key of the pattern (-1 = key does not play)
+}
+
+// DefaultKeyboardMapping maps each key to the next degree, with degree 0 on c-4 at the
+// frequency it has in 12-TET
+func DefaultKeyboardMapping() KeyboardMapping {
+	return KeyboardMapping{
+		FirstNote:          0,
+		LastNote:           127,
+		MiddleNote:         60,
+		ReferenceNote:      60,
+		ReferenceFrequency: 440 * math.Pow(2, -9.0/12),
+	}
+}
+
+// Tuning is a scale laid out on the keys by a keyboard mapping
+type Tuning struct {
+	Name    string
+	Scale   Scale
+	Mapping KeyboardMapping
+}
+
+// UserDir returns the folder shared by all projects, e.g. ~/.config/collidertracker/tunings
+func UserDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "collidertracker", Folder)
+}
+
+// ProjectDir returns the tuning folder of a project
+func ProjectDir(saveFolder string) string {
+	return filepath.Join(saveFolder, Folder)
+}
+
+// scalaLines returns the lines of a Scala file that are not comments
+func scalaLines(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
+
+// firstField returns what a Scala line holds; anything after it is a comment
+func firstField(line string) string {
+	if fields := strings.Fields(line); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// parsePitch reads a pitch of a scale: cents if it has a period, otherwise a ratio
+func parsePitch(text string) (float64, error) {
+	if strings.Contains(text, ".") {
+		return strconv.ParseFloat(text, 64)
+	}
+	numerator, denominator, isRatio := strings.Cut(text, "/")
+	n, err := strconv.ParseInt(numerator, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	d := int64(1)
+	if isRatio {
+		if d, err = strconv.ParseInt(denominator, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	if n <= 0 || d <= 0 {
+		return 0, fmt.Errorf("ratio %s is not positive", text)
+	}
+	return 1200 * math.Log2(float64(n)/float64(d)), nil
+}
+
+// ParseScale reads the contents of a .scl file
+func ParseScale(data string) (Scale, error) {
+	var scale Scale
+	lines := scalaLines(data)
+	if len(lines) < 2 {
+		return scale, fmt.Errorf("missing description or note count")
+	}
+	scale.Description = lines[0]
+	count, err := strconv.Atoi(firstField(lines[1]))
+	if err != nil || count < 1 {
+		return scale, fmt.Errorf("invalid note count %q", lines[1])
+	}
+	pitches := lines[2:]
+	if len(pitches) < count {
+		return scale, fmt.Errorf("expected %d pitches, found %d", count, len(pitches))
+	}
+	for i, line := range pitches[:count] {
+		cents, err := parsePitch(firstField(line))
+		if err != nil {
+			return scale, fmt.Errorf("pitch %d: invalid value %q", i+1, line)
+		}
+		scale.Cents = append(scale.Cents, cents)
+	}
+	if scale.Cents[count-1] <= 0 {
+		return scale, fmt.Errorf("the scale does not repeat at a higher pitch")
+	}
+	return scale, nil
+}
+
+// ParseKeyboardMapping reads the contents of a .kbm file
+func ParseKeyboardMapping(data string) (KeyboardMapping, error) {
+	var mapping KeyboardMapping
+	var lines []string
+	for _, line := range scalaLines(data) {
+		if line != "" {
+			lines = append(lines, firstField(line))
+		}
+	}
+	if len(lines) < 7 {
+		return mapping, fmt.Errorf("expected 7 header values, found %d", len(lines))
+	}
+	header := make([]int, 7)
+	for i, line := range lines[:7] {
+		if i == 5 {
+			continue
+		}
+		value, err := strconv.Atoi(line)
+		if err != nil {
+			return mapping, fmt.Errorf("line %d: invalid value %q", i+1, line)
+		}
+		header[i] = value
+	}
+	frequency, err := strconv.ParseFloat(lines[5], 64)
+	if err != nil || frequency <= 0 {
+		return mapping, fmt.Errorf("invalid reference frequency %q", lines[5])
+	}
+	mapping = KeyboardMapping{
+		Size:               header[0],
+		FirstNote:          header[1],
+		LastNote:           header[2],
+		MiddleNote:         header[3],
+		ReferenceNote:      header[4],
+		ReferenceFrequency: frequency,
+		OctaveDegree:       header[6],
+	}
+	if mapping.Size < 0 {
+		return mapping, fmt.Errorf("invalid map size %d", mapping.Size)
+	}
+
+	// Keys the file leaves out do not play
+	for i := range mapping.Size {
+		degree := -1
+		if i+7 < len(lines) && lines[i+7] != "x" {
+			if degree, err = strconv.Atoi(lines[i+7]); err != nil || degree < 0 {
+				return mapping, fmt.Errorf("key %d: invalid degree %q", i, lines[i+7])
+			}
+		}
+		mapping.Mapping = append(mapping.Mapping, degree)
+	}
+	return mapping, nil
+}
+
+// Load reads a tuning from a .scl file and the .kbm file with the same name, if any
+func Load(path string) (*Tuning, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	scale, err := ParseScale(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	t := &Tuning{
+		Name:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Scale:   scale,
+		Mapping: DefaultKeyboardMapping(),
+	}
+
+	kbmPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".kbm"
+	if data, err := os.ReadFile(kbmPath); err == nil {
+		if t.Mapping, err = ParseKeyboardMapping(string(data)); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(kbmPath), err)
+		}
+	}
+	if _, ok := t.keyCents(t.Mapping.ReferenceNote); !ok {
+		return nil, fmt.Errorf("%s: the reference key %d does not play", t.Name, t.Mapping.ReferenceNote)
+	}
+	return t, nil
+}
+
+// LoadAll reads the tunings in the given folders. A tuning in a later folder replaces
+// one with the same name in an earlier folder, so pass the user folder before the
+// project folder.
+func LoadAll(dirs ...string) map[string]*Tuning {
+	tunings := make(map[string]*Tuning)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".scl") {
+				continue
+			}
+			t, err := Load(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				log.Printf("Skipping tuning: %v", err)
+				continue
+			}
+			tunings[t.Name] = t
+		}
+	}
+	return tunings
+}
+
+// Names returns the names of the tunings, sorted
+func Names(tunings map[string]*Tuning) []string {
+	names := make([]string, 0, len(tunings))
+	for name := range tunings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// floorDiv divides rounding down, so that keys below the middle note fall in earlier periods
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// Size returns the number of degrees in a period of the scale
+func (t *Tuning) Size() int {
+	return len(t.Scale.Cents)
+}
+
+// period returns the interval the scale repeats at in cents
+func (t *Tuning) period() float64 {
+	return t.Scale.Cents[len(t.Scale.Cents)-1]
+}
+
+// Degree returns the degree of the scale a key plays, counted from degree 0 on the middle
+// note (so the key an octave above plays degree Size()), or false if the key does not play
+func (t *Tuning) Degree(key int) (int, bool) {
+	m := t.Mapping
+	if key < m.FirstNote || key > m.LastNote {
+		return 0, false
+	}
+	offset := key - m.MiddleNote
+	if m.Size == 0 {
+		return offset, true
+	}
+	degree := m.Mapping[offset-floorDiv(offset, m.Size)*m.Size]
+	if degree < 0 {
+		return 0, false
+	}
+	octaveDegree := m.OctaveDegree
+	if octaveDegree == 0 {
+		octaveDegree = t.Size()
+	}
+	return degree + floorDiv(offset, m.Size)*octaveDegree, true
+}
+
+// DegreeCents returns the pitch of a degree above degree 0
+func (t *Tuning) DegreeCents(degree int) float64 {
+	n := t.Size()
+	period := floorDiv(degree, n)
+	cents := float64(period) * t.period()
+	if i := degree - period*n; i > 0 {
+		cents += t.Scale.Cents[i-1]
+	}
+	return cents
+}
+
+// keyCents returns the pitch a key plays above degree 0
+func (t *Tuning) keyCents(key int) (float64, bool) {
+	degree, ok := t.Degree(key)
+	if !ok {
+		return 0, false
+	}
+	return t.DegreeCents(degree), true
+}
+
+// keyNote returns the 12-TET note number of the pitch a key plays
+func (t *Tuning) keyNote(key int) (float64, bool) {
+	cents, ok := t.keyCents(key)
+	if !ok {
+		return 0, false
+	}
+	referenceCents, _ := t.keyCents(t.Mapping.ReferenceNote)
+	reference := 69 + 12*math.Log2(t.Mapping.ReferenceFrequency/440)
+	return reference + (cents-referenceCents)/100, true
+}
+
+// Note converts a key to the fractional 12-TET note number that SuperCollider plays, or
+// returns false if the key does not play in the tuning. A nil tuning is 12-TET. Keys
+// between two whole keys, as from pitch slides, fall between their pitches.
+func (t *Tuning) Note(key float32) (float32, bool) {
+	if t == nil {
+		return key, true
+	}
+	whole := math.Floor(float64(key))
+	fraction := float64(key) - whole
+	note, ok := t.keyNote(int(whole))
+	if !ok {
+		return 0, false
+	}
+	if fraction > 0 {
+		if next, ok := t.keyNote(int(whole) + 1); ok {
+			note += (next - note) * fraction
+		} else {
+			note += fraction
+		}
+	}
+	return float32(note), true
+}
+
+// Notes converts keys with Note and leaves out the keys that do not play
+func (t *Tuning) Notes(keys []float32) []float32 {
+	if t == nil {
+		return keys
+	}
+	notes := make([]float32, 0, len(keys))
+	for _, key := range keys {
+		if note, ok := t.Note(key); ok {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// Transpose converts an offset in keys, like the pitch of a sampler row, to semitones:
+// the interval between the middle note and the key that far away from it
+func (t *Tuning) Transpose(keys float32) float32 {
+	if t == nil {
+		return keys
+	}
+	middle, ok := t.Note(float32(t.Mapping.MiddleNote))
+	if !ok {
+		return keys
+	}
+	transposed, ok := t.Note(float32(t.Mapping.MiddleNote) + keys)
+	if !ok {
+		return keys
+	}
+	return transposed - middle
+}
+
+// DegreeName names the degree a key plays like MidiToNoteName names notes: the degree
+// followed by its period, with the period of the middle note numbered 4, e.g. "7-4" or
+// "124". Keys that do not play are "---".
+func (t *Tuning) DegreeName(key int) string {
+	degree, ok := t.Degree(key)
+	if !ok {
+		return "---"
+	}
+	n := t.Size()
+	period := floorDiv(degree, n)
+	degree -= period * n
+	period = (period + 4) % 10
+	if period < 0 {
+		period = -period
+	}
+	if degree >= 10 {
+		return fmt.Sprintf("%02d%d", degree%100, period)
+	}
+	return fmt.Sprintf("%d-%d", degree, period)
+}
@@ -0,0 +1,185 @@
+package tuning
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const justMajor = `! just.scl
+!
+Just major
+ 7
+!
+ 9/8
+ 5/4
+ 4/3
+ 3/2      fifth
+ 5/3
+ 15/8
+ 2
+`
+
+// whiteKeys plays a 7-note scale on the white keys, with a-4 at 440 Hz
+const whiteKeys = `! white.kbm
+12
+0
+127
+60
+69
+440.0
+7
+! Mapping
+0
+x
+1
+x
+2
+3
+x
+4
+x
+5
+x
+6
+`
+
+// equalDivisions returns a scale that splits the octave into n equal steps
+func equalDivisions(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d-EDO\n%d\n", n, n)
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%.5f\n", 1200*float64(i)/float64(n))
+	}
+	return b.String()
+}
+
+func writeTuning(t *testing.T, dir, name, scl, kbm string) string {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	path := filepath.Join(dir, name+".scl")
+	require.NoError(t, os.WriteFile(path, []byte(scl), 0644))
+	if kbm != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".kbm"), []byte(kbm), 0644))
+	}
+	return path
+}
+
+func TestParseScale(t *testing.T) {
+	scale, err := ParseScale(justMajor)
+	require.NoError(t, err)
+	assert.Equal(t, "Just major", scale.Description)
+	require.Len(t, scale.Cents, 7)
+	assert.InDelta(t, 203.910, scale.Cents[0], 0.001)
+	assert.InDelta(t, 701.955, scale.Cents[3], 0.001)
+	assert.InDelta(t, 1200, scale.Cents[6], 0.001)
+
+	scale, err = ParseScale(equalDivisions(19))
+	require.NoError(t, err)
+	assert.InDelta(t, 63.158, scale.Cents[0], 0.001)
+
+	for name, data := range map[string]string{
+		"too few pitches": "short\n3\n100.0\n200.0\n",
+		"bad ratio":       "bad\n1\n3/0\n",
+		"bad count":       "bad\nseven\n",
+		"empty":           "",
+		"no period":       "flat\n1\n0.0\n",
+	} {
+		_, err := ParseScale(data)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseKeyboardMapping(t *testing.T) {
+	mapping, err := ParseKeyboardMapping(whiteKeys)
+	require.NoError(t, err)
+	assert.Equal(t, 12, mapping.Size)
+	assert.Equal(t, 69, mapping.ReferenceNote)
+	assert.Equal(t, 440.0, mapping.ReferenceFrequency)
+	assert.Equal(t, 7, mapping.OctaveDegree)
+	assert.Equal(t, []int{0, -1, 1, -1, 2, 3, -1, 4, -1, 5, -1, 6}, mapping.Mapping)
+
+	_, err = ParseKeyboardMapping("12\n0\n127\n60\n69\n")
+	assert.Error(t, err)
+	_, err = ParseKeyboardMapping("1\n0\n127\n60\n69\nA440\n1\n0\n")
+	assert.Error(t, err)
+}
+
+func TestTuningNotes(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("12-TET is the identity", func(t *testing.T) {
+		tuning, err := Load(writeTuning(t, dir, "12", equalDivisions(12), ""))
+		require.NoError(t, err)
+		for _, key := range []float32{0, 21, 60, 60.5, 69, 127} {
+			note, ok := tuning.Note(key)
+			require.True(t, ok)
+			assert.InDelta(t, key, note, 1e-4)
+		}
+
+		var equal *Tuning
+		note, ok := equal.Note(61.25)
+		assert.True(t, ok)
+		assert.Equal(t, float32(61.25), note)
+		assert.Equal(t, float32(7), equal.Transpose(7))
+	})
+
+	t.Run("19 equal divisions", func(t *testing.T) {
+		tuning, err := Load(writeTuning(t, dir, "19", equalDivisions(19), ""))
+		require.NoError(t, err)
+		for key, expected := range map[float32]float32{60: 60, 61: 60.6316, 79: 72, 41: 48, 60.5: 60.3158} {
+			note, ok := tuning.Note(key)
+			require.True(t, ok)
+			assert.InDelta(t, expected, note, 1e-3, "key %v", key)
+		}
+		assert.InDelta(t, 12, tuning.Transpose(19), 1e-3)
+		assert.InDelta(t, -0.6316, tuning.Transpose(-1), 1e-3)
+
+		assert.Equal(t, "0-4", tuning.DegreeName(60))
+		assert.Equal(t, "114", tuning.DegreeName(71))
+		assert.Equal(t, "0-5", tuning.DegreeName(79))
+		assert.Equal(t, "183", tuning.DegreeName(59))
+	})
+
+	t.Run("keyboard mapping", func(t *testing.T) {
+		tuning, err := Load(writeTuning(t, dir, "just", justMajor, whiteKeys))
+		require.NoError(t, err)
+		for key, expected := range map[float32]float32{69: 69, 60: 60.1564, 67: 67.1760, 72: 72.1564, 48: 48.1564} {
+			note, ok := tuning.Note(key)
+			require.True(t, ok)
+			assert.InDelta(t, expected, note, 1e-3, "key %v", key)
+		}
+
+		// Black keys do not play
+		_, ok := tuning.Note(61)
+		assert.False(t, ok)
+		assert.Equal(t, "---", tuning.DegreeName(61))
+		assert.Len(t, tuning.Notes([]float32{60, 61, 62}), 2)
+
+		degree, ok := tuning.Degree(74)
+		assert.True(t, ok)
+		assert.Equal(t, 8, degree)
+	})
+
+	t.Run("reference key must play", func(t *testing.T) {
+		_, err := Load(writeTuning(t, dir, "bad", justMajor, strings.Replace(whiteKeys, "\n69\n", "\n70\n", 1)))
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadAll(t *testing.T) {
+	userDir := filepath.Join(t.TempDir(), "user")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	writeTuning(t, userDir, "19-EDO", equalDivisions(19), "")
+	writeTuning(t, userDir, "Just", justMajor, "")
+	writeTuning(t, userDir, "Broken", "broken\n", "")
+	writeTuning(t, projectDir, "Just", justMajor, whiteKeys)
+
+	tunings := LoadAll(userDir, projectDir, filepath.Join(t.TempDir(), "missing"))
+	assert.Equal(t, []string{"19-EDO", "Just"}, Names(tunings))
+	assert.Equal(t, 12, tunings["Just"].Mapping.Size, "the project tuning replaces the user one")
+}
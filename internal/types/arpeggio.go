@@ -0,0 +1,140 @@
+package types
+
+import "fmt"
+
+// ArpeggioMaxOctaves is the widest octave range of an arpeggio row
+const ArpeggioMaxOctaves = 4
+
+// ArpeggioMaxRatchet is the most hits a step can be split into
+const ArpeggioMaxRatchet = 8
+
+// ArpeggioDirectionToString converts an ArpeggioDirection to its display string
+func ArpeggioDirectionToString(direction ArpeggioDirection) string {
+	switch direction {
+	case ArpeggioDirectionUp:
+		return "u-"
+	case ArpeggioDirectionDown:
+		return "d-"
+	case ArpeggioDirectionUpDown:
+		return "ud"
+	case ArpeggioDirectionDownUp:
+		return "du"
+	case ArpeggioDirectionRandom:
+		return "rn"
+	case ArpeggioDirectionAsPlayed:
+		return "pl"
+	case ArpeggioDirectionConverge:
+		return "cv"
+	default:
+		return "--"
+	}
+}
+
+// ArpeggioDirectionName returns the name of a direction for the status line
+func ArpeggioDirectionName(direction ArpeggioDirection) string {
+	switch direction {
+	case ArpeggioDirectionUp:
+		return "up"
+	case ArpeggioDirectionDown:
+		return "down"
+	case ArpeggioDirectionUpDown:
+		return "up-down"
+	case ArpeggioDirectionDownUp:
+		return "down-up"
+	case ArpeggioDirectionRandom:
+		return "random"
+	case ArpeggioDirectionAsPlayed:
+		return "as played"
+	case ArpeggioDirectionConverge:
+		return "converge"
+	default:
+		return "off"
+	}
+}
+
+// NewArpeggioRow returns an empty arpeggio row
+func NewArpeggioRow() ArpeggioRow {
+	return ArpeggioRow{Direction: int(ArpeggioDirectionNone), Count: -1, Divisor: -1}
+}
+
+// IsEmpty reports whether nothing is set in the row
+func (r ArpeggioRow) IsEmpty() bool {
+	return r == NewArpeggioRow()
+}
+
+// Value returns the value of a column of the row
+func (r ArpeggioRow) Value(col ArpeggioUIColumn) int {
+	switch col {
+	case ArpeggioColDI:
+		return r.Direction
+	case ArpeggioColCO:
+		return r.Count
+	case ArpeggioColDIV:
+		return r.Divisor
+	case ArpeggioColOC:
+		return r.Octaves
+	case ArpeggioColVE:
+		return r.Velocity
+	case ArpeggioColGT:
+		return r.Gate
+	case ArpeggioColRS:
+		return r.Rest
+	case ArpeggioColRT:
+		return r.Ratchet
+	}
+	return -1
+}
+
+// SetValue sets a column of the row, clamped to the range of the column
+func (r *ArpeggioRow) SetValue(col ArpeggioUIColumn, value int) {
+	switch col {
+	case ArpeggioColDI:
+		r.Direction = max(0, min(int(ArpeggioDirectionCount)-1, value))
+	case ArpeggioColCO:
+		r.Count = max(-1, min(254, value))
+	case ArpeggioColDIV:
+		if value <= 0 {
+			value = -1 // There is no divisor 00
+		}
+		r.Divisor = min(254, value)
+	case ArpeggioColOC:
+		r.Octaves = max(0, min(ArpeggioMaxOctaves, value))
+	case ArpeggioColVE:
+		r.Velocity = max(0, min(127, value))
+	case ArpeggioColGT:
+		r.Gate = max(0, min(254, value))
+	case ArpeggioColRS:
+		r.Rest = max(0, min(1, value))
+	case ArpeggioColRT:
+		r.Ratchet = max(0, min(ArpeggioMaxRatchet, value))
+	}
+}
+
+// ClearValue resets a column of the row to "--"
+func (r *ArpeggioRow) ClearValue(col ArpeggioUIColumn) {
+	r.SetValue(col, NewArpeggioRow().Value(col))
+}
+
+// CellText returns how a column of a row is displayed in the arpeggio view
+func (r ArpeggioRow) CellText(col ArpeggioUIColumn) string {
+	value := r.Value(col)
+	switch col {
+	case ArpeggioColDI:
+		return ArpeggioDirectionToString(ArpeggioDirection(value))
+	case ArpeggioColRS:
+		if value == 1 {
+			return "RS"
+		}
+		return "--"
+	case ArpeggioColCO, ArpeggioColDIV:
+		if value == -1 {
+			return "--"
+		}
+	default:
+		// The step columns are "--" at zero
+		if value == 0 {
+			return "--"
+		}
+	}
+	return fmt.Sprintf("%02X", value)
+}
@@ -0,0 +1,187 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// AutomationLanesPerOwner is how many lanes a phrase or a chain can have
+const AutomationLanesPerOwner = 4
+
+// AutomationMaxTick is the last tick a breakpoint can sit on (FFF)
+const AutomationMaxTick = 0xFFF
+
+// AutomationInterval is how often playback sends the automated values
+const AutomationInterval = 20 * time.Millisecond
+
+// AutomationScope is what a lane runs over
+type AutomationScope int
+
+const (
+	AutomationScopePhrase AutomationScope = iota // 0: the ticks of a phrase
+	AutomationScopeChain                         // 1: the ticks of a chain, across its phrases
+)
+
+// AutomationOwner is the phrase or chain a lane belongs to. Instrument and sampler tracks
+// have their own phrases and chains.
+type AutomationOwner struct {
+	Scope   AutomationScope `json:"scope"`
+	Sampler bool            `json:"sampler"`
+	ID      int             `json:"id"` // Phrase or chain (00-FE)
+}
+
+// AutomationCurve is how a lane moves from a breakpoint to the next
+type AutomationCurve int
+
+const (
+	AutomationCurveLinear      AutomationCurve = iota // 0: "lin" straight line
+	AutomationCurveExponential                        // 1: "exp" slow start, fast end
+	AutomationCurveLogarithmic                        // 2: "log" fast start, slow end
+	AutomationCurveCount
+)
+
+var automationCurveNames = [AutomationCurveCount]string{"lin", "exp", "log"}
+
+// AutomationCurveName returns the display name of a curve
+func AutomationCurveName(curve AutomationCurve) string {
+	if curve < 0 || curve >= AutomationCurveCount {
+		return "--"
+	}
+	return automationCurveNames[curve]
+}
+
+// shape maps the position within a segment (0-1) onto how far the value has moved (0-1)
+func (c AutomationCurve) shape(t float64) float64 {
+	switch c {
+	case AutomationCurveExponential:
+		return (math.Pow(2, 4*t) - 1) / 15
+	case AutomationCurveLogarithmic:
+		return 1 - (math.Pow(2, 4*(1-t))-1)/15
+	}
+	return t
+}
+
+// AutomationPoint is a breakpoint of a lane
+type AutomationPoint struct {
+	Tick  int             `json:"tick"`  // Ticks from the start of the phrase or chain
+	Value int             `json:"value"` // 00-FE across the range of the target
+	Curve AutomationCurve `json:"curve"` // Curve to the next breakpoint
+}
+
+// AutomationLane is a breakpoint envelope that moves a parameter of the voices of the
+// tracks playing its phrase or chain
+type AutomationLane struct {
+	Owner  AutomationOwner   `json:"owner"`
+	Target string            `json:"target"` // ID of an automation target
+	Points []AutomationPoint `json:"points"` // In tick order
+}
+
+// ValueAt returns the value of the lane at a tick (00-FE, fractional between breakpoints).
+// The first value holds before the first breakpoint and the last one after the last.
+func (l AutomationLane) ValueAt(tick float64) float64 {
+	if len(l.Points) == 0 {
+		return 0
+	}
+	if tick <= float64(l.Points[0].Tick) {
+		return float64(l.Points[0].Value)
+	}
+	for i := 0; i < len(l.Points)-1; i++ {
+		from, to := l.Points[i], l.Points[i+1]
+		if tick >= float64(to.Tick) {
+			continue
+		}
+		t := (tick - float64(from.Tick)) / float64(to.Tick-from.Tick)
+		return float64(from.Value) + float64(to.Value-from.Value)*from.Curve.shape(t)
+	}
+	return float64(l.Points[len(l.Points)-1].Value)
+}
+
+// AutomationPitchTarget is the pitch of sampler voices, which LFOs do not move
+var AutomationPitchTarget = LFOTarget{Key: "pitch", Name: "Pitch", Min: -24, Max: 24, Span: 24}
+
+// AutomationColumnTargets are the phrase columns a lane can move. They are not sent to the
+// voices: a row that starts playing takes the value of the lane instead of its own.
+var AutomationColumnTargets = []LFOTarget{
+	{Key: "columnGate", Name: "Gate (GT)", Min: 0, Max: 254, Center: 0x80},
+	{Key: "columnVelocity", Name: "Velocity (VE)", Min: 0, Max: 127, Center: 0x40},
+	{Key: "columnSampleStart", Name: "Sample start (ST)", Min: 0, Max: 254, Center: 0},
+	{Key: "columnSampleLength", Name: "Sample length (LN)", Min: 0, Max: 254, Center: 254},
+}
+
+var automationColumns = map[string]PhraseColumn{
+	"columnGate":         ColGate,
+	"columnVelocity":     ColVelocity,
+	"columnSampleStart":  ColSampleStart,
+	"columnSampleLength": ColSampleLength,
+}
+
+// AutomationColumn returns the phrase column an automation target moves, if it is one
+func AutomationColumn(id string) (PhraseColumn, bool) {
+	col, ok := automationColumns[id]
+	return col, ok
+}
+
+// AutomationTargets returns what a lane can move: the pitch of samplers, the phrase columns
+// and every LFO target
+func AutomationTargets() []LFOTarget {
+	targets := append([]LFOTarget{AutomationPitchTarget}, AutomationColumnTargets...)
+	return append(targets, LFOTargets()...)
+}
+
+// FindAutomationTarget returns the automation target with an ID
+func FindAutomationTarget(id string) (LFOTarget, bool) {
+	if id == AutomationPitchTarget.ID() {
+		return AutomationPitchTarget, true
+	}
+	for _, target := range AutomationColumnTargets {
+		if target.ID() == id {
+			return target, true
+		}
+	}
+	return FindLFOTarget(id)
+}
+
+// AutomationTargetName returns the name of a target for display, "--" for none
+func AutomationTargetName(id string) string {
+	if target, ok := FindAutomationTarget(id); ok {
+		return target.Name
+	}
+	return LFOTargetName(id)
+}
+
+// ValueFromHex maps a lane value (00-FE) onto the range of the target
+func (t LFOTarget) ValueFromHex(hex float64) float32 {
+	ratio := max(0, min(hex/254, 1))
+	if t.Exponential {
+		return float32(float64(t.Min) * math.Pow(float64(t.Max/t.Min), ratio))
+	}
+	return float32(float64(t.Min) + float64(t.Max-t.Min)*ratio)
+}
+
+// HexFromValue is the inverse of ValueFromHex, rounded to the closest lane value
+func (t LFOTarget) HexFromValue(value float32) int {
+	var ratio float64
+	if t.Exponential {
+		ratio = math.Log(float64(value/t.Min)) / math.Log(float64(t.Max/t.Min))
+	} else {
+		ratio = float64(value-t.Min) / float64(t.Max-t.Min)
+	}
+	return int(math.Round(max(0, min(ratio, 1)) * 254))
+}
+
+// AutomationUIColumn is a column of the breakpoints in the automation view
+type AutomationUIColumn int
+
+const (
+	AutomationColTick  AutomationUIColumn = iota // 0: TICK
+	AutomationColValue                           // 1: VAL
+	AutomationColCurve                           // 2: CURVE
+	AutomationColCount
+)
+
+// Rows of the automation view above the breakpoints
+const (
+	AutomationRowLane       = 0 // Lane of the phrase or chain being edited
+	AutomationRowTarget     = 1 // Target of the lane
+	AutomationRowFirstPoint = 2 // Breakpoints follow, then a row that adds one
+)
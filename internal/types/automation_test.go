@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationLaneValueAt(t *testing.T) {
+	lane := AutomationLane{Points: []AutomationPoint{
+		{Tick: 4, Value: 0x00},
+		{Tick: 12, Value: 0x80, Curve: AutomationCurveExponential},
+		{Tick: 20, Value: 0x00, Curve: AutomationCurveLogarithmic},
+		{Tick: 20, Value: 0xFE},
+	}}
+
+	// The first and last values hold outside the breakpoints
+	assert.Equal(t, 0.0, lane.ValueAt(0))
+	assert.Equal(t, 254.0, lane.ValueAt(40))
+
+	// Linear halfway, exponential slow to start and logarithmic quick to start
+	assert.InDelta(t, 64, lane.ValueAt(8), 1e-9)
+	assert.Less(t, 128-lane.ValueAt(16), 64.0)
+	assert.InDelta(t, 128, lane.ValueAt(12), 1e-9)
+
+	// Breakpoints on the same tick jump
+	assert.Equal(t, 254.0, lane.ValueAt(20))
+	assert.Equal(t, 0.0, AutomationLane{}.ValueAt(3))
+}
+
+func TestAutomationTargets(t *testing.T) {
+	targets := AutomationTargets()
+	assert.Equal(t, AutomationPitchTarget, targets[0])
+	columns := len(AutomationColumnTargets)
+	assert.Equal(t, AutomationColumnTargets, targets[1:columns+1])
+	assert.Equal(t, LFOMixerTargets, targets[columns+1:columns+len(LFOMixerTargets)+1])
+	assert.Equal(t, "Pitch", AutomationTargetName("pitch"))
+	assert.Equal(t, "Gate (GT)", AutomationTargetName("columnGate"))
+	assert.Equal(t, "LP filter", AutomationTargetName("lowPassFilter"))
+
+	// Lane values span the range of the target, exponentially for frequencies
+	pan, ok := FindAutomationTarget("pan")
+	require.True(t, ok)
+	assert.Equal(t, float32(0), pan.ValueFromHex(127))
+	assert.Equal(t, 127, pan.HexFromValue(pan.Center))
+	lowPass, ok := FindAutomationTarget("lowPassFilter")
+	require.True(t, ok)
+	assert.InDelta(t, 632.46, lowPass.ValueFromHex(127), 0.01)
+	assert.Equal(t, 254, lowPass.HexFromValue(lowPass.Center))
+	assert.Equal(t, 127, lowPass.HexFromValue(lowPass.ValueFromHex(127)))
+	pitch, ok := FindAutomationTarget("pitch")
+	require.True(t, ok)
+	assert.Equal(t, float32(24), pitch.ValueFromHex(300))
+
+	// Phrase columns keep the hex values of their column
+	for _, target := range AutomationColumnTargets {
+		_, ok := AutomationColumn(target.ID())
+		assert.True(t, ok, target.ID())
+	}
+	velocity, ok := FindAutomationTarget("columnVelocity")
+	require.True(t, ok)
+	assert.Equal(t, float32(127), velocity.ValueFromHex(254))
+	assert.Equal(t, 0x80, velocity.HexFromValue(velocity.Center))
+	col, ok := AutomationColumn("columnGate")
+	require.True(t, ok)
+	assert.Equal(t, ColGate, col)
+	_, ok = AutomationColumn("pan")
+	assert.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package types
+
+import (
+	"math"
+	"slices"
+)
+
+// chordShape lists the intervals of a chord type above the root, and the 7th that the
+// "7" addition adds to it
+type chordShape struct {
+	intervals []int
+	seventh   int
+}
+
+var chordShapes = [ChordTypeCount]chordShape{
+	ChordMajor:          {intervals: []int{4, 7}, seventh: 11},
+	ChordMinor:          {intervals: []int{3, 7}, seventh: 10},
+	ChordDominant:       {intervals: []int{4, 7}, seventh: 11}, // the 7 addition has always added a major 7th here
+	ChordSus2:           {intervals: []int{2, 7}, seventh: 10},
+	ChordSus4:           {intervals: []int{5, 7}, seventh: 10},
+	ChordDiminished:     {intervals: []int{3, 6}, seventh: 9},
+	ChordAugmented:      {intervals: []int{4, 8}, seventh: 10},
+	ChordMajor7:         {intervals: []int{4, 7, 11}, seventh: 11},
+	ChordHalfDiminished: {intervals: []int{3, 6, 10}, seventh: 10},
+	ChordSixth:          {intervals: []int{4, 7, 9}, seventh: 10},
+	ChordAddNine:        {intervals: []int{4, 7, 14}, seventh: 10},
+}
+
+// addChordTone appends a note unless the chord already has it
+func addChordTone(notes []int, note int) []int {
+	if slices.Contains(notes, note) {
+		return notes
+	}
+	return append(notes, note)
+}
+
+// ChordVoicing represents how the notes of a chord are spread out (V column)
+type ChordVoicing int
+
+const (
+	ChordVoicingClose  ChordVoicing = iota // "-" (default) notes as GetChordNotes stacks them
+	ChordVoicingDrop2                      // "2" second voice from the top down an octave
+	ChordVoicingSpread                     // "S" lowest voice down an octave below the rest
+	ChordVoicingOpen                       // "O" every other voice from the bottom up an octave
+	ChordVoicingLead                       // "L" the inversion closest to the previous chord
+	ChordVoicingCount                      // Total number of chord voicings
+)
+
+// ChordVoicingToString converts a ChordVoicing enum to its display string
+func ChordVoicingToString(voicing ChordVoicing) string {
+	switch voicing {
+	case ChordVoicingDrop2:
+		return "2"
+	case ChordVoicingSpread:
+		return "S"
+	case ChordVoicingOpen:
+		return "O"
+	case ChordVoicingLead:
+		return "L"
+	default:
+		return "-"
+	}
+}
+
+// ChordVoicingName returns the name of a voicing for the status line
+func ChordVoicingName(voicing ChordVoicing) string {
+	switch voicing {
+	case ChordVoicingDrop2:
+		return "drop-2"
+	case ChordVoicingSpread:
+		return "spread"
+	case ChordVoicingOpen:
+		return "open"
+	case ChordVoicingLead:
+		return "voice leading"
+	default:
+		return "close"
+	}
+}
+
+// VoiceChord returns the notes of a chord in a voicing. Notes are the chord as
+// GetChordNotes returns it; previous is the chord played before on the track, which
+// voice leading moves from (without one the chord stays as it is).
+func VoiceChord(notes []int, voicing ChordVoicing, previous []int) []int {
+	if len(notes) < 2 {
+		return notes
+	}
+	voiced := slices.Clone(notes)
+	switch voicing {
+	case ChordVoicingDrop2:
+		slices.Sort(voiced)
+		voiced[len(voiced)-2] -= 12
+	case ChordVoicingSpread:
+		slices.Sort(voiced)
+		voiced[0] -= 12
+	case ChordVoicingOpen:
+		slices.Sort(voiced)
+		for i := 1; i < len(voiced); i += 2 {
+			voiced[i] += 12
+		}
+	case ChordVoicingLead:
+		if len(previous) == 0 {
+			return voiced
+		}
+		voiced = leadChord(voiced, previous)
+	default:
+		return voiced
+	}
+	slices.Sort(voiced)
+	return voiced
+}
+
+// leadChord tries every inversion of a chord in every nearby octave and returns the one
+// whose notes move the least from the previous chord
+func leadChord(notes, previous []int) []int {
+	sorted := slices.Clone(notes)
+	slices.Sort(sorted)
+
+	var best []int
+	bestCost := math.MaxInt
+	for inversion := range sorted {
+		candidate := RotateChord(slices.Clone(sorted), ChordTrans0+ChordTransposition(inversion))
+		for octave := -3; octave <= 3; octave++ {
+			shifted := make([]int, len(candidate))
+			for i, note := range candidate {
+				shifted[i] = note + octave*12
+			}
+			if cost := voiceLeadingCost(shifted, previous); cost < bestCost {
+				best, bestCost = shifted, cost
+			}
+		}
+	}
+	return best
+}
+
+// voiceLeadingCost sums how far each note of one chord is from the closest note of the
+// other, both ways, so that chords of different sizes can be compared
+func voiceLeadingCost(a, b []int) int {
+	cost := 0
+	for _, pair := range [2][2][]int{{a, b}, {b, a}} {
+		for _, note := range pair[0] {
+			nearest := math.MaxInt
+			for _, other := range pair[1] {
+				nearest = min(nearest, max(note-other, other-note))
+			}
+			cost += nearest
+		}
+	}
+	return cost
+}
+
+// ChordTones returns the notes of a played chord the way an arpeggio walks them:
+// ascending and without doubles, together with the interval after which the pattern
+// repeats (the octaves the voicing spans)
+func ChordTones(notes []float32) ([]float32, float32) {
+	tones := slices.Clone(notes)
+	slices.Sort(tones)
+	tones = slices.Compact(tones)
+	if len(tones) == 0 {
+		return tones, 12
+	}
+	octaves := math.Floor(float64(tones[len(tones)-1]-tones[0])/12) + 1
+	return tones, float32(octaves * 12)
+}
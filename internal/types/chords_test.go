@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChordShapes(t *testing.T) {
+	tests := []struct {
+		ctype ChordType
+		add   ChordAddition
+		want  []int
+	}{
+		{ChordSus2, ChordAddNone, []int{60, 62, 67}},
+		{ChordSus4, ChordAdd7, []int{60, 65, 67, 70}},
+		{ChordDiminished, ChordAdd7, []int{60, 63, 66, 69}},
+		{ChordAugmented, ChordAddNone, []int{60, 64, 68}},
+		{ChordMajor7, ChordAdd7, []int{60, 64, 67, 71}}, // already has its 7th
+		{ChordHalfDiminished, ChordAddNone, []int{60, 63, 66, 70}},
+		{ChordSixth, ChordAddNone, []int{60, 64, 67, 69}},
+		{ChordAddNine, ChordAdd9, []int{60, 64, 67, 74}},
+	}
+	for _, tt := range tests {
+		t.Run(ChordTypeToString(tt.ctype), func(t *testing.T) {
+			assert.Equal(t, tt.want, GetChordNotes(60, tt.ctype, tt.add, ChordTransNone))
+		})
+	}
+
+	// Inversions rotate the new shapes like the old ones
+	assert.Equal(t, []int{63, 66, 70, 72}, GetChordNotes(60, ChordHalfDiminished, ChordAddNone, ChordTrans1))
+	assert.Equal(t, []int{60}, GetChordNotes(60, ChordTypeCount, ChordAddNone, ChordTransNone))
+}
+
+func TestVoiceChord(t *testing.T) {
+	cmaj7 := []int{60, 64, 67, 71}
+
+	assert.Equal(t, cmaj7, VoiceChord(cmaj7, ChordVoicingClose, nil))
+	assert.Equal(t, []int{55, 60, 64, 71}, VoiceChord(cmaj7, ChordVoicingDrop2, nil))
+	assert.Equal(t, []int{48, 64, 67, 71}, VoiceChord(cmaj7, ChordVoicingSpread, nil))
+	assert.Equal(t, []int{60, 67, 76, 83}, VoiceChord(cmaj7, ChordVoicingOpen, nil))
+	assert.Equal(t, []int{60}, VoiceChord([]int{60}, ChordVoicingDrop2, nil))
+
+	// Voicing leaves the input alone
+	assert.Equal(t, []int{60, 64, 67, 71}, cmaj7)
+
+	t.Run("voice leading", func(t *testing.T) {
+		// Without a previous chord the chord is played as it is
+		c := GetChordNotes(60, ChordMajor, ChordAddNone, ChordTransNone)
+		assert.Equal(t, []int{60, 64, 67}, VoiceChord(c, ChordVoicingLead, nil))
+
+		// C -> F picks second inversion C F A, C -> G picks first inversion B D G
+		f := GetChordNotes(65, ChordMajor, ChordAddNone, ChordTransNone)
+		assert.Equal(t, []int{60, 65, 69}, VoiceChord(f, ChordVoicingLead, c))
+		g := GetChordNotes(67, ChordMajor, ChordAddNone, ChordTransNone)
+		assert.Equal(t, []int{59, 62, 67}, VoiceChord(g, ChordVoicingLead, c))
+
+		// An octave away the previous chord still pulls the next one to its register
+		am := GetChordNotes(45, ChordMinor, ChordAddNone, ChordTransNone)
+		assert.Equal(t, []int{60, 64, 69}, VoiceChord(am, ChordVoicingLead, c))
+	})
+}
+
+func TestChordTones(t *testing.T) {
+	tones, span := ChordTones([]float32{60, 63, 67, 65})
+	assert.Equal(t, []float32{60, 63, 65, 67}, tones)
+	assert.Equal(t, float32(12), span)
+
+	tones, span = ChordTones([]float32{48, 64, 67, 71, 64})
+	assert.Equal(t, []float32{48, 64, 67, 71}, tones)
+	assert.Equal(t, float32(24), span)
+}
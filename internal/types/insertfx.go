@@ -0,0 +1,224 @@
+package types
+
+import "fmt"
+
+// InsertFXSlotsPerTrack is how many modules the insert chain of a track can hold
+const InsertFXSlotsPerTrack = 8
+
+// InsertFXMaxDelay is the longest delay time of the delay module in seconds
+const InsertFXMaxDelay = 2
+
+// DelayDivisions are the tempo-synced delay times, selected by their index
+var DelayDivisions = []LFOSyncDivision{
+	{"1/32", 0.125},
+	{"1/16T", 1.0 / 6},
+	{"1/16", 0.25},
+	{"1/16D", 0.375},
+	{"1/8T", 1.0 / 3},
+	{"1/8", 0.5},
+	{"1/8D", 0.75},
+	{"1/4T", 2.0 / 3},
+	{"1/4", 1},
+	{"1/4D", 1.5},
+	{"1/2", 2},
+}
+
+// DelayDivisionSeconds returns the length of a delay division in seconds at a tempo
+func DelayDivisionSeconds(index int, bpm float32) float32 {
+	index = max(0, min(index, len(DelayDivisions)-1))
+	if bpm <= 0 {
+		bpm = 120
+	}
+	return float32(DelayDivisions[index].Beats * 60 / float64(bpm))
+}
+
+// FormatDelayDivision formats a delay division index as its name, e.g. "1/8D"
+func FormatDelayDivision(value float32) string {
+	index := int(value)
+	if index < 0 || index >= len(DelayDivisions) {
+		return "--"
+	}
+	return DelayDivisions[index].Name
+}
+
+// InsertFXModule is an effect that can be inserted on a track. Its parameters are
+// arguments of its SynthDef, which processes the bus of the track in place.
+type InsertFXModule struct {
+	Name        string // Name shown in the insert FX view
+	SynthDef    string // SynthDef of the module in collidertracker.scd
+	Description string
+	Parameters  []InstrumentParameterDef
+	TempoSynced string // Key of a parameter given as a DelayDivisions index and sent in seconds
+}
+
+// InsertFXModules are the modules in the order the insert FX view cycles through them
+var InsertFXModules = []InsertFXModule{
+	{
+		Name:        "EQ3",
+		SynthDef:    "fxEQ3",
+		Description: "3-band EQ: low and high shelves with a peak in between",
+		Parameters: []InstrumentParameterDef{
+			{Key: "low", DisplayName: "Low", Type: ParameterTypeFloat, MinValue: -24, MaxValue: 24, Default: 0, Order: 0, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+			{Key: "mid", DisplayName: "Mid", Type: ParameterTypeFloat, MinValue: -24, MaxValue: 24, Default: 0, Order: 1, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+			{Key: "high", DisplayName: "High", Type: ParameterTypeFloat, MinValue: -24, MaxValue: 24, Default: 0, Order: 2, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+			{Key: "freq", DisplayName: "Freq", Type: ParameterTypeFloat, MinValue: 200, MaxValue: 5000, Default: 1000, Order: 3, CoarseStep: 100, FineStep: 10, DisplayFormat: "%.0fHz"},
+		},
+	},
+	{
+		Name:        "Compressor",
+		SynthDef:    "fxCompressor",
+		Description: "Compressor with make-up gain",
+		Parameters: []InstrumentParameterDef{
+			{Key: "threshold", DisplayName: "Thresh", Type: ParameterTypeFloat, MinValue: -60, MaxValue: 0, Default: -18, Order: 0, CoarseStep: 6, FineStep: 1, DisplayFormat: "%.0fdB"},
+			{Key: "ratio", DisplayName: "Ratio", Type: ParameterTypeFloat, MinValue: 1, MaxValue: 20, Default: 4, Order: 1, CoarseStep: 1, FineStep: 0.1, DisplayFormat: "%.1f:1"},
+			{Key: "attack", DisplayName: "Attack", Type: ParameterTypeFloat, MinValue: 0.1, MaxValue: 100, Default: 10, Order: 2, CoarseStep: 10, FineStep: 0.5, DisplayFormat: "%.1fms"},
+			{Key: "release", DisplayName: "Release", Type: ParameterTypeFloat, MinValue: 10, MaxValue: 1000, Default: 100, Order: 3, CoarseStep: 50, FineStep: 5, DisplayFormat: "%.0fms"},
+			{Key: "makeup", DisplayName: "Makeup", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 24, Default: 0, Order: 4, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+		},
+	},
+	{
+		Name:        "Bitcrusher",
+		SynthDef:    "fxBitcrusher",
+		Description: "Bit depth reduction and sample rate decimation",
+		Parameters: []InstrumentParameterDef{
+			{Key: "bits", DisplayName: "Bits", Type: ParameterTypeInt, MinValue: 1, MaxValue: 16, Default: 8, Order: 0, CoarseStep: 4, FineStep: 1},
+			{Key: "rate", DisplayName: "Rate", Type: ParameterTypeFloat, MinValue: 100, MaxValue: 48000, Default: 11025, Order: 1, CoarseStep: 1000, FineStep: 100, DisplayFormat: "%.0fHz"},
+			{Key: "mix", DisplayName: "Mix", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 1, Order: 2, CoarseStep: 0.1, FineStep: 0.01},
+		},
+	},
+	{
+		Name:        "Delay",
+		SynthDef:    "fxDelay",
+		Description: "Tempo-synced feedback delay",
+		Parameters: []InstrumentParameterDef{
+			{Key: "time", DisplayName: "Time", Type: ParameterTypeInt, MinValue: 0, MaxValue: float32(len(DelayDivisions) - 1), Default: 5, Order: 0, CoarseStep: 1, FineStep: 1, DisplayFormatter: FormatDelayDivision},
+			{Key: "feedback", DisplayName: "Feedback", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 0.95, Default: 0.4, Order: 1, CoarseStep: 0.1, FineStep: 0.01},
+			{Key: "mix", DisplayName: "Mix", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 0.3, Order: 2, CoarseStep: 0.1, FineStep: 0.01},
+		},
+		TempoSynced: "time",
+	},
+	{
+		Name:        "Chorus",
+		SynthDef:    "fxChorus",
+		Description: "Stereo chorus",
+		Parameters: []InstrumentParameterDef{
+			{Key: "rate", DisplayName: "Rate", Type: ParameterTypeFloat, MinValue: 0.05, MaxValue: 5, Default: 0.5, Order: 0, CoarseStep: 0.5, FineStep: 0.05, DisplayFormat: "%.2fHz"},
+			{Key: "depth", DisplayName: "Depth", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 0.5, Order: 1, CoarseStep: 0.1, FineStep: 0.01},
+			{Key: "mix", DisplayName: "Mix", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 0.5, Order: 2, CoarseStep: 0.1, FineStep: 0.01},
+		},
+	},
+	{
+		Name:        "Distortion",
+		SynthDef:    "fxDistortion",
+		Description: "Soft-clipping distortion with a tone filter",
+		Parameters: []InstrumentParameterDef{
+			{Key: "drive", DisplayName: "Drive", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 48, Default: 12, Order: 0, CoarseStep: 6, FineStep: 1, DisplayFormat: "%.0fdB"},
+			{Key: "tone", DisplayName: "Tone", Type: ParameterTypeFloat, MinValue: 200, MaxValue: 20000, Default: 8000, Order: 1, CoarseStep: 1000, FineStep: 100, DisplayFormat: "%.0fHz"},
+			{Key: "mix", DisplayName: "Mix", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 1, Order: 2, CoarseStep: 0.1, FineStep: 0.01},
+		},
+	},
+}
+
+// GetInsertFXModule returns the module with a name
+func GetInsertFXModule(name string) (InsertFXModule, bool) {
+	for _, module := range InsertFXModules {
+		if module.Name == name {
+			return module, true
+		}
+	}
+	return InsertFXModule{}, false
+}
+
+// InsertFXSlot is a module in the insert chain of a track, with the values of its parameters
+type InsertFXSlot struct {
+	Module     string             `json:"module"`
+	Parameters map[string]float32 `json:"parameters"`
+}
+
+// NewInsertFXSlot returns a slot of a module with the defaults of its parameters
+func NewInsertFXSlot(name string) InsertFXSlot {
+	slot := InsertFXSlot{Module: name, Parameters: make(map[string]float32)}
+	if module, ok := GetInsertFXModule(name); ok {
+		for _, param := range module.Parameters {
+			slot.Parameters[param.Key] = param.Default
+		}
+	}
+	return slot
+}
+
+// Value returns the value of a parameter of the slot, the default when it was never set
+func (s InsertFXSlot) Value(param InstrumentParameterDef) float32 {
+	if value, ok := s.Parameters[param.Key]; ok {
+		return value
+	}
+	return param.Default
+}
+
+// InsertFXChain is the insert chain of a track. Its slots process the track in order.
+type InsertFXChain struct {
+	Slots []InsertFXSlot `json:"slots,omitempty"`
+}
+
+// Add appends a slot of a module, unless the chain is full
+func (c *InsertFXChain) Add(name string) bool {
+	if len(c.Slots) >= InsertFXSlotsPerTrack {
+		return false
+	}
+	c.Slots = append(c.Slots, NewInsertFXSlot(name))
+	return true
+}
+
+// Remove deletes a slot
+func (c *InsertFXChain) Remove(index int) {
+	if index < 0 || index >= len(c.Slots) {
+		return
+	}
+	c.Slots = append(c.Slots[:index], c.Slots[index+1:]...)
+}
+
+// Move swaps a slot with its neighbour in a direction and returns where the slot ended up
+func (c *InsertFXChain) Move(index, direction int) int {
+	target := index + direction
+	if index < 0 || index >= len(c.Slots) || target < 0 || target >= len(c.Slots) {
+		return index
+	}
+	c.Slots[index], c.Slots[target] = c.Slots[target], c.Slots[index]
+	return target
+}
+
+// InsertFXOSCValue returns the value of a parameter as the SynthDef of its module takes it.
+// Tempo-synced parameters are sent in seconds at the tempo, up to InsertFXMaxDelay.
+func InsertFXOSCValue(module InsertFXModule, param InstrumentParameterDef, value, bpm float32) float32 {
+	if module.TempoSynced != "" && param.Key == module.TempoSynced {
+		return min(DelayDivisionSeconds(int(value), bpm), InsertFXMaxDelay)
+	}
+	return value
+}
+
+// InsertFXHexToValue maps a phrase value (00-FE) over the range of a parameter
+func InsertFXHexToValue(param InstrumentParameterDef, hexValue int) float32 {
+	hexValue = max(0, min(hexValue, 254))
+	value := param.MinValue + (param.MaxValue-param.MinValue)*float32(hexValue)/254
+	if param.Type == ParameterTypeInt {
+		value = float32(int(value + 0.5))
+	}
+	return value
+}
+
+// DecodeInsertFXColumn splits an FX phrase value into the slot (high digit) and the
+// parameter of its module (low digit)
+func DecodeInsertFXColumn(value int) (slot, param int) {
+	return value >> 4, value & 0x0F
+}
+
+// FormatInsertFXValue formats the value of a parameter for display
+func FormatInsertFXValue(param InstrumentParameterDef, value float32) string {
+	if param.DisplayFormatter != nil {
+		return param.DisplayFormatter(value)
+	} else if param.DisplayFormat != "" {
+		return fmt.Sprintf(param.DisplayFormat, value)
+	} else if param.Type == ParameterTypeInt {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
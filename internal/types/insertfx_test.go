@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertFXChain(t *testing.T) {
+	var chain InsertFXChain
+	require.True(t, chain.Add("EQ3"))
+	require.True(t, chain.Add("Delay"))
+	assert.Equal(t, float32(0.4), chain.Slots[1].Parameters["feedback"])
+
+	assert.Equal(t, 0, chain.Move(1, -1))
+	assert.Equal(t, "Delay", chain.Slots[0].Module)
+	assert.Equal(t, 0, chain.Move(0, -1)) // Already first
+	chain.Remove(0)
+	assert.Equal(t, "EQ3", chain.Slots[0].Module)
+
+	for len(chain.Slots) < InsertFXSlotsPerTrack {
+		chain.Add("Chorus")
+	}
+	assert.False(t, chain.Add("Chorus"))
+
+	// Parameters missing from a save fall back to their default
+	module, ok := GetInsertFXModule("EQ3")
+	require.True(t, ok)
+	assert.Equal(t, float32(1000), InsertFXSlot{Module: "EQ3"}.Value(module.Parameters[3]))
+}
+
+func TestInsertFXValues(t *testing.T) {
+	delay, ok := GetInsertFXModule("Delay")
+	require.True(t, ok)
+	time := delay.Parameters[0]
+
+	// Delay times are note divisions, sent in seconds at the tempo
+	assert.Equal(t, "1/8", FormatInsertFXValue(time, 5))
+	assert.InDelta(t, 0.25, InsertFXOSCValue(delay, time, 5, 120), 1e-6)
+	assert.InDelta(t, InsertFXMaxDelay, InsertFXOSCValue(delay, time, 10, 30), 1e-6)
+	assert.Equal(t, float32(0.3), InsertFXOSCValue(delay, delay.Parameters[2], 0.3, 120))
+
+	// Phrase values span the range of a parameter, rounded for integers
+	assert.Equal(t, float32(0), InsertFXHexToValue(time, 0))
+	assert.Equal(t, float32(10), InsertFXHexToValue(time, 0xFE))
+	assert.Equal(t, float32(5), InsertFXHexToValue(time, 0x7F))
+	eq, _ := GetInsertFXModule("EQ3")
+	assert.Equal(t, float32(-24), InsertFXHexToValue(eq.Parameters[0], 0))
+	assert.Equal(t, "+0.0dB", FormatInsertFXValue(eq.Parameters[0], InsertFXHexToValue(eq.Parameters[0], 0x7F)))
+
+	slot, param := DecodeInsertFXColumn(0x12)
+	assert.Equal(t, 1, slot)
+	assert.Equal(t, 2, param)
+}
@@ -0,0 +1,192 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LFOCount is the number of LFOs in the pool of a project
+const LFOCount = 16
+
+// LFO rates run freely between these, in Hz
+const (
+	LFOMinRate     = 0.01
+	LFOMaxRate     = 50
+	DefaultLFORate = 1
+)
+
+// LFOWaveform is the shape an LFO moves its target with
+type LFOWaveform int
+
+const (
+	LFOWaveformSine         LFOWaveform = iota // 0: "sine"
+	LFOWaveformTriangle                        // 1: "tri"
+	LFOWaveformSawUp                           // 2: "saw" rising
+	LFOWaveformSawDown                         // 3: "ramp" falling
+	LFOWaveformSquare                          // 4: "sqr"
+	LFOWaveformSampleHold                      // 5: "s&h" a random level held for each cycle
+	LFOWaveformSmoothRandom                    // 6: "drift" random levels glided between
+	LFOWaveformCount
+)
+
+var lfoWaveformNames = [LFOWaveformCount]string{"sine", "tri", "saw", "ramp", "sqr", "s&h", "drift"}
+
+// LFOWaveformName returns the display name of a waveform
+func LFOWaveformName(waveform LFOWaveform) string {
+	if waveform < 0 || waveform >= LFOWaveformCount {
+		return "--"
+	}
+	return lfoWaveformNames[waveform]
+}
+
+// LFOSyncDivision is a tempo-synced LFO rate
+type LFOSyncDivision struct {
+	Name  string
+	Beats float64 // Length of a cycle in beats
+}
+
+// LFOSyncDivisions are the tempo-synced rates, selected by LFOSettings.Sync starting from 1
+var LFOSyncDivisions = []LFOSyncDivision{
+	{"1/16", 0.25},
+	{"1/8T", 1.0 / 3},
+	{"1/8", 0.5},
+	{"1/4T", 2.0 / 3},
+	{"1/4", 1},
+	{"1/2", 2},
+	{"1bar", 4},
+	{"2bar", 8},
+	{"4bar", 16},
+	{"8bar", 32},
+}
+
+// LFOSettings is one LFO of the pool. An LFO without a target is off.
+type LFOSettings struct {
+	Waveform  int     `json:"waveform"`  // LFOWaveform
+	Rate      float32 `json:"rate"`      // Free rate in Hz (LFOMinRate-LFOMaxRate)
+	Sync      int     `json:"sync"`      // 0 = free rate, 1+ = LFOSyncDivisions[Sync-1]
+	Depth     int     `json:"depth"`     // 0-100% of the span of the target
+	Phase     int     `json:"phase"`     // Start phase in degrees (0-345)
+	Retrigger bool    `json:"retrigger"` // Restart at the phase on every note of the track
+	Track     int     `json:"track"`     // Track the LFO moves (0-7)
+	Target    string  `json:"target"`    // ID of an LFOTarget, "" = off
+}
+
+// NewLFOSettings returns an LFO that is off
+func NewLFOSettings() LFOSettings {
+	return LFOSettings{Waveform: int(LFOWaveformSine), Rate: DefaultLFORate, Depth: 50}
+}
+
+// RateHz returns the rate of the LFO in Hz at a tempo
+func (s LFOSettings) RateHz(bpm float32) float32 {
+	if s.Sync > 0 && s.Sync <= len(LFOSyncDivisions) {
+		return float32(float64(bpm) / 60 / LFOSyncDivisions[s.Sync-1].Beats)
+	}
+	return s.Rate
+}
+
+// RateName returns the rate for display, "1.00" Hz or a division like "1/16"
+func (s LFOSettings) RateName() string {
+	if s.Sync > 0 && s.Sync <= len(LFOSyncDivisions) {
+		return LFOSyncDivisions[s.Sync-1].Name
+	}
+	return fmt.Sprintf("%.2f", s.Rate)
+}
+
+// LFOTarget is a parameter of the voices of a track that an LFO can move. The LFO moves
+// the value each row sets for the parameter up and down by the depth times the span.
+type LFOTarget struct {
+	Key         string  // Argument of the voices in SuperCollider
+	SoundMaker  string  // SoundMaker the parameter belongs to, "" for parameters of every voice
+	Name        string  // Name shown in the LFO view
+	Min         float32 // The value is kept within Min and Max
+	Max         float32
+	Span        float32 // How far full depth moves the value either way, in octaves if Exponential
+	Exponential bool    // Frequencies move by octaves
+	Center      float32 // Value moved until a note of the track sets it
+}
+
+// ID returns how LFO settings refer to the target
+func (t LFOTarget) ID() string {
+	if t.SoundMaker == "" {
+		return t.Key
+	}
+	return t.SoundMaker + "/" + t.Key
+}
+
+// LFOMixerTargets are the parameters of every sampler and SoundMaker voice
+var LFOMixerTargets = []LFOTarget{
+	{Key: "pan", Name: "Pan", Min: -1, Max: 1, Span: 1},
+	{Key: "lowPassFilter", Name: "LP filter", Min: 20, Max: 20000, Span: 5, Exponential: true, Center: 20000},
+	{Key: "highPassFilter", Name: "HP filter", Min: 20, Max: 20000, Span: 5, Exponential: true, Center: 20},
+	{Key: "effectComb", Name: "Comb", Min: 0, Max: 1, Span: 1},
+	{Key: "effectReverb", Name: "Reverb", Min: 0, Max: 1, Span: 1},
+	{Key: "trackVolume", Name: "Level", Min: -96, Max: 32, Span: 24},
+}
+
+// LFOTargets returns the mixer targets followed by the continuous (hex and float)
+// parameters of each SoundMaker
+func LFOTargets() []LFOTarget {
+	targets := append([]LFOTarget(nil), LFOMixerTargets...)
+	for _, name := range GetAvailableSoundMakers() {
+		if name == MultisampleSoundMakerName {
+			continue // Zones play through the sampler
+		}
+		def, _ := GetInstrumentDefinition(name)
+		for _, param := range def.Parameters {
+			target := LFOTarget{Key: param.Key, SoundMaker: name, Name: name + " " + param.DisplayName}
+			switch param.Type {
+			case ParameterTypeHex:
+				// Hex values reach the synth as 0.0-1.0
+				target.Min, target.Max, target.Span = 0, 1, 1
+				target.Center = max(0, param.Default) / 254
+			case ParameterTypeFloat:
+				target.Min, target.Max, target.Span = param.MinValue, param.MaxValue, param.MaxValue-param.MinValue
+				target.Center = param.Default
+			default:
+				continue
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// FindLFOTarget returns the target with an ID
+func FindLFOTarget(id string) (LFOTarget, bool) {
+	if id == "" {
+		return LFOTarget{}, false
+	}
+	for _, target := range LFOTargets() {
+		if target.ID() == id {
+			return target, true
+		}
+	}
+	return LFOTarget{}, false
+}
+
+// LFOTargetName returns the name of a target for display, "--" when the LFO is off
+func LFOTargetName(id string) string {
+	if target, ok := FindLFOTarget(id); ok {
+		return target.Name
+	}
+	if id == "" {
+		return "--"
+	}
+	// SoundMakers that are gone keep their routing until it is changed
+	return strings.ReplaceAll(id, "/", " ") + "?"
+}
+
+// LFOUIColumn is a column of the LFO view
+type LFOUIColumn int
+
+const (
+	LFOColWaveform  LFOUIColumn = iota // 0: WA waveform
+	LFOColRate                         // 1: RATE free rate in Hz
+	LFOColSync                         // 2: SYNC tempo-synced division
+	LFOColDepth                        // 3: DEP depth
+	LFOColPhase                        // 4: PH start phase
+	LFOColRetrigger                    // 5: RT retrigger on notes
+	LFOColTrack                        // 6: TR track
+	LFOColTarget                       // 7: TARGET
+	LFOColCount
+)
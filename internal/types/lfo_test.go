@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLFORate(t *testing.T) {
+	settings := NewLFOSettings()
+	assert.Equal(t, float32(1), settings.RateHz(120))
+	assert.Equal(t, "1.00", settings.RateName())
+
+	settings.Sync = 1 // 1/16
+	assert.InDelta(t, 8, settings.RateHz(120), 1e-4)
+	assert.Equal(t, "1/16", settings.RateName())
+	settings.Sync = 7 // 1bar
+	assert.InDelta(t, 0.5, settings.RateHz(120), 1e-4)
+	assert.Equal(t, "1bar", settings.RateName())
+}
+
+func TestLFOTargets(t *testing.T) {
+	targets := LFOTargets()
+	assert.Equal(t, LFOMixerTargets, targets[:len(LFOMixerTargets)])
+
+	// Hex parameters are moved in the 0.0-1.0 they reach the synth as
+	target, ok := FindLFOTarget("PolyPerc/A")
+	require.True(t, ok)
+	assert.Equal(t, "PolyPerc A", target.Name)
+	assert.Equal(t, float32(1), target.Max)
+
+	target, ok = FindLFOTarget("MiPlaits/timbre")
+	require.True(t, ok)
+	assert.Equal(t, float32(1000), target.Span)
+
+	// Switches and selectors are not continuous, and Multisample zones play through the sampler
+	_, ok = FindLFOTarget("MiPlaits/engine")
+	assert.False(t, ok)
+	_, ok = FindLFOTarget("PolyPerc/monophonic")
+	assert.False(t, ok)
+	for _, target := range targets {
+		assert.NotEqual(t, MultisampleSoundMakerName, target.SoundMaker)
+	}
+
+	assert.Equal(t, "LP filter", LFOTargetName("lowPassFilter"))
+	assert.Equal(t, "--", LFOTargetName(""))
+	assert.Equal(t, "Gone cutoff?", LFOTargetName("Gone/cutoff"))
+}
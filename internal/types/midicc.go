@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MidiCCColumns is the number of CC columns of instrument phrases (the ADSR and effect
+// columns in MI mode)
+const MidiCCColumns = 9
+
+// MidiCC is the controller a CC column sends to a MIDI device
+type MidiCC struct {
+	Number int    `json:"number"`         // CC number (0-127)
+	Name   string `json:"name,omitempty"` // Friendly name, e.g. "Cutoff"
+}
+
+// midiCCNames names the controllers of the MIDI spec and General MIDI 2
+var midiCCNames = map[int]string{
+	0: "Bank Select", 1: "Mod Wheel", 2: "Breath", 4: "Foot", 5: "Portamento Time",
+	6: "Data Entry", 7: "Volume", 8: "Balance", 10: "Pan", 11: "Expression",
+	32: "Bank LSB", 64: "Sustain", 65: "Portamento", 66: "Sostenuto", 67: "Soft Pedal",
+	71: "Resonance", 72: "Release", 73: "Attack", 74: "Cutoff", 75: "Decay",
+	76: "Vibrato Rate", 77: "Vibrato Depth", 78: "Vibrato Delay", 84: "Portamento Control",
+	91: "Reverb", 92: "Tremolo", 93: "Chorus", 94: "Detune", 95: "Phaser",
+}
+
+// MidiCCName returns the standard name of a controller, "" when it has none
+func MidiCCName(number int) string {
+	return midiCCNames[number]
+}
+
+// DefaultMidiCCs returns the CCs of the columns of new MIDI settings: CC 0-8 in column order
+func DefaultMidiCCs() [MidiCCColumns]MidiCC {
+	var ccs [MidiCCColumns]MidiCC
+	for i := range ccs {
+		ccs[i].Number = i
+	}
+	return ccs
+}
+
+// Label returns the two characters that head the column of a CC: the initials of its
+// name, or its number in hex when it has no name
+func (c MidiCC) Label() string {
+	words := strings.FieldsFunc(c.Name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var label []rune
+	switch {
+	case len(words) == 0:
+		return fmt.Sprintf("%02X", c.Number)
+	case len(words) == 1:
+		label = []rune(words[0])
+	default:
+		label = []rune{[]rune(words[0])[0], []rune(words[1])[0]}
+	}
+	if len(label) == 1 {
+		label = append(label, ' ')
+	}
+	return strings.ToUpper(string(label[:2]))
+}
+
+// String describes a CC for status lines, e.g. "CC 74 Cutoff"
+func (c MidiCC) String() string {
+	if c.Name == "" {
+		return fmt.Sprintf("CC %d", c.Number)
+	}
+	return fmt.Sprintf("CC %d %s", c.Number, c.Name)
+}
+
+// SetCCNumber moves a column to another controller, named after the standard name of the
+// controller. The settings no longer follow their profile.
+func (s *MidiSettings) SetCCNumber(column, number int) {
+	if column < 0 || column >= MidiCCColumns {
+		return
+	}
+	number = max(0, min(number, 127))
+	if s.CCs[column].Number == number {
+		return
+	}
+	s.CCs[column] = MidiCC{Number: number, Name: MidiCCName(number)}
+	s.Profile = ""
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMidiCCLabel(t *testing.T) {
+	assert.Equal(t, "4A", MidiCC{Number: 74}.Label())
+	assert.Equal(t, "CU", MidiCC{Number: 74, Name: "Cutoff"}.Label())
+	assert.Equal(t, "MW", MidiCC{Number: 1, Name: "Mod Wheel"}.Label())
+	assert.Equal(t, "X ", MidiCC{Number: 1, Name: "x"}.Label())
+	assert.Equal(t, "CC 74 Cutoff", MidiCC{Number: 74, Name: "Cutoff"}.String())
+	assert.Equal(t, "CC 3", MidiCC{Number: 3}.String())
+}
+
+func TestMidiSettingsSetCCNumber(t *testing.T) {
+	settings := NewMidiSettings()
+	assert.Equal(t, MidiCC{Number: 4}, settings.CCs[4])
+
+	settings.Profile = "General MIDI"
+	settings.SetCCNumber(4, 200)
+	assert.Equal(t, MidiCC{Number: 127}, settings.CCs[4], "clamped, and 127 has no standard name")
+	assert.Empty(t, settings.Profile)
+
+	settings.SetCCNumber(4, 91)
+	assert.Equal(t, "Reverb", settings.CCs[4].Name)
+}
@@ -16,17 +16,21 @@ func TestInstrumentModulationColumn(t *testing.T) {
 		t.Errorf("Expected InstrumentColC to be at position 4, got %d", int(InstrumentColC))
 	}
 
-	// Test that columns after are correctly shifted
-	if int(InstrumentColVE) != 7 {
-		t.Errorf("Expected InstrumentColVE to be at position 7, got %d", int(InstrumentColVE))
+	// Test that the V column follows T and columns after are correctly shifted
+	if int(InstrumentColV) != 7 {
+		t.Errorf("Expected InstrumentColV to be at position 7, got %d", int(InstrumentColV))
 	}
 
-	// Test that SO/MI column is at position 19 and DU at position 20
-	if int(InstrumentColSOMI) != 19 {
-		t.Errorf("Expected InstrumentColSOMI to be at position 19, got %d", int(InstrumentColSOMI))
+	if int(InstrumentColVE) != 8 {
+		t.Errorf("Expected InstrumentColVE to be at position 8, got %d", int(InstrumentColVE))
 	}
 
-	if int(InstrumentColDU) != 20 {
-		t.Errorf("Expected InstrumentColDU to be at position 20, got %d", int(InstrumentColDU))
+	// Test that SO/MI column is at position 20 and DU at position 21
+	if int(InstrumentColSOMI) != 20 {
+		t.Errorf("Expected InstrumentColSOMI to be at position 20, got %d", int(InstrumentColSOMI))
+	}
+
+	if int(InstrumentColDU) != 21 {
+		t.Errorf("Expected InstrumentColDU to be at position 21, got %d", int(InstrumentColDU))
 	}
 }
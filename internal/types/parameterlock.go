@@ -0,0 +1,86 @@
+package types
+
+// ParameterLocks holds the SoundMaker parameters locked on rows of instrument phrases.
+// A lock overrides the value of the SoundMaker slot for that row only.
+type ParameterLocks map[int]map[string]float32 // [phrase*256+row] = parameter key -> value
+
+// parameterLockKey returns the key of a row of an instrument phrase
+func parameterLockKey(phrase, row int) int {
+	return phrase*256 + row
+}
+
+// Row returns the parameters locked on a row, nil when it has none
+func (p ParameterLocks) Row(phrase, row int) map[string]float32 {
+	return p[parameterLockKey(phrase, row)]
+}
+
+// Get returns the value a parameter is locked to on a row
+func (p ParameterLocks) Get(phrase, row int, key string) (float32, bool) {
+	value, ok := p[parameterLockKey(phrase, row)][key]
+	return value, ok
+}
+
+// Set locks a parameter of a row to a value
+func (p ParameterLocks) Set(phrase, row int, key string, value float32) {
+	rowKey := parameterLockKey(phrase, row)
+	if p[rowKey] == nil {
+		p[rowKey] = make(map[string]float32)
+	}
+	p[rowKey][key] = value
+}
+
+// Clear removes the lock of a parameter of a row
+func (p ParameterLocks) Clear(phrase, row int, key string) {
+	rowKey := parameterLockKey(phrase, row)
+	delete(p[rowKey], key)
+	if len(p[rowKey]) == 0 {
+		delete(p, rowKey)
+	}
+}
+
+// SetRow replaces the locks of a row with a copy of locks, e.g. when a row is pasted
+func (p ParameterLocks) SetRow(phrase, row int, locks map[string]float32) {
+	rowKey := parameterLockKey(phrase, row)
+	if len(locks) == 0 {
+		delete(p, rowKey)
+		return
+	}
+	p[rowKey] = make(map[string]float32, len(locks))
+	for key, value := range locks {
+		p[rowKey][key] = value
+	}
+}
+
+// CopyRow returns a copy of the locks of a row, nil when it has none
+func (p ParameterLocks) CopyRow(phrase, row int) map[string]float32 {
+	locks := p.Row(phrase, row)
+	if len(locks) == 0 {
+		return nil
+	}
+	rowCopy := make(map[string]float32, len(locks))
+	for key, value := range locks {
+		rowCopy[key] = value
+	}
+	return rowCopy
+}
+
+// ClearRow removes every lock of a row
+func (p ParameterLocks) ClearRow(phrase, row int) {
+	delete(p, parameterLockKey(phrase, row))
+}
+
+// CopyPhrase replaces the locks of every row of a phrase with the locks of another phrase
+func (p ParameterLocks) CopyPhrase(from, to int) {
+	for row := 0; row < 256; row++ {
+		p.SetRow(to, row, p.Row(from, row))
+	}
+}
+
+// SoundMakerValue returns the value a row plays a parameter of its SoundMaker with: the lock
+// of the row when there is one, otherwise the value of the slot (-1 when unset)
+func SoundMakerValue(settings *SoundMakerSettings, locks map[string]float32, key string) float32 {
+	if value, ok := locks[key]; ok {
+		return value
+	}
+	return settings.GetParameterValue(key)
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoundMakerValue(t *testing.T) {
+	settings := SoundMakerSettings{Name: "PolyPerc"}
+	settings.InitializeParameters()
+	settings.SetParameterValue("A", 0x20)
+
+	locks := make(ParameterLocks)
+	locks.Set(1, 2, "A", 0x80)
+	locks.Set(1, 2, "B", 0x10)
+
+	assert.Equal(t, float32(0x80), SoundMakerValue(&settings, locks.Row(1, 2), "A"))
+	assert.Equal(t, float32(0x20), SoundMakerValue(&settings, locks.Row(1, 3), "A"))
+	assert.Equal(t, float32(-1), SoundMakerValue(&settings, nil, "C"))
+
+	locks.Clear(1, 2, "A")
+	assert.Equal(t, map[string]float32{"B": 0x10}, locks.Row(1, 2))
+	locks.Clear(1, 2, "B")
+	assert.Empty(t, locks)
+}
+
+func TestParameterLocksRows(t *testing.T) {
+	locks := make(ParameterLocks)
+	locks.Set(1, 2, "A", 0x80)
+
+	rowCopy := locks.CopyRow(1, 2)
+	rowCopy["A"] = 0x10
+	assert.Equal(t, float32(0x80), locks.Row(1, 2)["A"])
+	assert.Nil(t, locks.CopyRow(1, 3))
+
+	locks.CopyPhrase(1, 4)
+	assert.Equal(t, map[string]float32{"A": 0x80}, locks.Row(4, 2))
+	locks.SetRow(4, 2, nil)
+	assert.Nil(t, locks.Row(4, 2))
+	locks.ClearRow(1, 2)
+	assert.Empty(t, locks)
+}
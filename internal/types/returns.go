@@ -0,0 +1,57 @@
+package types
+
+// Return buses of the mixer. Tracks feed them through their sends, rows through the VE column.
+const (
+	ReturnReverb = iota // Reverb with shimmer, also fed by the VE column
+	ReturnDelay         // Tempo-synced stereo delay
+	ReturnCount
+)
+
+// Rows of the mixer: the set level of a track, then its send to each return bus
+const (
+	MixerRowSetLevel = iota
+	MixerRowSend     // Send to ReturnReverb, the other returns follow
+)
+
+// ReturnSendKeys are the arguments of the insertOut SynthDef that set the sends of a track
+var ReturnSendKeys = [ReturnCount]string{"reverbSend", "delaySend"}
+
+// ReturnModules describe the return buses like insert FX modules. Their parameters are
+// arguments of the out SynthDef, so their keys are unique across the returns.
+var ReturnModules = [ReturnCount]InsertFXModule{
+	{
+		Name:        "Reverb",
+		Description: "Reverb with shimmer (see Settings)",
+		Parameters: []InstrumentParameterDef{
+			{Key: "reverbSize", DisplayName: "Size", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 100, Default: 70, Order: 0, CoarseStep: 10, FineStep: 1, DisplayFormat: "%.0f%%"},
+			{Key: "reverbDamping", DisplayName: "Damping", Type: ParameterTypeFloat, MinValue: 500, MaxValue: 20000, Default: 5500, Order: 1, CoarseStep: 1000, FineStep: 100, DisplayFormat: "%.0fHz"},
+			{Key: "reverbPredelay", DisplayName: "Predelay", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 300, Default: 200, Order: 2, CoarseStep: 20, FineStep: 1, DisplayFormat: "%.0fms"},
+			{Key: "reverbLevel", DisplayName: "Level", Type: ParameterTypeFloat, MinValue: -48, MaxValue: 12, Default: 0, Order: 3, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+		},
+	},
+	{
+		Name:        "Delay",
+		Description: "Tempo-synced stereo delay; spread moves the repeats between the sides",
+		Parameters: []InstrumentParameterDef{
+			{Key: "delayTime", DisplayName: "Time", Type: ParameterTypeInt, MinValue: 0, MaxValue: float32(len(DelayDivisions) - 1), Default: 6, Order: 0, CoarseStep: 1, FineStep: 1, DisplayFormatter: FormatDelayDivision},
+			{Key: "delayFeedback", DisplayName: "Feedback", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 0.95, Default: 0.5, Order: 1, CoarseStep: 0.1, FineStep: 0.01},
+			{Key: "delayTone", DisplayName: "Tone", Type: ParameterTypeFloat, MinValue: 200, MaxValue: 20000, Default: 6000, Order: 2, CoarseStep: 1000, FineStep: 100, DisplayFormat: "%.0fHz"},
+			{Key: "delaySpread", DisplayName: "Spread", Type: ParameterTypeFloat, MinValue: 0, MaxValue: 1, Default: 1, Order: 3, CoarseStep: 0.1, FineStep: 0.01},
+			{Key: "delayLevel", DisplayName: "Level", Type: ParameterTypeFloat, MinValue: -48, MaxValue: 12, Default: 0, Order: 4, CoarseStep: 3, FineStep: 0.5, DisplayFormat: "%+.1fdB"},
+		},
+		TempoSynced: "delayTime",
+	},
+}
+
+// ReturnSettings are the parameters of a return bus
+type ReturnSettings struct {
+	Parameters map[string]float32 `json:"parameters,omitempty"`
+}
+
+// Value returns the value of a parameter of the return, the default when it was never set
+func (r ReturnSettings) Value(param InstrumentParameterDef) float32 {
+	if value, ok := r.Parameters[param.Key]; ok {
+		return value
+	}
+	return param.Default
+}
@@ -1,7 +1,12 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 type ViewMode int
@@ -21,6 +26,18 @@ const (
 	MidiView
 	SoundMakerView
 	DuckingView
+	SliceEditorView
+	MultisampleView
+	LibraryView
+	ProjectFilesView
+	SampleToolsView
+	GranularView
+	PresetView
+	LFOView
+	AutomationView
+	ParameterLockView
+	InsertFXView
+	ReturnsView
 )
 
 type PhraseViewType int
@@ -69,7 +86,7 @@ const (
 	ColEffectReverb                           // Column 12: VE (00-FE)
 	ColEffectDucking                          // Column 13: DU (00-FE, sticky)
 	ColFilename                               // Column 14: Filename index
-	ColChord                                  // Column 15: Chord (Instrument view only: "-", "M", "m", "d")
+	ColChord                                  // Column 15: Chord (Instrument view only: "-", "M", "m", "d", "2", "4", "o", "+", "Δ", "ø", "6", "9")
 	ColChordAddition                          // Column 16: Chord Addition (Instrument view only: "-", "7", "9", "4")
 	ColChordTransposition                     // Column 17: Chord Transposition (Instrument view only: "-", "0"-"F")
 	ColArpeggio                               // Column 18: Arpeggio (Instrument view only: 00-FE)
@@ -90,18 +107,39 @@ const (
 	ColMidiCC6 // Column 32: MIDI CC 6 (00-7F, 0-127)
 	ColMidiCC7 // Column 33: MIDI CC 7 (00-7F, 0-127)
 	ColMidiCC8 // Column 34: MIDI CC 8 (00-7F, 0-127)
-	ColCount   // Total number of columns
+	// Granular column (Sampler view only, added after the CC columns to keep older saves aligned)
+	ColGranular // Column 35: Granular setting index (Sampler view only: 00-FE)
+	// Sample region columns (Sampler view only, sticky)
+	ColSampleStart  // Column 36: Start offset into the slice (00-FE, 00 = slice start, 7F = middle)
+	ColSampleLength // Column 37: Length as a fraction of the slice (00-FE, FE = whole slice)
+	// Chord voicing column (Instrument view only)
+	ColChordVoicing // Column 38: Chord voicing (Instrument view only: "-", "2", "S", "O", "L")
+	// MIDI expression columns (Instrument view only, visible when SO/MI column is in MI mode)
+	ColPitchBend  // Column 39: Pitch bend (00-FE, 80 = center)
+	ColAftertouch // Column 40: Channel aftertouch (00-7F, 0-127)
+	// Insert FX columns (both views)
+	ColInsertFX      // Column 41: FX - slot of the insert chain (high digit) and parameter of its module (low digit)
+	ColInsertFXValue // Column 42: FV - value of the FX parameter (00-FE over its range)
+	ColCount         // Total number of columns
 )
 
 // ChordType represents different chord types for instrument tracks
 type ChordType int
 
 const (
-	ChordNone      ChordType = iota // "-" (default)
-	ChordMajor                      // "M"
-	ChordMinor                      // "m"
-	ChordDominant                   // "d"
-	ChordTypeCount                  // Total number of chord types
+	ChordNone           ChordType = iota // "-" (default)
+	ChordMajor                           // "M"
+	ChordMinor                           // "m"
+	ChordDominant                        // "d"
+	ChordSus2                            // "2"
+	ChordSus4                            // "4"
+	ChordDiminished                      // "o"
+	ChordAugmented                       // "+"
+	ChordMajor7                          // "Δ"
+	ChordHalfDiminished                  // "ø" (m7b5)
+	ChordSixth                           // "6"
+	ChordAddNine                         // "9" (add9)
+	ChordTypeCount                       // Total number of chord types
 )
 
 // ChordAddition represents different chord additions for instrument tracks
@@ -150,6 +188,22 @@ func ChordTypeToString(chordType ChordType) string {
 		return "m"
 	case ChordDominant:
 		return "d"
+	case ChordSus2:
+		return "2"
+	case ChordSus4:
+		return "4"
+	case ChordDiminished:
+		return "o"
+	case ChordAugmented:
+		return "+"
+	case ChordMajor7:
+		return "Δ"
+	case ChordHalfDiminished:
+		return "ø"
+	case ChordSixth:
+		return "6"
+	case ChordAddNine:
+		return "9"
 	default:
 		return "-"
 	}
@@ -182,20 +236,25 @@ const (
 	InstrumentColC     InstrumentUIColumn = 4  // C - Chord
 	InstrumentColA     InstrumentUIColumn = 5  // A - Chord Addition
 	InstrumentColT     InstrumentUIColumn = 6  // T - Chord Transposition
-	InstrumentColVE    InstrumentUIColumn = 7  // VE - Velocity
-	InstrumentColGT    InstrumentUIColumn = 8  // GT - Gate
-	InstrumentColATK   InstrumentUIColumn = 9  // A - Attack
-	InstrumentColDECAY InstrumentUIColumn = 10 // D - Decay
-	InstrumentColSUS   InstrumentUIColumn = 11 // S - Sustain
-	InstrumentColREL   InstrumentUIColumn = 12 // R - Release
-	InstrumentColRE    InstrumentUIColumn = 13 // RE - Reverb
-	InstrumentColCO    InstrumentUIColumn = 14 // CO - Comb
-	InstrumentColPA    InstrumentUIColumn = 15 // PA - Pan
-	InstrumentColLP    InstrumentUIColumn = 16 // LP - LowPass
-	InstrumentColHP    InstrumentUIColumn = 17 // HP - HighPass
-	InstrumentColAR    InstrumentUIColumn = 18 // AR - Arpeggio
-	InstrumentColSOMI  InstrumentUIColumn = 19 // SO/MI - SoundMaker/MIDI (toggleable)
-	InstrumentColDU    InstrumentUIColumn = 20 // DU - Ducking
+	InstrumentColV     InstrumentUIColumn = 7  // V - Chord Voicing
+	InstrumentColVE    InstrumentUIColumn = 8  // VE - Velocity
+	InstrumentColGT    InstrumentUIColumn = 9  // GT - Gate
+	InstrumentColATK   InstrumentUIColumn = 10 // A - Attack
+	InstrumentColDECAY InstrumentUIColumn = 11 // D - Decay
+	InstrumentColSUS   InstrumentUIColumn = 12 // S - Sustain
+	InstrumentColREL   InstrumentUIColumn = 13 // R - Release
+	InstrumentColRE    InstrumentUIColumn = 14 // RE - Reverb
+	InstrumentColCO    InstrumentUIColumn = 15 // CO - Comb
+	InstrumentColPA    InstrumentUIColumn = 16 // PA - Pan
+	InstrumentColLP    InstrumentUIColumn = 17 // LP - LowPass
+	InstrumentColHP    InstrumentUIColumn = 18 // HP - HighPass
+	InstrumentColAR    InstrumentUIColumn = 19 // AR - Arpeggio
+	InstrumentColSOMI  InstrumentUIColumn = 20 // SO/MI - SoundMaker/MIDI (toggleable)
+	InstrumentColDU    InstrumentUIColumn = 21 // DU - Ducking
+	InstrumentColFX    InstrumentUIColumn = 22 // FX - Insert FX slot and parameter
+	InstrumentColFV    InstrumentUIColumn = 23 // FV - Insert FX value
+	InstrumentColPB    InstrumentUIColumn = 24 // PB - Pitch bend (MI mode only)
+	InstrumentColAT    InstrumentUIColumn = 25 // AT - Channel aftertouch (MI mode only)
 )
 
 // UI Column positions for Sampler Phrase View - to prevent hardcoding issues
@@ -209,27 +268,41 @@ const (
 	SamplerColVE  SamplerUIColumn = 4  // VE - Velocity
 	SamplerColPI  SamplerUIColumn = 5  // PI - Pitch
 	SamplerColGT  SamplerUIColumn = 6  // GT - Gate
-	SamplerColRT  SamplerUIColumn = 7  // RT - Retrigger
-	SamplerColTS  SamplerUIColumn = 8  // TS - Timestretch
-	SamplerColREV SamplerUIColumn = 9  // Я - Reverse
-	SamplerColPA  SamplerUIColumn = 10 // PA - Pan
-	SamplerColLP  SamplerUIColumn = 11 // LP - Low Pass Filter
-	SamplerColHP  SamplerUIColumn = 12 // HP - High Pass Filter
-	SamplerColCO  SamplerUIColumn = 13 // CO - Comb
-	SamplerColRE  SamplerUIColumn = 14 // RE - Reverb
-	SamplerColDU  SamplerUIColumn = 15 // DU - Ducking
-	SamplerColFI  SamplerUIColumn = 16 // FI - Filename
+	SamplerColST  SamplerUIColumn = 7  // ST - Start offset
+	SamplerColLN  SamplerUIColumn = 8  // LN - Length
+	SamplerColRT  SamplerUIColumn = 9  // RT - Retrigger
+	SamplerColTS  SamplerUIColumn = 10 // TS - Timestretch
+	SamplerColGR  SamplerUIColumn = 11 // GR - Granular
+	SamplerColREV SamplerUIColumn = 12 // Я - Reverse
+	SamplerColPA  SamplerUIColumn = 13 // PA - Pan
+	SamplerColLP  SamplerUIColumn = 14 // LP - Low Pass Filter
+	SamplerColHP  SamplerUIColumn = 15 // HP - High Pass Filter
+	SamplerColCO  SamplerUIColumn = 16 // CO - Comb
+	SamplerColRE  SamplerUIColumn = 17 // RE - Reverb
+	SamplerColDU  SamplerUIColumn = 18 // DU - Ducking
+	SamplerColFX  SamplerUIColumn = 19 // FX - Insert FX slot and parameter
+	SamplerColFV  SamplerUIColumn = 20 // FV - Insert FX value
+	SamplerColFI  SamplerUIColumn = 21 // FI - Filename
 )
 
 // UI Column positions for Arpeggio View - to prevent hardcoding issues
 type ArpeggioUIColumn int
 
 const (
-	ArpeggioColDI  ArpeggioUIColumn = 0 // DI - Direction
-	ArpeggioColCO  ArpeggioUIColumn = 1 // CO - Count
-	ArpeggioColDIV ArpeggioUIColumn = 2 // Divisor
+	ArpeggioColDI    ArpeggioUIColumn = 0 // DI - Direction
+	ArpeggioColCO    ArpeggioUIColumn = 1 // CO - Count
+	ArpeggioColDIV   ArpeggioUIColumn = 2 // Divisor
+	ArpeggioColOC    ArpeggioUIColumn = 3 // OC - Octave range
+	ArpeggioColVE    ArpeggioUIColumn = 4 // VE - Step velocity
+	ArpeggioColGT    ArpeggioUIColumn = 5 // GT - Step gate
+	ArpeggioColRS    ArpeggioUIColumn = 6 // RS - Rest
+	ArpeggioColRT    ArpeggioUIColumn = 7 // RT - Ratchet
+	ArpeggioColCount ArpeggioUIColumn = 8 // Total number of columns
 )
 
+// ArpeggioLatchRow is the row of the latch setting below the 16 arpeggio rows
+const ArpeggioLatchRow = 16
+
 // ChordTranspositionToString converts a ChordTransposition enum to its display string
 func ChordTranspositionToString(chordTrans ChordTransposition) string {
 	switch chordTrans {
@@ -272,35 +345,34 @@ func ChordTranspositionToString(chordTrans ChordTransposition) string {
 	}
 }
 
+// GetChordNotes returns the notes of a chord in close position: the shape of the chord
+// type, the addition, then T rotations that each move the lowest note up an octave
 func GetChordNotes(root int, ctype ChordType, add ChordAddition, transpose ChordTransposition) []int {
 	notes := []int{root}
 
-	if ctype == ChordNone {
+	if ctype <= ChordNone || ctype >= ChordTypeCount {
 		return notes
 	}
 
-	switch ctype {
-	case ChordMajor:
-		notes = append(notes, root+4, root+7)
-	case ChordMinor:
-		notes = append(notes, root+3, root+7)
-	case ChordDominant:
-		notes = append(notes, root+4, root+7) // dominant is like major triad, add7 handled below
+	shape := chordShapes[ctype]
+	for _, interval := range shape.intervals {
+		notes = append(notes, root+interval)
 	}
 
 	switch add {
 	case ChordAdd7:
-		if ctype == ChordMinor {
-			notes = append(notes, root+10) // minor 7th
-		} else {
-			notes = append(notes, root+11) // major 7th
-		}
+		notes = addChordTone(notes, root+shape.seventh)
 	case ChordAdd9:
-		notes = append(notes, root+14) // 9th = collidertrackerd + octave
+		notes = addChordTone(notes, root+14) // 9th = 2nd + octave
 	case ChordAdd4:
-		notes = append(notes, root+5) // 4th
+		notes = addChordTone(notes, root+5) // 4th
 	}
 
+	return RotateChord(notes, transpose)
+}
+
+// RotateChord applies T inversions: each moves the lowest note of the chord up an octave
+func RotateChord(notes []int, transpose ChordTransposition) []int {
 	if transpose > ChordTrans0 {
 		for i := ChordTrans0; i < transpose; i++ {
 			first := notes[0]
@@ -308,15 +380,148 @@ func GetChordNotes(root int, ctype ChordType, add ChordAddition, transpose Chord
 			notes = append(notes, first+12)
 		}
 	}
-
 	return notes
 }
 
 type FileMetadata struct {
-	BPM         float32 `json:"bpm"`         // Source BPM for the file
-	Slices      int     `json:"slices"`      // Number of slices in the file
-	Playthrough int     `json:"playthrough"` // 0=Sliced, 1=Oneshot
-	SyncToBPM   int     `json:"synctobpm"`   // 0=No, 1=Yes (default)
+	BPM          float32   `json:"bpm"`                    // Source BPM for the file
+	Slices       int       `json:"slices"`                 // Number of slices in the file
+	Playthrough  int       `json:"playthrough"`            // 0=Sliced, 1=Oneshot
+	SyncToBPM    int       `json:"synctobpm"`              // 0=No, 1=Yes (default)
+	TrimStart    float32   `json:"trimstart,omitempty"`    // Trim start as a fraction of the file (0-1, default 0)
+	TrimEnd      float32   `json:"trimend,omitempty"`      // Trim end as a fraction of the file (0-1, 0 = end of file)
+	SliceMarkers []float32 `json:"slicemarkers,omitempty"` // Inner slice boundaries as fractions of the file (empty = equal slices)
+	LoopMode     int       `json:"loopmode,omitempty"`     // 0=Off, 1=Forward, 2=Ping-pong
+	LoopStart    int       `json:"loopstart,omitempty"`    // Loop start in frames
+	LoopEnd      int       `json:"loopend,omitempty"`      // Loop end in frames (0 = end of file)
+	LoopXfadeMs  int       `json:"loopxfade,omitempty"`    // Loop crossfade length in milliseconds (forward loops only)
+	Frames       int       `json:"frames,omitempty"`       // Length of the file in frames (0 = not read yet)
+}
+
+// EffectiveTrimEnd returns the trim end point, treating 0 as the end of the file
+func (fm FileMetadata) EffectiveTrimEnd() float32 {
+	if fm.TrimEnd <= 0 || fm.TrimEnd > 1 {
+		return 1
+	}
+	return fm.TrimEnd
+}
+
+// HasCustomSlicing reports whether trim points or slice markers differ from the default equal slicing
+func (fm FileMetadata) HasCustomSlicing() bool {
+	return fm.TrimStart > 0 || fm.EffectiveTrimEnd() < 1 || len(fm.SliceMarkers) > 0
+}
+
+// Markers returns all slice boundaries as fractions of the file: trim start, the inner
+// slice markers and trim end (Slices+1 values). Without explicit markers the trimmed
+// region is divided into equal slices.
+func (fm FileMetadata) Markers() []float32 {
+	slices := fm.Slices
+	if slices < 1 {
+		slices = 1
+	}
+	start := fm.TrimStart
+	end := fm.EffectiveTrimEnd()
+
+	markers := make([]float32, slices+1)
+	markers[0] = start
+	markers[slices] = end
+	if len(fm.SliceMarkers) == slices-1 {
+		copy(markers[1:slices], fm.SliceMarkers)
+	} else {
+		for i := 1; i < slices; i++ {
+			markers[i] = start + (end-start)*float32(i)/float32(slices)
+		}
+	}
+	return markers
+}
+
+// SliceBounds returns the start and end of a slice as fractions of the file
+func (fm FileMetadata) SliceBounds(slice int) (start, end float32) {
+	markers := fm.Markers()
+	slices := len(markers) - 1
+	slice = ((slice % slices) + slices) % slices
+	return markers[slice], markers[slice+1]
+}
+
+// Cropped returns the metadata for the file after only frames [start, end) of its
+// original frames were kept. Trim points, slice markers and the loop region stay on
+// the same audio; markers that fall outside the kept audio reset to equal slicing.
+func (fm FileMetadata) Cropped(start, end, frames int) FileMetadata {
+	if frames <= 0 || end <= start {
+		return fm
+	}
+	kept := float64(end - start)
+	remap := func(f float32) float32 {
+		x := (float64(f)*float64(frames) - float64(start)) / kept
+		return float32(math.Max(0, math.Min(1, x)))
+	}
+
+	out := fm
+	out.TrimStart = remap(fm.TrimStart)
+	if fm.TrimEnd > 0 && fm.TrimEnd < 1 {
+		if out.TrimEnd = remap(fm.TrimEnd); out.TrimEnd >= 1 {
+			out.TrimEnd = 0
+		}
+	}
+	out.SliceMarkers = nil
+	if len(fm.SliceMarkers) > 0 {
+		markers := make([]float32, len(fm.SliceMarkers))
+		previous := out.TrimStart
+		for i, marker := range fm.SliceMarkers {
+			markers[i] = remap(marker)
+			if markers[i] <= previous {
+				markers = nil
+				break
+			}
+			previous = markers[i]
+		}
+		if markers != nil && previous < out.EffectiveTrimEnd() {
+			out.SliceMarkers = markers
+		}
+	}
+
+	out.LoopStart = max(0, min(fm.LoopStart-start, end-start))
+	if fm.LoopEnd > 0 {
+		if out.LoopEnd = fm.LoopEnd - start; out.LoopEnd >= end-start || out.LoopEnd <= out.LoopStart {
+			out.LoopEnd = 0
+		}
+	}
+	return out
+}
+
+// Reversed returns the metadata for the file after it was reversed, mirroring the trim
+// points, slice markers and loop region
+func (fm FileMetadata) Reversed(frames int) FileMetadata {
+	out := fm
+	out.TrimStart = 1 - fm.EffectiveTrimEnd()
+	if out.TrimEnd = 1 - fm.TrimStart; out.TrimEnd >= 1 {
+		out.TrimEnd = 0
+	}
+	if len(fm.SliceMarkers) > 0 {
+		out.SliceMarkers = make([]float32, len(fm.SliceMarkers))
+		for i, marker := range fm.SliceMarkers {
+			out.SliceMarkers[len(fm.SliceMarkers)-1-i] = 1 - marker
+		}
+	}
+
+	loopEnd := fm.LoopEnd
+	if loopEnd <= 0 || loopEnd > frames {
+		loopEnd = frames
+	}
+	out.LoopStart = max(0, frames-loopEnd)
+	if out.LoopEnd = frames - fm.LoopStart; out.LoopEnd >= frames {
+		out.LoopEnd = 0
+	}
+	return out
+}
+
+// Resampled returns the metadata for the file after its sample rate was multiplied by ratio.
+// Fractions of the file are unchanged; loop points in frames are scaled.
+func (fm FileMetadata) Resampled(ratio float64) FileMetadata {
+	out := fm
+	out.LoopStart = int(math.Round(float64(fm.LoopStart) * ratio))
+	out.LoopEnd = int(math.Round(float64(fm.LoopEnd) * ratio))
+	return out
 }
 
 type RetriggerSettings struct {
@@ -340,6 +545,23 @@ type TimestrechSettings struct {
 	Probability int     `json:"probability"` // Probability percentage (0-100, default 100) - chance of activation after Every check
 }
 
+// DefaultGranularDensity is the number of grains per second of a fresh granular slot
+const DefaultGranularDensity = 20
+
+type GranularSettings struct {
+	Size           float32 `json:"size"`           // Grain size in milliseconds (1-1000, default 0 = granular off)
+	Density        float32 `json:"density"`        // Grains per second (1-200, default 20)
+	Position       float32 `json:"position"`       // Read position within the slice (0.0-1.0, default 0)
+	PositionJitter float32 `json:"positionJitter"` // Random offset of each grain's position (0.0-1.0 of the slice, default 0)
+	PitchJitter    float32 `json:"pitchJitter"`    // Random pitch of each grain (0-24 semitones, default 0)
+	Spray          float32 `json:"spray"`          // Randomness of grain timing (0.0 = regular, 1.0 = fully random, default 0)
+}
+
+// Active returns whether the settings play the row as a grain cloud
+func (g GranularSettings) Active() bool {
+	return g.Size > 0 && g.Density > 0
+}
+
 type ModulateSettings struct {
 	Seed        int    `json:"seed"`        // Random seed: -1 for "none" (no randomization), 0 for "random" (time seeding), 1-128 for fixed seed
 	IRandom     int    `json:"irandom"`     // Random range: 0-128 (0 means no randomization)
@@ -365,9 +587,15 @@ type DuckingSettings struct {
 type ArpeggioDirection int
 
 const (
-	ArpeggioDirectionNone ArpeggioDirection = iota // 0: "--"
-	ArpeggioDirectionUp                            // 1: "u-"
-	ArpeggioDirectionDown                          // 2: "d-"
+	ArpeggioDirectionNone     ArpeggioDirection = iota // 0: "--"
+	ArpeggioDirectionUp                                // 1: "u-"
+	ArpeggioDirectionDown                              // 2: "d-"
+	ArpeggioDirectionUpDown                            // 3: "ud" up to the top of the range and back down
+	ArpeggioDirectionDownUp                            // 4: "du" down from the top of the range and back up
+	ArpeggioDirectionRandom                            // 5: "rn" any note of the range, never the same twice
+	ArpeggioDirectionAsPlayed                          // 6: "pl" the chord in the order it is voiced
+	ArpeggioDirectionConverge                          // 7: "cv" outer notes first, moving inwards
+	ArpeggioDirectionCount                             // Total number of directions
 )
 
 // SoundMakerRow represents different rows in the SoundMaker settings view
@@ -404,6 +632,21 @@ const (
 	InputSettingsRowReverbSendPercent                         // 1: ReverbSendPercent
 )
 
+// TuningSettingsRow represents different rows in the Tuning settings column
+type TuningSettingsRow int
+
+const (
+	TuningSettingsRowProject TuningSettingsRow = iota // 0: Tuning of the project
+	TuningSettingsRowTrack1                           // 1: Tuning of track 1, tracks 2-8 follow
+)
+
+// Rows below the tracks
+const (
+	TuningSettingsRowDegrees = TuningSettingsRowTrack1 + 8 + iota // 9: Show scale degrees
+	TuningSettingsRowKey                                          // 10: Key of the project
+	TuningSettingsRowScale                                        // 11: Scale of the project
+)
+
 // BrailleDotRow represents different rows in a 2x4 Braille cell
 type BrailleDotRow int
 
@@ -422,16 +665,52 @@ const (
 	FileMetadataRowSlices                             // 1: Slices
 	FileMetadataRowPlaythrough                        // 2: Playthrough
 	FileMetadataRowSyncToBPM                          // 3: Sync to BPM
+	FileMetadataRowLoopMode                           // 4: Loop mode
+	FileMetadataRowLoopStart                          // 5: Loop start (frames)
+	FileMetadataRowLoopEnd                            // 6: Loop end (frames)
+	FileMetadataRowLoopXfade                          // 7: Loop crossfade (ms)
 )
 
+// LoopMode represents how a sampler voice loops while its gate is held
+type LoopMode int
+
+const (
+	LoopModeOff      LoopMode = iota // 0: No looping
+	LoopModeForward                  // 1: Jump back to loop start
+	LoopModePingPong                 // 2: Alternate direction at the loop points
+	LoopModeCount
+)
+
+// GetLoopModeString returns the display string for a loop mode
+func GetLoopModeString(mode int) string {
+	switch LoopMode(mode) {
+	case LoopModeForward:
+		return "Forward"
+	case LoopModePingPong:
+		return "Ping-pong"
+	default:
+		return "Off"
+	}
+}
+
 // MidiSettingsRow represents different rows in the MIDI settings view
 type MidiSettingsRow int
 
 const (
-	MidiSettingsRowDevice  MidiSettingsRow = iota // 0: MIDI Device
-	MidiSettingsRowChannel                        // 1: MIDI Channel
+	MidiSettingsRowDevice    MidiSettingsRow = iota // 0: MIDI Device
+	MidiSettingsRowChannel                          // 1: MIDI Channel
+	MidiSettingsRowProgram                          // 2: Program change (-- or 1-128)
+	MidiSettingsRowBankMSB                          // 3: Bank select MSB, CC 0 (-- or 0-127)
+	MidiSettingsRowBankLSB                          // 4: Bank select LSB, CC 32 (-- or 0-127)
+	MidiSettingsRowBendRange                        // 5: Pitch bend range (-- or 1-24 semitones)
+	MidiSettingsRowLatency                          // 6: Latency of the device (0-500 ms)
+	MidiSettingsRowProfile                          // 7: Device profile the CCs are set from
+	MidiSettingsRowCC0                              // 8-16: CCs of the CC columns
 )
 
+// MidiSettingsRowFirstDevice is the row of the first available MIDI device, after the CC rows
+const MidiSettingsRowFirstDevice = MidiSettingsRowCC0 + MidiCCColumns
+
 // RetriggerSettingsRow represents different rows in the retrigger settings view
 type RetriggerSettingsRow int
 
@@ -459,6 +738,18 @@ const (
 	TimestrechSettingsRowProbability                              // 4: Probability
 )
 
+// GranularSettingsRow represents different rows in the granular settings view
+type GranularSettingsRow int
+
+const (
+	GranularSettingsRowSize           GranularSettingsRow = iota // 0: Size
+	GranularSettingsRowDensity                                   // 1: Density
+	GranularSettingsRowPosition                                  // 2: Position
+	GranularSettingsRowPositionJitter                            // 3: Position jitter
+	GranularSettingsRowPitchJitter                               // 4: Pitch jitter
+	GranularSettingsRowSpray                                     // 5: Spray
+)
+
 // ModulateSettingsRow represents different rows in the modulate settings view
 type ModulateSettingsRow int
 
@@ -487,34 +778,105 @@ const (
 )
 
 type ArpeggioRow struct {
-	Direction int `json:"direction"` // Direction: 0="--", 1="u-", 2="d-"
-	Count     int `json:"count"`     // Count: -1="--", 0-254 for hex values 00-FE
-	Divisor   int `json:"divisor"`   // Divisor: -1="--", 1-254 for hex values 01-FE
+	Direction int `json:"direction"`          // Direction: 0="--", 1="u-", 2="d-", 3="ud", 4="du", 5="rn", 6="pl", 7="cv"
+	Count     int `json:"count"`              // Count: -1="--", 0-254 for hex values 00-FE
+	Divisor   int `json:"divisor"`            // Divisor: -1="--", 1-254 for hex values 01-FE
+	Octaves   int `json:"octaves,omitempty"`  // Octave range: 0="--" (u-/d- walk freely, other modes stay in one octave), 1-4
+	Velocity  int `json:"velocity,omitempty"` // Step velocity: 0="--" (row velocity), 01-7F
+	Gate      int `json:"gate,omitempty"`     // Step gate: 0="--" (row gate and length), 01-FE of the step length
+	Rest      int `json:"rest,omitempty"`     // Rest: 0="--" plays, 1="RS" keeps time without sounding
+	Ratchet   int `json:"ratchet,omitempty"`  // Ratchet: 0="--" (one hit), 1-8 hits per step
 }
 
 type ArpeggioSettings struct {
-	Rows [16]ArpeggioRow `json:"rows"` // 16 rows (00-0F), each with its own DI and CO
+	Rows  [16]ArpeggioRow `json:"rows"`            // 16 rows (00-0F), each with its own DI and CO
+	Latch bool            `json:"latch,omitempty"` // Keep looping across rows until the track plays another arpeggio
 }
 
 type MidiSettings struct {
-	Device  string `json:"device"`  // MIDI Device name
-	Channel string `json:"channel"` // MIDI Channel (1-16 or "all")
+	Device    string                `json:"device"`            // MIDI Device name
+	Channel   string                `json:"channel"`           // MIDI Channel (1-16 or "all")
+	Program   int                   `json:"program"`           // Program change sent before the first note (0-127, -1 = none)
+	BankMSB   int                   `json:"bankMSB"`           // Bank select MSB sent with the program (0-127, -1 = none)
+	BankLSB   int                   `json:"bankLSB"`           // Bank select LSB sent with the program (0-127, -1 = none)
+	BendRange int                   `json:"bendRange"`         // Pitch bend range of the synth in semitones (1-24, -1 = not sent)
+	CCs       [MidiCCColumns]MidiCC `json:"ccs"`               // Controllers of the CC columns, in column order
+	Profile   string                `json:"profile,omitempty"` // Device profile the CCs were set from, "" once edited
+}
+
+// DefaultPitchBendRange is the pitch bend range synths use unless told otherwise
+const DefaultPitchBendRange = 2
+
+// NewMidiSettings returns MIDI settings without a device that leave the patch to the synth
+func NewMidiSettings() MidiSettings {
+	return MidiSettings{
+		Device:    "None",
+		Channel:   "1", // Default to channel 1
+		Program:   -1,
+		BankMSB:   -1,
+		BankLSB:   -1,
+		BendRange: -1,
+		CCs:       DefaultMidiCCs(),
+	}
+}
+
+// PitchBendRange returns the pitch bend range of the synth in semitones
+func (s MidiSettings) PitchBendRange() int {
+	if s.BendRange < 0 {
+		return DefaultPitchBendRange
+	}
+	return s.BendRange
 }
 
 type SoundMakerSettings struct {
-	Name       string             `json:"name"`       // SoundMaker name ("PolyPerc", "Infinite Pad", "DX7", etc.)
-	Parameters map[string]float32 `json:"parameters"` // Key-value pairs for parameters (e.g. "preset": 5, "A": 128)
-	PatchName  string             `json:"patchName"`  // Patch name (used for DX7 when setting by name)
+	Name       string             `json:"name"`               // SoundMaker name ("PolyPerc", "Infinite Pad", "DX7", etc.)
+	Parameters map[string]float32 `json:"parameters"`         // Key-value pairs for parameters (e.g. "preset": 5, "A": 128)
+	PatchName  string             `json:"patchName"`          // Patch name (used for DX7 when setting by name)
+	DX7Voice   string             `json:"dx7Voice,omitempty"` // Bank and slot of an imported DX7 voice (see supercollider.DX7VoiceRef)
+	Zones      []MultisampleZone  `json:"zones,omitempty"`    // Sample zones (used by the Multisample SoundMaker)
 }
 
+// MultisampleSoundMakerName is the SoundMaker that plays sample zones instead of a synth
+const MultisampleSoundMakerName = "Multisample"
+
+// MultisampleZone maps a sample file to a key range and velocity layer
+type MultisampleZone struct {
+	File         string `json:"file"`         // Path to the sample file
+	LowNote      int    `json:"lowNote"`      // Lowest MIDI note played by this zone
+	HighNote     int    `json:"highNote"`     // Highest MIDI note played by this zone
+	RootNote     int    `json:"rootNote"`     // MIDI note the sample plays at original pitch
+	LowVelocity  int    `json:"lowVelocity"`  // Lowest velocity (0-127)
+	HighVelocity int    `json:"highVelocity"` // Highest velocity (0-127)
+	FineTune     int    `json:"fineTune"`     // Fine tune in cents (-100 to 100)
+	RoundRobin   int    `json:"roundRobin"`   // Round-robin group (0 = always plays, 1-16 = alternates within group)
+}
+
+type MultisampleZoneColumn int
+
+const (
+	MultisampleColFile         MultisampleZoneColumn = iota // File
+	MultisampleColLowNote                                   // Lo - lowest note
+	MultisampleColHighNote                                  // Hi - highest note
+	MultisampleColRootNote                                  // Rt - root note
+	MultisampleColLowVelocity                               // VL - lowest velocity
+	MultisampleColHighVelocity                              // VH - highest velocity
+	MultisampleColFineTune                                  // Tune - cents
+	MultisampleColRoundRobin                                // RR - round-robin group
+	MultisampleColCount
+)
+
+// MultisampleMaxRoundRobin is the highest round-robin group number
+const MultisampleMaxRoundRobin = 16
+
 type ClipboardData struct {
 	// Cell data
 	Value    int
 	CellType CellType
 	// Row data
-	RowData     []int
-	RowFilename string
-	SourceView  ViewMode
+	RowData           []int
+	RowFilename       string
+	RowParameterLocks map[string]float32 // Parameter locks of an instrument phrase row
+	SourceView        ViewMode
 	// Arpeggio row data
 	ArpeggioRowData struct {
 		Direction []int
@@ -570,11 +932,15 @@ type SaveData struct {
 	RecordingEnabled           bool                    `json:"recordingEnabled"`
 	RetriggerSettings          [255]RetriggerSettings  `json:"retriggerSettings"`
 	TimestrechSettings         [255]TimestrechSettings `json:"timestrechSettings"`
+	GranularSettings           [255]GranularSettings   `json:"granularSettings"`
 	ModulateSettings           [255]ModulateSettings   `json:"modulateSettings"`           // Legacy field for backward compatibility
 	InstrumentModulateSettings [255]ModulateSettings   `json:"instrumentModulateSettings"` // New separate pools
 	SamplerModulateSettings    [255]ModulateSettings   `json:"samplerModulateSettings"`    // New separate pools
 	DuckingSettings            [255]DuckingSettings    `json:"duckingSettings"`
 	DuckingEditingIndex        int                     `json:"duckingEditingIndex"`
+	LFOSettings                [LFOCount]LFOSettings   `json:"lfoSettings"`
+	AutomationLanes            []AutomationLane        `json:"automationLanes,omitempty"`
+	ParameterLocks             ParameterLocks          `json:"parameterLocks,omitempty"`
 	ArpeggioSettings           [255]ArpeggioSettings   `json:"arpeggioSettings"`
 	MidiSettings               [255]MidiSettings       `json:"midiSettings"`
 	SoundMakerSettings         [255]SoundMakerSettings `json:"soundMakerSettings"`
@@ -587,7 +953,18 @@ type SaveData struct {
 	TrackTypes                 [9]bool                 `json:"trackTypes"`
 	CurrentMixerTrack          int                     `json:"currentMixerTrack"`
 	SOColumnMode               SOColumnMode            `json:"soColumnMode"`
-	MidiCCNumbers              [9]int                  `json:"midiCCNumbers"`
+	MidiCCNumbers              *[9]int                 `json:"midiCCNumbers,omitempty"` // CC numbers of every MIDI device, from saves before per-device CCs
+	MidiLatencies              map[string]int          `json:"midiLatencies,omitempty"` // Latency of each MIDI device in ms
+	InsertFX                   [8]InsertFXChain        `json:"insertFX"`                // Insert chain of each track
+	Tuning                     string                  `json:"tuning,omitempty"`
+	TrackTunings               [8]string               `json:"trackTunings,omitempty"`
+	ShowScaleDegrees           bool                    `json:"showScaleDegrees,omitempty"`
+	ProjectKey                 int                     `json:"projectKey,omitempty"`
+	ProjectScale               string                  `json:"projectScale,omitempty"`
+
+	// Send/return buses of the mixer
+	Returns    [ReturnCount]ReturnSettings `json:"returns"`    // Parameters of the return buses
+	TrackSends [8][ReturnCount]float32     `json:"trackSends"` // Send of each track to each return bus (0-100%)
 }
 
 const SaveFile = "tracker-save.json"
@@ -641,6 +1018,21 @@ func ReleaseToSeconds(hexValue int) float32 {
 	return minSeconds * float32(math.Pow(float64(maxSeconds/minSeconds), float64(ratio)))
 }
 
+// PitchBendToMidi converts a PB value (00-FE, 80 = center) to a 14-bit MIDI pitch bend
+// (0-16383, 8192 = center). 00 bends fully down and FE fully up.
+func PitchBendToMidi(hexValue int) int {
+	hexValue = max(0, min(hexValue, 254))
+	if hexValue <= 0x80 {
+		return hexValue * 8192 / 0x80
+	}
+	return 8192 + (hexValue-0x80)*8191/(254-0x80)
+}
+
+// PitchBendToSemitones returns how far a PB value bends, for a synth with a bend range in semitones
+func PitchBendToSemitones(hexValue, bendRange int) float32 {
+	return float32(PitchBendToMidi(hexValue)-8192) / 8192 * float32(bendRange)
+}
+
 // VirtualDefaultConfig holds virtual default value for columns that display "--" but behave as a specific value
 type VirtualDefaultConfig struct {
 	DefaultValue int
@@ -659,6 +1051,8 @@ func GetVirtualDefault(col PhraseColumn) *VirtualDefaultConfig {
 		return &VirtualDefaultConfig{DefaultValue: 0xFE} // 0xFE = 20kHz (no filtering)
 	case ColVelocity:
 		return &VirtualDefaultConfig{DefaultValue: 0x40} // 0x40 = 64 = default velocity
+	case ColPitchBend:
+		return &VirtualDefaultConfig{DefaultValue: 0x80} // 0x80 = no bend
 	default:
 		return nil
 	}
@@ -673,6 +1067,32 @@ const (
 	ParameterTypeFloat                                // Float values with custom range
 )
 
+var parameterTypeNames = map[string]InstrumentParameterType{
+	"hex":   ParameterTypeHex,
+	"int":   ParameterTypeInt,
+	"float": ParameterTypeFloat,
+}
+
+// UnmarshalJSON accepts the type as a number or as "hex", "int" or "float", so that
+// definition files of user SoundMakers can be written by hand
+func (t *InstrumentParameterType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid parameter type %s", data)
+		}
+		*t = InstrumentParameterType(n)
+		return nil
+	}
+	value, ok := parameterTypeNames[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown parameter type %q (use hex, int or float)", name)
+	}
+	*t = value
+	return nil
+}
+
 // ParameterFormatter is a function type for custom parameter value formatting
 type ParameterFormatter func(value float32) string
 
@@ -693,9 +1113,10 @@ type InstrumentParameterDef struct {
 }
 
 type InstrumentDefinition struct {
-	Name        string                   `json:"name"`        // Instrument name (e.g. "DX7", "PolyPerc")
-	Description string                   `json:"description"` // Short description of the instrument
-	Parameters  []InstrumentParameterDef `json:"parameters"`  // Parameter definitions
+	Name        string                   `json:"name"`               // Instrument name (e.g. "DX7", "PolyPerc")
+	Description string                   `json:"description"`        // Short description of the instrument
+	Parameters  []InstrumentParameterDef `json:"parameters"`         // Parameter definitions
+	SynthDef    string                   `json:"synthDef,omitempty"` // SynthDef file of a user SoundMaker (empty for built-in ones)
 }
 
 // Global registry of all instrument definitions
@@ -915,6 +1336,58 @@ var InstrumentRegistry = map[string]InstrumentDefinition{
 			},
 		},
 	},
+	MultisampleSoundMakerName: {
+		Name:        MultisampleSoundMakerName,
+		Description: "Sampler that maps files to key ranges and velocity layers",
+		Parameters: []InstrumentParameterDef{
+			{
+				Key: "oneshot", DisplayName: "Oneshot", Type: ParameterTypeInt,
+				MinValue: 0, MaxValue: 1, DefaultValue: 0, Default: 0, Column: 0, Order: 0,
+				DisplayFormatter: FormatYesNo,
+			},
+			{
+				Key: "monophonic", DisplayName: "Monophonic", Type: ParameterTypeInt,
+				MinValue: 0, MaxValue: 1, DefaultValue: 0, Default: 0, Column: 1, Order: 0,
+				DisplayFormatter: FormatYesNo,
+			},
+		},
+	},
+}
+
+// builtinInstruments remembers the names of the SoundMakers compiled into the tracker
+var builtinInstruments = func() map[string]bool {
+	names := make(map[string]bool, len(InstrumentRegistry))
+	for name := range InstrumentRegistry {
+		names[name] = true
+	}
+	return names
+}()
+
+// IsBuiltinInstrument reports whether a SoundMaker is compiled into the tracker
+func IsBuiltinInstrument(name string) bool {
+	return builtinInstruments[name]
+}
+
+// RegisterInstrument adds a user SoundMaker to the registry, replacing an earlier
+// user SoundMaker of the same name. Built-in SoundMakers cannot be replaced.
+func RegisterInstrument(def InstrumentDefinition) error {
+	if def.Name == "" || strings.EqualFold(def.Name, "None") {
+		return fmt.Errorf("invalid SoundMaker name %q", def.Name)
+	}
+	if IsBuiltinInstrument(def.Name) {
+		return fmt.Errorf("SoundMaker %s is built in and cannot be replaced", def.Name)
+	}
+	InstrumentRegistry[def.Name] = def
+	return nil
+}
+
+// UnregisterUserInstruments removes all user SoundMakers from the registry
+func UnregisterUserInstruments() {
+	for name := range InstrumentRegistry {
+		if !IsBuiltinInstrument(name) {
+			delete(InstrumentRegistry, name)
+		}
+	}
 }
 
 // Helper functions for the instrument framework
@@ -1089,3 +1562,227 @@ func (settings *SoundMakerSettings) InitializeParameters() {
 		}
 	}
 }
+
+// Helper functions for the Multisample SoundMaker
+
+// noteNamePattern matches note names such as "C4", "f#2", "Bb-1" that don't follow a letter
+var noteNamePattern = regexp.MustCompile(`(?i)(?:^|[^a-z])([a-g])([#b]?)(-1|[0-9])`)
+
+// NoteFromFilename returns the MIDI note named in a sample filename (e.g. "piano_C4.wav" -> 60).
+// When several note names appear, the last one wins.
+func NoteFromFilename(filename string) (int, bool) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	var match []string
+	for _, loc := range noteNamePattern.FindAllStringSubmatchIndex(name, -1) {
+		// The note name must end the token ("C4" but not "C45" or "C4a")
+		if end := loc[1]; end < len(name) && isAlphanumeric(name[end]) {
+			continue
+		}
+		match = []string{name[loc[2]:loc[3]], name[loc[4]:loc[5]], name[loc[6]:loc[7]]}
+	}
+	if match == nil {
+		return 0, false
+	}
+
+	pitchClasses := map[string]int{"c": 0, "d": 2, "e": 4, "f": 5, "g": 7, "a": 9, "b": 11}
+	note := pitchClasses[strings.ToLower(match[0])]
+	switch match[1] {
+	case "#":
+		note++
+	case "b", "B":
+		note--
+	}
+	octave := -1
+	if match[2] != "-1" {
+		octave = int(match[2][0] - '0')
+	}
+	note += (octave + 1) * 12 // C4 = 60
+	if note < 0 || note > 127 {
+		return 0, false
+	}
+	return note, true
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// NewMultisampleZone creates a zone for a file covering all velocities. The root note is
+// taken from the filename when it names one, otherwise C4.
+func NewMultisampleZone(file string) MultisampleZone {
+	root, ok := NoteFromFilename(file)
+	if !ok {
+		root = 60
+	}
+	return MultisampleZone{
+		File:         file,
+		LowNote:      root,
+		HighNote:     root,
+		RootNote:     root,
+		LowVelocity:  0,
+		HighVelocity: 127,
+		FineTune:     0,
+		RoundRobin:   0,
+	}
+}
+
+// Contains reports whether the zone plays the given note and velocity
+func (z MultisampleZone) Contains(note, velocity int) bool {
+	return note >= z.LowNote && note <= z.HighNote &&
+		velocity >= z.LowVelocity && velocity <= z.HighVelocity
+}
+
+// Pitch returns the pitch offset in semitones needed to play a note from this zone
+func (z MultisampleZone) Pitch(note float32) float32 {
+	return note - float32(z.RootNote) + float32(z.FineTune)/100.0
+}
+
+// SelectMultisampleZones returns the zones to play for a note and velocity. Zones in round-robin
+// group 0 are layered; for other groups next(group, count) picks one of the matching zones.
+func SelectMultisampleZones(zones []MultisampleZone, note, velocity int, next func(group, count int) int) []MultisampleZone {
+	var selected []MultisampleZone
+	groups := make(map[int][]MultisampleZone)
+	var groupOrder []int
+	for _, zone := range zones {
+		if zone.File == "" || !zone.Contains(note, velocity) {
+			continue
+		}
+		if zone.RoundRobin == 0 {
+			selected = append(selected, zone)
+			continue
+		}
+		if _, exists := groups[zone.RoundRobin]; !exists {
+			groupOrder = append(groupOrder, zone.RoundRobin)
+		}
+		groups[zone.RoundRobin] = append(groups[zone.RoundRobin], zone)
+	}
+	for _, group := range groupOrder {
+		candidates := groups[group]
+		index := next(group, len(candidates))
+		if index < 0 || index >= len(candidates) {
+			index = 0
+		}
+		selected = append(selected, candidates[index])
+	}
+	return selected
+}
+
+// SetZoneFile assigns a file to the zone at index, appending a new zone when index is past
+// the last zone. Returns the index of the zone that was set.
+func (settings *SoundMakerSettings) SetZoneFile(index int, file string) int {
+	if index < 0 || index >= len(settings.Zones) {
+		settings.Zones = append(settings.Zones, NewMultisampleZone(file))
+		return len(settings.Zones) - 1
+	}
+	settings.Zones[index].File = file
+	return index
+}
+
+// RemoveZone deletes the zone at index
+func (settings *SoundMakerSettings) RemoveZone(index int) {
+	if index < 0 || index >= len(settings.Zones) {
+		return
+	}
+	settings.Zones = append(settings.Zones[:index], settings.Zones[index+1:]...)
+}
+
+// ProjectFileIssueKind is a problem found when consolidating the project's sample files
+type ProjectFileIssueKind int
+
+const (
+	ProjectFileUnused        ProjectFileIssueKind = iota // Not played by any phrase row or zone
+	ProjectFileMissing                                   // Played but the file does not exist
+	ProjectFileDuplicate                                 // Same content as another project file
+	ProjectFileStaleMetadata                             // Metadata for a file the project no longer uses
+	ProjectFileUntracked                                 // In the save folder but not in the file list (e.g. recordings)
+)
+
+// String returns the short label shown in the project files view
+func (k ProjectFileIssueKind) String() string {
+	switch k {
+	case ProjectFileUnused:
+		return "unused"
+	case ProjectFileMissing:
+		return "missing"
+	case ProjectFileDuplicate:
+		return "duplicate"
+	case ProjectFileStaleMetadata:
+		return "metadata"
+	case ProjectFileUntracked:
+		return "untracked"
+	}
+	return "?"
+}
+
+// ProjectFileIssue is one problem found with the project's sample files
+type ProjectFileIssue struct {
+	Kind     ProjectFileIssueKind
+	Path     string
+	Original string // File with the same content that is kept (duplicates only)
+	Uses     int    // Phrase rows and Multisample zones playing the file
+}
+
+// SampleTool is an offline operation that rewrites a sample file
+type SampleTool int
+
+const (
+	SampleToolNormalizePeak SampleTool = iota // Scale to a peak level
+	SampleToolNormalizeRMS                    // Scale to an RMS level
+	SampleToolTrimSilence                     // Cut silence from both ends
+	SampleToolFadeIn                          // Fade in from silence
+	SampleToolFadeOut                         // Fade out to silence
+	SampleToolReverse                         // Reverse the sample
+	SampleToolMono                            // Mix down to mono
+	SampleToolStereo                          // Convert to stereo
+	SampleToolResample                        // Convert the sample rate
+	SampleToolRestore                         // Restore the latest backup
+	SampleToolCount
+)
+
+func (t SampleTool) String() string {
+	switch t {
+	case SampleToolNormalizePeak:
+		return "Normalize peak"
+	case SampleToolNormalizeRMS:
+		return "Normalize RMS"
+	case SampleToolTrimSilence:
+		return "Trim silence"
+	case SampleToolFadeIn:
+		return "Fade in"
+	case SampleToolFadeOut:
+		return "Fade out"
+	case SampleToolReverse:
+		return "Reverse"
+	case SampleToolMono:
+		return "To mono"
+	case SampleToolStereo:
+		return "To stereo"
+	case SampleToolResample:
+		return "Sample rate"
+	case SampleToolRestore:
+		return "Restore backup"
+	}
+	return "Unknown"
+}
+
+// SampleToolSettings holds the parameters of the sample tools
+type SampleToolSettings struct {
+	PeakDB     float64 // Normalize peak target in dBFS
+	RMSDB      float64 // Normalize RMS target in dBFS
+	SilenceDB  float64 // Level below which trim silence cuts, in dBFS
+	FadeInMs   int     // Fade in length
+	FadeOutMs  int     // Fade out length
+	SampleRate int     // Target sample rate
+}
+
+// DefaultSampleToolSettings returns the initial sample tool parameters
+func DefaultSampleToolSettings() SampleToolSettings {
+	return SampleToolSettings{
+		PeakDB:     -0.1,
+		RMSDB:      -14,
+		SilenceDB:  -60,
+		FadeInMs:   10,
+		FadeOutMs:  10,
+		SampleRate: 48000,
+	}
+}
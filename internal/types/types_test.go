@@ -293,6 +293,7 @@ func TestGetVirtualDefault(t *testing.T) {
 		{"ColGate", ColGate, &VirtualDefaultConfig{DefaultValue: 0x80}},
 		{"ColPan", ColPan, &VirtualDefaultConfig{DefaultValue: 0x80}},
 		{"ColLowPassFilter", ColLowPassFilter, &VirtualDefaultConfig{DefaultValue: 0xFE}},
+		{"ColPitchBend", ColPitchBend, &VirtualDefaultConfig{DefaultValue: 0x80}},
 		{"ColNote (no default)", ColNote, nil},
 		{"ColFilename (no default)", ColFilename, nil},
 	}
@@ -309,3 +310,215 @@ func TestGetVirtualDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestPitchBendToMidi(t *testing.T) {
+	assert.Equal(t, 0, PitchBendToMidi(0x00))
+	assert.Equal(t, 4096, PitchBendToMidi(0x40))
+	assert.Equal(t, 8192, PitchBendToMidi(0x80))
+	assert.Equal(t, 16383, PitchBendToMidi(0xFE))
+	assert.Equal(t, 16383, PitchBendToMidi(0x1FF), "clamped")
+
+	assert.Equal(t, float32(-2), PitchBendToSemitones(0x00, 2))
+	assert.Equal(t, float32(0), PitchBendToSemitones(0x80, 12))
+	assert.InDelta(t, 12, PitchBendToSemitones(0xFE, 12), 0.01)
+}
+
+func TestMidiSettingsPitchBendRange(t *testing.T) {
+	settings := NewMidiSettings()
+	assert.Equal(t, -1, settings.Program)
+	assert.Equal(t, DefaultPitchBendRange, settings.PitchBendRange())
+	settings.BendRange = 24
+	assert.Equal(t, 24, settings.PitchBendRange())
+}
+
+func TestFileMetadataMarkers(t *testing.T) {
+	// Default: equal slices across the whole file
+	fm := FileMetadata{BPM: 120, Slices: 4}
+	assert.False(t, fm.HasCustomSlicing())
+	assert.Equal(t, []float32{0, 0.25, 0.5, 0.75, 1}, fm.Markers())
+
+	// Trim points rescale equal slices
+	fm.TrimStart = 0.2
+	fm.TrimEnd = 0.6
+	assert.True(t, fm.HasCustomSlicing())
+	markers := fm.Markers()
+	assert.Len(t, markers, 5)
+	assert.InDelta(t, 0.2, markers[0], 1e-6)
+	assert.InDelta(t, 0.3, markers[1], 1e-6)
+	assert.InDelta(t, 0.6, markers[4], 1e-6)
+
+	// Explicit markers are used when they match the slice count
+	fm.SliceMarkers = []float32{0.25, 0.4, 0.5}
+	assert.Equal(t, []float32{0.2, 0.25, 0.4, 0.5, 0.6}, fm.Markers())
+
+	// Stale markers for a different slice count are ignored
+	fm.Slices = 2
+	assert.Len(t, fm.Markers(), 3)
+	assert.InDelta(t, 0.4, fm.Markers()[1], 1e-6)
+}
+
+func TestFileMetadataSliceBounds(t *testing.T) {
+	fm := FileMetadata{Slices: 4, SliceMarkers: []float32{0.1, 0.5, 0.9}}
+
+	start, end := fm.SliceBounds(0)
+	assert.Equal(t, float32(0), start)
+	assert.Equal(t, float32(0.1), end)
+
+	start, end = fm.SliceBounds(3)
+	assert.Equal(t, float32(0.9), start)
+	assert.Equal(t, float32(1), end)
+
+	// Slice numbers wrap like the sampler does
+	start, end = fm.SliceBounds(5)
+	assert.Equal(t, float32(0.1), start)
+	assert.Equal(t, float32(0.5), end)
+
+	// TrimEnd of 0 means end of file
+	assert.Equal(t, float32(1), FileMetadata{}.EffectiveTrimEnd())
+}
+
+func TestFileMetadataRemap(t *testing.T) {
+	fm := FileMetadata{BPM: 120, Slices: 3, TrimStart: 0.2, TrimEnd: 0.8, SliceMarkers: []float32{0.4, 0.6}, LoopStart: 300, LoopEnd: 700}
+
+	// Keeping frames 100-900 of 1000 stretches fractions around the kept audio
+	cropped := fm.Cropped(100, 900, 1000)
+	assert.InDelta(t, 0.125, cropped.TrimStart, 1e-6)
+	assert.InDelta(t, 0.875, cropped.TrimEnd, 1e-6)
+	assert.InDeltaSlice(t, []float32{0.375, 0.625}, cropped.SliceMarkers, 1e-6)
+	assert.Equal(t, 200, cropped.LoopStart)
+	assert.Equal(t, 600, cropped.LoopEnd)
+	assert.Equal(t, float32(120), cropped.BPM)
+
+	// Cutting into the trimmed region drops the markers and resets the loop end
+	cropped = fm.Cropped(500, 650, 1000)
+	assert.Equal(t, float32(0), cropped.TrimStart)
+	assert.Equal(t, float32(0), cropped.TrimEnd)
+	assert.Nil(t, cropped.SliceMarkers)
+	assert.Equal(t, 0, cropped.LoopStart)
+	assert.Equal(t, 0, cropped.LoopEnd)
+
+	reversed := fm.Reversed(1000)
+	assert.InDelta(t, 0.2, reversed.TrimStart, 1e-6)
+	assert.InDelta(t, 0.8, reversed.TrimEnd, 1e-6)
+	assert.InDeltaSlice(t, []float32{0.4, 0.6}, reversed.SliceMarkers, 1e-6)
+	assert.Equal(t, 300, reversed.LoopStart)
+	assert.Equal(t, 700, reversed.LoopEnd)
+
+	// A loop to the end of the file becomes a loop from the start
+	reversed = FileMetadata{LoopStart: 250}.Reversed(1000)
+	assert.Equal(t, 0, reversed.LoopStart)
+	assert.Equal(t, 750, reversed.LoopEnd)
+	assert.Equal(t, FileMetadata{LoopStart: 250}, reversed.Reversed(1000))
+
+	resampled := fm.Resampled(0.5)
+	assert.Equal(t, 150, resampled.LoopStart)
+	assert.Equal(t, 350, resampled.LoopEnd)
+	assert.Equal(t, fm.SliceMarkers, resampled.SliceMarkers)
+}
+
+func TestNoteFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		note     int
+		ok       bool
+	}{
+		{"piano_C4.wav", 60, true},
+		{"samples/Piano-F#2.flac", 42, true},
+		{"choir_Bb3_soft.wav", 58, true},
+		{"sub_c-1.wav", 0, true},
+		{"Rhodes_C3_D3.wav", 50, true}, // last note name wins
+		{"amen_beats8_bpm172.wav", 0, false},
+		{"kick.wav", 0, false},
+		{"C45.wav", 0, false},
+	}
+	for _, tt := range tests {
+		note, ok := NoteFromFilename(tt.filename)
+		assert.Equal(t, tt.ok, ok, tt.filename)
+		if tt.ok {
+			assert.Equal(t, tt.note, note, tt.filename)
+		}
+	}
+
+	zone := NewMultisampleZone("piano_A4.wav")
+	assert.Equal(t, 69, zone.RootNote)
+	assert.Equal(t, 69, zone.LowNote)
+	assert.Equal(t, 69, zone.HighNote)
+	assert.Equal(t, 127, zone.HighVelocity)
+	assert.Equal(t, 60, NewMultisampleZone("kick.wav").RootNote)
+}
+
+func TestSelectMultisampleZones(t *testing.T) {
+	zones := []MultisampleZone{
+		{File: "low.wav", LowNote: 0, HighNote: 59, RootNote: 48, HighVelocity: 127},
+		{File: "soft.wav", LowNote: 60, HighNote: 127, RootNote: 60, HighVelocity: 63},
+		{File: "loud1.wav", LowNote: 60, HighNote: 127, RootNote: 60, LowVelocity: 64, HighVelocity: 127, RoundRobin: 1},
+		{File: "loud2.wav", LowNote: 60, HighNote: 127, RootNote: 60, LowVelocity: 64, HighVelocity: 127, RoundRobin: 1, FineTune: -50},
+		{File: "", LowNote: 0, HighNote: 127, HighVelocity: 127}, // zones without a file never play
+	}
+	next := func(group, count int) int { return 1 }
+
+	selected := SelectMultisampleZones(zones, 50, 100, next)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "low.wav", selected[0].File)
+	assert.Equal(t, float32(2), selected[0].Pitch(50))
+
+	selected = SelectMultisampleZones(zones, 62, 30, next)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "soft.wav", selected[0].File)
+
+	// Round-robin groups play one zone picked by next
+	selected = SelectMultisampleZones(zones, 62, 100, next)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "loud2.wav", selected[0].File)
+	assert.Equal(t, float32(1.5), selected[0].Pitch(62))
+
+	// Zones outside their ranges are skipped
+	assert.Empty(t, SelectMultisampleZones(zones[:1], 70, 100, next))
+}
+
+func TestSoundMakerZones(t *testing.T) {
+	var settings SoundMakerSettings
+
+	// Setting past the last zone appends
+	assert.Equal(t, 0, settings.SetZoneFile(0, "piano_C4.wav"))
+	assert.Equal(t, 1, settings.SetZoneFile(5, "piano_C5.wav"))
+	assert.Len(t, settings.Zones, 2)
+	assert.Equal(t, 72, settings.Zones[1].RootNote)
+
+	// Setting an existing zone only replaces the file
+	settings.Zones[0].HighNote = 66
+	assert.Equal(t, 0, settings.SetZoneFile(0, "piano_D4.wav"))
+	assert.Equal(t, "piano_D4.wav", settings.Zones[0].File)
+	assert.Equal(t, 66, settings.Zones[0].HighNote)
+
+	settings.RemoveZone(0)
+	assert.Len(t, settings.Zones, 1)
+	assert.Equal(t, "piano_C5.wav", settings.Zones[0].File)
+	settings.RemoveZone(3)
+	assert.Len(t, settings.Zones, 1)
+}
+
+func TestArpeggioRowValues(t *testing.T) {
+	row := NewArpeggioRow()
+	assert.True(t, row.IsEmpty())
+	for col := range ArpeggioColCount {
+		assert.Equal(t, "--", row.CellText(col))
+	}
+
+	row.SetValue(ArpeggioColDI, 99)
+	assert.Equal(t, "cv", row.CellText(ArpeggioColDI))
+	row.SetValue(ArpeggioColDIV, 0)
+	assert.Equal(t, -1, row.Divisor, "there is no divisor 00")
+	row.SetValue(ArpeggioColVE, 0x90)
+	assert.Equal(t, "7F", row.CellText(ArpeggioColVE))
+	row.SetValue(ArpeggioColRS, 1)
+	assert.Equal(t, "RS", row.CellText(ArpeggioColRS))
+	row.SetValue(ArpeggioColRT, 12)
+	assert.Equal(t, ArpeggioMaxRatchet, row.Ratchet)
+	assert.False(t, row.IsEmpty())
+
+	for col := range ArpeggioColCount {
+		row.ClearValue(col)
+	}
+	assert.True(t, row.IsEmpty())
+}
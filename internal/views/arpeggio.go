@@ -9,33 +9,54 @@ import (
 	"github.com/schollz/collidertracker/internal/types"
 )
 
+// arpeggioColumnLabels are the headers of the arpeggio columns in ArpeggioUIColumn order
+var arpeggioColumnLabels = [types.ArpeggioColCount]string{"DI", "CO", "/", "OC", "VE", "GT", "RS", "RT"}
+
 func GetArpeggioStatusMessage(m *model.Model) string {
 	settings := m.ArpeggioSettings[m.ArpeggioEditingIndex]
-	currentRow := &settings.Rows[m.CurrentRow]
 
 	var columnStatus string
-	switch m.CurrentCol {
-	case 0: // DI (Direction) column
-		directionText := "--"
-		switch types.ArpeggioDirection(currentRow.Direction) {
-		case types.ArpeggioDirectionUp:
-			directionText = "u-"
-		case types.ArpeggioDirectionDown:
-			directionText = "d-"
-		}
-		columnStatus = fmt.Sprintf("Direction %s", directionText)
-	case 1: // CO (Count) column
-		countText := "--"
-		if currentRow.Count != -1 {
-			countText = fmt.Sprintf("%02d", currentRow.Count)
+	if m.CurrentRow >= types.ArpeggioLatchRow {
+		if settings.Latch {
+			columnStatus = "Latch On: keeps looping across rows while playing, until the track plays a row without it"
+		} else {
+			columnStatus = "Latch Off: the arpeggio plays once per row"
 		}
-		columnStatus = fmt.Sprintf("Count %s", countText)
-	case 2: // Divisor (/) column
-		divisorText := "--"
-		if currentRow.Divisor != -1 {
-			divisorText = fmt.Sprintf("%02d", currentRow.Divisor)
+	} else {
+		currentRow := settings.Rows[m.CurrentRow]
+		text := currentRow.CellText(types.ArpeggioUIColumn(m.CurrentCol))
+		switch types.ArpeggioUIColumn(m.CurrentCol) {
+		case types.ArpeggioColDI: // DI (Direction) column
+			columnStatus = fmt.Sprintf("Direction %s (%s)", text, types.ArpeggioDirectionName(types.ArpeggioDirection(currentRow.Direction)))
+		case types.ArpeggioColCO: // CO (Count) column
+			columnStatus = fmt.Sprintf("Count %s", text)
+		case types.ArpeggioColDIV: // Divisor (/) column
+			columnStatus = fmt.Sprintf("Divisor /%s", text)
+		case types.ArpeggioColOC: // OC (Octave range) column
+			if currentRow.Octaves == 0 {
+				columnStatus = "Octaves -- (u-/d- keep climbing, other directions stay in one octave)"
+			} else {
+				columnStatus = fmt.Sprintf("Octaves %d", currentRow.Octaves)
+			}
+		case types.ArpeggioColVE: // VE (Velocity) column
+			columnStatus = fmt.Sprintf("Step velocity %s", text)
+			if currentRow.Velocity == 0 {
+				columnStatus += " (row velocity)"
+			}
+		case types.ArpeggioColGT: // GT (Gate) column
+			columnStatus = fmt.Sprintf("Step gate %s", text)
+			if currentRow.Gate == 0 {
+				columnStatus += " (row gate)"
+			}
+		case types.ArpeggioColRS: // RS (Rest) column
+			if currentRow.Rest == 1 {
+				columnStatus = "Rest: steps keep time without playing"
+			} else {
+				columnStatus = "Rest -- (steps play)"
+			}
+		case types.ArpeggioColRT: // RT (Ratchet) column
+			columnStatus = fmt.Sprintf("Ratchet %d hits per step", max(1, currentRow.Ratchet))
 		}
-		columnStatus = fmt.Sprintf("Divisor /%s", divisorText)
 	}
 
 	baseMsg := fmt.Sprintf("Up/Down: Navigate rows | Left/Right: Navigate columns | %s+Arrow: Adjust values | Shift+Left: Back to Phrase view", input.GetModifierKey())
@@ -49,75 +70,46 @@ func RenderArpeggioView(m *model.Model) string {
 		content.WriteString("\n")
 
 		// Render header for the arpeggio table
-		headerRow := fmt.Sprintf("     %-4s %-4s %-4s", styles.Label.Render("DI"), styles.Label.Render("CO"), styles.Label.Render("/"))
+		headerRow := "    "
+		for _, label := range arpeggioColumnLabels {
+			headerRow += " " + styles.Label.Render(fmt.Sprintf("%-2s", label))
+		}
 		content.WriteString(headerRow)
 		content.WriteString("\n")
 
 		// Get current arpeggio settings
 		settings := m.ArpeggioSettings[m.ArpeggioEditingIndex]
 
-		// Render 16 rows (00 to 0F), each with its own DI and CO values
+		// Render 16 rows (00 to 0F), each with its own values
 		for row := 0; row < 16; row++ {
 			// Row label
 			rowLabel := fmt.Sprintf("%02X", row)
 
-			// Get DI and CO values for this specific row
-			arpeggioRow := settings.Rows[row]
-
-			// Direction (DI) text for this row
-			var diText string
-			switch types.ArpeggioDirection(arpeggioRow.Direction) {
-			case types.ArpeggioDirectionNone:
-				diText = "--"
-			case types.ArpeggioDirectionUp:
-				diText = "u-"
-			case types.ArpeggioDirectionDown:
-				diText = "d-"
-			default:
-				diText = "--"
-			}
-
-			// Count (CO) text for this row
-			coText := "--"
-			if arpeggioRow.Count != -1 {
-				coText = fmt.Sprintf("%02X", arpeggioRow.Count)
-			}
-
-			// Divisor (/) text for this row
-			divisorText := "--"
-			if arpeggioRow.Divisor != -1 {
-				divisorText = fmt.Sprintf("%02X", arpeggioRow.Divisor)
+			rowData := fmt.Sprintf("  %s", styles.Label.Render(rowLabel))
+			for col := range types.ArpeggioColCount {
+				// Cell is selectable if this row and column are selected
+				cellText := settings.Rows[row].CellText(col)
+				if m.CurrentRow == row && m.CurrentCol == int(col) {
+					rowData += " " + styles.Selected.Render(cellText)
+				} else {
+					rowData += " " + styles.Normal.Render(cellText)
+				}
 			}
-
-			// Direction (DI) column - selectable if this row and column are selected
-			var diCell string
-			if m.CurrentRow == row && m.CurrentCol == 0 { // Column 0 for DI
-				diCell = styles.Selected.Render(diText)
-			} else {
-				diCell = styles.Normal.Render(diText)
-			}
-
-			// Count (CO) column - selectable if this row and column are selected
-			var coCell string
-			if m.CurrentRow == row && m.CurrentCol == 1 { // Column 1 for CO
-				coCell = styles.Selected.Render(coText)
-			} else {
-				coCell = styles.Normal.Render(coText)
-			}
-
-			// Divisor (/) column - selectable if this row and column are selected
-			var divisorCell string
-			if m.CurrentRow == row && m.CurrentCol == 2 { // Column 2 for Divisor
-				divisorCell = styles.Selected.Render(divisorText)
-			} else {
-				divisorCell = styles.Normal.Render(divisorText)
-			}
-
-			rowData := fmt.Sprintf("  %-4s %-4s %-4s %-4s", styles.Label.Render(rowLabel), diCell, coCell, divisorCell)
 			content.WriteString(rowData)
 			content.WriteString("\n")
 		}
 
+		// Latch setting below the rows
+		latchText := "Off"
+		if settings.Latch {
+			latchText = "On"
+		}
+		latchCell := styles.Normal.Render(latchText)
+		if m.CurrentRow == types.ArpeggioLatchRow {
+			latchCell = styles.Selected.Render(latchText)
+		}
+		content.WriteString(fmt.Sprintf("\n  %s %s\n", styles.Label.Render("Latch:"), latchCell))
+
 		return content.String()
-	}, statusMsg, 18) // 16 rows + 1 header + 1 spacing
+	}, statusMsg, 20) // 16 rows + 1 header + 1 spacing + latch
 }
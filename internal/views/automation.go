@@ -0,0 +1,200 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// Size of the graph of the lane being edited
+const (
+	automationGraphWidth  = 48
+	automationGraphHeight = 8
+)
+
+// automationVisiblePoints is the number of breakpoint rows shown at once
+const automationVisiblePoints = 8
+
+// automationBlocks draw the graph with eight levels per line
+var automationBlocks = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// automationOwnerName names the phrase or chain whose lanes are edited
+func automationOwnerName(owner types.AutomationOwner) string {
+	kind := "instrument"
+	if owner.Sampler {
+		kind = "sampler"
+	}
+	if owner.Scope == types.AutomationScopeChain {
+		return fmt.Sprintf("Chain %02X (%s)", owner.ID, kind)
+	}
+	return fmt.Sprintf("Phrase %02X (%s)", owner.ID, kind)
+}
+
+// renderAutomationGraph draws a lane as a line over the length of its phrase or chain.
+// Breakpoints are highlighted, the selected one like a selected cell.
+func renderAutomationGraph(styles *ViewStyles, lane types.AutomationLane, length, selectedPoint int) string {
+	length = max(length, 1)
+	if len(lane.Points) > 0 {
+		length = max(length, lane.Points[len(lane.Points)-1].Tick)
+	}
+
+	// The breakpoint each column shows, if any
+	pointAt := make(map[int]int)
+	for i, point := range lane.Points {
+		pointAt[point.Tick*(automationGraphWidth-1)/length] = i
+	}
+
+	levels := make([]int, automationGraphWidth)
+	for x := range levels {
+		tick := float64(x) * float64(length) / float64(automationGraphWidth-1)
+		levels[x] = int(lane.ValueAt(tick) / 254 * float64(automationGraphHeight*8-1))
+	}
+
+	var graph strings.Builder
+	for line := automationGraphHeight - 1; line >= 0; line-- {
+		axis := "  "
+		if line == automationGraphHeight-1 {
+			axis = "FE"
+		} else if line == 0 {
+			axis = "00"
+		}
+		graph.WriteString("  " + styles.Label.Render(axis) + " ")
+		for x, level := range levels {
+			cell := " "
+			if level/8 == line {
+				cell = automationBlocks[level%8]
+			}
+			if i, ok := pointAt[x]; ok && level/8 == line {
+				if i == selectedPoint {
+					cell = styles.Selected.Render(cell)
+				} else {
+					cell = styles.Playback.Render(cell)
+				}
+			} else {
+				cell = styles.Normal.Render(cell)
+			}
+			graph.WriteString(cell)
+		}
+		graph.WriteString("\n")
+	}
+	end := fmt.Sprintf("%03X", length)
+	graph.WriteString("     " + styles.Label.Render("000"+strings.Repeat(" ", automationGraphWidth-3-len(end))+end) + "\n")
+	return graph.String()
+}
+
+func GetAutomationStatusMessage(m *model.Model) string {
+	lane := input.AutomationEditingLane(m)
+	var columnStatus string
+	switch {
+	case m.CurrentRow == types.AutomationRowLane:
+		columnStatus = fmt.Sprintf("Lane %d of %d", m.AutomationLane+1, types.AutomationLanesPerOwner)
+	case m.CurrentRow == types.AutomationRowTarget:
+		if lane == nil {
+			columnStatus = "Target -- (choose one to start the lane)"
+		} else {
+			columnStatus = "Target " + types.AutomationTargetName(lane.Target) + " | Backspace: Remove lane"
+		}
+	case lane == nil:
+	case m.CurrentRow >= types.AutomationRowFirstPoint+len(lane.Points):
+		columnStatus = fmt.Sprintf("%s+Arrow: Add breakpoint", input.GetModifierKey())
+	default:
+		point := lane.Points[m.CurrentRow-types.AutomationRowFirstPoint]
+		switch types.AutomationUIColumn(m.CurrentCol) {
+		case types.AutomationColTick:
+			columnStatus = fmt.Sprintf("Tick %d", point.Tick)
+		case types.AutomationColValue:
+			columnStatus = fmt.Sprintf("Value %02X", point.Value)
+			if target, ok := types.FindAutomationTarget(lane.Target); ok {
+				columnStatus += fmt.Sprintf(" (%.2f)", target.ValueFromHex(float64(point.Value)))
+			}
+		case types.AutomationColCurve:
+			columnStatus = "Curve to the next breakpoint: " + types.AutomationCurveName(point.Curve)
+		}
+		columnStatus += " | Backspace: Remove breakpoint"
+	}
+
+	baseMsg := fmt.Sprintf("%s+Arrow: Adjust | Shift+Left: Back", input.GetModifierKey())
+	if columnStatus == "" {
+		return baseMsg
+	}
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderAutomationView(m *model.Model) string {
+	statusMsg := GetAutomationStatusMessage(m)
+	return renderViewWithCommonPattern(m, "Automation", automationOwnerName(m.AutomationEditing), func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		cell := func(row, col int, text string) string {
+			if m.CurrentRow == row && (row < types.AutomationRowFirstPoint || m.CurrentCol == col) {
+				return styles.Selected.Render(text)
+			}
+			return styles.Normal.Render(text)
+		}
+
+		// Lanes of the phrase or chain, the selected one shown as a cell
+		laneCount := len(m.AutomationLaneIndices(m.AutomationEditing))
+		lanesRow := "  " + styles.Label.Render(fmt.Sprintf("%-8s", "Lane"))
+		for i := 0; i < types.AutomationLanesPerOwner; i++ {
+			text := fmt.Sprintf("%d", i+1)
+			if i == m.AutomationLane {
+				lanesRow += " " + cell(types.AutomationRowLane, 0, text)
+			} else if i < laneCount {
+				lanesRow += " " + styles.Normal.Render(text)
+			} else {
+				lanesRow += " " + styles.Label.Render(text)
+			}
+		}
+		content.WriteString(lanesRow + "\n")
+
+		lane := input.AutomationEditingLane(m)
+		targetName := "--"
+		if lane != nil {
+			targetName = types.AutomationTargetName(lane.Target)
+		}
+		content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%-8s", "Target")) + " " +
+			cell(types.AutomationRowTarget, 0, targetName) + "\n")
+		length := input.AutomationOwnerTicks(m, m.AutomationEditing)
+		content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%-8s", "Length")) + " " +
+			styles.Normal.Render(fmt.Sprintf("%d ticks", length)) + "\n\n")
+
+		var points []types.AutomationPoint
+		if lane != nil {
+			points = lane.Points
+			content.WriteString(renderAutomationGraph(styles, *lane, length, m.CurrentRow-types.AutomationRowFirstPoint))
+		} else {
+			content.WriteString(strings.Repeat("\n", automationGraphHeight+1))
+		}
+		content.WriteString("\n")
+
+		content.WriteString("  " + styles.Label.Render("PT TICK VAL CURVE") + "\n")
+		if lane != nil {
+			// Keep the selected breakpoint in view
+			selected := m.CurrentRow - types.AutomationRowFirstPoint
+			start := max(0, min(selected-automationVisiblePoints+1, len(points)+1-automationVisiblePoints))
+			end := min(start+automationVisiblePoints, len(points)+1)
+			for i := start; i < end; i++ {
+				row := types.AutomationRowFirstPoint + i
+				if i == len(points) {
+					addText := styles.Label.Render("(add breakpoint)")
+					if m.CurrentRow == row {
+						addText = styles.Selected.Render("(add breakpoint)")
+					}
+					content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%02X", i)) + " " + addText + "\n")
+					continue
+				}
+				point := points[i]
+				content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%02X", i)) +
+					" " + cell(row, int(types.AutomationColTick), fmt.Sprintf("%03X", point.Tick)) +
+					"  " + cell(row, int(types.AutomationColValue), fmt.Sprintf("%02X", point.Value)) +
+					"  " + cell(row, int(types.AutomationColCurve), types.AutomationCurveName(point.Curve)) + "\n")
+			}
+		}
+
+		return content.String()
+	}, statusMsg, automationGraphHeight+automationVisiblePoints+8) // lanes, target, length, graph, axis, header and spacing
+}
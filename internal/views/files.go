@@ -28,6 +28,11 @@ func RenderFileMetadataView(m *model.Model) string {
 		playthroughOptions := []string{"Sliced", "Oneshot"}
 		syncToBPMOptions := []string{"No", "Yes"}
 
+		loopEndValue := "End"
+		if metadata.LoopEnd > 0 {
+			loopEndValue = fmt.Sprintf("%d", metadata.LoopEnd)
+		}
+
 		// Metadata settings with common rendering pattern
 		settings := []struct {
 			label string
@@ -38,6 +43,10 @@ func RenderFileMetadataView(m *model.Model) string {
 			{"Slices:", fmt.Sprintf("%d", metadata.Slices), 1},
 			{"Playthrough:", playthroughOptions[metadata.Playthrough], 2},
 			{"Sync to BPM:", syncToBPMOptions[metadata.SyncToBPM], 3},
+			{"Loop:", types.GetLoopModeString(metadata.LoopMode), 4},
+			{"Loop start:", fmt.Sprintf("%d", metadata.LoopStart), 5},
+			{"Loop end:", loopEndValue, 6},
+			{"Crossfade:", fmt.Sprintf("%d ms", metadata.LoopXfadeMs), 7},
 		}
 
 		for _, setting := range settings {
@@ -57,10 +66,19 @@ func RenderFileMetadataView(m *model.Model) string {
 		// File info
 		fileInfo := fmt.Sprintf("File: %s", m.MetadataEditingFile)
 		content.WriteString(styles.Normal.Render(fileInfo))
-		content.WriteString("\n\n")
+		content.WriteString("\n")
+		if metadata.HasCustomSlicing() {
+			trimInfo := fmt.Sprintf("Trim: %.2f%% - %.2f%%", metadata.TrimStart*100, metadata.EffectiveTrimEnd()*100)
+			if len(metadata.SliceMarkers) > 0 {
+				trimInfo += " (custom slice markers)"
+			}
+			content.WriteString(styles.Label.Render(trimInfo))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
 
 		return content.String()
-	}, fmt.Sprintf("Up/Down: Navigate | %s+Arrow: Adjust values | Shift+Down: Back to File Browser", input.GetModifierKey()), 7)
+	}, fmt.Sprintf("Up/Down: Navigate | %s+Arrow: Adjust values | Shift+Right: Slice editor | Shift+Down: Back to File Browser", input.GetModifierKey()), 11)
 }
 
 func RenderFileView(m *model.Model) string {
@@ -99,5 +117,5 @@ func RenderFileView(m *model.Model) string {
 		}
 
 		return content.String()
-	}, fmt.Sprintf("SPACE: Select file | %s+Right: Play/Stop | Shift+Left: Back to phrase", input.GetModifierKey()), m.GetVisibleRows())
+	}, fmt.Sprintf("SPACE: Select file | %s+Right: Play/Stop | E: Sample tools | /: Sample library | Shift+Left: Back to phrase", input.GetModifierKey()), m.GetVisibleRows())
 }
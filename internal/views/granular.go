@@ -0,0 +1,69 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func RenderGranularView(m *model.Model) string {
+	// Styles
+	selectedStyle := lipgloss.NewStyle().Background(lipgloss.Color("7")).Foreground(lipgloss.Color("0")) // Lighter background, dark text
+	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	// Main container style with padding
+	containerStyle := lipgloss.NewStyle().
+		Padding(1, 2)
+
+	// Content builder
+	var content strings.Builder
+
+	// Render header
+	header := "Granular Settings"
+	granularHeader := fmt.Sprintf("Granular %02X", m.GranularEditingIndex)
+	content.WriteString(RenderHeader(m, header, granularHeader))
+	content.WriteString("\n")
+
+	// Get current granular settings
+	settings := m.GranularSettings[m.GranularEditingIndex]
+
+	size := "off"
+	if settings.Size > 0 {
+		size = fmt.Sprintf("%.0f ms", settings.Size)
+	}
+	rows := []struct {
+		row   types.GranularSettingsRow
+		label string
+		value string
+	}{
+		{types.GranularSettingsRowSize, "Size:", size},
+		{types.GranularSettingsRowDensity, "Density:", fmt.Sprintf("%.0f /s", settings.Density)},
+		{types.GranularSettingsRowPosition, "Position:", fmt.Sprintf("%.0f%%", settings.Position*100)},
+		{types.GranularSettingsRowPositionJitter, "Pos jitter:", fmt.Sprintf("%.0f%%", settings.PositionJitter*100)},
+		{types.GranularSettingsRowPitchJitter, "Pitch jitter:", fmt.Sprintf("%.1f st", settings.PitchJitter)},
+		{types.GranularSettingsRowSpray, "Spray:", fmt.Sprintf("%.0f%%", settings.Spray*100)},
+	}
+	for _, r := range rows {
+		var cell string
+		if m.CurrentRow == int(r.row) {
+			cell = selectedStyle.Render(r.value)
+		} else {
+			cell = normalStyle.Render(r.value)
+		}
+		content.WriteString(fmt.Sprintf("  %-12s %s", labelStyle.Render(r.label), cell))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	// Footer with status
+	statusMsg := fmt.Sprintf("Up/Down: Navigate | %s+Arrow: Adjust values | Size 0 plays the row normally | Shift+Left: Back to Phrase view", input.GetModifierKey())
+	content.WriteString(RenderFooter(m, len(rows)+3, statusMsg))
+
+	// Apply container padding
+	return containerStyle.Render(content.String())
+}
@@ -0,0 +1,101 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// insertFXModuleWidth is the width of the module column of the insert FX view
+const insertFXModuleWidth = 10
+
+// insertFXStatus describes the FX and FV cells of the phrase row under the cursor
+func insertFXStatus(m *model.Model) string {
+	phrasesData := m.GetCurrentPhrasesData()
+	fx := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColInsertFX]
+	fv := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColInsertFXValue]
+	if fx == -1 {
+		return "FX: -- (slot and parameter, e.g. 12 = slot 1 parameter 2) | FV: 00-FE over its range"
+	}
+	if m.CurrentTrack < 0 || m.CurrentTrack >= len(m.InsertFX) {
+		return fmt.Sprintf("FX: %02X", fx)
+	}
+	slot, paramIndex := types.DecodeInsertFXColumn(fx)
+	chain := m.InsertFX[m.CurrentTrack]
+	if slot >= len(chain.Slots) {
+		return fmt.Sprintf("FX: %02X (track %d has no slot %X)", fx, m.CurrentTrack+1, slot)
+	}
+	module, ok := types.GetInsertFXModule(chain.Slots[slot].Module)
+	if !ok || paramIndex >= len(module.Parameters) {
+		return fmt.Sprintf("FX: %02X (%s has no parameter %X)", fx, chain.Slots[slot].Module, paramIndex)
+	}
+	param := module.Parameters[paramIndex]
+	status := fmt.Sprintf("FX: %02X (%s %s)", fx, module.Name, param.DisplayName)
+	if fv != -1 {
+		status += fmt.Sprintf(" | FV: %02X (%s)", fv, types.FormatInsertFXValue(param, types.InsertFXHexToValue(param, fv)))
+	}
+	return status
+}
+
+func GetInsertFXStatusMessage(m *model.Model) string {
+	var columnStatus string
+	chain := m.InsertFX[m.InsertFXEditingTrack]
+	if m.CurrentRow >= len(chain.Slots) {
+		columnStatus = fmt.Sprintf("%s+Up/Down: Add a module", input.GetModifierKey())
+	} else if module, ok := types.GetInsertFXModule(chain.Slots[m.CurrentRow].Module); !ok {
+		columnStatus = fmt.Sprintf("Unknown module %q", chain.Slots[m.CurrentRow].Module)
+	} else if m.CurrentCol == 0 {
+		columnStatus = fmt.Sprintf("Slot %X: %s", m.CurrentRow, module.Description)
+	} else if m.CurrentCol-1 < len(module.Parameters) {
+		param := module.Parameters[m.CurrentCol-1]
+		value := chain.Slots[m.CurrentRow].Value(param)
+		columnStatus = fmt.Sprintf("%s %s: %s (FX %X%X)", module.Name, param.DisplayName, types.FormatInsertFXValue(param, value), m.CurrentRow, m.CurrentCol-1)
+		if module.TempoSynced == param.Key {
+			columnStatus += fmt.Sprintf(", %.0f ms at %.0f BPM", types.DelayDivisionSeconds(int(value), m.BPM)*1000, m.BPM)
+		}
+	}
+
+	baseMsg := fmt.Sprintf("%s+Arrow: Adjust | Shift+Up/Down: Move | Backspace: Remove | Shift+Left: Back to Phrase view", input.GetModifierKey())
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderInsertFXView(m *model.Model) string {
+	statusMsg := GetInsertFXStatusMessage(m)
+	return renderViewWithCommonPattern(m, "Insert FX", fmt.Sprintf("Track %d", m.InsertFXEditingTrack+1), func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+		content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%-2s %-*s %s", "FX", insertFXModuleWidth, "MODULE", "PARAMETERS")) + "\n")
+
+		chain := m.InsertFX[m.InsertFXEditingTrack]
+		for row := 0; row < input.InsertFXRowCount(m); row++ {
+			moduleText := "--"
+			var module types.InsertFXModule
+			if row < len(chain.Slots) {
+				moduleText = chain.Slots[row].Module
+				module, _ = types.GetInsertFXModule(chain.Slots[row].Module)
+			}
+			moduleCell := fmt.Sprintf("%-*s", insertFXModuleWidth, moduleText)
+			if m.CurrentRow == row && m.CurrentCol == 0 {
+				moduleCell = styles.Selected.Render(moduleCell)
+			} else {
+				moduleCell = styles.Normal.Render(moduleCell)
+			}
+			line := fmt.Sprintf("  %s %s", styles.Label.Render(fmt.Sprintf("%X ", row)), moduleCell)
+			for i, param := range module.Parameters {
+				valueCell := fmt.Sprintf("%-7s", types.FormatInsertFXValue(param, chain.Slots[row].Value(param)))
+				if m.CurrentRow == row && m.CurrentCol == i+1 {
+					valueCell = styles.Selected.Render(valueCell)
+				} else {
+					valueCell = styles.Normal.Render(valueCell)
+				}
+				line += fmt.Sprintf(" %s %s", styles.Label.Render(fmt.Sprintf("%X:%s", i, param.DisplayName)), valueCell)
+			}
+			content.WriteString(line + "\n")
+		}
+
+		return content.String()
+	}, statusMsg, types.InsertFXSlotsPerTrack+2) // Slots + 1 header + 1 spacing
+}
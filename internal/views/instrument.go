@@ -21,6 +21,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 	sliceDownbeatStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("7"))                          // Lighter gray for downbeats
 	playbackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))                              // Green
 	copiedStyle := lipgloss.NewStyle().Background(lipgloss.Color("3")).Foreground(lipgloss.Color("0")) // Yellow background
+	lockedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))                                 // Yellow
 
 	// Main container style with padding
 	containerStyle := lipgloss.NewStyle().
@@ -41,53 +42,19 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 
 	if m.SOColumnMode == types.SOModeMIDI {
 		somiHeader = headerStyle.Render("MI")
-		// Change ADSR and effect columns to show CC numbers from MidiCCNumbers array
-		// Format each CC number with highlighting if on header row
-
-		cc0 := fmt.Sprintf("%02X", m.MidiCCNumbers[0])
-		cc1 := fmt.Sprintf("%02X", m.MidiCCNumbers[1])
-		cc2 := fmt.Sprintf("%02X", m.MidiCCNumbers[2])
-		cc3 := fmt.Sprintf("%02X", m.MidiCCNumbers[3])
-		cc4 := fmt.Sprintf("%02X", m.MidiCCNumbers[4])
-		cc5 := fmt.Sprintf("%02X", m.MidiCCNumbers[5])
-		cc6 := fmt.Sprintf("%02X", m.MidiCCNumbers[6])
-		cc7 := fmt.Sprintf("%02X", m.MidiCCNumbers[7])
-		cc8 := fmt.Sprintf("%02X", m.MidiCCNumbers[8])
-
-		// Apply header style to all CC numbers first (white)
-		cc0 = headerStyle.Render(cc0)
-		cc1 = headerStyle.Render(cc1)
-		cc2 = headerStyle.Render(cc2)
-		cc3 = headerStyle.Render(cc3)
-		cc4 = headerStyle.Render(cc4)
-		cc5 = headerStyle.Render(cc5)
-		cc6 = headerStyle.Render(cc6)
-		cc7 = headerStyle.Render(cc7)
-		cc8 = headerStyle.Render(cc8)
-
-		// Then highlight the selected column header if on header row
-		if m.CurrentRow == -1 {
-			switch m.CurrentCol {
-			case int(types.InstrumentColATK):
-				cc0 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[0]))
-			case int(types.InstrumentColDECAY):
-				cc1 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[1]))
-			case int(types.InstrumentColSUS):
-				cc2 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[2]))
-			case int(types.InstrumentColREL):
-				cc3 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[3]))
-			case int(types.InstrumentColRE):
-				cc4 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[4]))
-			case int(types.InstrumentColCO):
-				cc5 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[5]))
-			case int(types.InstrumentColPA):
-				cc6 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[6]))
-			case int(types.InstrumentColLP):
-				cc7 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[7]))
-			case int(types.InstrumentColHP):
-				cc8 = highlightStyle.Render(fmt.Sprintf("%02X", m.MidiCCNumbers[8]))
+		// Change ADSR and effect columns to show the CCs of the MIDI settings of the phrase,
+		// by name when they have one
+		ccs := phraseMidiCCs(m)
+		var labels [types.MidiCCColumns]string
+		for i, cc := range ccs {
+			// Highlight the selected column header if on header row
+			if m.CurrentRow == -1 && input.GetCCColumnIndex(m.CurrentCol) == i {
+				labels[i] = highlightStyle.Render(cc.Label())
+			} else {
+				labels[i] = headerStyle.Render(cc.Label())
 			}
 		}
+		cc0, cc1, cc2, cc3, cc4, cc5, cc6, cc7, cc8 := labels[0], labels[1], labels[2], labels[3], labels[4], labels[5], labels[6], labels[7], labels[8]
 
 		// the spacing is important here to keep alignment
 		adsrHeader = cc0 + cc1 + cc2 + cc3 + "  "
@@ -102,7 +69,10 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 	}
 
-	columnHeader := headerStyle.Render("  SL  DT  NOT  MO  CAT  VE  GT ") + adsrHeader + effectHeader + headerStyle.Render("  AR  ") + somiHeader + headerStyle.Render("  DU")
+	columnHeader := headerStyle.Render("  SL  DT  NOT  MO  CATV VE  GT ") + adsrHeader + effectHeader + headerStyle.Render("  AR  ") + somiHeader + headerStyle.Render("  DU  FX  FV")
+	if m.SOColumnMode == types.SOModeMIDI {
+		columnHeader += headerStyle.Render("  PB  AT")
+	}
 	phrasesData := m.GetCurrentPhrasesData()
 	totalTicks := ticks.CalculatePhraseTicks(phrasesData, m.CurrentPhrase)
 	phraseHeader := headerStyle.Render(fmt.Sprintf("Instrument %02X (%d ticks)", m.CurrentPhrase, totalTicks))
@@ -166,7 +136,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		noteValue := (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColNote]
 		noteText := "---"
 		if noteValue != -1 {
-			noteText = music.MidiToNoteName(noteValue)
+			noteText = phraseNoteName(m, noteValue)
 		}
 
 		var noteCell string
@@ -253,6 +223,23 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 			chordTransCell = normalStyle.Render(fmt.Sprintf("%1s", chordTransText))
 		}
 
+		// Chord Voicing (V) - display voicing
+		chordVoicingValue := (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColChordVoicing]
+		chordVoicingText := types.ChordVoicingToString(types.ChordVoicing(chordVoicingValue))
+
+		var chordVoicingCell string
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColV) { // Column 7 is the V column
+			chordVoicingCell = selectedStyle.Render(fmt.Sprintf("%1s", chordVoicingText))
+		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColV)) {
+				chordVoicingCell = copiedStyle.Render(fmt.Sprintf("%1s", chordVoicingText))
+			} else {
+				chordVoicingCell = normalStyle.Render(fmt.Sprintf("%1s", chordVoicingText))
+			}
+		} else {
+			chordVoicingCell = normalStyle.Render(fmt.Sprintf("%1s", chordVoicingText))
+		}
+
 		// Velocity (VE) - display velocity value (00-7F)
 		velocityValue := (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColVelocity]
 		velocityText := "--"
@@ -261,7 +248,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var velocityCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColVE) { // Column 7 is the VE column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColVE) { // Column 8 is the VE column
 			velocityCell = selectedStyle.Render(fmt.Sprintf("%2s", velocityText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColVE)) {
@@ -281,7 +268,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var gateCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColGT) { // Column 8 is the GT column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColGT) { // Column 9 is the GT column
 			gateCell = selectedStyle.Render(fmt.Sprintf("%2s", gateText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColGT)) {
@@ -306,7 +293,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var attackCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColATK) { // Column 9 is the A column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColATK) { // Column 10 is the A column
 			attackCell = selectedStyle.Render(fmt.Sprintf("%2s", attackText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColATK)) {
@@ -331,7 +318,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var decayCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColDECAY) { // Column 10 is the D column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColDECAY) { // Column 11 is the D column
 			decayCell = selectedStyle.Render(fmt.Sprintf("%2s", decayText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColDECAY)) {
@@ -356,7 +343,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var sustainCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColSUS) { // Column 11 is the S column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColSUS) { // Column 12 is the S column
 			sustainCell = selectedStyle.Render(fmt.Sprintf("%2s", sustainText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColSUS)) {
@@ -381,7 +368,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var releaseCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColREL) { // Column 12 is the R column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColREL) { // Column 13 is the R column
 			releaseCell = selectedStyle.Render(fmt.Sprintf("%2s", releaseText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColREL)) {
@@ -526,7 +513,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var arpeggioCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColAR) { // Column 18 is the AR column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColAR) { // Column 19 is the AR column
 			arpeggioCell = selectedStyle.Render(fmt.Sprintf("%2s", arpeggioText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColAR)) {
@@ -552,7 +539,7 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 		}
 
 		var somiCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColSOMI) { // Column 19 is the SO/MI column
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.InstrumentColSOMI) { // Column 20 is the SO/MI column
 			somiCell = selectedStyle.Render(fmt.Sprintf("%2s", somiText))
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
 			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.InstrumentColSOMI)) {
@@ -564,6 +551,12 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 			somiCell = normalStyle.Render(fmt.Sprintf("%2s", somiText))
 		}
 
+		// Rows with parameter locks are marked next to their SoundMaker
+		lockMark := " "
+		if m.SOColumnMode != types.SOModeMIDI && len(m.ParameterLocks.Row(m.CurrentPhrase, dataIndex)) > 0 {
+			lockMark = lockedStyle.Render("*")
+		}
+
 		// Ducking (DU) - display ducking index
 		duckingValue := (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectDucking]
 		duckingText := "--"
@@ -584,7 +577,36 @@ func RenderInstrumentPhraseView(m *model.Model) string {
 			duckingCell = normalStyle.Render(fmt.Sprintf("%2s", duckingText))
 		}
 
-		row := fmt.Sprintf("%s %-3s  %s  %s  %s  %s%s%s  %s  %s %s%s%s%s  %s  %s  %s  %s  %s  %s  %s  %s", arrow, sliceCell, dtCell, noteCell, modulateCell, chordCell, chordAddCell, chordTransCell, velocityCell, gateCell, attackCell, decayCell, sustainCell, releaseCell, reverbCell, combCell, panCell, lpCell, hpCell, arpeggioCell, somiCell, duckingCell)
+		row := fmt.Sprintf("%s %-3s  %s  %s  %s  %s%s%s%s %s  %s %s%s%s%s  %s  %s  %s  %s  %s  %s  %s%s %s", arrow, sliceCell, dtCell, noteCell, modulateCell, chordCell, chordAddCell, chordTransCell, chordVoicingCell, velocityCell, gateCell, attackCell, decayCell, sustainCell, releaseCell, reverbCell, combCell, panCell, lpCell, hpCell, arpeggioCell, somiCell, lockMark, duckingCell)
+
+		// Insert FX (FX) and its value (FV), then pitch bend (PB) and aftertouch (AT) - MIDI only
+		columns := []struct {
+			data types.PhraseColumn
+			ui   types.InstrumentUIColumn
+		}{
+			{types.ColInsertFX, types.InstrumentColFX}, {types.ColInsertFXValue, types.InstrumentColFV},
+			{types.ColPitchBend, types.InstrumentColPB}, {types.ColAftertouch, types.InstrumentColAT},
+		}
+		if m.SOColumnMode != types.SOModeMIDI {
+			columns = columns[:2]
+		}
+		for _, column := range columns {
+			value := (*phrasesData)[m.CurrentPhrase][dataIndex][column.data]
+			text := "--"
+			if value != -1 {
+				text = fmt.Sprintf("%02X", value)
+			}
+			var cell string
+			if m.CurrentRow == dataIndex && m.CurrentCol == int(column.ui) {
+				cell = selectedStyle.Render(text)
+			} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex &&
+				(m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(column.ui))) {
+				cell = copiedStyle.Render(text)
+			} else {
+				cell = normalStyle.Render(text)
+			}
+			row += "  " + cell
+		}
 		content.WriteString(row)
 		content.WriteString("\n")
 	}
@@ -641,8 +663,10 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 				ccIndex = 8
 				ccName = "HighPass/CC8"
 			}
-			if ccIndex != -1 {
-				statusMsg = fmt.Sprintf("%s CC#: %d | Ctrl+Up: +16, Ctrl+Right: +1, Ctrl+Down: -16, Ctrl+Left: -1", ccName, m.MidiCCNumbers[ccIndex])
+			if ccIndex != -1 && input.CCMidiSettingsIndex(m) == -1 {
+				statusMsg = fmt.Sprintf("%s: set MI on a row to map its CCs", ccName)
+			} else if ccIndex != -1 {
+				statusMsg = fmt.Sprintf("%s: MIDI %02X sends %s | Ctrl+Up: +16, Ctrl+Right: +1, Ctrl+Down: -16, Ctrl+Left: -1", ccName, input.CCMidiSettingsIndex(m), phraseMidiCCs(m)[ccIndex])
 			} else {
 				statusMsg = "Header row"
 			}
@@ -659,15 +683,17 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 	if columnMapping != nil && (columnMapping.DataColumnIndex == int(types.ColNote) ||
 		columnMapping.DataColumnIndex == int(types.ColChord) ||
 		columnMapping.DataColumnIndex == int(types.ColChordAddition) ||
-		columnMapping.DataColumnIndex == int(types.ColChordTransposition)) { // NOT, C, A, or T columns
+		columnMapping.DataColumnIndex == int(types.ColChordTransposition) ||
+		columnMapping.DataColumnIndex == int(types.ColChordVoicing)) { // NOT, C, A, T or V columns
 		// Get current row data
 		noteValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColNote]
 		chordValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColChord]
 		chordAddValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColChordAddition]
 		chordTransValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColChordTransposition]
+		chordVoicingValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColChordVoicing]
 
 		if noteValue >= 0 && noteValue <= 127 {
-			noteName := music.MidiToNoteName(noteValue)
+			noteName := phraseNoteName(m, noteValue)
 
 			// Check if chord is defined (not null/"-")
 			if chordValue > int(types.ChordNone) {
@@ -685,6 +711,22 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 					chordName = rootNote + "min"
 				case types.ChordDominant:
 					chordName = rootNote // Dominant chords have no suffix
+				case types.ChordSus2:
+					chordName = rootNote + "sus2"
+				case types.ChordSus4:
+					chordName = rootNote + "sus4"
+				case types.ChordDiminished:
+					chordName = rootNote + "dim"
+				case types.ChordAugmented:
+					chordName = rootNote + "aug"
+				case types.ChordMajor7:
+					chordName = rootNote + "maj7"
+				case types.ChordHalfDiminished:
+					chordName = rootNote + "m7b5"
+				case types.ChordSixth:
+					chordName = rootNote + "6"
+				case types.ChordAddNine:
+					chordName = rootNote + "add9"
 				default:
 					chordName = rootNote
 				}
@@ -704,10 +746,14 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 				// Add transposition if defined and not 0
 				if chordTransValue > int(types.ChordTrans0) {
 					transpositionStr := types.ChordTranspositionToString(types.ChordTransposition(chordTransValue))
-					statusMsg = fmt.Sprintf("Chord: %s (octave %d, transpose %s)", chordName, octave, transpositionStr)
+					statusMsg = fmt.Sprintf("Chord: %s (octave %d, transpose %s", chordName, octave, transpositionStr)
 				} else {
-					statusMsg = fmt.Sprintf("Chord: %s (octave %d)", chordName, octave)
+					statusMsg = fmt.Sprintf("Chord: %s (octave %d", chordName, octave)
 				}
+				if voicing := types.ChordVoicing(chordVoicingValue); voicing > types.ChordVoicingClose && voicing < types.ChordVoicingCount {
+					statusMsg += ", " + types.ChordVoicingName(voicing)
+				}
+				statusMsg += ")"
 			} else {
 				// Chord is null, show simple note info with transposition if defined and not 0
 				if chordTransValue > int(types.ChordTrans0) {
@@ -717,6 +763,7 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 					statusMsg = fmt.Sprintf("Note: %s", noteName)
 				}
 			}
+			statusMsg += tunedNoteInfo(m, noteValue)
 		} else {
 			statusMsg = "No note selected"
 		}
@@ -996,12 +1043,12 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 		// Show MIDI CC info with controller number and decimal value
 		ccIndex := columnMapping.DataColumnIndex - int(types.ColMidiCC0)
 		ccValue := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.PhraseColumn(columnMapping.DataColumnIndex)]
-		ccNumber := m.MidiCCNumbers[ccIndex]
+		cc := phraseMidiCCs(m)[ccIndex]
 
 		if ccValue == -1 {
-			statusMsg = fmt.Sprintf("CC%d: -- (Controller=%d)", ccIndex, ccNumber)
+			statusMsg = fmt.Sprintf("CC%d: -- (%s)", ccIndex, cc)
 		} else {
-			statusMsg = fmt.Sprintf("CC%d: %02X (Controller=%d, Value=%d)", ccIndex, ccValue, ccNumber, ccValue)
+			statusMsg = fmt.Sprintf("CC%d: %02X (%s, Value=%d)", ccIndex, ccValue, cc, ccValue)
 		}
 	} else {
 		// On other columns - show basic info
@@ -1023,12 +1070,67 @@ func GetInstrumentPhraseStatusMessage(m *model.Model) string {
 		if m.SOColumnMode == types.SOModeMIDI {
 			statusMsg += " | Shift+Right: MIDI Settings | Ctrl+Down/Left: Switch to SO | Shift+Left: Back to chain view"
 		} else {
-			statusMsg += " | Shift+Right: SoundMaker Settings | Ctrl+Up/Right: Switch to MI | Shift+Left: Back to chain view"
+			statusMsg += " | Shift+Right: SoundMaker Settings | O: Parameter locks | Ctrl+Up/Right: Switch to MI | Shift+Left: Back to chain view"
 		}
 	} else if m.CurrentCol == int(types.InstrumentColDU) {
 		statusMsg += " | Shift+Right: Ducking | Shift+Left: Back to chain view"
+	} else if m.CurrentCol == int(types.InstrumentColFX) || m.CurrentCol == int(types.InstrumentColFV) {
+		statusMsg += " | " + insertFXStatus(m) + " | Shift+Right: Insert FX | Shift+Left: Back to chain view"
+	} else if m.CurrentCol == int(types.InstrumentColPB) {
+		statusMsg += " | " + pitchBendStatus(m) + " | Shift+Left: Back to chain view"
+	} else if m.CurrentCol == int(types.InstrumentColAT) {
+		statusMsg += " | AT: Channel aftertouch 00-7F, sent after the notes | Shift+Left: Back to chain view"
 	} else {
 		statusMsg += " | Shift+Left: Back to chain view"
 	}
 	return statusMsg
 }
+
+// phraseMidiCCs returns the CCs the CC columns of the phrase view send, the defaults when
+// the phrase plays no MIDI settings
+func phraseMidiCCs(m *model.Model) [types.MidiCCColumns]types.MidiCC {
+	if index := input.CCMidiSettingsIndex(m); index >= 0 && index < 255 {
+		return m.MidiSettings[index].CCs
+	}
+	return types.DefaultMidiCCs()
+}
+
+// pitchBendStatus describes the pitch bend under the cursor in semitones, using the bend
+// range of the MIDI settings the row plays
+func pitchBendStatus(m *model.Model) string {
+	phrasesData := m.GetCurrentPhrasesData()
+	value := (*phrasesData)[m.CurrentPhrase][m.CurrentRow][types.ColPitchBend]
+	if value == -1 {
+		return "PB: Pitch bend 00-FE, 80 = center"
+	}
+	bendRange := types.DefaultPitchBendRange
+	if midi := input.GetEffectiveValueForTrack(m, m.CurrentPhrase, m.CurrentRow, int(types.ColMidi), m.CurrentTrack); midi >= 0 && midi < 255 {
+		bendRange = m.MidiSettings[midi].PitchBendRange()
+	}
+	return fmt.Sprintf("PB: %+.2f semitones (range ±%d)", types.PitchBendToSemitones(value, bendRange), bendRange)
+}
+
+// phraseNoteName names a note of the phrase view: as a degree of the tuning of the track
+// when scale degrees are shown, otherwise as a 12-TET note
+func phraseNoteName(m *model.Model, note int) string {
+	if t := m.TuningForTrack(m.CurrentTrack); t != nil && m.ShowScaleDegrees {
+		return t.DegreeName(note)
+	}
+	return music.MidiToNoteName(note)
+}
+
+// tunedNoteInfo describes where the tuning of the track puts a note, for the status line
+func tunedNoteInfo(m *model.Model, note int) string {
+	t := m.TuningForTrack(m.CurrentTrack)
+	if t == nil {
+		return ""
+	}
+	degree, ok := t.Degree(note)
+	if !ok {
+		return fmt.Sprintf(" | %s: key does not play", t.Name)
+	}
+	tunedNote, _ := t.Note(float32(note))
+	n := t.Size()
+	frequency := 440 * math.Pow(2, (float64(tunedNote)-69)/12)
+	return fmt.Sprintf(" | %s: degree %d of %d, %.2f Hz", t.Name, ((degree%n)+n)%n, n, frequency)
+}
@@ -0,0 +1,127 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// lfoColumns are the headers and widths of the LFO columns in LFOUIColumn order
+var lfoColumns = [types.LFOColCount]struct {
+	label string
+	width int
+}{
+	{"WA", 5}, {"RATE", 5}, {"SYNC", 4}, {"DEP", 4}, {"PH", 3}, {"RT", 2}, {"TR", 2}, {"TARGET", 22},
+}
+
+// lfoCellText returns the text of a column of an LFO
+func lfoCellText(settings types.LFOSettings, col types.LFOUIColumn) string {
+	switch col {
+	case types.LFOColWaveform:
+		return types.LFOWaveformName(types.LFOWaveform(settings.Waveform))
+	case types.LFOColRate:
+		if settings.Sync > 0 {
+			return "--"
+		}
+		return settings.RateName()
+	case types.LFOColSync:
+		if settings.Sync == 0 {
+			return "--"
+		}
+		return settings.RateName()
+	case types.LFOColDepth:
+		return fmt.Sprintf("%d%%", settings.Depth)
+	case types.LFOColPhase:
+		return fmt.Sprintf("%d", settings.Phase)
+	case types.LFOColRetrigger:
+		if settings.Retrigger {
+			return "on"
+		}
+		return "--"
+	case types.LFOColTrack:
+		return fmt.Sprintf("T%d", settings.Track+1)
+	case types.LFOColTarget:
+		return types.LFOTargetName(settings.Target)
+	}
+	return ""
+}
+
+func GetLFOStatusMessage(m *model.Model) string {
+	settings := m.LFOSettings[m.CurrentRow]
+	var columnStatus string
+	switch types.LFOUIColumn(m.CurrentCol) {
+	case types.LFOColWaveform:
+		columnStatus = "Waveform " + lfoCellText(settings, types.LFOColWaveform)
+	case types.LFOColRate:
+		columnStatus = fmt.Sprintf("Rate %.2f Hz", settings.RateHz(m.BPM))
+	case types.LFOColSync:
+		if settings.Sync == 0 {
+			columnStatus = "Sync -- (free rate)"
+		} else {
+			columnStatus = fmt.Sprintf("Sync %s per cycle (%.2f Hz at %.0f BPM)", settings.RateName(), settings.RateHz(m.BPM), m.BPM)
+		}
+	case types.LFOColDepth:
+		columnStatus = fmt.Sprintf("Depth %d%%", settings.Depth)
+		if target, ok := types.FindLFOTarget(settings.Target); ok {
+			span := float32(settings.Depth) / 100 * target.Span
+			if target.Exponential {
+				columnStatus += fmt.Sprintf(" (±%.2f octaves)", span)
+			} else {
+				columnStatus += fmt.Sprintf(" (±%.2f)", span)
+			}
+		}
+	case types.LFOColPhase:
+		columnStatus = fmt.Sprintf("Start phase %d°", settings.Phase)
+	case types.LFOColRetrigger:
+		if settings.Retrigger {
+			columnStatus = "Retrigger on: restarts at its phase on every note of the track"
+		} else {
+			columnStatus = "Retrigger off: runs freely"
+		}
+	case types.LFOColTrack:
+		columnStatus = fmt.Sprintf("Moves track %d", settings.Track+1)
+	case types.LFOColTarget:
+		if settings.Target == "" {
+			columnStatus = "Target -- (off)"
+		} else {
+			columnStatus = "Target " + types.LFOTargetName(settings.Target) + ", around the value of each row"
+		}
+	}
+
+	baseMsg := fmt.Sprintf("Arrows: Navigate | %s+Arrow: Adjust | Esc: Off | Shift+Left: Back to Mixer", input.GetModifierKey())
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderLFOView(m *model.Model) string {
+	statusMsg := GetLFOStatusMessage(m)
+	return renderViewWithCommonPattern(m, "LFOs", fmt.Sprintf("LFO %X", m.CurrentRow), func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		headerRow := "   "
+		for _, column := range lfoColumns {
+			headerRow += " " + styles.Label.Render(fmt.Sprintf("%-*s", column.width, column.label))
+		}
+		content.WriteString(headerRow)
+		content.WriteString("\n")
+
+		for row, settings := range m.LFOSettings {
+			rowData := fmt.Sprintf("  %s", styles.Label.Render(fmt.Sprintf("%X", row)))
+			for col, column := range lfoColumns {
+				cellText := fmt.Sprintf("%-*s", column.width, lfoCellText(settings, types.LFOUIColumn(col)))
+				if m.CurrentRow == row && m.CurrentCol == col {
+					rowData += " " + styles.Selected.Render(cellText)
+				} else {
+					rowData += " " + styles.Normal.Render(cellText)
+				}
+			}
+			content.WriteString(rowData)
+			content.WriteString("\n")
+		}
+
+		return content.String()
+	}, statusMsg, types.LFOCount+2) // 16 LFOs + 1 header + 1 spacing
+}
@@ -0,0 +1,106 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/library"
+	"github.com/schollz/collidertracker/internal/model"
+)
+
+// libraryNameWidth is the width of the file name column
+const libraryNameWidth = 32
+
+// formatLibraryEntry returns the columns of one library row
+func formatLibraryEntry(e library.Entry) string {
+	name := e.Name()
+	if len(name) > libraryNameWidth {
+		name = name[:libraryNameWidth-1] + "~"
+	}
+
+	bpm := "  --"
+	if e.BPM > 0 {
+		bpm = fmt.Sprintf("%4.0f", e.BPM)
+	}
+	length := "    --"
+	if e.Duration > 0 {
+		length = fmt.Sprintf("%5.2fs", e.Duration)
+	}
+	channels := "--"
+	if e.Channels > 0 {
+		channels = fmt.Sprintf("%2d", e.Channels)
+	}
+	kind := "one"
+	if e.IsLoop() {
+		kind = "lp "
+	}
+
+	tags := ""
+	if len(e.Tags) > 0 {
+		tags = "#" + strings.Join(e.Tags, " #")
+	}
+	return fmt.Sprintf("%-*s %s %s %s %s %s", libraryNameWidth, name, kind, bpm, length, channels, tags)
+}
+
+func GetLibraryStatusMessage(m *model.Model) string {
+	if m.LibraryTagEditing {
+		return "Type tags separated by spaces | Enter: Save tags | Esc: Cancel"
+	}
+	if m.Library == nil || len(m.Library.Roots) == 0 {
+		return fmt.Sprintf("No library folders | %s+A: Add %s | Shift+Left: Back to file browser", input.GetModifierKey(), m.CurrentDir)
+	}
+
+	status := ""
+	if entry := input.SelectedLibraryEntry(m); entry != nil {
+		status = entry.Path + " | "
+	}
+	mod := input.GetModifierKey()
+	return status + fmt.Sprintf("Enter: Select | %s+Right: Play/Stop | %s+T: Tags | %s+A: Add/remove folder | %s+U: Rescan | Shift+Left: Back", mod, mod, mod, mod)
+}
+
+func RenderLibraryView(m *model.Model) string {
+	statusMsg := GetLibraryStatusMessage(m)
+
+	rightHeader := ""
+	if m.Library != nil {
+		rightHeader = fmt.Sprintf("%d/%d files", len(m.LibraryResults), len(m.Library.Entries))
+		if m.LibraryScanning {
+			rightHeader = "Scanning... " + rightHeader
+		}
+	}
+
+	visibleRows := input.LibraryVisibleRows(m)
+	return renderViewWithCommonPattern(m, "Sample Library", rightHeader, func(styles *ViewStyles) string {
+		var content strings.Builder
+
+		// Search box (or tag editor for the selected file)
+		if m.LibraryTagEditing {
+			content.WriteString(fmt.Sprintf("  %s %s\n", styles.Label.Render("Tags:"), styles.Selected.Render(m.LibraryTagInput+"_")))
+		} else if m.LibraryQuery == "" {
+			content.WriteString(fmt.Sprintf("  %s %s\n", styles.Label.Render("Search:"), styles.Label.Render("type to search, e.g. kick #dark is:loop bpm:160-180 dur:<1")))
+		} else {
+			content.WriteString(fmt.Sprintf("  %s %s\n", styles.Label.Render("Search:"), styles.Normal.Render(m.LibraryQuery+"_")))
+		}
+
+		header := fmt.Sprintf("%-*s %s %s %s %s %s", libraryNameWidth, "File", "Typ", " BPM", "Length", "Ch", "Tags")
+		content.WriteString("  " + styles.Label.Render(header) + "\n")
+
+		for i := 0; i < visibleRows && i+m.ScrollOffset < len(m.LibraryResults); i++ {
+			row := i + m.ScrollOffset
+			entry := m.Library.Entries[m.LibraryResults[row]]
+
+			arrow := " "
+			text := formatLibraryEntry(entry)
+			if m.CurrentRow == row {
+				arrow = "▶"
+				text = styles.Selected.Render(text)
+			} else {
+				text = styles.Normal.Render(text)
+			}
+			content.WriteString(fmt.Sprintf("%s %s\n", arrow, text))
+		}
+
+		return content.String()
+	}, statusMsg, visibleRows+2) // results + search line + header
+}
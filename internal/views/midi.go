@@ -3,8 +3,10 @@ package views
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/midiplayer"
 	"github.com/schollz/collidertracker/internal/model"
 	"github.com/schollz/collidertracker/internal/types"
 )
@@ -18,10 +20,27 @@ func GetMidiStatusMessage(m *model.Model) string {
 		columnStatus = fmt.Sprintf("MIDI Device: %s", settings.Device)
 	case types.MidiSettingsRowChannel: // MIDI Channel row
 		columnStatus = fmt.Sprintf("MIDI Channel: %s", settings.Channel)
+	case types.MidiSettingsRowProgram:
+		columnStatus = fmt.Sprintf("Program change: %s (sent before the first note and when it changes)", formatMidiProgram(settings.Program))
+	case types.MidiSettingsRowBankMSB:
+		columnStatus = fmt.Sprintf("Bank select MSB (CC 0): %s", formatMidiPatchValue(settings.BankMSB))
+	case types.MidiSettingsRowBankLSB:
+		columnStatus = fmt.Sprintf("Bank select LSB (CC 32): %s", formatMidiPatchValue(settings.BankLSB))
+	case types.MidiSettingsRowBendRange:
+		columnStatus = fmt.Sprintf("Pitch bend range: ±%d semitones (%s sends it as RPN 0)", settings.PitchBendRange(), formatMidiPatchValue(settings.BendRange))
+	case types.MidiSettingsRowLatency:
+		columnStatus = fmt.Sprintf("Latency of %s: %s (MIDI waits this long to line up with the audio) | %s", settings.Device, formatMidiLatency(m, settings.Device), heldNotesStatus())
+	case types.MidiSettingsRowProfile:
+		columnStatus = fmt.Sprintf("Profile: %s (sets the CCs of the columns)", formatMidiProfile(settings.Profile))
 	default:
+		if column := m.CurrentRow - int(types.MidiSettingsRowCC0); column >= 0 && column < types.MidiCCColumns {
+			columnStatus = fmt.Sprintf("%s column sends %s", midiCCColumnNames[column], settings.CCs[column])
+			break
+		}
 		// Device selection rows
-		if m.CurrentRow >= 2 && m.CurrentRow-2+m.ScrollOffset < len(m.AvailableMidiDevices) {
-			deviceIndex := m.CurrentRow - 2 + m.ScrollOffset
+		firstDevice := int(types.MidiSettingsRowFirstDevice)
+		if m.CurrentRow >= firstDevice && m.CurrentRow-firstDevice < len(m.AvailableMidiDevices) {
+			deviceIndex := m.CurrentRow - firstDevice
 			columnStatus = fmt.Sprintf("Select Device: %s", m.AvailableMidiDevices[deviceIndex])
 		} else {
 			columnStatus = "Available MIDI Devices"
@@ -42,13 +61,23 @@ func RenderMidiView(m *model.Model) string {
 		settings := m.MidiSettings[m.MidiEditingIndex]
 
 		// Settings rows with common rendering pattern
-		settingsRows := []struct {
+		type settingsRow struct {
 			label string
 			value string
 			row   int
-		}{
+		}
+		settingsRows := []settingsRow{
 			{"Device:", settings.Device, int(types.MidiSettingsRowDevice)},
 			{"Channel:", settings.Channel, int(types.MidiSettingsRowChannel)},
+			{"Program:", formatMidiProgram(settings.Program), int(types.MidiSettingsRowProgram)},
+			{"Bank MSB:", formatMidiPatchValue(settings.BankMSB), int(types.MidiSettingsRowBankMSB)},
+			{"Bank LSB:", formatMidiPatchValue(settings.BankLSB), int(types.MidiSettingsRowBankLSB)},
+			{"Bend:", formatMidiPatchValue(settings.BendRange), int(types.MidiSettingsRowBendRange)},
+			{"Latency:", formatMidiLatency(m, settings.Device), int(types.MidiSettingsRowLatency)},
+			{"Profile:", formatMidiProfile(settings.Profile), int(types.MidiSettingsRowProfile)},
+		}
+		for column, cc := range settings.CCs {
+			settingsRows = append(settingsRows, settingsRow{"CC " + midiCCColumnNames[column] + ":", strings.TrimSpace(fmt.Sprintf("%d %s", cc.Number, cc.Name)), int(types.MidiSettingsRowCC0) + column})
 		}
 
 		for _, setting := range settingsRows {
@@ -58,7 +87,7 @@ func RenderMidiView(m *model.Model) string {
 			} else {
 				valueCell = styles.Normal.Render(setting.value)
 			}
-			row := fmt.Sprintf("  %-8s %s", styles.Label.Render(setting.label), valueCell)
+			row := fmt.Sprintf("  %-9s %s", styles.Label.Render(setting.label), valueCell)
 			content.WriteString(row)
 			content.WriteString("\n")
 		}
@@ -69,12 +98,15 @@ func RenderMidiView(m *model.Model) string {
 		content.WriteString("\n\n")
 
 		// Available MIDI devices list (scrollable)
-		visibleRows := m.GetVisibleRows() - 6 // Reserve space for header, settings, and labels
-		deviceStartRow := 2                   // Devices start at row 2 (after Device and Channel settings)
+		visibleRows := max(1, m.GetVisibleRows()-21)            // Reserve space for header, settings, and labels
+		deviceStartRow := int(types.MidiSettingsRowFirstDevice) // Devices start after the settings rows
 
-		for i := 0; i < visibleRows && i+m.ScrollOffset < len(m.AvailableMidiDevices); i++ {
-			dataIndex := i + m.ScrollOffset
-			deviceRow := deviceStartRow + i
+		// Scroll the list to keep the selected device visible
+		scrollOffset := max(0, m.CurrentRow-deviceStartRow-visibleRows+1)
+
+		for i := 0; i < visibleRows && i+scrollOffset < len(m.AvailableMidiDevices); i++ {
+			dataIndex := i + scrollOffset
+			deviceRow := deviceStartRow + dataIndex
 
 			// Arrow for current selection
 			arrow := " "
@@ -99,3 +131,51 @@ func RenderMidiView(m *model.Model) string {
 		return content.String()
 	}, statusMsg, m.GetVisibleRows()) // Use dynamic visible rows
 }
+
+// midiCCColumnNames names the CC columns of the instrument view in MI mode
+var midiCCColumnNames = [types.MidiCCColumns]string{"A", "D", "S", "R", "RE", "CO", "PA", "LP", "HP"}
+
+// formatMidiProfile formats the device profile of MIDI settings, "--" when they have none
+func formatMidiProfile(profile string) string {
+	if profile == "" {
+		return "--"
+	}
+	return profile
+}
+
+// formatMidiLatency formats the latency of a MIDI device, "--" without a device
+func formatMidiLatency(m *model.Model, device string) string {
+	if device == "None" || device == "" {
+		return "--"
+	}
+	return fmt.Sprintf("%d ms", m.MidiLatencies[device])
+}
+
+// heldNotesStatus counts the notes that are on at the MIDI devices, to spot hanging notes
+func heldNotesStatus() string {
+	notes := midiplayer.HeldNotes()
+	now := time.Now()
+	hanging := 0
+	for _, note := range notes {
+		if note.Hanging(now) {
+			hanging++
+		}
+	}
+	return fmt.Sprintf("Notes on: %d, hanging: %d", len(notes), hanging)
+}
+
+// formatMidiPatchValue formats a bank or bend range setting, "--" when it is left to the synth
+func formatMidiPatchValue(value int) string {
+	if value < 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+// formatMidiProgram formats a program the way synths number them, 1-128
+func formatMidiProgram(program int) string {
+	if program < 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%d", program+1)
+}
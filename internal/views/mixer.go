@@ -9,8 +9,12 @@ import (
 	"github.com/muesli/termenv"
 	"github.com/schollz/collidertracker/internal/input"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
 )
 
+// mixerSendLabels label the send rows of the mixer, one per return bus
+var mixerSendLabels = [types.ReturnCount]string{"RV", "DL"}
+
 // getUnicodeBlock returns the appropriate Unicode block character for a fill ratio (0-1)
 func getUnicodeBlock(fillRatio float64) string {
 	if fillRatio <= 0 {
@@ -149,9 +153,16 @@ func getMixerStatusMessage(m *model.Model) string {
 		trackLabel = fmt.Sprintf("Track %d", track+1)
 	}
 
+	if ret := m.CurrentMixerRow - types.MixerRowSend; ret >= 0 && ret < types.ReturnCount && track < 8 {
+		name := types.ReturnModules[ret].Name
+		statusMsg := fmt.Sprintf("%s: %s send %.0f%%", trackLabel, name, m.TrackSends[track][ret])
+		statusMsg += fmt.Sprintf(" | Up/Down: Row │ %s+Arrow: Adjust │ Shift+Right: %s return │ Shift+Up: Back", input.GetModifierKey(), name)
+		return statusMsg
+	}
+
 	statusMsg := fmt.Sprintf("%s: Set %.1fdB (Hex %02X)",
 		trackLabel, setLevel, dbToHex(setLevel))
-	statusMsg += fmt.Sprintf(" | Left/Right: Select │ %s+Arrow: Adjust │ Shift+Up: Back", input.GetModifierKey())
+	statusMsg += fmt.Sprintf(" | Left/Right: Select │ %s+Arrow: Adjust │ Shift+Right: LFOs │ Shift+Up: Back", input.GetModifierKey())
 
 	return statusMsg
 }
@@ -248,8 +259,24 @@ func RenderMixerView(m *model.Model) string {
 		} else {
 			content.WriteString(styles.Label.Render(inputSetHex))
 		}
-		content.WriteString("\n\n")
+		content.WriteString("\n")
+
+		// Send rows (hex codes of the percentage), the Input track has none
+		for ret := 0; ret < types.ReturnCount; ret++ {
+			content.WriteString(styles.Label.Render(fmt.Sprintf("  %s", mixerSendLabels[ret])))
+			for track := 0; track < 8; track++ {
+				content.WriteString("  ")
+				sendHex := fmt.Sprintf("%02X", int(m.TrackSends[track][ret]))
+				if track == m.CurrentMixerTrack && m.CurrentMixerRow == types.MixerRowSend+ret {
+					content.WriteString(styles.Selected.Render(sendHex))
+				} else {
+					content.WriteString(styles.Label.Render(sendHex))
+				}
+			}
+			content.WriteString("  " + styles.Label.Render("--") + "\n")
+		}
+		content.WriteString("\n")
 
 		return content.String()
-	}, getMixerStatusMessage(m), 14)
+	}, getMixerStatusMessage(m), 14+types.ReturnCount)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/schollz/collidertracker/internal/input"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/modulation"
 )
 
 func RenderModulateView(m *model.Model) string {
@@ -139,6 +140,8 @@ func RenderModulateView(m *model.Model) string {
 	scaleValue := settings.Scale
 	if scaleValue == "" {
 		scaleValue = "all"
+	} else if scaleValue == modulation.ProjectScale {
+		scaleValue = fmt.Sprintf("%s (%s %s)", scaleValue, modulation.NoteNames[m.ProjectKey], m.ProjectScaleName())
 	}
 	var scaleCell string
 	if m.CurrentRow == 7 {
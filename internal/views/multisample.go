@@ -0,0 +1,141 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/music"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// multisampleVisibleZones is the number of zone rows shown at once
+const multisampleVisibleZones = 16
+
+// multisampleFileWidth is the width of the file column
+const multisampleFileWidth = 20
+
+// formatMultisampleZoneCell returns the display text of a zone field
+func formatMultisampleZoneCell(zone types.MultisampleZone, col types.MultisampleZoneColumn) string {
+	switch col {
+	case types.MultisampleColFile:
+		name := filepath.Base(zone.File)
+		if len(name) > multisampleFileWidth {
+			name = name[:multisampleFileWidth-1] + "~"
+		}
+		return fmt.Sprintf("%-*s", multisampleFileWidth, name)
+	case types.MultisampleColLowNote:
+		return music.MidiToNoteName(zone.LowNote)
+	case types.MultisampleColHighNote:
+		return music.MidiToNoteName(zone.HighNote)
+	case types.MultisampleColRootNote:
+		return music.MidiToNoteName(zone.RootNote)
+	case types.MultisampleColLowVelocity:
+		return fmt.Sprintf("%02X", zone.LowVelocity)
+	case types.MultisampleColHighVelocity:
+		return fmt.Sprintf("%02X", zone.HighVelocity)
+	case types.MultisampleColFineTune:
+		return fmt.Sprintf("%+04d", zone.FineTune)
+	case types.MultisampleColRoundRobin:
+		if zone.RoundRobin == 0 {
+			return "--"
+		}
+		return fmt.Sprintf("%02d", zone.RoundRobin)
+	}
+	return ""
+}
+
+func GetMultisampleStatusMessage(m *model.Model) string {
+	settings := m.SoundMakerSettings[m.SoundMakerEditingIndex]
+
+	var columnStatus string
+	if m.CurrentRow >= len(settings.Zones) {
+		columnStatus = "Shift+Right: Add zone from file browser"
+	} else {
+		zone := settings.Zones[m.CurrentRow]
+		switch types.MultisampleZoneColumn(m.CurrentCol) {
+		case types.MultisampleColFile:
+			columnStatus = fmt.Sprintf("File: %s | Shift+Right: Change file", zone.File)
+		case types.MultisampleColLowNote:
+			columnStatus = fmt.Sprintf("Low note: %s", music.MidiToNoteName(zone.LowNote))
+		case types.MultisampleColHighNote:
+			columnStatus = fmt.Sprintf("High note: %s", music.MidiToNoteName(zone.HighNote))
+		case types.MultisampleColRootNote:
+			columnStatus = fmt.Sprintf("Root note: %s", music.MidiToNoteName(zone.RootNote))
+		case types.MultisampleColLowVelocity:
+			columnStatus = fmt.Sprintf("Low velocity: %d", zone.LowVelocity)
+		case types.MultisampleColHighVelocity:
+			columnStatus = fmt.Sprintf("High velocity: %d", zone.HighVelocity)
+		case types.MultisampleColFineTune:
+			columnStatus = fmt.Sprintf("Fine tune: %+d cents", zone.FineTune)
+		case types.MultisampleColRoundRobin:
+			if zone.RoundRobin == 0 {
+				columnStatus = "Round-robin: -- (always plays)"
+			} else {
+				columnStatus = fmt.Sprintf("Round-robin group: %d", zone.RoundRobin)
+			}
+		}
+	}
+
+	baseMsg := fmt.Sprintf("%s+Arrow: Adjust values | Backspace: Remove zone | Shift+Left: Back to SoundMaker", input.GetModifierKey())
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderMultisampleView(m *model.Model) string {
+	statusMsg := GetMultisampleStatusMessage(m)
+	return renderViewWithCommonPattern(m, "Multisample Zones", fmt.Sprintf("SoundMaker %02X", m.SoundMakerEditingIndex), func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		// Header row
+		headers := []string{"Lo", "Hi", "Rt", "VL", "VH", "Tune", "RR"}
+		headerRow := "     " + styles.Label.Render(fmt.Sprintf("%-*s", multisampleFileWidth, "File"))
+		for _, header := range headers {
+			headerRow += " " + styles.Label.Render(fmt.Sprintf("%-4s", header))
+		}
+		content.WriteString(headerRow)
+		content.WriteString("\n")
+
+		settings := m.SoundMakerSettings[m.SoundMakerEditingIndex]
+		startRow := m.ScrollOffset
+		endRow := startRow + multisampleVisibleZones
+		if endRow > len(settings.Zones)+1 {
+			endRow = len(settings.Zones) + 1
+		}
+
+		for row := startRow; row < endRow; row++ {
+			rowLabel := styles.Label.Render(fmt.Sprintf("%02X", row))
+
+			// The row after the last zone adds a new zone
+			if row == len(settings.Zones) {
+				addText := fmt.Sprintf("%-*s", multisampleFileWidth, "(add zone)")
+				if m.CurrentRow == row {
+					content.WriteString(fmt.Sprintf("  %s %s\n", rowLabel, styles.Selected.Render(addText)))
+				} else {
+					content.WriteString(fmt.Sprintf("  %s %s\n", rowLabel, styles.Label.Render(addText)))
+				}
+				continue
+			}
+
+			zone := settings.Zones[row]
+			rowData := "  " + rowLabel
+			for col := types.MultisampleColFile; col < types.MultisampleColCount; col++ {
+				text := formatMultisampleZoneCell(zone, col)
+				var cell string
+				if m.CurrentRow == row && m.CurrentCol == int(col) {
+					cell = styles.Selected.Render(text)
+				} else {
+					cell = styles.Normal.Render(text)
+				}
+				// Pad outside the style so the highlight only covers the value
+				rowData += " " + cell + strings.Repeat(" ", max(0, 4-len(text)))
+			}
+			content.WriteString(rowData)
+			content.WriteString("\n")
+		}
+
+		return content.String()
+	}, statusMsg, multisampleVisibleZones+2) // zone rows + 1 header + 1 spacing
+}
@@ -0,0 +1,79 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// parameterLockValueWidth is the width of the slot and lock columns
+const parameterLockValueWidth = 14
+
+func GetParameterLockStatusMessage(m *model.Model) string {
+	var columnStatus string
+	index := input.ParameterLockSoundMaker(m)
+	def, ok := input.ParameterLockDefinition(m)
+	switch {
+	case index == -1:
+		columnStatus = "No SoundMaker plays on this row"
+	case !ok:
+		columnStatus = "Unknown SoundMaker"
+	case m.CurrentRow >= 0 && m.CurrentRow < len(def.Parameters):
+		param := def.Parameters[m.CurrentRow]
+		settings := m.SoundMakerSettings[index]
+		slot := formatSoundMakerValue(settings.Name, param, settings.GetParameterValue(param.Key))
+		if value, locked := m.ParameterLocks.Get(m.CurrentPhrase, m.ParameterLockRow, param.Key); locked {
+			columnStatus = fmt.Sprintf("%s: locked to %s (slot %s) | Backspace: Unlock", param.DisplayName,
+				formatSoundMakerValue(settings.Name, param, value), slot)
+		} else {
+			columnStatus = fmt.Sprintf("%s: %s from the slot", param.DisplayName, slot)
+		}
+	}
+
+	baseMsg := fmt.Sprintf("Up/Down: Navigate | %s+Arrow: Lock and adjust | Shift+Left: Back to Phrase view", input.GetModifierKey())
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderParameterLockView(m *model.Model) string {
+	statusMsg := GetParameterLockStatusMessage(m)
+	return renderViewWithCommonPattern(m, "Parameter Locks", fmt.Sprintf("Phrase %02X Row %02X", m.CurrentPhrase, m.ParameterLockRow), func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		index := input.ParameterLockSoundMaker(m)
+		if index == -1 {
+			content.WriteString(fmt.Sprintf("  %-12s %s\n", styles.Label.Render("SoundMaker:"), styles.Normal.Render("--")))
+			return content.String()
+		}
+		settings := m.SoundMakerSettings[index]
+		content.WriteString(fmt.Sprintf("  %-12s %s\n\n", styles.Label.Render("SoundMaker:"),
+			styles.Normal.Render(fmt.Sprintf("%02X %s", index, settings.Name))))
+
+		def, ok := types.GetInstrumentDefinition(settings.Name)
+		if !ok {
+			return content.String()
+		}
+		content.WriteString("  " + styles.Label.Render(fmt.Sprintf("%-10s %-*s %s", "PARAMETER", parameterLockValueWidth, "SLOT", "LOCK")) + "\n")
+		locks := m.ParameterLocks.Row(m.CurrentPhrase, m.ParameterLockRow)
+		for i, param := range def.Parameters {
+			slot := formatSoundMakerValue(settings.Name, param, settings.GetParameterValue(param.Key))
+			lock := "--"
+			if value, locked := locks[param.Key]; locked {
+				lock = formatSoundMakerValue(settings.Name, param, value)
+			}
+			var lockCell string
+			if m.CurrentRow == i {
+				lockCell = styles.Selected.Render(lock)
+			} else {
+				lockCell = styles.Normal.Render(lock)
+			}
+			content.WriteString(fmt.Sprintf("  %s %s %s\n", styles.Label.Render(fmt.Sprintf("%-10s", param.DisplayName+":")),
+				styles.Normal.Render(fmt.Sprintf("%-*s", parameterLockValueWidth, slot)), lockCell))
+		}
+
+		return content.String()
+	}, statusMsg, 13) // SoundMaker, header and up to 9 parameters
+}
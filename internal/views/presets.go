@@ -0,0 +1,103 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/soundmakers"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// presetNameWidth is the width of the preset name column
+const presetNameWidth = 24
+
+// formatPresetValues lists the parameters of a preset in the order of the SoundMaker's definition
+func formatPresetValues(p soundmakers.Preset) string {
+	var parts []string
+	if def, exists := types.GetInstrumentDefinition(p.SoundMaker); exists {
+		for _, param := range def.Parameters {
+			value, ok := p.Parameters[param.Key]
+			if !ok || value == -1 {
+				continue
+			}
+			switch {
+			case param.DisplayFormatter != nil:
+				parts = append(parts, fmt.Sprintf("%s %s", param.DisplayName, param.DisplayFormatter(value)))
+			case param.Type == types.ParameterTypeHex:
+				parts = append(parts, fmt.Sprintf("%s %02X", param.DisplayName, int(value)))
+			default:
+				parts = append(parts, fmt.Sprintf("%s %g", param.DisplayName, value))
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	keys := make([]string, 0, len(p.Parameters))
+	for key := range p.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s %g", key, p.Parameters[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func GetPresetStatusMessage(m *model.Model) string {
+	if m.PresetNaming {
+		return "Type a name | Enter: Save | Esc: Cancel"
+	}
+	status := ""
+	if m.PresetMessage != "" {
+		status = m.PresetMessage + " | "
+	}
+	return status + "Enter: Load | C: Audition | N: Save current as... | Left/Right: SoundMaker | Shift+Left: Back"
+}
+
+func RenderPresetView(m *model.Model) string {
+	statusMsg := GetPresetStatusMessage(m)
+	rightHeader := fmt.Sprintf("SoundMaker %02X", m.SoundMakerEditingIndex)
+
+	visibleRows := input.PresetVisibleRows(m)
+	return renderViewWithCommonPattern(m, "SoundMaker Presets", rightHeader, func(styles *ViewStyles) string {
+		var content strings.Builder
+
+		content.WriteString(fmt.Sprintf("  %s %s\n", styles.Label.Render("SoundMaker:"), styles.Normal.Render("◀ "+m.PresetSoundMaker+" ▶")))
+		if m.PresetNaming {
+			current := m.SoundMakerSettings[m.SoundMakerEditingIndex].Name
+			content.WriteString(fmt.Sprintf("  %s %s\n", styles.Label.Render("Save "+current+" as:"), styles.Selected.Render(m.PresetNameInput+"_")))
+		} else {
+			content.WriteString(fmt.Sprintf("  %s\n", styles.Label.Render(fmt.Sprintf("%d presets in %s", len(m.Presets), m.PresetDir))))
+		}
+
+		if len(m.Presets) == 0 {
+			content.WriteString(fmt.Sprintf("  %s\n", styles.Normal.Render("No presets yet - press N to save the current SoundMaker")))
+		}
+		for i := 0; i < visibleRows && i+m.ScrollOffset < len(m.Presets); i++ {
+			row := i + m.ScrollOffset
+			preset := m.Presets[row]
+
+			name := preset.Name
+			if len(name) > presetNameWidth {
+				name = name[:presetNameWidth-1] + "~"
+			}
+			text := fmt.Sprintf("%-*s %s", presetNameWidth, name, formatPresetValues(preset))
+			if len(text) > 72 {
+				text = text[:71] + "~"
+			}
+
+			arrow := " "
+			if m.CurrentRow == row {
+				arrow = "▶"
+				text = styles.Selected.Render(text)
+			} else {
+				text = styles.Normal.Render(text)
+			}
+			content.WriteString(fmt.Sprintf("%s %s\n", arrow, text))
+		}
+
+		return content.String()
+	}, statusMsg, visibleRows+2) // presets + SoundMaker line + info line
+}
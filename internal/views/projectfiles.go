@@ -0,0 +1,96 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// projectFileNameWidth is the width of the file name column
+const projectFileNameWidth = 36
+
+// formatProjectFileIssue returns the columns of one project file row
+func formatProjectFileIssue(issue types.ProjectFileIssue) string {
+	name := filepath.Base(issue.Path)
+	if len(name) > projectFileNameWidth {
+		name = name[:projectFileNameWidth-1] + "~"
+	}
+
+	info := ""
+	switch issue.Kind {
+	case types.ProjectFileMissing:
+		info = fmt.Sprintf("%d uses", issue.Uses)
+	case types.ProjectFileDuplicate:
+		info = "= " + filepath.Base(issue.Original)
+	}
+	return fmt.Sprintf("%-9s %-*s %s", issue.Kind, projectFileNameWidth, name, info)
+}
+
+func GetProjectFilesStatusMessage(m *model.Model) string {
+	if len(m.ProjectFileIssues) == 0 {
+		return "All sample files are in use and found | Shift+Left: Back"
+	}
+
+	mod := input.GetModifierKey()
+	action := ""
+	if issue := input.SelectedProjectFileIssue(m); issue != nil {
+		switch issue.Kind {
+		case types.ProjectFileUnused:
+			action = "Backspace: Delete | "
+		case types.ProjectFileMissing:
+			action = "Shift+Right: Relink | "
+		case types.ProjectFileDuplicate:
+			action = "Backspace: Merge | "
+		case types.ProjectFileStaleMetadata:
+			action = "Backspace: Drop metadata | "
+		case types.ProjectFileUntracked:
+			action = "Not in the project | Backspace: Delete | "
+			if m.ProjectFileDeleteConfirm == issue.Path {
+				action = "Backspace again: Delete from disk | "
+			}
+		}
+		action = issue.Path + " | " + action
+	}
+	return action + fmt.Sprintf("%s+F: Fix all | %s+U: Recheck | Shift+Left: Back", mod, mod)
+}
+
+func RenderProjectFilesView(m *model.Model) string {
+	statusMsg := GetProjectFilesStatusMessage(m)
+
+	counts := make(map[types.ProjectFileIssueKind]int)
+	for _, issue := range m.ProjectFileIssues {
+		counts[issue.Kind]++
+	}
+	rightHeader := fmt.Sprintf("%d unused %d missing %d duplicate %d untracked", counts[types.ProjectFileUnused], counts[types.ProjectFileMissing], counts[types.ProjectFileDuplicate], counts[types.ProjectFileUntracked])
+
+	visibleRows := m.GetVisibleRows()
+	return renderViewWithCommonPattern(m, "Project Files", rightHeader, func(styles *ViewStyles) string {
+		var content strings.Builder
+
+		header := fmt.Sprintf("%-9s %-*s %s", "Issue", projectFileNameWidth, "File", "Info")
+		content.WriteString("  " + styles.Label.Render(header) + "\n")
+
+		if len(m.ProjectFileIssues) == 0 {
+			content.WriteString("  " + styles.Normal.Render("No issues found") + "\n")
+		}
+		for i := 0; i < visibleRows && i+m.ScrollOffset < len(m.ProjectFileIssues); i++ {
+			row := i + m.ScrollOffset
+
+			arrow := " "
+			text := formatProjectFileIssue(m.ProjectFileIssues[row])
+			if m.CurrentRow == row {
+				arrow = "▶"
+				text = styles.Selected.Render(text)
+			} else {
+				text = styles.Normal.Render(text)
+			}
+			content.WriteString(fmt.Sprintf("%s %s\n", arrow, text))
+		}
+
+		return content.String()
+	}, statusMsg, visibleRows+1) // issues + header
+}
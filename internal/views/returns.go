@@ -0,0 +1,51 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+func GetReturnsStatusMessage(m *model.Model) string {
+	var columnStatus string
+	module := types.ReturnModules[m.CurrentRow]
+	if m.CurrentCol < len(module.Parameters) {
+		param := module.Parameters[m.CurrentCol]
+		value := m.Returns[m.CurrentRow].Value(param)
+		columnStatus = fmt.Sprintf("%s %s: %s", module.Name, param.DisplayName, types.FormatInsertFXValue(param, value))
+		if module.TempoSynced == param.Key {
+			columnStatus += fmt.Sprintf(", %.0f ms at %.0f BPM", types.DelayDivisionSeconds(int(value), m.BPM)*1000, m.BPM)
+		}
+		columnStatus += " | " + module.Description
+	}
+
+	baseMsg := fmt.Sprintf("%s+Arrow: Adjust | Shift+Left: Back to Mixer", input.GetModifierKey())
+	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
+}
+
+func RenderReturnsView(m *model.Model) string {
+	statusMsg := GetReturnsStatusMessage(m)
+	return renderViewWithCommonPattern(m, "Returns", types.ReturnModules[m.CurrentRow].Name, func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		for row, module := range types.ReturnModules {
+			line := "  " + styles.Label.Render(fmt.Sprintf("%-*s", insertFXModuleWidth, module.Name))
+			for i, param := range module.Parameters {
+				valueCell := fmt.Sprintf("%-7s", types.FormatInsertFXValue(param, m.Returns[row].Value(param)))
+				if m.CurrentRow == row && m.CurrentCol == i {
+					valueCell = styles.Selected.Render(valueCell)
+				} else {
+					valueCell = styles.Normal.Render(valueCell)
+				}
+				line += fmt.Sprintf(" %s %s", styles.Label.Render(param.DisplayName), valueCell)
+			}
+			content.WriteString(line + "\n")
+		}
+
+		return content.String()
+	}, statusMsg, types.ReturnCount+1) // Returns + 1 spacing
+}
@@ -30,7 +30,7 @@ func RenderSamplerPhraseView(m *model.Model) string {
 	var content strings.Builder
 
 	// Render header (Я is a single-character column)
-	columnHeader := "  SL  DT  NN  MO  VE  PI  GT  RT  TS  Я  PA  LP  HP  CO  RE  DU  FI"
+	columnHeader := "  SL  DT  NN  MO  VE  PI  GT  ST  LN  RT  TS  GR  Я  PA  LP  HP  CO  RE  DU  FX  FV  FI"
 	phrasesData := m.GetCurrentPhrasesData()
 	totalTicks := ticks.CalculatePhraseTicks(phrasesData, m.CurrentPhrase)
 	phraseHeader := fmt.Sprintf("Phrase %02X (%d ticks)", m.CurrentPhrase, totalTicks)
@@ -180,16 +180,52 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			gtCell = normalStyle.Render(gtText)
 		}
 
-		// Retrigger (RT) - now at position 7
+		// Start offset (ST) - now at position 7, sticky
+		stText := "--"
+		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColSampleStart] != -1 {
+			stText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColSampleStart])
+		}
+		var stCell string
+		if m.CurrentRow == dataIndex && m.CurrentCol == 7 {
+			stCell = selectedStyle.Render(stText)
+		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 7) {
+				stCell = copiedStyle.Render(stText)
+			} else {
+				stCell = normalStyle.Render(stText)
+			}
+		} else {
+			stCell = normalStyle.Render(stText)
+		}
+
+		// Length (LN) - now at position 8, sticky
+		lnText := "--"
+		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColSampleLength] != -1 {
+			lnText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColSampleLength])
+		}
+		var lnCell string
+		if m.CurrentRow == dataIndex && m.CurrentCol == 8 {
+			lnCell = selectedStyle.Render(lnText)
+		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 8) {
+				lnCell = copiedStyle.Render(lnText)
+			} else {
+				lnCell = normalStyle.Render(lnText)
+			}
+		} else {
+			lnCell = normalStyle.Render(lnText)
+		}
+
+		// Retrigger (RT) - now at position 9
 		rtText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColRetrigger] != -1 {
 			rtText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColRetrigger])
 		}
 		var rtCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 7 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 9 {
 			rtCell = selectedStyle.Render(rtText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 7) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 9) {
 				rtCell = copiedStyle.Render(rtText)
 			} else {
 				rtCell = normalStyle.Render(rtText)
@@ -198,16 +234,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			rtCell = normalStyle.Render(rtText)
 		}
 
-		// Timestretch (TS) - now at position 8
+		// Timestretch (TS) - now at position 10
 		tsText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColTimestretch] != -1 {
 			tsText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColTimestretch])
 		}
 		var tsCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 8 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 10 {
 			tsCell = selectedStyle.Render(tsText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 8) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 10) {
 				tsCell = copiedStyle.Render(tsText)
 			} else {
 				tsCell = normalStyle.Render(tsText)
@@ -216,16 +252,34 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			tsCell = normalStyle.Render(tsText)
 		}
 
-		// Я (EffectReverse) — hex char: "-", "0" to "F" - now at position 9
+		// Granular (GR) - now at position 11
+		grText := "--"
+		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColGranular] != -1 {
+			grText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColGranular])
+		}
+		var grCell string
+		if m.CurrentRow == dataIndex && m.CurrentCol == 11 {
+			grCell = selectedStyle.Render(grText)
+		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 11) {
+				grCell = copiedStyle.Render(grText)
+			} else {
+				grCell = normalStyle.Render(grText)
+			}
+		} else {
+			grCell = normalStyle.Render(grText)
+		}
+
+		// Я (EffectReverse) — hex char: "-", "0" to "F" - now at position 12
 		revText := "-"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectReverse] != -1 {
 			revText = fmt.Sprintf("%X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectReverse])
 		}
 		var revCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 9 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 12 {
 			revCell = selectedStyle.Render(revText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 9) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 12) {
 				revCell = copiedStyle.Render(revText)
 			} else {
 				revCell = normalStyle.Render(revText)
@@ -234,16 +288,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			revCell = normalStyle.Render(revText)
 		}
 
-		// PA (Pan) - now at position 10
+		// PA (Pan) - now at position 13
 		paText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColPan] != -1 {
 			paText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColPan])
 		}
 		var paCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 10 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 13 {
 			paCell = selectedStyle.Render(paText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 10) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 13) {
 				paCell = copiedStyle.Render(paText)
 			} else {
 				paCell = normalStyle.Render(paText)
@@ -252,16 +306,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			paCell = normalStyle.Render(paText)
 		}
 
-		// LP (LowPassFilter) - now at position 11
+		// LP (LowPassFilter) - now at position 14
 		lpText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColLowPassFilter] != -1 {
 			lpText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColLowPassFilter])
 		}
 		var lpCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 11 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 14 {
 			lpCell = selectedStyle.Render(lpText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 11) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 14) {
 				lpCell = copiedStyle.Render(lpText)
 			} else {
 				lpCell = normalStyle.Render(lpText)
@@ -270,16 +324,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			lpCell = normalStyle.Render(lpText)
 		}
 
-		// HP (HighPassFilter) - now at position 12
+		// HP (HighPassFilter) - now at position 15
 		hpText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColHighPassFilter] != -1 {
 			hpText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColHighPassFilter])
 		}
 		var hpCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 12 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 15 {
 			hpCell = selectedStyle.Render(hpText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 12) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 15) {
 				hpCell = copiedStyle.Render(hpText)
 			} else {
 				hpCell = normalStyle.Render(hpText)
@@ -288,16 +342,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			hpCell = normalStyle.Render(hpText)
 		}
 
-		// CO (EffectComb) - now at position 13
+		// CO (EffectComb) - now at position 16
 		combText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectComb] != -1 {
 			combText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectComb])
 		}
 		var combCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 13 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 16 {
 			combCell = selectedStyle.Render(combText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 13) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 16) {
 				combCell = copiedStyle.Render(combText)
 			} else {
 				combCell = normalStyle.Render(combText)
@@ -306,16 +360,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			combCell = normalStyle.Render(combText)
 		}
 
-		// RE (EffectReverb) - now at position 14
+		// RE (EffectReverb) - now at position 17
 		reverbText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectReverb] != -1 {
 			reverbText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectReverb])
 		}
 		var reverbCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 14 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 17 {
 			reverbCell = selectedStyle.Render(reverbText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 14) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 17) {
 				reverbCell = copiedStyle.Render(reverbText)
 			} else {
 				reverbCell = normalStyle.Render(reverbText)
@@ -324,16 +378,16 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			reverbCell = normalStyle.Render(reverbText)
 		}
 
-		// DU (EffectDucking) - now at position 15
+		// DU (EffectDucking) - now at position 18
 		duckingText := "--"
 		if (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectDucking] != -1 {
 			duckingText = fmt.Sprintf("%02X", (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColEffectDucking])
 		}
 		var duckingCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 15 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == 18 {
 			duckingCell = selectedStyle.Render(duckingText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 15) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 18) {
 				duckingCell = copiedStyle.Render(duckingText)
 			} else {
 				duckingCell = normalStyle.Render(duckingText)
@@ -342,7 +396,28 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			duckingCell = normalStyle.Render(duckingText)
 		}
 
-		// Filename (FI) - first 8 characters - now at position 16
+		// Insert FX (FX) and its value (FV)
+		var fxCells [2]string
+		for i, column := range []struct {
+			data types.PhraseColumn
+			ui   types.SamplerUIColumn
+		}{{types.ColInsertFX, types.SamplerColFX}, {types.ColInsertFXValue, types.SamplerColFV}} {
+			value := (*phrasesData)[m.CurrentPhrase][dataIndex][column.data]
+			text := "--"
+			if value != -1 {
+				text = fmt.Sprintf("%02X", value)
+			}
+			if m.CurrentRow == dataIndex && m.CurrentCol == int(column.ui) {
+				fxCells[i] = selectedStyle.Render(text)
+			} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex &&
+				(m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(column.ui))) {
+				fxCells[i] = copiedStyle.Render(text)
+			} else {
+				fxCells[i] = normalStyle.Render(text)
+			}
+		}
+
+		// Filename (FI) - first 8 characters
 		fiText := "--------"
 		fileIndex := (*phrasesData)[m.CurrentPhrase][dataIndex][types.ColFilename]
 		phrasesFiles := m.GetCurrentPhrasesFiles()
@@ -356,10 +431,10 @@ func RenderSamplerPhraseView(m *model.Model) string {
 			}
 		}
 		var fiCell string
-		if m.CurrentRow == dataIndex && m.CurrentCol == 16 {
+		if m.CurrentRow == dataIndex && m.CurrentCol == int(types.SamplerColFI) {
 			fiCell = selectedStyle.Render(fiText)
 		} else if m.Clipboard.HasData && m.Clipboard.HighlightView == types.PhraseView && m.Clipboard.HighlightPhrase == m.CurrentPhrase && m.Clipboard.HighlightRow == dataIndex {
-			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == 16) {
+			if m.Clipboard.Mode == types.RowMode || (m.Clipboard.Mode == types.CellMode && m.Clipboard.HighlightCol == int(types.SamplerColFI)) {
 				fiCell = copiedStyle.Render(fiText)
 			} else {
 				fiCell = normalStyle.Render(fiText)
@@ -369,8 +444,8 @@ func RenderSamplerPhraseView(m *model.Model) string {
 		}
 
 		// NOTE the %-1s for Я to keep it one character wide
-		row := fmt.Sprintf("%s %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-1s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-8s",
-			arrow, sliceCell, dtCell, noteCell, moCell, velocityCell, pitchCell, gtCell, rtCell, tsCell, revCell, paCell, lpCell, hpCell, combCell, reverbCell, duckingCell, fiCell)
+		row := fmt.Sprintf("%s %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-1s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-3s  %-8s",
+			arrow, sliceCell, dtCell, noteCell, moCell, velocityCell, pitchCell, gtCell, stCell, lnCell, rtCell, tsCell, grCell, revCell, paCell, lpCell, hpCell, combCell, reverbCell, duckingCell, fxCells[0], fxCells[1], fiCell)
 		content.WriteString(row)
 		content.WriteString("\n")
 	}
@@ -389,6 +464,7 @@ func GetPhraseStatusMessage(m *model.Model) string {
 	// Use correct sampler UI column indices
 	rtUI := int(types.SamplerColRT)
 	tsUI := int(types.SamplerColTS)
+	grUI := int(types.SamplerColGR)
 	moUI := int(types.SamplerColMO)
 	duUI := int(types.SamplerColDU)
 	fiUI := int(types.SamplerColFI)
@@ -426,6 +502,8 @@ func GetPhraseStatusMessage(m *model.Model) string {
 				statusMsg = fmt.Sprintf("Ducking: -- (%02X, sticky)", effectiveDuckingValue)
 			}
 		}
+	} else if m.CurrentCol == int(types.SamplerColFX) || m.CurrentCol == int(types.SamplerColFV) {
+		statusMsg = insertFXStatus(m)
 	} else if m.CurrentCol == fiUI {
 		// On filename column - show file info
 		phrasesData := m.GetCurrentPhrasesData()
@@ -468,6 +546,22 @@ func GetPhraseStatusMessage(m *model.Model) string {
 					gateFloat := float32(value) / 96.0
 					statusMsg = fmt.Sprintf("Gate: %02X (%.2f, sticky)", value, gateFloat)
 				}
+			} else if colIndex == int(types.ColSampleStart) || colIndex == int(types.ColSampleLength) {
+				// ST/LN columns - show the fraction of the slice
+				name := "Start"
+				empty := "slice start"
+				if colIndex == int(types.ColSampleLength) {
+					name = "Length"
+					empty = "whole slice"
+				}
+				if value == -1 {
+					value = input.GetEffectiveValueForTrack(m, m.CurrentPhrase, m.CurrentRow, colIndex, m.CurrentTrack)
+				}
+				if value == -1 {
+					statusMsg = fmt.Sprintf("%s: -- (%s, sticky)", name, empty)
+				} else {
+					statusMsg = fmt.Sprintf("%s: %02X (%.0f%% of slice, sticky)", name, value, float32(value)/254.0*100)
+				}
 			} else if colIndex == int(types.ColPitch) {
 				// PI (Pitch) column - show -24 to +24 mapping, 128 (0x80) means 0.0 pitch
 				if value == -1 {
@@ -575,6 +669,16 @@ func GetPhraseStatusMessage(m *model.Model) string {
 				} else {
 					statusMsg = fmt.Sprintf("Timestretch: %02X", value)
 				}
+			} else if colIndex == int(types.ColGranular) {
+				// GR (Granular) column - show the grain size and density of the slot
+				if value == -1 {
+					statusMsg = "No granular selected"
+				} else if value < 255 && m.GranularSettings[value].Active() {
+					gs := m.GranularSettings[value]
+					statusMsg = fmt.Sprintf("Granular: %02X (%.0f ms, %.0f/s)", value, gs.Size, gs.Density)
+				} else {
+					statusMsg = fmt.Sprintf("Granular: %02X (off)", value)
+				}
 			} else if value == -1 {
 				statusMsg = "Current value: --"
 			} else {
@@ -598,8 +702,12 @@ func GetPhraseStatusMessage(m *model.Model) string {
 		statusMsg += " | Shift+Right: Retrigger | Shift+Left: Back to chain view"
 	} else if m.CurrentCol == tsUI {
 		statusMsg += " | Shift+Right: Timestretch | Shift+Left: Back to chain view"
+	} else if m.CurrentCol == grUI {
+		statusMsg += " | Shift+Right: Granular | Shift+Left: Back to chain view"
 	} else if m.CurrentCol == duUI {
 		statusMsg += " | Shift+Right: Ducking | Shift+Left: Back to chain view"
+	} else if m.CurrentCol == int(types.SamplerColFX) || m.CurrentCol == int(types.SamplerColFV) {
+		statusMsg += " | Shift+Right: Insert FX | Shift+Left: Back to chain view"
 	} else {
 		statusMsg += " | Shift+Right: File browser | Shift+Left: Back to chain view"
 	}
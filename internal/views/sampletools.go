@@ -0,0 +1,85 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/types"
+)
+
+// sampleToolsWaveHeight is the waveform height in Braille cells
+const sampleToolsWaveHeight = 6
+
+// sampleToolValue returns the parameter shown next to a tool
+func sampleToolValue(m *model.Model, tool types.SampleTool) string {
+	s := m.SampleToolsSettings
+	switch tool {
+	case types.SampleToolNormalizePeak:
+		return fmt.Sprintf("%.1f dB", s.PeakDB)
+	case types.SampleToolNormalizeRMS:
+		return fmt.Sprintf("%.1f dB", s.RMSDB)
+	case types.SampleToolTrimSilence:
+		return fmt.Sprintf("below %.0f dB", s.SilenceDB)
+	case types.SampleToolFadeIn:
+		return fmt.Sprintf("%d ms", s.FadeInMs)
+	case types.SampleToolFadeOut:
+		return fmt.Sprintf("%d ms", s.FadeOutMs)
+	case types.SampleToolResample:
+		return fmt.Sprintf("%d Hz", s.SampleRate)
+	case types.SampleToolRestore:
+		return fmt.Sprintf("%d backups", len(input.SampleToolsBackups(m)))
+	}
+	return ""
+}
+
+func GetSampleToolsStatusMessage(m *model.Model) string {
+	status := ""
+	if m.SampleToolsMessage != "" {
+		status = m.SampleToolsMessage + " | "
+	}
+	return status + fmt.Sprintf("Enter: Apply | %s+Arrow: Adjust | C: Play/Stop | Shift+Left: Back to File Browser", input.GetModifierKey())
+}
+
+func RenderSampleToolsView(m *model.Model) string {
+	header := fmt.Sprintf("Sample Tools: %s", filepath.Base(m.SampleToolsFile))
+	statusMsg := GetSampleToolsStatusMessage(m)
+
+	return renderViewWithCommonPattern(m, header, "", func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		width := m.TermWidth - 4 // account for container padding
+		if width < 8 {
+			width = 8
+		}
+		content.WriteString(RenderEnvelope(width, sampleToolsWaveHeight, m.SampleToolsPeaks, nil))
+		content.WriteString("\n")
+		if len(m.SampleToolsPeaks) == 0 {
+			content.WriteString(styles.Label.Render("  Waveform unavailable (only PCM WAV files can be processed)"))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+
+		for tool := types.SampleTool(0); tool < types.SampleToolCount; tool++ {
+			arrow := " "
+			name := fmt.Sprintf("%-15s", tool)
+			value := sampleToolValue(m, tool)
+			if m.CurrentRow == int(tool) {
+				arrow = "▶"
+				name = styles.Selected.Render(name)
+			} else {
+				name = styles.Normal.Render(name)
+			}
+			content.WriteString(fmt.Sprintf("%s %s %s\n", arrow, name, styles.Label.Render(value)))
+		}
+
+		content.WriteString("\n")
+		content.WriteString(styles.Label.Render("Files outside the project are copied into it first; every change is backed up"))
+		content.WriteString("\n")
+
+		return content.String()
+	}, statusMsg, sampleToolsWaveHeight+int(types.SampleToolCount)+6)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/schollz/collidertracker/internal/input"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/modulation"
+	"github.com/schollz/collidertracker/internal/types"
 )
 
 func RenderSettingsView(m *model.Model) string {
@@ -14,6 +16,7 @@ func RenderSettingsView(m *model.Model) string {
 		// Column widths
 		const globalColWidth = 18
 		const inputColWidth = 16
+		const tuningColWidth = 22
 
 		// Column styles
 		columnStyle := lipgloss.NewStyle().
@@ -24,8 +27,12 @@ func RenderSettingsView(m *model.Model) string {
 			Width(inputColWidth).
 			Align(lipgloss.Left)
 
+		tuningColumnStyle := lipgloss.NewStyle().
+			Width(tuningColWidth).
+			Align(lipgloss.Left)
+
 		// Column headers
-		var globalHeader, inputHeader string
+		var globalHeader, inputHeader, tuningHeader string
 		if m.CurrentCol == 0 {
 			globalHeader = styles.Selected.Render("Global")
 		} else {
@@ -36,18 +43,27 @@ func RenderSettingsView(m *model.Model) string {
 		} else {
 			inputHeader = styles.Label.Render("Input")
 		}
+		if m.CurrentCol == 2 {
+			tuningHeader = styles.Selected.Render("Tuning")
+		} else {
+			tuningHeader = styles.Label.Render("Tuning")
+		}
 
 		// Create header row
 		globalHeaderCell := columnStyle.Render(globalHeader)
 		inputHeaderCell := inputColumnStyle.Render(inputHeader)
-		headerRow := lipgloss.JoinHorizontal(lipgloss.Top, globalHeaderCell, inputHeaderCell)
+		tuningHeaderCell := tuningColumnStyle.Render(tuningHeader)
+		headerRow := lipgloss.JoinHorizontal(lipgloss.Top, globalHeaderCell, inputHeaderCell, tuningHeaderCell)
 
-		// Global settings (column 0)
-		globalSettings := []struct {
+		// A setting is a labelled value on a row of its column
+		type setting struct {
 			label string
 			value string
 			row   int
-		}{
+		}
+
+		// Global settings (column 0)
+		globalSettings := []setting{
 			{"BPM:", fmt.Sprintf("%.2f", m.BPM), 0},
 			{"PPQ:", fmt.Sprintf("%d", m.PPQ), 1},
 			{"Pre:", fmt.Sprintf("%.1f dB", m.PregainDB), 2},
@@ -60,23 +76,34 @@ func RenderSettingsView(m *model.Model) string {
 		}
 
 		// Input settings (column 1)
-		inputSettings := []struct {
-			label string
-			value string
-			row   int
-		}{
+		inputSettings := []setting{
 			{"Input:", fmt.Sprintf("%.1f dB", m.InputLevelDB), 0},
 			{"Reverb:", fmt.Sprintf("%.1f%%", m.ReverbSendPercent), 1},
 		}
 
+		// Tuning settings (column 2)
+		tuningSettings := []setting{
+			{"Project:", tuningDisplayName(m.Tuning, "12-TET"), int(types.TuningSettingsRowProject)},
+		}
+		for track, name := range m.TrackTunings {
+			tuningSettings = append(tuningSettings, setting{fmt.Sprintf("T%d:", track+1), tuningDisplayName(name, "--"), int(types.TuningSettingsRowTrack1) + track})
+		}
+		degreesValue := "Off"
+		if m.ShowScaleDegrees {
+			degreesValue = "On"
+		}
+		tuningSettings = append(tuningSettings,
+			setting{"Degrees:", degreesValue, int(types.TuningSettingsRowDegrees)},
+			setting{"Key:", modulation.NoteNames[m.ProjectKey], int(types.TuningSettingsRowKey)},
+			setting{"Scale:", tuningDisplayName(m.ProjectScale, "all"), int(types.TuningSettingsRowScale)},
+		)
+
 		// Build column content
 		var globalRows []string
 		var inputRows []string
+		var tuningRows []string
 
-		maxRows := len(globalSettings)
-		if len(inputSettings) > maxRows {
-			maxRows = len(inputSettings)
-		}
+		maxRows := max(len(globalSettings), len(inputSettings), len(tuningSettings))
 
 		for i := 0; i < maxRows; i++ {
 			// Global column row
@@ -108,14 +135,30 @@ func RenderSettingsView(m *model.Model) string {
 			} else {
 				inputRows = append(inputRows, "") // Empty row
 			}
+
+			// Tuning column row
+			if i < len(tuningSettings) {
+				setting := tuningSettings[i]
+				var valueStyle lipgloss.Style
+				if m.CurrentCol == 2 && m.CurrentRow == setting.row {
+					valueStyle = styles.Selected
+				} else {
+					valueStyle = styles.Normal
+				}
+				row := fmt.Sprintf("%-8s %s", styles.Label.Render(setting.label), valueStyle.Render(setting.value))
+				tuningRows = append(tuningRows, row)
+			} else {
+				tuningRows = append(tuningRows, "") // Empty row
+			}
 		}
 
 		// Join rows in each column
 		globalColumn := columnStyle.Render(strings.Join(globalRows, "\n"))
 		inputColumn := inputColumnStyle.Render(strings.Join(inputRows, "\n"))
+		tuningColumn := tuningColumnStyle.Render(strings.Join(tuningRows, "\n"))
 
 		// Join columns horizontally
-		columnsRow := lipgloss.JoinHorizontal(lipgloss.Top, globalColumn, inputColumn)
+		columnsRow := lipgloss.JoinHorizontal(lipgloss.Top, globalColumn, inputColumn, tuningColumn)
 
 		// Timing info
 		beatsPerSecond := float64(m.BPM) / 60.0
@@ -136,5 +179,16 @@ func RenderSettingsView(m *model.Model) string {
 		)
 
 		return content
-	}, fmt.Sprintf("Up/Down/Left/Right: Navigate | %s+Arrow: Adjust values | Shift+Down: Back to Chain view", input.GetModifierKey()), 13)
+	}, fmt.Sprintf("Up/Down/Left/Right: Navigate | %s+Arrow: Adjust values | Shift+Right: Project files | Shift+Down: Back to Chain view", input.GetModifierKey()), 16)
+}
+
+// tuningDisplayName shortens the name of a tuning to fit the Tuning column
+func tuningDisplayName(name, unset string) string {
+	if name == "" {
+		return unset
+	}
+	if len(name) > 12 {
+		return name[:11] + "…"
+	}
+	return name
 }
@@ -0,0 +1,154 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/schollz/collidertracker/internal/input"
+	"github.com/schollz/collidertracker/internal/model"
+)
+
+// sliceEditorWaveHeight is the waveform height in Braille cells
+const sliceEditorWaveHeight = 8
+
+// sliceEditorWindow returns the visible portion of the file (as fractions) for the
+// current zoom, centered on the selected marker
+func sliceEditorWindow(m *model.Model, selected float32) (float64, float64) {
+	zoom := m.SliceEditorZoom
+	if zoom < 1 {
+		zoom = 1
+	}
+	span := 1.0 / float64(zoom)
+	start := float64(selected) - span/2
+	if start < 0 {
+		start = 0
+	}
+	if start+span > 1 {
+		start = 1 - span
+	}
+	return start, start + span
+}
+
+func RenderSliceEditorView(m *model.Model) string {
+	filename := filepath.Base(m.MetadataEditingFile)
+	header := fmt.Sprintf("Slice Editor: %s", filename)
+	zoom := m.SliceEditorZoom
+	if zoom < 1 {
+		zoom = 1
+	}
+	zoomHeader := fmt.Sprintf("Zoom x%d", zoom)
+
+	return renderViewWithCommonPattern(m, header, zoomHeader, func(styles *ViewStyles) string {
+		var content strings.Builder
+		content.WriteString("\n")
+
+		metadata, exists := m.FileMetadata[m.MetadataEditingFile]
+		if !exists {
+			metadata.BPM = 120.0
+			metadata.Slices = 16
+			metadata.SyncToBPM = 1
+		}
+		markers := metadata.Markers()
+		last := len(markers) - 1
+		selected := m.CurrentCol
+		if selected < 0 {
+			selected = 0
+		} else if selected > last {
+			selected = last
+		}
+
+		width := m.TermWidth - 4 // account for container padding
+		if width < 8 {
+			width = 8
+		}
+		windowStart, windowEnd := sliceEditorWindow(m, markers[selected])
+		span := windowEnd - windowStart
+
+		// Markers relative to the visible window
+		visibleMarkers := make([]float64, 0, len(markers))
+		for _, pos := range markers {
+			rel := (float64(pos) - windowStart) / span
+			visibleMarkers = append(visibleMarkers, rel)
+		}
+
+		if len(m.SliceEditorPeaks) > 0 {
+			n := len(m.SliceEditorPeaks)
+			i0 := int(windowStart * float64(n))
+			i1 := int(windowEnd * float64(n))
+			if i1 <= i0 {
+				i1 = i0 + 1
+			}
+			if i1 > n {
+				i1 = n
+			}
+			content.WriteString(RenderEnvelope(width, sliceEditorWaveHeight, m.SliceEditorPeaks[i0:i1], visibleMarkers))
+		} else {
+			content.WriteString(RenderEnvelope(width, sliceEditorWaveHeight, nil, visibleMarkers))
+			content.WriteString("\n")
+			content.WriteString(styles.Label.Render("  Waveform unavailable (only PCM WAV files can be previewed)"))
+		}
+		content.WriteString("\n")
+
+		// Marker ruler: [ = trim start, ] = trim end, | = slice marker, ^ = selected
+		ruler := []rune(strings.Repeat(" ", width))
+		selectedCol := -1
+		for i, rel := range visibleMarkers {
+			if rel < 0 || rel > 1 {
+				continue
+			}
+			col := int(rel*float64(width*2-1)) / 2
+			switch {
+			case i == selected:
+				selectedCol = col
+				continue
+			case i == 0:
+				ruler[col] = '['
+			case i == last:
+				ruler[col] = ']'
+			default:
+				ruler[col] = '|'
+			}
+		}
+		if selectedCol >= 0 {
+			content.WriteString(styles.Slice.Render(string(ruler[:selectedCol])))
+			content.WriteString(styles.Selected.Render("^"))
+			content.WriteString(styles.Slice.Render(string(ruler[selectedCol+1:])))
+		} else {
+			content.WriteString(styles.Slice.Render(string(ruler)))
+		}
+		content.WriteString("\n\n")
+
+		// Marker details
+		var markerName string
+		switch selected {
+		case 0:
+			markerName = "Trim start"
+		case last:
+			markerName = "Trim end"
+		default:
+			markerName = fmt.Sprintf("Slice %02d", selected)
+		}
+		sliceIndex := selected
+		if sliceIndex > last-1 {
+			sliceIndex = last - 1
+		}
+		sliceStart, sliceEnd := metadata.SliceBounds(sliceIndex)
+
+		rows := []struct {
+			label string
+			value string
+		}{
+			{"Marker:", fmt.Sprintf("%s @ %.2f%%", markerName, markers[selected]*100)},
+			{"Slice:", fmt.Sprintf("%02d  %.2f%% - %.2f%%", sliceIndex, sliceStart*100, sliceEnd*100)},
+			{"Trim:", fmt.Sprintf("%.2f%% - %.2f%%", metadata.TrimStart*100, metadata.EffectiveTrimEnd()*100)},
+			{"Slices:", fmt.Sprintf("%d", last)},
+		}
+		for _, row := range rows {
+			content.WriteString(fmt.Sprintf("  %-8s %s", styles.Label.Render(row.label), styles.Normal.Render(row.value)))
+			content.WriteString("\n")
+		}
+
+		return content.String()
+	}, fmt.Sprintf("Left/Right: Marker | Up/Down: Zoom | %s+Arrow: Move marker | C: Audition | Backspace: Reset | Shift+Left: Back", input.GetModifierKey()), sliceEditorWaveHeight+8)
+}
@@ -84,7 +84,15 @@ func GetSoundMakerStatusMessage(m *model.Model) string {
 		}
 	}
 
-	baseMsg := fmt.Sprintf("Up/Down: Navigate | SPACE: Select SoundMaker | %s+Arrow: Adjust values | Shift+Left: Back to Phrase view", input.GetModifierKey())
+	baseMsg := fmt.Sprintf("Up/Down: Navigate | SPACE: Select SoundMaker | %s+Arrow: Adjust values | /: Presets | Shift+Left: Back to Phrase view", input.GetModifierKey())
+	if settings.Name == types.MultisampleSoundMakerName {
+		baseMsg = "Shift+Right: Edit zones | " + baseMsg
+	} else if settings.Name == "DX7" {
+		baseMsg = "Shift+Right: Import SysEx bank | " + baseMsg
+		if m.DX7BankMessage != "" {
+			baseMsg = m.DX7BankMessage + " | " + baseMsg
+		}
+	}
 	return fmt.Sprintf("%s | %s", columnStatus, baseMsg)
 }
 
@@ -113,6 +121,9 @@ func RenderSoundMakerView(m *model.Model) string {
 		if def, exists := types.GetInstrumentDefinition(settings.Name); exists && def.Description != "" {
 			content.WriteString(fmt.Sprintf("  %-12s %s\n", styles.Label.Render("Description:"), styles.Normal.Render(def.Description)))
 		}
+		if settings.Name == types.MultisampleSoundMakerName {
+			content.WriteString(fmt.Sprintf("  %-12s %s\n", styles.Label.Render("Zones:"), styles.Normal.Render(fmt.Sprintf("%d", len(settings.Zones)))))
+		}
 		content.WriteString("\n")
 
 		// Get instrument definition and render parameters in single column
@@ -123,55 +134,7 @@ func RenderSoundMakerView(m *model.Model) string {
 			// Render all parameters in a single column, sorted by their original order
 			for i, param := range def.Parameters {
 				value := settings.GetParameterValue(param.Key)
-				var valueStr string
-
-				// Special formatting for DX7 preset
-				if param.Key == "preset" && settings.Name == "DX7" {
-					if value == -1 {
-						valueStr = "--"
-					} else {
-						patchName, err := supercollider.GetDX7PatchName(int(value))
-						if err == nil {
-							valueStr = fmt.Sprintf("%s", patchName)
-						} else {
-							valueStr = fmt.Sprintf("%.0f", value)
-						}
-					}
-				} else if param.Key == "model" && settings.Name == "MiBraids" {
-					// Special formatting for MiBraids model
-					if value == -1 {
-						valueStr = "--"
-					} else {
-						modelName := types.GetMiBraidsModelName(int(value))
-						valueStr = fmt.Sprintf("%s", modelName)
-					}
-				} else if param.Key == "engine" && settings.Name == "MiPlaits" {
-					// Special formatting for MiPlaits engine
-					if value == -1 {
-						valueStr = "--"
-					} else {
-						engineName := types.GetMiPlaitsEngineName(int(value))
-						valueStr = fmt.Sprintf("%s", engineName)
-					}
-				} else {
-					if value == -1 {
-						valueStr = "--"
-					} else {
-						// Use DisplayFormatter if available, otherwise use DisplayFormat or default formatting
-						if param.DisplayFormatter != nil {
-							valueStr = param.DisplayFormatter(value)
-						} else if param.DisplayFormat != "" {
-							valueStr = fmt.Sprintf(param.DisplayFormat, value)
-						} else if param.Type == types.ParameterTypeHex {
-							valueStr = fmt.Sprintf("%02X", int(value))
-						} else if param.Type == types.ParameterTypeFloat {
-							// Display float parameters directly
-							valueStr = fmt.Sprintf("%.2f", value)
-						} else {
-							valueStr = fmt.Sprintf("%.0f", value)
-						}
-					}
-				}
+				valueStr := formatSoundMakerValue(settings.Name, param, value)
 
 				// Row index is i+1 because name is row 0
 				paramRowIndex := i + 1
@@ -203,3 +166,33 @@ func RenderSoundMakerView(m *model.Model) string {
 		return content.String()
 	}, statusMsg, 15) // Fixed height for stable view
 }
+
+// formatSoundMakerValue formats the value of a SoundMaker parameter for display, "--" when unset
+func formatSoundMakerValue(name string, param types.InstrumentParameterDef, value float32) string {
+	if value == -1 {
+		return "--"
+	}
+	switch {
+	case param.Key == "preset" && name == "DX7":
+		if patchName, err := supercollider.GetDX7PatchName(int(value)); err == nil {
+			return patchName
+		}
+		return fmt.Sprintf("%.0f", value)
+	case param.Key == "model" && name == "MiBraids":
+		return types.GetMiBraidsModelName(int(value))
+	case param.Key == "engine" && name == "MiPlaits":
+		return types.GetMiPlaitsEngineName(int(value))
+	}
+
+	// Use DisplayFormatter if available, otherwise use DisplayFormat or default formatting
+	if param.DisplayFormatter != nil {
+		return param.DisplayFormatter(value)
+	} else if param.DisplayFormat != "" {
+		return fmt.Sprintf(param.DisplayFormat, value)
+	} else if param.Type == types.ParameterTypeHex {
+		return fmt.Sprintf("%02X", int(value))
+	} else if param.Type == types.ParameterTypeFloat {
+		return fmt.Sprintf("%.2f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
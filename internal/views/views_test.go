@@ -8,7 +8,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/schollz/collidertracker/internal/library"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/soundmakers"
+	"github.com/schollz/collidertracker/internal/tuning"
 	"github.com/schollz/collidertracker/internal/types"
 )
 
@@ -114,6 +117,48 @@ func TestRenderSettingsView(t *testing.T) {
 	// Should contain settings values
 	assert.Contains(t, view, "BPM")
 	assert.Contains(t, view, "140") // BPM value
+
+	// Tuning column
+	assert.Contains(t, view, "Tuning")
+	assert.Contains(t, view, "12-TET")
+	assert.Contains(t, view, "T8:")
+	m.TrackTunings[7] = "Bohlen-Pierce-Equal"
+	assert.Contains(t, RenderSettingsView(m), "Bohlen-Pier…")
+
+	// Key and scale of the project
+	m.ProjectKey = 9
+	m.ProjectScale = "harmonic-minor"
+	view = RenderSettingsView(m)
+	assert.Contains(t, view, "Key:")
+	assert.Contains(t, view, "harmonic-mi…")
+}
+
+func TestRenderPhraseViewScaleDegrees(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PhraseView
+	m.CurrentTrack = 2
+	m.TrackTypes[2] = false // Instrument track
+	m.CurrentPhrase = 3
+	(*m.GetCurrentPhrasesData())[3][0][types.ColNote] = 71
+
+	scale := tuning.Scale{}
+	for i := 1; i <= 19; i++ {
+		scale.Cents = append(scale.Cents, 1200*float64(i)/19)
+	}
+	m.Tunings = map[string]*tuning.Tuning{"19-EDO": {Name: "19-EDO", Scale: scale, Mapping: tuning.DefaultKeyboardMapping()}}
+	m.Tuning = "19-EDO"
+
+	// Note names stay until scale degrees are switched on
+	assert.Contains(t, RenderPhraseView(m), "b-4")
+	m.ShowScaleDegrees = true
+	view := RenderPhraseView(m)
+	assert.Contains(t, view, "114")
+	assert.NotContains(t, view, "b-4")
+
+	// The status line tells where the tuning puts the note
+	m.CurrentRow = 0
+	m.CurrentCol = int(types.InstrumentColNOT)
+	assert.Contains(t, RenderPhraseView(m), "19-EDO: degree 11 of 19")
 }
 
 func TestRenderFileMetadataView(t *testing.T) {
@@ -139,6 +184,141 @@ func TestRenderFileMetadataView(t *testing.T) {
 	assert.Contains(t, view, "test.wav")
 }
 
+func TestRenderSliceEditorView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.SliceEditorView
+	m.MetadataEditingFile = "test.wav"
+	m.FileMetadata["test.wav"] = types.FileMetadata{BPM: 120, Slices: 4, SyncToBPM: 1, TrimEnd: 0.8}
+
+	// Without decoded peaks the view still renders markers
+	view := RenderSliceEditorView(m)
+	assert.Contains(t, view, "Slice Editor: test.wav")
+	assert.Contains(t, view, "Waveform unavailable")
+	assert.Contains(t, view, "Trim start")
+
+	// With peaks, zoomed, on an inner marker
+	m.SliceEditorPeaks = make([]float64, 1024)
+	for i := range m.SliceEditorPeaks {
+		m.SliceEditorPeaks[i] = float64(i%32) / 32.0
+	}
+	m.SliceEditorZoom = 4
+	m.CurrentCol = 2
+	view = RenderSliceEditorView(m)
+	assert.Contains(t, view, "Zoom x4")
+	assert.Contains(t, view, "Slice 02")
+	assert.NotContains(t, view, "Waveform unavailable")
+}
+
+func TestRenderMultisampleView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.MultisampleView
+	m.SoundMakerEditingIndex = 2
+	m.SoundMakerSettings[2].Name = types.MultisampleSoundMakerName
+
+	// Empty editor shows only the add row
+	view := RenderMultisampleView(m)
+	assert.Contains(t, view, "Multisample Zones")
+	assert.Contains(t, view, "SoundMaker 02")
+	assert.Contains(t, view, "(add zone)")
+	assert.Contains(t, view, "Add zone from file browser")
+
+	m.SoundMakerSettings[2].SetZoneFile(0, "samples/piano_C4.wav")
+	m.SoundMakerSettings[2].Zones[0].FineTune = -12
+	m.CurrentCol = int(types.MultisampleColFineTune)
+	view = RenderMultisampleView(m)
+	assert.Contains(t, view, "piano_C4.wav")
+	assert.Contains(t, view, "c-4")
+	assert.Contains(t, view, "-012")
+	assert.Contains(t, view, "Fine tune: -12 cents")
+
+	// SoundMaker view lists the zone count and the zone editor shortcut
+	m.ViewMode = types.SoundMakerView
+	m.CurrentRow = 0
+	view = RenderSoundMakerView(m)
+	assert.Contains(t, view, "Zones:")
+	assert.Contains(t, view, "Shift+Right: Edit zones")
+}
+
+func TestRenderLibraryView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.LibraryView
+	m.CurrentDir = "/samples"
+
+	// Without folders the status explains how to add one
+	m.Library = &library.Index{}
+	view := RenderLibraryView(m)
+	assert.Contains(t, view, "Sample Library")
+	assert.Contains(t, view, "No library folders")
+
+	m.Library = &library.Index{
+		Roots: []string{"/samples"},
+		Entries: []library.Entry{
+			{Path: "/samples/amen_bpm172.wav", Duration: 5.58, BPM: 172, Channels: 2, Loop: true, Tags: []string{"breaks"}},
+			{Path: "/samples/kick.wav", Duration: 0.4, Channels: 1},
+		},
+	}
+	m.LibraryQuery = "is:loop"
+	m.LibraryResults = m.Library.Search(m.LibraryQuery)
+	view = RenderLibraryView(m)
+	assert.Contains(t, view, "1/2 files")
+	assert.Contains(t, view, "amen_bpm172.wav")
+	assert.Contains(t, view, " 172")
+	assert.Contains(t, view, "5.58s")
+	assert.Contains(t, view, "#breaks")
+	assert.NotContains(t, view, "kick.wav")
+
+	m.LibraryTagEditing = true
+	m.LibraryTagInput = "breaks"
+	view = RenderLibraryView(m)
+	assert.Contains(t, view, "Tags:")
+	assert.Contains(t, view, "Enter: Save tags")
+}
+
+func TestRenderProjectFilesView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.ProjectFilesView
+
+	view := RenderProjectFilesView(m)
+	assert.Contains(t, view, "Project Files")
+	assert.Contains(t, view, "No issues found")
+
+	m.ProjectFileIssues = []types.ProjectFileIssue{
+		{Kind: types.ProjectFileUnused, Path: "/song/leftover.wav"},
+		{Kind: types.ProjectFileMissing, Path: "/samples/gone.wav", Uses: 3},
+		{Kind: types.ProjectFileDuplicate, Path: "/samples/kick-copy.wav", Original: "/song/kick.wav", Uses: 1},
+	}
+	m.CurrentRow = 1
+	view = RenderProjectFilesView(m)
+	assert.Contains(t, view, "1 unused 1 missing 1 duplicate")
+	assert.Contains(t, view, "leftover.wav")
+	assert.Contains(t, view, "3 uses")
+	assert.Contains(t, view, "= kick.wav")
+	assert.Contains(t, view, "Shift+Right: Relink")
+}
+
+func TestRenderSampleToolsView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.SampleToolsView
+	m.SampleToolsFile = "/samples/stab.wav"
+	m.CurrentRow = int(types.SampleToolTrimSilence)
+
+	view := RenderSampleToolsView(m)
+	assert.Contains(t, view, "Sample Tools: stab.wav")
+	assert.Contains(t, view, "Waveform unavailable")
+	assert.Contains(t, view, "Normalize peak")
+	assert.Contains(t, view, "-0.1 dB")
+	assert.Contains(t, view, "below -60 dB")
+	assert.Contains(t, view, "48000 Hz")
+	assert.Contains(t, view, "0 backups")
+	assert.Contains(t, view, "Enter: Apply")
+
+	m.SampleToolsPeaks = []float64{0.1, 0.5, 1, 0.5}
+	m.SampleToolsMessage = "Reverse done"
+	view = RenderSampleToolsView(m)
+	assert.NotContains(t, view, "Waveform unavailable")
+	assert.Contains(t, view, "Reverse done")
+}
+
 func TestRenderRetriggerView(t *testing.T) {
 	m := createTestModel()
 	m.ViewMode = types.RetriggerView
@@ -169,6 +349,95 @@ func TestRenderTimestrechView(t *testing.T) {
 	assert.Contains(t, view, "0A") // Hex index
 }
 
+func TestRenderGranularView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.GranularView
+	m.GranularEditingIndex = 10
+	m.GranularSettings[10].Size = 80
+
+	view := RenderGranularView(m)
+	assert.Contains(t, view, "Granular 0A")
+	assert.Contains(t, view, "80 ms")
+	assert.Contains(t, view, "20 /s")
+	assert.Contains(t, view, "Spray")
+}
+
+func TestRenderPresetView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.PresetView
+	m.SoundMakerEditingIndex = 2
+	m.PresetSoundMaker = "TB303"
+	m.Presets = []soundmakers.Preset{{Name: "Acid", SoundMaker: "TB303", Parameters: map[string]float32{"resonance": 2.5, "monophonic": 1}}}
+
+	view := RenderPresetView(m)
+	assert.Contains(t, view, "SoundMaker 02")
+	assert.Contains(t, view, "TB303")
+	assert.Contains(t, view, "Acid")
+	assert.Contains(t, view, "Resonance 2.5")
+	assert.Contains(t, view, "Monophonic Yes")
+
+	m.PresetNaming = true
+	m.PresetNameInput = "new"
+	assert.Contains(t, RenderPresetView(m), "new_")
+}
+
+func TestRenderLFOView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.LFOView
+	m.BPM = 120
+	m.LFOSettings[2] = types.LFOSettings{Waveform: int(types.LFOWaveformSampleHold), Rate: 4, Sync: 3, Depth: 80, Track: 6, Target: "TB303/resonance"}
+	m.CurrentRow = 2
+	m.CurrentCol = int(types.LFOColSync)
+
+	view := RenderLFOView(m)
+	assert.Contains(t, view, "TARGET")
+	assert.Contains(t, view, "s&h")
+	assert.Contains(t, view, "T7")
+	assert.Contains(t, view, "TB303 Resonance")
+	assert.Contains(t, view, "Sync 1/8 per cycle (4.00 Hz at 120 BPM)")
+}
+
+func TestRenderAutomationView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.AutomationView
+	m.AutomationEditing = types.AutomationOwner{Scope: types.AutomationScopeChain, Sampler: true, ID: 0x1A}
+	m.AutomationLanes = []types.AutomationLane{{
+		Owner:  m.AutomationEditing,
+		Target: "highPassFilter",
+		Points: []types.AutomationPoint{{Tick: 0, Value: 0}, {Tick: 0x40, Value: 0xFE, Curve: types.AutomationCurveExponential}},
+	}}
+	m.CurrentRow = types.AutomationRowFirstPoint + 1
+	m.CurrentCol = int(types.AutomationColValue)
+
+	view := RenderAutomationView(m)
+	assert.Contains(t, view, "Chain 1A (sampler)")
+	assert.Contains(t, view, "HP filter")
+	assert.Contains(t, view, "040")
+	assert.Contains(t, view, "(add breakpoint)")
+	assert.Contains(t, view, "█")
+	assert.Contains(t, view, "Value FE (20000.00)")
+}
+
+func TestRenderParameterLockView(t *testing.T) {
+	m := createTestModel()
+	m.ViewMode = types.ParameterLockView
+	m.TrackTypes[0] = false
+	m.CurrentPhrase = 0x0A
+	m.ParameterLockRow = 0x05
+	m.SoundMakerSettings[1].Name = "PolyPerc"
+	m.SoundMakerSettings[1].InitializeParameters()
+	m.SoundMakerSettings[1].SetParameterValue("A", 0x40)
+	m.InstrumentPhrasesData[0x0A][0x05][types.ColSoundMaker] = 1
+	m.ParameterLocks.Set(0x0A, 0x05, "A", 0xC0)
+	m.CurrentRow = 0
+
+	view := RenderParameterLockView(m)
+	assert.Contains(t, view, "Phrase 0A Row 05")
+	assert.Contains(t, view, "01 PolyPerc")
+	assert.Contains(t, view, "C0")
+	assert.Contains(t, view, "A: locked to C0 (slot 40)")
+}
+
 func TestRenderArpeggioView(t *testing.T) {
 	m := createTestModel()
 	m.ViewMode = types.ArpeggioView
@@ -182,6 +451,15 @@ func TestRenderArpeggioView(t *testing.T) {
 
 	// Should contain arpeggio settings
 	assert.Contains(t, view, "0F") // Hex index
+	assert.Contains(t, view, "RT")
+	assert.Contains(t, view, "Latch:")
+
+	m.ArpeggioSettings[15].Rows[0] = types.ArpeggioRow{Direction: int(types.ArpeggioDirectionConverge), Count: 4, Divisor: 2, Rest: 1}
+	m.CurrentRow = 0
+	view = RenderArpeggioView(m)
+	assert.Contains(t, view, "cv")
+	assert.Contains(t, view, "Direction cv (converge)")
+	assert.Contains(t, view, "RS")
 }
 
 func TestRenderMidiView(t *testing.T) {
@@ -128,3 +128,77 @@ func RenderWaveform(width, height int, data []float64) string {
 	}
 	return b.String()
 }
+
+// RenderEnvelope renders a peak envelope (values in [0,1]) as a Braille string,
+// mirrored around the vertical center. Each dot column shows the largest peak it
+// covers. markers are positions in [0,1] across the width that are drawn as full
+// vertical lines. width and height are in Braille cells.
+func RenderEnvelope(width, height int, peaks []float64, markers []float64) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+
+	fineW := width * 2
+	fineH := height * 4
+	masks := make([]byte, width*height)
+
+	// Braille bit for a dot at (inRow 0..3, inCol 0..1)
+	dotBits := [4][2]byte{
+		{0x01, 0x08},
+		{0x02, 0x10},
+		{0x04, 0x20},
+		{0x40, 0x80},
+	}
+	setDot := func(x, y int) {
+		if x < 0 || x >= fineW || y < 0 || y >= fineH {
+			return
+		}
+		masks[(y>>2)*width+(x>>1)] |= dotBits[y&3][x&1]
+	}
+
+	center := float64(fineH-1) / 2.0
+	for x := 0; x < fineW && len(peaks) > 0; x++ {
+		start := x * len(peaks) / fineW
+		end := (x + 1) * len(peaks) / fineW
+		if end <= start {
+			end = start + 1
+		}
+		peak := 0.0
+		for _, v := range peaks[start:end] {
+			if v > peak {
+				peak = v
+			}
+		}
+		if peak > 1 {
+			peak = 1
+		}
+		top := int(math.Round(center - peak*center))
+		bottom := int(math.Round(center + peak*center))
+		for y := top; y <= bottom; y++ {
+			setDot(x, y)
+		}
+	}
+
+	for _, pos := range markers {
+		if pos < 0 || pos > 1 {
+			continue
+		}
+		x := int(math.Round(pos * float64(fineW-1)))
+		for y := 0; y < fineH; y++ {
+			setDot(x, y)
+		}
+	}
+
+	const brailleBase = 0x2800
+	var b strings.Builder
+	b.Grow(height*width + (height - 1))
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			b.WriteRune(rune(brailleBase + int(masks[row*width+col])))
+		}
+		if row != height-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
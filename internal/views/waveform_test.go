@@ -2,6 +2,7 @@ package views
 
 import (
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +27,29 @@ func TestBrailleSineWave(t *testing.T) {
 	// Log the waveform so it shows up in test output.
 	t.Log("\n" + out)
 }
+
+func TestRenderEnvelope(t *testing.T) {
+	peaks := []float64{0, 0.5, 1, 0.5, 0}
+	out := RenderEnvelope(10, 2, peaks, []float64{0, 1})
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n != 10 {
+			t.Fatalf("expected 10 cells per line, got %d", n)
+		}
+	}
+	// Marker at position 0 fills the first dot column on every row
+	for _, line := range lines {
+		r := []rune(line)[0]
+		if (r-0x2800)&0x47 != 0x47 {
+			t.Fatalf("expected full left dot column for marker, got %U", r)
+		}
+	}
+
+	if RenderEnvelope(0, 2, peaks, nil) != "" {
+		t.Fatalf("expected empty output for zero width")
+	}
+	t.Log("\n" + out)
+}
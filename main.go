@@ -17,8 +17,11 @@ import (
 	"github.com/schollz/collidertracker/internal/hacks"
 	"github.com/schollz/collidertracker/internal/input"
 	"github.com/schollz/collidertracker/internal/midiconnector"
+	"github.com/schollz/collidertracker/internal/midiprofiles"
 	"github.com/schollz/collidertracker/internal/model"
+	"github.com/schollz/collidertracker/internal/modulation"
 	"github.com/schollz/collidertracker/internal/project"
+	"github.com/schollz/collidertracker/internal/soundmakers"
 	"github.com/schollz/collidertracker/internal/storage"
 	"github.com/schollz/collidertracker/internal/supercollider"
 	"github.com/schollz/collidertracker/internal/types"
@@ -30,13 +33,15 @@ var (
 
 	// Command-line configuration
 	config struct {
-		port            int
-		project         string
-		projectProvided bool // Track if --project flag was explicitly provided
-		record          bool
-		debug           string
-		skipSC          bool
-		vim             bool
+		port               int
+		project            string
+		projectProvided    bool // Track if --project flag was explicitly provided
+		record             bool
+		debug              string
+		skipSC             bool
+		vim                bool
+		virtualMidi        string
+		projectSoundMakers bool // Load the SoundMakers in the project's soundmakers folder
 	}
 )
 
@@ -71,6 +76,11 @@ func init() {
 		"Skip SuperCollider detection and management entirely")
 	rootCmd.PersistentFlags().BoolVar(&config.vim, "vim", false,
 		"Enable vim-style cursor movement (h/j/k/l)")
+	rootCmd.PersistentFlags().StringVar(&config.virtualMidi, "virtual-midi", "",
+		"Create a virtual MIDI output with this name for other software to connect to")
+	rootCmd.PersistentFlags().Lookup("virtual-midi").NoOptDefVal = "ColliderTracker"
+	rootCmd.PersistentFlags().BoolVar(&config.projectSoundMakers, "project-soundmakers", false,
+		"Load the SoundMakers shipped in the project's soundmakers folder (their SynthDefs run as SuperCollider code)")
 
 	// Set up a callback to track when --project is explicitly provided
 	rootCmd.PersistentFlags().Lookup("project").Changed = false
@@ -156,11 +166,15 @@ func restartWithProject() {
 			tm.model.SendOSCReverbSendMessage()
 			tm.model.SendOSCTapeMessage()
 			tm.model.SendOSCShimmerMessage()
+			tm.model.SendOSCSynthDefMessages()
 
 			// Send track set levels too
 			for track := 0; track < 8; track++ {
 				tm.model.SendOSCTrackSetLevelMessage(track)
 			}
+			tm.model.SendOSCLFOMessages()
+			tm.model.SendOSCInsertFXMessages()
+			tm.model.SendOSCReturnMessages()
 			initialPreferencesSent = true
 		}
 
@@ -183,6 +197,15 @@ func restartWithProject() {
 
 	p := tea.NewProgram(tm, tea.WithAltScreen())
 
+	// Bounces are added to the sampler files once SuperCollider has written them
+	d.AddMsgHandler("/recorded", func(msg *osc.Message) {
+		if len(msg.Arguments) > 0 {
+			if filename, ok := msg.Arguments[0].(string); ok {
+				p.Send(input.BounceWrittenMsg{File: filename})
+			}
+		}
+	})
+
 	// Start OSC server after p is created but before p.Run()
 	server := &osc.Server{Addr: fmt.Sprintf(":%d", config.port+1), Dispatcher: d}
 	go func() {
@@ -379,11 +402,15 @@ func runColliderTracker(cmd *cobra.Command, args []string) {
 			tm.model.SendOSCReverbSendMessage()
 			tm.model.SendOSCTapeMessage()
 			tm.model.SendOSCShimmerMessage()
+			tm.model.SendOSCSynthDefMessages()
 
 			// Send track set levels too
 			for track := 0; track < 8; track++ {
 				tm.model.SendOSCTrackSetLevelMessage(track)
 			}
+			tm.model.SendOSCLFOMessages()
+			tm.model.SendOSCInsertFXMessages()
+			tm.model.SendOSCReturnMessages()
 			initialPreferencesSent = true
 		}
 
@@ -406,6 +433,15 @@ func runColliderTracker(cmd *cobra.Command, args []string) {
 
 	p := tea.NewProgram(tm, tea.WithAltScreen())
 
+	// Bounces are added to the sampler files once SuperCollider has written them
+	d.AddMsgHandler("/recorded", func(msg *osc.Message) {
+		if len(msg.Arguments) > 0 {
+			if filename, ok := msg.Arguments[0].(string); ok {
+				p.Send(input.BounceWrittenMsg{File: filename})
+			}
+		}
+	})
+
 	// Start OSC server after p is created but before p.Run()
 	server := &osc.Server{Addr: fmt.Sprintf(":%d", config.port+1), Dispatcher: d}
 	go func() {
@@ -499,6 +535,23 @@ func runColliderTracker(cmd *cobra.Command, args []string) {
 }
 
 func initialModel(oscPort int, saveFolder string, vimMode bool, dispatcher *osc.StandardDispatcher) *TrackerModel {
+	// Register user SoundMakers before loading so that their settings keep their parameters
+	soundmakers.Register(saveFolder, config.projectSoundMakers)
+	// Imported DX7 banks extend the patch list that saved DX7 presets point into
+	if count := supercollider.LoadDX7Banks(supercollider.DX7BankDir()); count > 0 {
+		log.Printf("Loaded %d DX7 voices from imported banks", count)
+	}
+	// Device profiles from the user and project folders join the built-in ones
+	if count := midiprofiles.Register(saveFolder); count > 0 {
+		log.Printf("Loaded %d MIDI profiles", count)
+	}
+	// User scales join the scale library for modulation and the project scale
+	if count, err := modulation.LoadUserScales(modulation.UserScalesPath()); err != nil {
+		log.Printf("Error loading user scales: %v", err)
+	} else if count > 0 {
+		log.Printf("Loaded %d user scales", count)
+	}
+
 	m := model.NewModel(oscPort, saveFolder, vimMode)
 
 	// Try to load saved state
@@ -509,6 +562,7 @@ func initialModel(oscPort int, saveFolder string, vimMode bool, dispatcher *osc.
 		// Load files for new model
 		storage.LoadFiles(m)
 	}
+	m.LoadTunings()
 
 	// Note: Preference OSC messages are now sent when first CPU message is received
 	// to ensure SuperCollider is ready to receive them
@@ -525,6 +579,12 @@ func initialModel(oscPort int, saveFolder string, vimMode bool, dispatcher *osc.
 		m.PushWaveformSample(sample, maxCols*2/3)
 	})
 
+	// A virtual output is listed with the devices, so open it before listing them
+	if config.virtualMidi != "" {
+		if err := midiconnector.OpenVirtual(config.virtualMidi); err != nil {
+			log.Printf("Error opening virtual MIDI output: %v", err)
+		}
+	}
 	m.AvailableMidiDevices = midiconnector.Devices()
 	for _, device := range m.AvailableMidiDevices {
 		log.Printf("MIDI device found: %+v", device)
@@ -585,11 +645,11 @@ func tickSplash() tea.Cmd {
 func (tm *TrackerModel) Init() tea.Cmd {
 	if tm.showingSplash {
 		// Start splash screen animation at 60fps
-		return tickSplash()
+		return tea.Batch(tickSplash(), input.WatchMidiDevices())
 	}
 	// Start a 30fps UI loop so the waveform redraws smoothly.
 	// Playback advancement stays on its own schedule (input.TickMsg).
-	return tickWaveform(30)
+	return tea.Batch(tickWaveform(30), input.WatchMidiDevices())
 }
 
 func (tm *TrackerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -620,11 +680,46 @@ func (tm *TrackerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Tempo/engine ticks: only advance playback here, at your musical rate.
 		if tm.model.IsPlaying {
 			input.AdvancePlayback(tm.model)
+			if !tm.model.IsPlaying {
+				// Playback ended on its own (e.g. a finished bounce, which rings out before it
+				// stops recording)
+				return tm, input.BounceTail(tm.model)
+			}
 			// Reschedule the next tempo tick according to your input package.
-			return tm, input.Tick(tm.model)
+			// Arpeggios and automation started by the new rows are clocked from here too.
+			return tm, tea.Batch(input.Tick(tm.model), input.ArpeggioTick(tm.model), input.AutomationTick(tm.model))
 		}
 		return tm, nil
 
+	case input.ArpeggioTickMsg:
+		return tm, input.HandleArpeggioTick(tm.model, msg)
+
+	case input.AutomationTickMsg:
+		return tm, input.HandleAutomationTick(tm.model, msg)
+
+	case input.BounceTailMsg:
+		return tm, input.HandleBounceTail(tm.model, msg)
+
+	case input.BounceWrittenMsg:
+		input.HandleBounceWritten(tm.model, msg)
+		return tm, nil
+
+	case input.BounceWriteTimeoutMsg:
+		input.HandleBounceWriteTimeout(tm.model, msg)
+		return tm, nil
+
+	case input.LibraryScanMsg:
+		input.ApplyLibraryScan(tm.model, msg)
+		return tm, nil
+
+	case input.MidiDevicesTickMsg:
+		return tm, input.RefreshMidiDevices()
+
+	case input.MidiDevicesMsg:
+		// Keep watching for devices plugged in or out
+		input.ApplyMidiDevices(tm.model, msg)
+		return tm, input.WatchMidiDevices()
+
 	case scReadyMsg:
 		// SC is ready — leave the splash screen
 		tm.showingSplash = false
@@ -637,7 +732,8 @@ func (tm *TrackerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return tm, tickWaveform(30)
 		}
 		// Keys may toggle playback, change views, etc.
-		return tm, input.HandleKeyInput(tm.model, msg)
+		// Rows played from the keyboard may start an arpeggio.
+		return tm, tea.Batch(input.HandleKeyInput(tm.model, msg), input.ArpeggioTick(tm.model))
 	}
 
 	return tm, nil
@@ -659,10 +755,26 @@ func (tm TrackerModel) View() string {
 		return views.RenderSettingsView(tm.model)
 	case types.FileMetadataView:
 		return views.RenderFileMetadataView(tm.model)
+	case types.SliceEditorView:
+		return views.RenderSliceEditorView(tm.model)
 	case types.RetriggerView:
 		return views.RenderRetriggerView(tm.model)
 	case types.TimestrechView:
 		return views.RenderTimestrechView(tm.model)
+	case types.GranularView:
+		return views.RenderGranularView(tm.model)
+	case types.PresetView:
+		return views.RenderPresetView(tm.model)
+	case types.LFOView:
+		return views.RenderLFOView(tm.model)
+	case types.InsertFXView:
+		return views.RenderInsertFXView(tm.model)
+	case types.ReturnsView:
+		return views.RenderReturnsView(tm.model)
+	case types.AutomationView:
+		return views.RenderAutomationView(tm.model)
+	case types.ParameterLockView:
+		return views.RenderParameterLockView(tm.model)
 	case types.ModulateView:
 		return views.RenderModulateView(tm.model)
 	case types.ArpeggioView:
@@ -671,6 +783,14 @@ func (tm TrackerModel) View() string {
 		return views.RenderMidiView(tm.model)
 	case types.SoundMakerView:
 		return views.RenderSoundMakerView(tm.model)
+	case types.MultisampleView:
+		return views.RenderMultisampleView(tm.model)
+	case types.LibraryView:
+		return views.RenderLibraryView(tm.model)
+	case types.ProjectFilesView:
+		return views.RenderProjectFilesView(tm.model)
+	case types.SampleToolsView:
+		return views.RenderSampleToolsView(tm.model)
 	case types.DuckingView:
 		return views.RenderDuckingView(tm.model)
 	case types.MixerView:
@@ -81,6 +81,13 @@ func TestTrackerModelView(t *testing.T) {
 		types.FileMetadataView,
 		types.RetriggerView,
 		types.TimestrechView,
+		types.GranularView,
+		types.PresetView,
+		types.LFOView,
+		types.AutomationView,
+		types.ParameterLockView,
+		types.InsertFXView,
+		types.ReturnsView,
 		types.ArpeggioView,
 		types.MidiView,
 		types.SoundMakerView,